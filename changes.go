@@ -129,6 +129,35 @@ func (db *DB) Changes(ctx context.Context, options ...Option) *Changes {
 	return newChanges(ctx, endQuery, changesi)
 }
 
+// SelectorChanges is like [DB.Changes], but returns only changes to documents
+// matching selector, which is evaluated by the server, as with CouchDB's
+// filter=_selector. selector may be any value accepted by [DB.Watch]. If the
+// driver cannot filter the feed by selector, the returned iterator fails with
+// [net/http.StatusNotImplemented].
+func (db *DB) SelectorChanges(ctx context.Context, selector interface{}, options ...Option) *Changes {
+	if db.err != nil {
+		return &Changes{iter: errIterator(db.err)}
+	}
+	changer, ok := db.driverDB.(driver.SelectorChanger)
+	if !ok {
+		return &Changes{iter: errIterator(&internal.Error{Status: http.StatusNotImplemented, Message: "kivik: selector changes not supported by driver"})}
+	}
+	rawSelector, err := toSelector(selector)
+	if err != nil {
+		return &Changes{iter: errIterator(err)}
+	}
+	endQuery, err := db.startQuery()
+	if err != nil {
+		return &Changes{iter: errIterator(err)}
+	}
+	changesi, err := changer.SelectorChanges(ctx, rawSelector, multiOptions(options))
+	if err != nil {
+		endQuery()
+		return &Changes{iter: errIterator(err)}
+	}
+	return newChanges(ctx, endQuery, changesi)
+}
+
 // Seq returns the Seq of the current result.
 func (c *Changes) Seq() string {
 	return c.curVal.(*driver.Change).Seq
@@ -14,6 +14,7 @@ package kivik
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -355,6 +356,67 @@ func TestChanges(t *testing.T) {
 	})
 }
 
+func TestSelectorChanges(t *testing.T) {
+	type tt struct {
+		db       *DB
+		selector interface{}
+		status   int
+		err      string
+	}
+	tests := testy.NewTable()
+	tests.Add("db error", tt{
+		db: &DB{
+			err: errors.New("db error"),
+		},
+		status: http.StatusInternalServerError,
+		err:    "db error",
+	})
+	tests.Add("not supported", tt{
+		db: &DB{
+			client:   &Client{},
+			driverDB: &mock.DB{},
+		},
+		selector: `{"type":"user"}`,
+		status:   http.StatusNotImplemented,
+		err:      "kivik: selector changes not supported by driver",
+	})
+	tests.Add("invalid selector", tt{
+		db: &DB{
+			client:   &Client{},
+			driverDB: &mock.SelectorChanger{},
+		},
+		selector: make(chan int),
+		status:   http.StatusBadRequest,
+		err:      "json: unsupported type: chan int",
+	})
+	tests.Add("success", tt{
+		db: &DB{
+			client: &Client{},
+			driverDB: &mock.SelectorChanger{
+				SelectorChangesFunc: func(_ context.Context, selector json.RawMessage, options driver.Options) (driver.Changes, error) {
+					if d := testy.DiffJSON([]byte(`{"type":"user"}`), []byte(selector)); d != nil {
+						return nil, fmt.Errorf("Unexpected selector:\n%s", d)
+					}
+					opts := map[string]interface{}{}
+					options.Apply(opts)
+					if d := testy.DiffInterface(map[string]interface{}{"since": "now"}, opts); d != nil {
+						return nil, fmt.Errorf("Unexpected options:\n%s", d)
+					}
+					return &mock.Changes{}, nil
+				},
+			},
+		},
+		selector: map[string]string{"type": "user"},
+	})
+
+	tests.Run(t, func(t *testing.T, tt tt) {
+		changes := tt.db.SelectorChanges(context.Background(), tt.selector, Param("since", "now"))
+		if d := internal.StatusErrorDiff(tt.err, tt.status, changes.Err()); d != "" {
+			t.Error(d)
+		}
+	})
+}
+
 func TestChanges_uninitialized_should_not_panic(*testing.T) {
 	// These must not panic, because they can be called before iterating
 	// begins.
@@ -0,0 +1,183 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package cmd
+
+import (
+	"encoding/json"
+
+	"github.com/spf13/cobra"
+
+	"github.com/go-kivik/kivik/v4"
+	"github.com/go-kivik/kivik/v4/cmd/kivik/errors"
+	"github.com/go-kivik/kivik/v4/cmd/kivik/output"
+)
+
+type changes struct {
+	*root
+	feed        string
+	since       string
+	filter      string
+	selector    string
+	includeDocs bool
+	follow      bool
+}
+
+func changesCmd(r *root) *cobra.Command {
+	c := &changes{
+		root: r,
+	}
+	cmd := &cobra.Command{
+		Use:   "changes [dsn]/[database]",
+		Short: "Stream a database's changes feed",
+		Long: `Stream the changes feed of a database, writing each change as it arrives.
+
+With --format=json, each change is written as one JSON object per line. With
+--format=yaml, each change is a separate YAML document, and with
+--format=go-template, the template is executed once per change.
+
+With --follow, the feed is re-opened from the last seen sequence whenever it
+ends, or is interrupted by a server or network error. A normal feed is
+followed as a longpoll feed.`,
+		RunE: c.RunE,
+	}
+
+	pf := cmd.PersistentFlags()
+	pf.StringVar(&c.feed, "feed", "normal", "Feed type. One of: normal|longpoll|continuous")
+	pf.StringVar(&c.since, "since", "", "Start the feed after this update sequence, or 'now'")
+	pf.StringVar(&c.filter, "filter", "", "Filter function, as designdoc/filtername, or a built-in filter such as _design")
+	pf.StringVar(&c.selector, "selector", "", "Mango selector, as JSON, with which to filter the feed. Cannot be combined with --filter")
+	pf.BoolVar(&c.includeDocs, "include-docs", false, "Include the document body with each change")
+	pf.BoolVar(&c.follow, "follow", false, "Re-open the feed from the last seen sequence when it ends")
+
+	return cmd
+}
+
+func (c *changes) params() (map[string]interface{}, error) {
+	params := map[string]interface{}{}
+	for k, v := range c.options {
+		params[k] = v
+	}
+	switch c.feed {
+	case "normal", "longpoll", "continuous":
+	default:
+		return nil, errors.Codef(errors.ErrUsage, "invalid feed type: %s", c.feed)
+	}
+	params["feed"] = c.feed
+	if c.follow && c.feed == "normal" {
+		params["feed"] = "longpoll"
+	}
+	if c.filter != "" && c.selector != "" {
+		return nil, errors.Code(errors.ErrUsage, "--filter and --selector are mutually exclusive")
+	}
+	if c.filter != "" {
+		params["filter"] = c.filter
+	}
+	if c.selector != "" && !json.Valid([]byte(c.selector)) {
+		return nil, errors.Code(errors.ErrUsage, "invalid selector: must be valid JSON")
+	}
+	if c.includeDocs {
+		params["include_docs"] = true
+	}
+	return params, nil
+}
+
+func (c *changes) RunE(cmd *cobra.Command, _ []string) error {
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+	dbName, err := c.conf.DB()
+	if err != nil {
+		return err
+	}
+	params, err := c.params()
+	if err != nil {
+		return err
+	}
+	includeDocs, _ := params["include_docs"].(bool)
+	db := client.DB(dbName)
+	c.log.Debugf("[changes] Will stream changes for %s/%s", client.DSN(), dbName)
+	f := &follower{
+		ctx:    cmd.Context(),
+		log:    c.log,
+		name:   "changes",
+		follow: c.follow,
+		since:  c.since,
+		open: func(since string) feedIterator {
+			opts := []kivik.Option{kivik.Params(params)}
+			if since != "" {
+				opts = append(opts, kivik.Param("since", since))
+			}
+			if c.selector != "" {
+				return &changesIterator{
+					Changes:     db.SelectorChanges(cmd.Context(), json.RawMessage(c.selector), opts...),
+					includeDocs: includeDocs,
+				}
+			}
+			return &changesIterator{
+				Changes:     db.Changes(cmd.Context(), opts...),
+				includeDocs: includeDocs,
+			}
+		},
+	}
+	return c.fmt.OutputStream(f.next)
+}
+
+type changeRev struct {
+	Rev string `json:"rev"`
+}
+
+// changeEvent is the output representation of a single change, in the same
+// format as returned by CouchDB.
+type changeEvent struct {
+	Seq     string          `json:"seq"`
+	ID      string          `json:"id"`
+	Changes []changeRev     `json:"changes"`
+	Deleted bool            `json:"deleted,omitempty"`
+	Doc     json.RawMessage `json:"doc,omitempty"`
+}
+
+const changeTmpl = `{{ .Seq }} {{ .ID }}{{ range .Changes }} {{ .Rev }}{{ end }}{{ if .Deleted }} (deleted){{ end }}
+`
+
+type changesIterator struct {
+	*kivik.Changes
+	includeDocs bool
+}
+
+var _ feedIterator = (*changesIterator)(nil)
+
+func (c *changesIterator) event() (output.FriendlyOutput, string, error) {
+	event := changeEvent{
+		Seq:     c.Seq(),
+		ID:      c.ID(),
+		Deleted: c.Deleted(),
+	}
+	for _, rev := range c.Changes.Changes() {
+		event.Changes = append(event.Changes, changeRev{Rev: rev})
+	}
+	if c.includeDocs {
+		if err := c.ScanDoc(&event.Doc); err != nil {
+			return nil, "", err
+		}
+	}
+	return output.TemplateReader(changeTmpl, event, output.JSONReader(event)), event.Seq, nil
+}
+
+func (c *changesIterator) lastSeq() string {
+	meta, err := c.Metadata()
+	if err != nil {
+		return ""
+	}
+	return meta.LastSeq
+}
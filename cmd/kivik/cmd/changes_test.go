@@ -0,0 +1,171 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gitlab.com/flimzy/testy"
+
+	"github.com/go-kivik/kivik/v4/cmd/kivik/errors"
+)
+
+// changesServer serves the changes feed for database db. Each request is
+// handled by the next of responses; any further requests fail with 400.
+func changesServer(t *testing.T, responses ...func(w http.ResponseWriter, r *http.Request)) *httptest.Server {
+	t.Helper()
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/db/_changes" {
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if len(responses) == 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"bad_request","reason":"no more changes"}`))
+			return
+		}
+		respond := responses[0]
+		responses = responses[1:]
+		respond(w, r)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+const testChanges = `{"results":[
+{"seq":"1-x","id":"foo","changes":[{"rev":"1-a"}]},
+{"seq":"2-x","id":"bar","changes":[{"rev":"2-b"}],"deleted":true}
+],"last_seq":"2-x","pending":0}`
+
+func writeChanges(w http.ResponseWriter, _ *http.Request) {
+	_, _ = w.Write([]byte(testChanges))
+}
+
+func Test_changes_RunE(t *testing.T) {
+	tests := testy.NewTable()
+
+	tests.Add("missing dsn", cmdTest{
+		args:   []string{"changes"},
+		status: errors.ErrUsage,
+	})
+	tests.Add("invalid feed", cmdTest{
+		args:   []string{"changes", "http://example.com/db", "--feed", "eventsource"},
+		status: errors.ErrUsage,
+	})
+	tests.Add("invalid selector", cmdTest{
+		args:   []string{"changes", "http://example.com/db", "--selector", "{"},
+		status: errors.ErrUsage,
+	})
+	tests.Add("filter and selector", cmdTest{
+		args:   []string{"changes", "http://example.com/db", "--filter", "_design", "--selector", `{"name":"Bob"}`},
+		status: errors.ErrUsage,
+	})
+	tests.Add("changes", func(t *testing.T) interface{} {
+		s := changesServer(t, writeChanges)
+		return cmdTest{
+			args: []string{"changes", s.URL + "/db"},
+		}
+	})
+	tests.Add("json", func(t *testing.T) interface{} {
+		s := changesServer(t, writeChanges)
+		return cmdTest{
+			args: []string{"changes", s.URL + "/db", "--format", "json"},
+		}
+	})
+	tests.Add("yaml", func(t *testing.T) interface{} {
+		s := changesServer(t, writeChanges)
+		return cmdTest{
+			args: []string{"changes", s.URL + "/db", "--format", "yaml"},
+		}
+	})
+	tests.Add("go-template", func(t *testing.T) interface{} {
+		s := changesServer(t, writeChanges)
+		return cmdTest{
+			args: []string{"changes", s.URL + "/db", "--format", "go-template={{ .id }}"},
+		}
+	})
+	tests.Add("continuous, include docs", func(t *testing.T) interface{} {
+		s := changesServer(t, func(w http.ResponseWriter, r *http.Request) {
+			q := r.URL.Query()
+			if q.Get("feed") != "continuous" || q.Get("include_docs") != "true" || q.Get("since") != "now" {
+				t.Errorf("Unexpected query: %s", r.URL.RawQuery)
+			}
+			_, _ = w.Write([]byte(`{"seq":"1-x","id":"foo","changes":[{"rev":"1-a"}],"doc":{"_id":"foo","_rev":"1-a","name":"Bob"}}
+{"last_seq":"1-x","pending":0}
+`))
+		})
+		return cmdTest{
+			args: []string{"changes", s.URL + "/db", "--feed", "continuous", "--since", "now", "--include-docs", "--format", "json"},
+		}
+	})
+	tests.Add("selector", func(t *testing.T) interface{} {
+		s := changesServer(t, func(w http.ResponseWriter, r *http.Request) {
+			if filter := r.URL.Query().Get("filter"); filter != "_selector" {
+				t.Errorf("Unexpected filter: %s", filter)
+			}
+			if d := testy.DiffAsJSON([]byte(`{"selector":{"name":"Bob"}}`), gunzipBody(t, r.Body)); d != nil {
+				t.Errorf("Unexpected body: %s", d)
+			}
+			writeChanges(w, r)
+		})
+		return cmdTest{
+			args: []string{"changes", s.URL + "/db", "--selector", `{"name":"Bob"}`},
+		}
+	})
+	tests.Add("follow", func(t *testing.T) interface{} {
+		old := followDelay
+		followDelay = 0
+		t.Cleanup(func() { followDelay = old })
+		s := changesServer(t,
+			func(w http.ResponseWriter, r *http.Request) {
+				q := r.URL.Query()
+				if q.Get("feed") != "longpoll" || q.Has("since") {
+					t.Errorf("Unexpected query: %s", r.URL.RawQuery)
+				}
+				writeChanges(w, r)
+			},
+			func(w http.ResponseWriter, r *http.Request) {
+				if since := r.URL.Query().Get("since"); since != "2-x" {
+					t.Errorf("Unexpected since: %s", since)
+				}
+				w.WriteHeader(http.StatusServiceUnavailable)
+				_, _ = w.Write([]byte(`{"error":"unavailable","reason":"try again"}`))
+			},
+			func(w http.ResponseWriter, r *http.Request) {
+				if since := r.URL.Query().Get("since"); since != "2-x" {
+					t.Errorf("Unexpected since: %s", since)
+				}
+				_, _ = w.Write([]byte(`{"results":[{"seq":"3-x","id":"baz","changes":[{"rev":"1-c"}]}],"last_seq":"4-x","pending":0}`))
+			},
+			func(w http.ResponseWriter, r *http.Request) {
+				if since := r.URL.Query().Get("since"); since != "4-x" {
+					t.Errorf("Unexpected since: %s", since)
+				}
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"error":"not_found","reason":"Database does not exist."}`))
+			},
+		)
+		return cmdTest{
+			args:   []string{"changes", s.URL + "/db", "--follow"},
+			status: errors.ErrNotFound,
+		}
+	})
+
+	tests.Run(t, func(t *testing.T, tt cmdTest) {
+		tt.Test(t)
+	})
+}
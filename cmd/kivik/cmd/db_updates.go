@@ -0,0 +1,133 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/go-kivik/kivik/v4"
+	"github.com/go-kivik/kivik/v4/cmd/kivik/errors"
+	"github.com/go-kivik/kivik/v4/cmd/kivik/output"
+)
+
+type dbUpdates struct {
+	*root
+	feed   string
+	since  string
+	follow bool
+}
+
+func dbUpdatesCmd(r *root) *cobra.Command {
+	c := &dbUpdates{
+		root: r,
+	}
+	cmd := &cobra.Command{
+		Use:   "db-updates [dsn]",
+		Short: "Stream the server's database updates feed",
+		Long: `Stream the server's _db_updates feed, writing each event as it arrives.
+
+Output formats behave as for the changes command: one JSON object per line
+with --format=json, one YAML document per event with --format=yaml, and one
+template execution per event with --format=go-template.
+
+With --follow, the feed is re-opened from the last seen sequence whenever it
+ends, or is interrupted by a server or network error. A normal feed is
+followed as a longpoll feed.`,
+		RunE: c.RunE,
+	}
+
+	pf := cmd.PersistentFlags()
+	pf.StringVar(&c.feed, "feed", "normal", "Feed type. One of: normal|longpoll|continuous")
+	pf.StringVar(&c.since, "since", "", "Start the feed after this update sequence, or 'now'")
+	pf.BoolVar(&c.follow, "follow", false, "Re-open the feed from the last seen sequence when it ends")
+
+	return cmd
+}
+
+func (c *dbUpdates) params() (map[string]interface{}, error) {
+	params := map[string]interface{}{}
+	for k, v := range c.options {
+		params[k] = v
+	}
+	switch c.feed {
+	case "normal", "longpoll", "continuous":
+	default:
+		return nil, errors.Codef(errors.ErrUsage, "invalid feed type: %s", c.feed)
+	}
+	params["feed"] = c.feed
+	if c.follow && c.feed == "normal" {
+		params["feed"] = "longpoll"
+	}
+	return params, nil
+}
+
+func (c *dbUpdates) RunE(cmd *cobra.Command, _ []string) error {
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+	c.conf.Finalize()
+	params, err := c.params()
+	if err != nil {
+		return err
+	}
+	c.log.Debugf("[db-updates] Will stream database updates for %s", client.DSN())
+	f := &follower{
+		ctx:    cmd.Context(),
+		log:    c.log,
+		name:   "db-updates",
+		follow: c.follow,
+		since:  c.since,
+		open: func(since string) feedIterator {
+			// The since parameter is always set, as the CouchDB driver
+			// otherwise defaults to since=now.
+			return &dbUpdatesIterator{
+				DBUpdates: client.DBUpdates(cmd.Context(), kivik.Params(params), kivik.Param("since", since)),
+			}
+		},
+	}
+	return c.fmt.OutputStream(f.next)
+}
+
+// dbUpdateEvent is the output representation of a single database update.
+type dbUpdateEvent struct {
+	DBName string `json:"db_name"`
+	Type   string `json:"type"`
+	Seq    string `json:"seq"`
+}
+
+const dbUpdateTmpl = `{{ .Seq }} {{ .DBName }} {{ .Type }}
+`
+
+type dbUpdatesIterator struct {
+	*kivik.DBUpdates
+}
+
+var _ feedIterator = (*dbUpdatesIterator)(nil)
+
+func (u *dbUpdatesIterator) event() (output.FriendlyOutput, string, error) {
+	event := dbUpdateEvent{
+		DBName: u.DBName(),
+		Type:   u.Type(),
+		Seq:    u.Seq(),
+	}
+	return output.TemplateReader(dbUpdateTmpl, event, output.JSONReader(event)), event.Seq, nil
+}
+
+func (u *dbUpdatesIterator) lastSeq() string {
+	seq, err := u.LastSeq()
+	if err != nil {
+		return ""
+	}
+	return seq
+}
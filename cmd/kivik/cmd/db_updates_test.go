@@ -0,0 +1,109 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gitlab.com/flimzy/testy"
+
+	"github.com/go-kivik/kivik/v4/cmd/kivik/errors"
+)
+
+// dbUpdatesServer serves the _db_updates feed. Each request is handled by the
+// next of responses; any further requests fail with 400.
+func dbUpdatesServer(t *testing.T, responses ...func(w http.ResponseWriter, r *http.Request)) *httptest.Server {
+	t.Helper()
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/_db_updates" {
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if len(responses) == 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"bad_request","reason":"no more updates"}`))
+			return
+		}
+		respond := responses[0]
+		responses = responses[1:]
+		respond(w, r)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func writeDBUpdates(w http.ResponseWriter, _ *http.Request) {
+	_, _ = w.Write([]byte(`{"results":[
+{"db_name":"foo","type":"created","seq":"1-x"},
+{"db_name":"foo","type":"updated","seq":"2-x"}
+],"last_seq":"2-x"}`))
+}
+
+func Test_dbUpdates_RunE(t *testing.T) {
+	tests := testy.NewTable()
+
+	tests.Add("missing dsn", cmdTest{
+		args:   []string{"db-updates"},
+		status: errors.ErrUsage,
+	})
+	tests.Add("invalid feed", cmdTest{
+		args:   []string{"db-updates", "http://example.com/", "--feed", "eventsource"},
+		status: errors.ErrUsage,
+	})
+	tests.Add("db updates", func(t *testing.T) interface{} {
+		s := dbUpdatesServer(t, func(w http.ResponseWriter, r *http.Request) {
+			q := r.URL.Query()
+			if q.Get("feed") != "normal" || q.Has("since") {
+				t.Errorf("Unexpected query: %s", r.URL.RawQuery)
+			}
+			writeDBUpdates(w, r)
+		})
+		return cmdTest{
+			args: []string{"db-updates", s.URL},
+		}
+	})
+	tests.Add("json", func(t *testing.T) interface{} {
+		s := dbUpdatesServer(t, writeDBUpdates)
+		return cmdTest{
+			args: []string{"db-updates", s.URL, "--format", "json"},
+		}
+	})
+	tests.Add("follow", func(t *testing.T) interface{} {
+		s := dbUpdatesServer(t,
+			func(w http.ResponseWriter, r *http.Request) {
+				if feed := r.URL.Query().Get("feed"); feed != "longpoll" {
+					t.Errorf("Unexpected feed: %s", feed)
+				}
+				writeDBUpdates(w, r)
+			},
+			func(w http.ResponseWriter, r *http.Request) {
+				if since := r.URL.Query().Get("since"); since != "2-x" {
+					t.Errorf("Unexpected since: %s", since)
+				}
+				_, _ = w.Write([]byte(`{"results":[{"db_name":"bar","type":"deleted","seq":"3-x"}],"last_seq":"3-x"}`))
+			},
+		)
+		return cmdTest{
+			args:   []string{"db-updates", s.URL, "--follow", "--format", "go-template={{ .db_name }} {{ .type }}"},
+			status: errors.ErrBadRequest,
+		}
+	})
+
+	tests.Run(t, func(t *testing.T, tt cmdTest) {
+		tt.Test(t)
+	})
+}
@@ -17,7 +17,7 @@ import (
 )
 
 type _delete struct {
-	doc, db, att, cf *cobra.Command
+	doc, db, att, cf, reshardJob *cobra.Command
 	*root
 }
 
@@ -28,6 +28,8 @@ func deleteCmd(r *root) *cobra.Command {
 		db:   deleteDBCmd(r),
 		att:  deleteAttachmentCmd(r),
 		cf:   deleteConfigCmd(r),
+
+		reshardJob: deleteReshardJobCmd(r),
 	}
 	cmd := &cobra.Command{
 		Use:     "delete [command]",
@@ -41,6 +43,7 @@ func deleteCmd(r *root) *cobra.Command {
 	cmd.AddCommand(c.doc)
 	cmd.AddCommand(c.db)
 	cmd.AddCommand(c.cf)
+	cmd.AddCommand(c.reshardJob)
 
 	return cmd
 }
@@ -53,6 +56,9 @@ func (c *_delete) RunE(cmd *cobra.Command, args []string) error {
 	if _, _, ok := configFromDSN(dsn); ok {
 		return c.cf.RunE(cmd, args)
 	}
+	if jobID, ok := reshardJobFromDSN(dsn); ok && jobID != "" {
+		return c.reshardJob.RunE(cmd, args)
+	}
 	if c.conf.HasAttachment() {
 		return c.att.RunE(cmd, args)
 	}
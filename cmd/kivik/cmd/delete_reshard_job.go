@@ -0,0 +1,65 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/go-kivik/kivik/v4/cmd/kivik/errors"
+)
+
+type deleteReshardJob struct {
+	*root
+	job string
+}
+
+func deleteReshardJobCmd(r *root) *cobra.Command {
+	c := &deleteReshardJob{
+		root: r,
+	}
+	cmd := &cobra.Command{
+		Use:   "reshard-job [dsn]",
+		Short: "Stop and remove a resharding job",
+		RunE:  c.RunE,
+	}
+
+	pf := cmd.PersistentFlags()
+	pf.StringVarP(&c.job, "job", "j", "", "The ID of the job to delete")
+
+	return cmd
+}
+
+func (c *deleteReshardJob) RunE(cmd *cobra.Command, _ []string) error {
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+	dsn, err := c.conf.URL()
+	if err != nil {
+		return err
+	}
+	if jobID, ok := reshardJobFromDSN(dsn); ok && jobID != "" {
+		c.job = jobID
+	}
+	if c.job == "" {
+		return errors.Code(errors.ErrUsage, "job ID required")
+	}
+
+	c.log.Debugf("[delete] Will delete reshard job: %s/%s", client.DSN(), c.job)
+	return c.retry(func() error {
+		if err := client.DeleteReshardJob(cmd.Context(), c.job); err != nil {
+			return err
+		}
+		return c.fmt.OK()
+	})
+}
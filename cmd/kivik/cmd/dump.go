@@ -0,0 +1,106 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package cmd
+
+import (
+	"context"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/go-kivik/kivik/v4"
+	"github.com/go-kivik/kivik/v4/cmd/kivik/output"
+	"github.com/go-kivik/kivik/v4/x/dump"
+)
+
+type dumpDB struct {
+	*root
+	opts dump.Options
+}
+
+func dumpCmd(r *root) *cobra.Command {
+	c := &dumpDB{
+		root: r,
+	}
+	cmd := &cobra.Command{
+		Use:   "dump [dsn]/[database]",
+		Short: "Dump a database",
+		Long: `Write every leaf revision of every document in a database, including
+conflicts and deleted documents, as newline-delimited JSON, suitable for use
+with kivik restore. The dump is written to stdout, or to the file named by
+--output.
+
+Attachments are inlined as base64 data, unless --attachments-dir is given, in
+which case each is written to a file in that directory, named by the SHA-256
+sum of its content.`,
+		RunE: c.RunE,
+	}
+
+	pf := cmd.PersistentFlags()
+	pf.StringVar(&c.opts.AttachmentDir, "attachments-dir", "", "Write attachments to files in this directory, rather than inline")
+	pf.BoolVar(&c.opts.Security, "security", false, "Include the _security object")
+	pf.BoolVar(&c.opts.Local, "local", false, "Include _local documents")
+
+	return cmd
+}
+
+func (c *dumpDB) RunE(cmd *cobra.Command, _ []string) error {
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+	dbName, err := c.conf.DB()
+	if err != nil {
+		return err
+	}
+	c.log.Debugf("[dump] Will dump %s/%s", client.DSN(), dbName)
+	out := &dumpOutput{
+		ctx:  cmd.Context(),
+		db:   client.DB(dbName),
+		opts: c.opts,
+	}
+	if err := c.fmt.Output(out); err != nil {
+		return err
+	}
+	c.log.Debugf("[dump] Dumped %d document revisions, %d local documents and %d attachments",
+		out.result.Docs, out.result.Local, out.result.Attachments)
+	return nil
+}
+
+// dumpOutput streams a dump to the output.
+type dumpOutput struct {
+	ctx    context.Context
+	db     *kivik.DB
+	opts   dump.Options
+	result *dump.Result
+	pr     *io.PipeReader
+}
+
+var _ output.FriendlyOutput = (*dumpOutput)(nil)
+
+func (d *dumpOutput) Execute(w io.Writer) error {
+	var err error
+	d.result, err = dump.Dump(d.ctx, d.db, w, d.opts)
+	return err
+}
+
+func (d *dumpOutput) Read(p []byte) (int, error) {
+	if d.pr == nil {
+		var pw *io.PipeWriter
+		d.pr, pw = io.Pipe()
+		go func() {
+			pw.CloseWithError(d.Execute(pw))
+		}()
+	}
+	return d.pr.Read(p)
+}
@@ -0,0 +1,87 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gitlab.com/flimzy/testy"
+
+	"github.com/go-kivik/kivik/v4/cmd/kivik/errors"
+)
+
+// dumpServer serves a database db, containing a single document and a single
+// _local document.
+func dumpServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method + " " + r.URL.Path {
+		case "GET /db/_security":
+			_, _ = w.Write([]byte(`{"admins":{"names":["bob"]}}`))
+		case "POST /db/_changes":
+			_, _ = w.Write([]byte(`{"results":[{"seq":"1-x","id":"foo","changes":[{"rev":"1-a"}]}],"last_seq":"1-x","pending":0}`))
+		case "GET /db/foo":
+			if r.URL.Query().Get("open_revs") != `["all"]` {
+				t.Errorf("Unexpected query: %s", r.URL.RawQuery)
+			}
+			w.Header().Set("Content-Type", `multipart/mixed; boundary="abc"`)
+			_, _ = w.Write([]byte(`--abc
+Content-Type: application/json
+
+{"_id":"foo","_rev":"1-a","_revisions":{"start":1,"ids":["a"]},"name":"Bob"}
+--abc--
+`))
+		case "GET /db/_local_docs":
+			_, _ = w.Write([]byte(`{"total_rows":null,"offset":null,"rows":[{"id":"_local/x","key":"_local/x","value":{"rev":"0-1"},"doc":{"_id":"_local/x","_rev":"0-1","seq":5}}]}`))
+		default:
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func Test_dump_RunE(t *testing.T) {
+	tests := testy.NewTable()
+
+	tests.Add("missing dsn", cmdTest{
+		args:   []string{"dump"},
+		status: errors.ErrUsage,
+	})
+	tests.Add("dump", func(t *testing.T) interface{} {
+		s := dumpServer(t)
+		return cmdTest{
+			args: []string{"dump", s.URL + "/db"},
+		}
+	})
+	tests.Add("security and local docs", func(t *testing.T) interface{} {
+		s := dumpServer(t)
+		return cmdTest{
+			args: []string{"dump", s.URL + "/db", "--security", "--local"},
+		}
+	})
+	tests.Add("raw format", func(t *testing.T) interface{} {
+		s := dumpServer(t)
+		return cmdTest{
+			args: []string{"dump", s.URL + "/db", "--format", "raw"},
+		}
+	})
+
+	tests.Run(t, func(t *testing.T, tt cmdTest) {
+		tt.Test(t)
+	})
+}
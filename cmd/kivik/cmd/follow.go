@@ -0,0 +1,96 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package cmd
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/go-kivik/kivik/v4"
+	"github.com/go-kivik/kivik/v4/cmd/kivik/log"
+	"github.com/go-kivik/kivik/v4/cmd/kivik/output"
+)
+
+// followDelay is the delay before re-opening a followed feed after a server
+// or network error.
+var followDelay = time.Second
+
+// feedIterator is the common interface of the changes and _db_updates feeds.
+type feedIterator interface {
+	Next() bool
+	Err() error
+	Close() error
+	// event returns the current event, and its update sequence.
+	event() (output.FriendlyOutput, string, error)
+	// lastSeq returns the last update sequence reported by the feed, once
+	// iteration is complete.
+	lastSeq() string
+}
+
+// follower streams the events of a feed. When follow is set, the feed is
+// re-opened from the last seen update sequence whenever it ends, or fails
+// with a server or network error.
+type follower struct {
+	ctx    context.Context
+	log    log.Logger
+	name   string
+	follow bool
+	since  string
+	open   func(since string) feedIterator
+	iter   feedIterator
+}
+
+// next satisfies the function signature expected by
+// [output.Formatter.OutputStream].
+func (f *follower) next() (io.Reader, error) {
+	for {
+		if f.iter == nil {
+			f.iter = f.open(f.since)
+		}
+		if f.iter.Next() {
+			event, seq, err := f.iter.event()
+			if err != nil {
+				_ = f.iter.Close()
+				return nil, err
+			}
+			f.since = seq
+			return event, nil
+		}
+		err := f.iter.Err()
+		if err == nil {
+			if seq := f.iter.lastSeq(); seq != "" {
+				f.since = seq
+			}
+		}
+		_ = f.iter.Close()
+		f.iter = nil
+		if err != nil && (!f.follow || kivik.HTTPStatus(err) < http.StatusInternalServerError || f.ctx.Err() != nil) {
+			return nil, err
+		}
+		if !f.follow {
+			return nil, io.EOF
+		}
+		if err != nil {
+			f.log.Errorf("[%s] %s; reconnecting from %q", f.name, err, f.since)
+			select {
+			case <-f.ctx.Done():
+				return nil, f.ctx.Err()
+			case <-time.After(followDelay):
+			}
+			continue
+		}
+		f.log.Debugf("[%s] Feed ended; reconnecting from %q", f.name, f.since)
+	}
+}
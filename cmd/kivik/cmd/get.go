@@ -18,6 +18,7 @@ import (
 
 type get struct {
 	alldbs, att, doc, db, ver, cf, sec, cluster *cobra.Command
+	shards, reshard, reshardJobs                *cobra.Command
 	*root
 }
 
@@ -32,6 +33,10 @@ func getCmd(r *root) *cobra.Command {
 		cf:      getConfigCmd(r),
 		sec:     getSecurityCmd(r),
 		cluster: getClusterSetupCmd(r),
+
+		shards:      getShardsCmd(r),
+		reshard:     getReshardCmd(r),
+		reshardJobs: getReshardJobsCmd(r),
 	}
 	cmd := &cobra.Command{
 		Use:   "get [command]",
@@ -48,6 +53,9 @@ func getCmd(r *root) *cobra.Command {
 	cmd.AddCommand(g.cf)
 	cmd.AddCommand(g.sec)
 	cmd.AddCommand(g.cluster)
+	cmd.AddCommand(g.shards)
+	cmd.AddCommand(g.reshard)
+	cmd.AddCommand(g.reshardJobs)
 
 	return cmd
 }
@@ -63,6 +71,15 @@ func (g *get) RunE(cmd *cobra.Command, args []string) error {
 	if _, ok := securityFromDSN(dsn); ok {
 		return g.sec.RunE(cmd, args)
 	}
+	if _, _, ok := shardsFromDSN(dsn); ok {
+		return g.shards.RunE(cmd, args)
+	}
+	if dsn.Path == "/_reshard" {
+		return g.reshard.RunE(cmd, args)
+	}
+	if _, ok := reshardJobFromDSN(dsn); ok {
+		return g.reshardJobs.RunE(cmd, args)
+	}
 	if g.conf.HasAttachment() {
 		return g.att.RunE(cmd, args)
 	}
@@ -0,0 +1,133 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package cmd
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/go-kivik/kivik/v4/cmd/kivik/output"
+)
+
+type getReshard struct {
+	*root
+}
+
+func getReshardCmd(r *root) *cobra.Command {
+	g := &getReshard{
+		root: r,
+	}
+	return &cobra.Command{
+		Use:   "reshard [dsn]",
+		Short: "Get a summary of shard splitting across the cluster",
+		RunE:  g.RunE,
+	}
+}
+
+func (c *getReshard) RunE(cmd *cobra.Command, _ []string) error {
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+
+	c.log.Debugf("[get] Will fetch reshard summary: %s", client.DSN())
+	return c.retry(func() error {
+		summary, err := client.ReshardSummary(cmd.Context())
+		if err != nil {
+			return err
+		}
+		return c.fmt.Output(output.JSONReader(summary))
+	})
+}
+
+// reshardJobFromDSN matches /_reshard/jobs and /_reshard/jobs/{jobid}.
+func reshardJobFromDSN(dsn *url.URL) (jobID string, ok bool) {
+	parts := strings.Split(dsn.Path, "/")
+	if len(parts) < 3 || len(parts) > 4 || parts[1] != "_reshard" || parts[2] != "jobs" {
+		return "", false
+	}
+	if len(parts) == 4 { // nolint:gomnd
+		jobID = parts[3]
+	}
+	return jobID, true
+}
+
+// reshardStateFromDSN matches /_reshard/state and /_reshard/jobs/{jobid}/state.
+func reshardStateFromDSN(dsn *url.URL) (jobID string, ok bool) {
+	parts := strings.Split(dsn.Path, "/")
+	switch {
+	case len(parts) == 3 && parts[1] == "_reshard" && parts[2] == "state":
+		return "", true
+	case len(parts) == 5 && parts[1] == "_reshard" && parts[2] == "jobs" && parts[4] == "state":
+		return parts[3], true
+	}
+	return "", false
+}
+
+type getReshardJobs struct {
+	*root
+	job string
+}
+
+func getReshardJobsCmd(r *root) *cobra.Command {
+	g := &getReshardJobs{
+		root: r,
+	}
+	cmd := &cobra.Command{
+		Use:     "reshard-jobs [dsn]",
+		Aliases: []string{"reshard-job"},
+		Short:   "List resharding jobs",
+		Long:    `List all resharding jobs, or fetch a single job`,
+		RunE:    g.RunE,
+	}
+
+	pf := cmd.PersistentFlags()
+	pf.StringVarP(&g.job, "job", "j", "", "Fetch only the job with the specified ID")
+
+	return cmd
+}
+
+func (c *getReshardJobs) RunE(cmd *cobra.Command, _ []string) error {
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+	dsn, err := c.conf.URL()
+	if err != nil {
+		return err
+	}
+	if jobID, ok := reshardJobFromDSN(dsn); ok && jobID != "" {
+		c.job = jobID
+	}
+
+	if c.job != "" {
+		c.log.Debugf("[get] Will fetch reshard job: %s/%s", client.DSN(), c.job)
+		return c.retry(func() error {
+			job, err := client.ReshardJob(cmd.Context(), c.job)
+			if err != nil {
+				return err
+			}
+			return c.fmt.Output(output.JSONReader(job))
+		})
+	}
+	c.log.Debugf("[get] Will fetch reshard jobs: %s", client.DSN())
+	return c.retry(func() error {
+		jobs, err := client.ReshardJobs(cmd.Context())
+		if err != nil {
+			return err
+		}
+		return c.fmt.Output(output.JSONReader(jobs))
+	})
+}
@@ -0,0 +1,121 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package cmd
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"gitlab.com/flimzy/testy"
+
+	"github.com/go-kivik/kivik/v4/cmd/kivik/errors"
+)
+
+const reshardJobJSON = `{
+	"history": [
+		{"detail": null, "timestamp": "2019-03-28T15:28:02Z", "type": "new"}
+	],
+	"id": "001-abc",
+	"job_state": "completed",
+	"node": "node1@127.0.0.1",
+	"source": "shards/00000000-1fffffff/d1.1553786862",
+	"split_state": "completed",
+	"start_time": "2019-03-28T15:28:02Z",
+	"state_info": {},
+	"target": [
+		"shards/00000000-0fffffff/d1.1553786862",
+		"shards/10000000-1fffffff/d1.1553786862"
+	],
+	"type": "split",
+	"update_time": "2019-03-28T15:28:08Z"
+}`
+
+func Test_get_reshard_RunE(t *testing.T) {
+	tests := testy.NewTable()
+
+	tests.Add("missing dsn", cmdTest{
+		args:   []string{"get", "reshard"},
+		status: errors.ErrUsage,
+	})
+	tests.Add("success", func(t *testing.T) interface{} {
+		s := testy.ServeResponseValidator(t, &http.Response{
+			Header: http.Header{"Content-Type": []string{"application/json"}},
+			Body:   io.NopCloser(strings.NewReader(`{"completed":21,"failed":0,"running":3,"state":"running","state_reason":null,"stopped":0,"total":24}`)),
+		}, func(t *testing.T, req *http.Request) { //nolint:thelper // Not a helper
+			if req.URL.Path != "/_reshard" {
+				t.Errorf("Unexpected path: %s", req.URL.Path)
+			}
+		})
+
+		return cmdTest{
+			args: []string{"get", s.URL + "/_reshard"},
+		}
+	})
+
+	tests.Run(t, func(t *testing.T, tt cmdTest) {
+		tt.Test(t)
+	})
+}
+
+func Test_get_reshard_jobs_RunE(t *testing.T) {
+	tests := testy.NewTable()
+
+	tests.Add("all jobs", func(t *testing.T) interface{} {
+		s := testy.ServeResponseValidator(t, &http.Response{
+			Header: http.Header{"Content-Type": []string{"application/json"}},
+			Body:   io.NopCloser(strings.NewReader(`{"jobs":[` + reshardJobJSON + `],"offset":0,"total_rows":1}`)),
+		}, func(t *testing.T, req *http.Request) { //nolint:thelper // Not a helper
+			if req.URL.Path != "/_reshard/jobs" {
+				t.Errorf("Unexpected path: %s", req.URL.Path)
+			}
+		})
+
+		return cmdTest{
+			args: []string{"get", "reshard-jobs", s.URL},
+		}
+	})
+	tests.Add("single job", func(t *testing.T) interface{} {
+		s := testy.ServeResponseValidator(t, &http.Response{
+			Header: http.Header{"Content-Type": []string{"application/json"}},
+			Body:   io.NopCloser(strings.NewReader(reshardJobJSON)),
+		}, func(t *testing.T, req *http.Request) { //nolint:thelper // Not a helper
+			if req.URL.Path != "/_reshard/jobs/001-abc" {
+				t.Errorf("Unexpected path: %s", req.URL.Path)
+			}
+		})
+
+		return cmdTest{
+			args: []string{"get", "reshard-jobs", s.URL, "--job", "001-abc"},
+		}
+	})
+	tests.Add("auto from path", func(t *testing.T) interface{} {
+		s := testy.ServeResponseValidator(t, &http.Response{
+			Header: http.Header{"Content-Type": []string{"application/json"}},
+			Body:   io.NopCloser(strings.NewReader(reshardJobJSON)),
+		}, func(t *testing.T, req *http.Request) { //nolint:thelper // Not a helper
+			if req.URL.Path != "/_reshard/jobs/001-abc" {
+				t.Errorf("Unexpected path: %s", req.URL.Path)
+			}
+		})
+
+		return cmdTest{
+			args: []string{"get", s.URL + "/_reshard/jobs/001-abc"},
+		}
+	})
+
+	tests.Run(t, func(t *testing.T, tt cmdTest) {
+		tt.Test(t)
+	})
+}
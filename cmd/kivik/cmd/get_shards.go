@@ -0,0 +1,92 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package cmd
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/go-kivik/kivik/v4/cmd/kivik/output"
+)
+
+type getShards struct {
+	*root
+}
+
+func getShardsCmd(r *root) *cobra.Command {
+	g := &getShards{
+		root: r,
+	}
+	return &cobra.Command{
+		Use:   "shards [dsn]/[database]/[document]",
+		Short: "Get a database's shard map",
+		Long: `Get a database's shard map, or the shard to which a document ID maps,
+if a document is given.`,
+		RunE: g.RunE,
+	}
+}
+
+// shardsFromDSN matches /{db}/_shards and /{db}/_shards/{docid}.
+func shardsFromDSN(dsn *url.URL) (db, docID string, ok bool) {
+	parts := strings.SplitN(dsn.Path, "/", 4) // nolint:gomnd
+	if len(parts) < 3 || parts[2] != "_shards" {
+		return "", "", false
+	}
+	if len(parts) == 4 { // nolint:gomnd
+		docID = parts[3]
+	}
+	return parts[1], docID, true
+}
+
+func (c *getShards) RunE(cmd *cobra.Command, _ []string) error {
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+	dsn, err := c.conf.URL()
+	if err != nil {
+		return err
+	}
+	db, docID, ok := shardsFromDSN(dsn)
+	switch {
+	case ok:
+	case c.conf.HasDoc():
+		db, docID, err = c.conf.DBDoc()
+	default:
+		db, err = c.conf.DB()
+	}
+	if err != nil {
+		return err
+	}
+
+	if docID != "" {
+		c.log.Debugf("[get] Will fetch document shard: %s/%s/%s", client.DSN(), db, docID)
+		return c.retry(func() error {
+			shard, err := client.DB(db).DocShard(cmd.Context(), docID)
+			if err != nil {
+				return err
+			}
+			return c.fmt.Output(output.JSONReader(shard))
+		})
+	}
+	c.log.Debugf("[get] Will fetch shard map: %s/%s", client.DSN(), db)
+	return c.retry(func() error {
+		shards, err := client.DB(db).Shards(cmd.Context())
+		if err != nil {
+			return err
+		}
+		return c.fmt.Output(output.JSONReader(map[string]interface{}{"shards": shards}))
+	})
+}
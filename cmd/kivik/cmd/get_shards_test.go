@@ -0,0 +1,79 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package cmd
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"gitlab.com/flimzy/testy"
+
+	"github.com/go-kivik/kivik/v4/cmd/kivik/errors"
+)
+
+func Test_get_shards_RunE(t *testing.T) {
+	tests := testy.NewTable()
+
+	tests.Add("missing database", cmdTest{
+		args:   []string{"get", "shards"},
+		status: errors.ErrUsage,
+	})
+	tests.Add("shard map", func(t *testing.T) interface{} {
+		s := testy.ServeResponseValidator(t, &http.Response{
+			Header: http.Header{"Content-Type": []string{"application/json"}},
+			Body:   io.NopCloser(strings.NewReader(`{"shards":{"00000000-7fffffff":["node1@127.0.0.1"],"80000000-ffffffff":["node2@127.0.0.1"]}}`)),
+		}, func(t *testing.T, req *http.Request) { //nolint:thelper // Not a helper
+			if req.URL.Path != "/db/_shards" {
+				t.Errorf("Unexpected path: %s", req.URL.Path)
+			}
+		})
+
+		return cmdTest{
+			args: []string{"get", "shards", s.URL + "/db"},
+		}
+	})
+	tests.Add("document shard", func(t *testing.T) interface{} {
+		s := testy.ServeResponseValidator(t, &http.Response{
+			Header: http.Header{"Content-Type": []string{"application/json"}},
+			Body:   io.NopCloser(strings.NewReader(`{"range":"e0000000-ffffffff","nodes":["node1@127.0.0.1"]}`)),
+		}, func(t *testing.T, req *http.Request) { //nolint:thelper // Not a helper
+			if req.URL.Path != "/db/_shards/doc" {
+				t.Errorf("Unexpected path: %s", req.URL.Path)
+			}
+		})
+
+		return cmdTest{
+			args: []string{"get", "shards", s.URL + "/db/doc"},
+		}
+	})
+	tests.Add("auto from path", func(t *testing.T) interface{} {
+		s := testy.ServeResponseValidator(t, &http.Response{
+			Header: http.Header{"Content-Type": []string{"application/json"}},
+			Body:   io.NopCloser(strings.NewReader(`{"range":"e0000000-ffffffff","nodes":["node1@127.0.0.1"]}`)),
+		}, func(t *testing.T, req *http.Request) { //nolint:thelper // Not a helper
+			if req.URL.Path != "/db/_shards/doc" {
+				t.Errorf("Unexpected path: %s", req.URL.Path)
+			}
+		})
+
+		return cmdTest{
+			args: []string{"get", s.URL + "/db/_shards/doc"},
+		}
+	})
+
+	tests.Run(t, func(t *testing.T, tt cmdTest) {
+		tt.Test(t)
+	})
+}
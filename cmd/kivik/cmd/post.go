@@ -26,6 +26,7 @@ type post struct {
 	*root
 	*input.Input
 	doc, vc, flush, compact, cv, purge, repl, cluster *cobra.Command
+	syncShards, reshardJobs                           *cobra.Command
 }
 
 func postCmd(r *root) *cobra.Command {
@@ -37,10 +38,13 @@ func postCmd(r *root) *cobra.Command {
 		compact: postCompactCmd(r),
 		cv:      postCompactViewsCmd(r),
 		repl:    postReplicateCmd(r),
+
+		syncShards: postSyncShardsCmd(r),
 	}
 	c.doc = postDocCmd(c)
 	c.purge = postPurgeCmd(c)
 	c.cluster = postClusterSetupCmd(c)
+	c.reshardJobs = postReshardJobsCmd(c)
 
 	cmd := &cobra.Command{
 		Use:   "post",
@@ -59,6 +63,8 @@ func postCmd(r *root) *cobra.Command {
 	cmd.AddCommand(c.purge)
 	cmd.AddCommand(c.repl)
 	cmd.AddCommand(c.cluster)
+	cmd.AddCommand(c.syncShards)
+	cmd.AddCommand(c.reshardJobs)
 
 	return cmd
 }
@@ -88,12 +94,16 @@ func (c *post) RunE(cmd *cobra.Command, args []string) error {
 		return c.compact.RunE(cmd, args)
 	case "_purge":
 		return c.purge.RunE(cmd, args)
+	case "_sync_shards":
+		return c.syncShards.RunE(cmd, args)
 	}
 	switch dsn.Path {
 	case "/_replicate":
 		return c.repl.RunE(cmd, args)
 	case "/_cluster_setup":
 		return c.cluster.RunE(cmd, args)
+	case "/_reshard/jobs":
+		return c.reshardJobs.RunE(cmd, args)
 	}
 	if c.conf.HasDB() {
 		return c.doc.RunE(cmd, args)
@@ -0,0 +1,90 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/go-kivik/kivik/v4"
+	"github.com/go-kivik/kivik/v4/cmd/kivik/errors"
+	"github.com/go-kivik/kivik/v4/cmd/kivik/input"
+	"github.com/go-kivik/kivik/v4/cmd/kivik/output"
+)
+
+type postReshardJobs struct {
+	*root
+	*input.Input
+}
+
+func postReshardJobsCmd(p *post) *cobra.Command {
+	c := &postReshardJobs{
+		root:  p.root,
+		Input: p.Input,
+	}
+	cmd := &cobra.Command{
+		Use:     "reshard-jobs [dsn]",
+		Aliases: []string{"reshard-job"},
+		Short:   "Create resharding jobs",
+		Long: `Create resharding jobs. The input is a JSON object with the fields
+"db" or "shard", and optionally "node", "range" and "type", which defaults to
+"split".`,
+		RunE: c.RunE,
+	}
+
+	return cmd
+}
+
+// reshardJobResult is the output format of a single job creation.
+type reshardJobResult struct {
+	OK     bool   `json:"ok"`
+	ID     string `json:"id,omitempty"`
+	Node   string `json:"node,omitempty"`
+	Shard  string `json:"shard,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
+
+func (c *postReshardJobs) RunE(cmd *cobra.Command, _ []string) error {
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+
+	var job kivik.ReshardJobRequest
+	if err := c.As(&job); err != nil {
+		return err
+	}
+	if job.DB == "" && job.Shard == "" {
+		return errors.Code(errors.ErrUsage, "db or shard required")
+	}
+	c.log.Debugf("[post] Will create reshard jobs: %s", client.DSN())
+
+	return c.retry(func() error {
+		results, err := client.CreateReshardJobs(cmd.Context(), job)
+		if err != nil {
+			return err
+		}
+		out := make([]reshardJobResult, len(results))
+		for i, r := range results {
+			out[i] = reshardJobResult{
+				OK:    r.Error == nil,
+				ID:    r.ID,
+				Node:  r.Node,
+				Shard: r.Shard,
+			}
+			if r.Error != nil {
+				out[i].Reason = r.Error.Error()
+			}
+		}
+		return c.fmt.Output(output.JSONReader(out))
+	})
+}
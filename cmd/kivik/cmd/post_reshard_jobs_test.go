@@ -0,0 +1,61 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package cmd
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"gitlab.com/flimzy/testy"
+
+	"github.com/go-kivik/kivik/v4/cmd/kivik/errors"
+)
+
+func Test_post_reshard_jobs_RunE(t *testing.T) {
+	tests := testy.NewTable()
+
+	tests.Add("missing db and shard", cmdTest{
+		args:   []string{"post", "reshard-jobs", "http://localhost:1/", "--data", `{"node":"node1@127.0.0.1"}`},
+		status: errors.ErrUsage,
+	})
+	tests.Add("success", func(t *testing.T) interface{} {
+		s := testy.ServeResponseValidator(t, &http.Response{
+			StatusCode: http.StatusCreated,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body: io.NopCloser(strings.NewReader(`[
+				{"id":"001-abc","node":"node1@127.0.0.1","ok":true,"shard":"shards/00000000-7fffffff/db3.1554148353"},
+				{"error":"conflict","node":"node2@127.0.0.1","reason":"Job already exists","shard":"shards/00000000-7fffffff/db3.1554148353"}
+			]`)),
+		}, gunzip(func(t *testing.T, req *http.Request) { //nolint:thelper // Not a helper
+			if req.Method != http.MethodPost {
+				t.Errorf("Unexpected method: %v", req.Method)
+			}
+			if req.URL.Path != "/_reshard/jobs" {
+				t.Errorf("Unexpected path: %s", req.URL.Path)
+			}
+			if d := testy.DiffAsJSON([]byte(`{"db":"db3","range":"00000000-7fffffff","type":"split"}`), req.Body); d != nil {
+				t.Errorf("Unexpected request body: %s", d)
+			}
+		}))
+
+		return cmdTest{
+			args: []string{"post", s.URL + "/_reshard/jobs", "--data", `{"db":"db3","range":"00000000-7fffffff"}`},
+		}
+	})
+
+	tests.Run(t, func(t *testing.T, tt cmdTest) {
+		tt.Test(t)
+	})
+}
@@ -0,0 +1,43 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/go-kivik/kivik/v4/cmd/kivik/errors"
+)
+
+type push struct {
+	*root
+}
+
+func pushCmd(r *root) *cobra.Command {
+	c := &push{
+		root: r,
+	}
+	cmd := &cobra.Command{
+		Use:   "push",
+		Short: "Push local resources to the server",
+		Long:  `Push resources maintained locally, such as design documents, to the server`,
+		RunE:  c.RunE,
+	}
+
+	cmd.AddCommand(pushDDocsCmd(r))
+
+	return cmd
+}
+
+func (c *push) RunE(*cobra.Command, []string) error {
+	return errors.Code(errors.ErrUsage, "missing resource type, e.g. ddocs")
+}
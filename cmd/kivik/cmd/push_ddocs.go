@@ -0,0 +1,123 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/go-kivik/kivik/v4"
+	"github.com/go-kivik/kivik/v4/cmd/kivik/errors"
+	"github.com/go-kivik/kivik/v4/cmd/kivik/output"
+	"github.com/go-kivik/kivik/v4/x/ddocs"
+)
+
+type pushDDocs struct {
+	*root
+	dir         string
+	dryRun      bool
+	parallelism int
+}
+
+type pushDDocsResult struct {
+	ID   string `json:"id"`
+	Rev  string `json:"rev,omitempty"`
+	Diff string `json:"diff,omitempty"`
+}
+
+func pushDDocsCmd(r *root) *cobra.Command {
+	c := &pushDDocs{
+		root: r,
+	}
+	cmd := &cobra.Command{
+		Use:     "ddocs [dsn]/[database]",
+		Aliases: []string{"ddoc", "design-docs"},
+		Short:   "Deploy design documents",
+		Long: `Deploy design documents from a directory tree, in which each subdirectory
+is a design document, laid out as views/{name}/map.js, views/{name}/reduce.js,
+filters/{name}.js, validate_doc_update.js, and ddoc.json for any other fields.
+
+Only changed design documents are deployed. Each is first put as a staging
+design document, whose views are built before it is copied over the live
+one, so that queries are not blocked while indexes are rebuilt.`,
+		RunE: c.RunE,
+	}
+
+	pf := cmd.PersistentFlags()
+	pf.StringVarP(&c.dir, "dir", "d", ".", "Directory from which to read design documents")
+	pf.BoolVar(&c.dryRun, "dry-run", false, "Show the changes which would be deployed, without deploying them")
+	pf.IntVar(&c.parallelism, "parallel", 0, "Number of views to build concurrently. The default is 4.")
+
+	return cmd
+}
+
+func (c *pushDDocs) RunE(cmd *cobra.Command, _ []string) error {
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+	dbName, err := c.conf.DB()
+	if err != nil {
+		return err
+	}
+	local, err := ddocs.LoadDir(c.dir)
+	if err != nil {
+		return errors.Code(errors.ErrNoInput, err)
+	}
+	db := client.DB(dbName)
+
+	var changes []*ddocs.Change
+	if err := c.retry(func() error {
+		var err error
+		changes, err = ddocs.Plan(cmd.Context(), db, local)
+		return err
+	}); err != nil {
+		return err
+	}
+	c.log.Debugf("[push] %d of %d design documents changed in %s/%s", len(changes), len(local), client.DSN(), dbName)
+
+	results := make([]pushDDocsResult, len(changes))
+	for i, change := range changes {
+		results[i] = pushDDocsResult{ID: change.DDoc.ID(), Rev: change.Rev, Diff: change.Diff}
+	}
+	if c.dryRun || len(changes) == 0 {
+		format := `{{ range . }}{{ .Diff }}{{ else }}No changes
+{{ end }}`
+		return c.fmt.Output(output.TemplateReader(format, results, output.JSONReader(results)))
+	}
+
+	options := []kivik.Option{
+		kivik.WarmProgress(func(p kivik.ViewProgress) {
+			switch {
+			case p.Err != nil:
+				c.log.Errorf("Failed to build %s/%s: %s", p.DDoc, p.View, p.Err)
+			case p.Done:
+				c.log.Debugf("[push] Built %s/%s", p.DDoc, p.View)
+			default:
+				c.log.Debugf("[push] Building %s/%s: %d%%", p.DDoc, p.View, p.Progress)
+			}
+		}),
+	}
+	if c.parallelism > 0 {
+		options = append(options, kivik.WarmParallelism(c.parallelism))
+	}
+	revs, err := ddocs.Deploy(cmd.Context(), db, changes, options...)
+	if err != nil {
+		return err
+	}
+	for i, rev := range revs {
+		results[i] = pushDDocsResult{ID: results[i].ID, Rev: rev}
+	}
+	format := `{{ range . }}Deployed {{ .ID }} as {{ .Rev }}
+{{ end }}`
+	return c.fmt.Output(output.TemplateReader(format, results, output.JSONReader(results)))
+}
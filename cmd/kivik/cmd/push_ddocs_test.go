@@ -0,0 +1,121 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gitlab.com/flimzy/testy"
+
+	"github.com/go-kivik/kivik/v4/cmd/kivik/errors"
+)
+
+const pushDDocsDeployed = `{"_id":"_design/app","_rev":"1-xxx","language":"javascript","views":{"byName":{"map":"function(doc) {\n  emit(doc.name, null);\n}","reduce":"_count"}}}`
+
+// pushDDocsServer serves the requests needed to deploy the design documents in
+// testdata/ddocs to the database db, where _design/app is deployed as ddoc,
+// or not at all if ddoc is empty.
+func pushDDocsServer(t *testing.T, ddoc string) *httptest.Server {
+	t.Helper()
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method + " " + r.URL.Path {
+		case "GET /db/_design/app":
+			if ddoc == "" {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"error":"not_found","reason":"missing"}`))
+				return
+			}
+			_, _ = w.Write([]byte(ddoc))
+		case "HEAD /db/_design/app-staging":
+			w.WriteHeader(http.StatusNotFound)
+		case "PUT /db/_design/app-staging":
+			w.Header().Set("ETag", `"1-yyy"`)
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"ok":true,"id":"_design/app-staging","rev":"1-yyy"}`))
+		case "GET /db/_design/app-staging":
+			_, _ = w.Write([]byte(`{"_id":"_design/app-staging","_rev":"1-yyy","views":{"byName":{"map":"function(doc) {}"}}}`))
+		case "GET /db/_design/app-staging/_view/byName":
+			_, _ = w.Write([]byte(`{"total_rows":0,"offset":0,"rows":[]}`))
+		case "GET /_active_tasks":
+			_, _ = w.Write([]byte(`[]`))
+		case "COPY /db/_design/app-staging":
+			if dest := r.Header.Get("Destination"); dest != "_design/app?rev=1-xxx" {
+				t.Errorf("Unexpected destination: %s", dest)
+			}
+			w.Header().Set("ETag", `"2-zzz"`)
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"ok":true,"id":"_design/app","rev":"2-zzz"}`))
+		case "DELETE /db/_design/app-staging":
+			w.Header().Set("ETag", `"2-yyy"`)
+			_, _ = w.Write([]byte(`{"ok":true,"id":"_design/app-staging","rev":"2-yyy"}`))
+		default:
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func Test_push_ddocs_RunE(t *testing.T) {
+	tests := testy.NewTable()
+
+	tests.Add("missing dsn", cmdTest{
+		args:   []string{"push", "ddocs"},
+		status: errors.ErrUsage,
+	})
+	tests.Add("missing dir", func(t *testing.T) interface{} {
+		s := pushDDocsServer(t, "")
+		return cmdTest{
+			args:   []string{"push", "ddocs", s.URL + "/db", "--dir", "testdata/missing"},
+			status: errors.ErrNoInput,
+		}
+	})
+	tests.Add("dry run", func(t *testing.T) interface{} {
+		s := pushDDocsServer(t, `{"_id":"_design/app","_rev":"1-xxx","language":"javascript","views":{"byName":{"map":"function(doc) { emit(doc.name); }"}}}`)
+		return cmdTest{
+			args: []string{"push", "ddocs", s.URL + "/db", "--dir", "testdata/ddocs", "--dry-run"},
+		}
+	})
+	tests.Add("dry run, new ddoc", func(t *testing.T) interface{} {
+		s := pushDDocsServer(t, "")
+		return cmdTest{
+			args: []string{"push", "ddocs", s.URL + "/db", "--dir", "testdata/ddocs", "--dry-run"},
+		}
+	})
+	tests.Add("up to date", func(t *testing.T) interface{} {
+		s := pushDDocsServer(t, pushDDocsDeployed)
+		return cmdTest{
+			args: []string{"push", "ddocs", s.URL + "/db", "--dir", "testdata/ddocs"},
+		}
+	})
+	tests.Add("deploy", func(t *testing.T) interface{} {
+		s := pushDDocsServer(t, `{"_id":"_design/app","_rev":"1-xxx","views":{}}`)
+		return cmdTest{
+			args: []string{"push", "ddocs", s.URL + "/db", "--dir", "testdata/ddocs"},
+		}
+	})
+	tests.Add("deploy, json output", func(t *testing.T) interface{} {
+		s := pushDDocsServer(t, `{"_id":"_design/app","_rev":"1-xxx","views":{}}`)
+		return cmdTest{
+			args: []string{"push", "ddocs", s.URL + "/db", "--dir", "testdata/ddocs", "--format", "json"},
+		}
+	})
+
+	tests.Run(t, func(t *testing.T, tt cmdTest) {
+		tt.Test(t)
+	})
+}
@@ -23,7 +23,7 @@ type put struct {
 	*input.Input
 	*root
 
-	db, doc, att, cf, sec *cobra.Command
+	db, doc, att, cf, sec, reshardState *cobra.Command
 }
 
 func putCmd(r *root) *cobra.Command {
@@ -36,6 +36,7 @@ func putCmd(r *root) *cobra.Command {
 	c.att = putAttCmd(c)
 	c.cf = putConfigCmd(c)
 	c.sec = putSecurityCmd(c)
+	c.reshardState = putReshardStateCmd(c)
 
 	cmd := &cobra.Command{
 		Use:   "put",
@@ -51,6 +52,7 @@ func putCmd(r *root) *cobra.Command {
 	cmd.AddCommand(c.att)
 	cmd.AddCommand(c.cf)
 	cmd.AddCommand(c.sec)
+	cmd.AddCommand(c.reshardState)
 
 	return cmd
 }
@@ -66,6 +68,9 @@ func (c *put) RunE(cmd *cobra.Command, args []string) error {
 	if _, ok := securityFromDSN(dsn); ok {
 		return c.sec.RunE(cmd, args)
 	}
+	if _, ok := reshardStateFromDSN(dsn); ok {
+		return c.reshardState.RunE(cmd, args)
+	}
 	if c.conf.HasAttachment() {
 		return c.att.RunE(cmd, args)
 	}
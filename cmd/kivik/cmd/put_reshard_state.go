@@ -0,0 +1,100 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/go-kivik/kivik/v4"
+	"github.com/go-kivik/kivik/v4/cmd/kivik/errors"
+)
+
+type putReshardState struct {
+	*put
+	job, state, reason string
+}
+
+func putReshardStateCmd(p *put) *cobra.Command {
+	c := &putReshardState{
+		put: p,
+	}
+	cmd := &cobra.Command{
+		Use:   "reshard-state [dsn]",
+		Short: "Start or stop resharding",
+		Long: `Start or stop resharding across the cluster, or of a single job if
+a job ID is given.
+
+The new state may be given with the --state and --reason flags, or as a JSON
+object of the form {"state":"stopped","reason":"..."} with --data or
+--data-file. Flags take precedence over input data.`,
+		RunE: c.RunE,
+	}
+
+	pf := cmd.PersistentFlags()
+	pf.StringVarP(&c.job, "job", "j", "", "Set the state of only the job with the specified ID")
+	pf.StringVar(&c.state, "state", "", "The new state, one of running or stopped")
+	pf.StringVar(&c.reason, "reason", "", "An optional reason for the state change")
+
+	return cmd
+}
+
+func (c *putReshardState) RunE(cmd *cobra.Command, _ []string) error {
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+	dsn, err := c.conf.URL()
+	if err != nil {
+		return err
+	}
+	if jobID, ok := reshardStateFromDSN(dsn); ok && jobID != "" {
+		c.job = jobID
+	}
+	if c.HasInput() {
+		var body struct {
+			State  string `json:"state"`
+			Reason string `json:"reason"`
+		}
+		if err := c.As(&body); err != nil {
+			return err
+		}
+		if c.state == "" {
+			c.state = body.State
+		}
+		if c.reason == "" {
+			c.reason = body.Reason
+		}
+	}
+	switch c.state {
+	case kivik.ReshardRunning, kivik.ReshardStopped:
+	default:
+		return errors.Code(errors.ErrUsage, "--state must be one of running or stopped")
+	}
+
+	if c.job != "" {
+		c.log.Debugf("[put] Will set reshard job state: %s/%s", client.DSN(), c.job)
+		return c.retry(func() error {
+			if err := client.SetReshardJobState(cmd.Context(), c.job, c.state, c.reason); err != nil {
+				return err
+			}
+			return c.fmt.OK()
+		})
+	}
+	c.log.Debugf("[put] Will set reshard state: %s", client.DSN())
+	return c.retry(func() error {
+		if err := client.SetReshardState(cmd.Context(), c.state, c.reason); err != nil {
+			return err
+		}
+		return c.fmt.OK()
+	})
+}
@@ -0,0 +1,92 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package cmd
+
+import (
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/go-kivik/kivik/v4"
+	"github.com/go-kivik/kivik/v4/cmd/kivik/errors"
+	"github.com/go-kivik/kivik/v4/cmd/kivik/output"
+	"github.com/go-kivik/kivik/v4/x/dump"
+)
+
+type restoreDB struct {
+	*root
+	input  string
+	create bool
+	opts   dump.Options
+}
+
+func restoreCmd(r *root) *cobra.Command {
+	c := &restoreDB{
+		root: r,
+	}
+	cmd := &cobra.Command{
+		Use:   "restore [dsn]/[database]",
+		Short: "Restore a database dump",
+		Long: `Restore a dump written by kivik dump. Document revisions are written with
+new_edits=false, so that revision histories and conflicts are preserved.
+
+Revisions already present in the database are skipped, so an interrupted
+restore may be resumed by running it again.`,
+		RunE: c.RunE,
+	}
+
+	pf := cmd.PersistentFlags()
+	pf.StringVarP(&c.input, "input", "i", "-", "Read the dump from the named file. Use - for stdin.")
+	pf.StringVar(&c.opts.AttachmentDir, "attachments-dir", "", "Read attachments from files in this directory")
+	pf.BoolVar(&c.create, "create", false, "Create the database, if it does not exist")
+	pf.IntVar(&c.opts.BatchSize, "batch-size", 0, "Number of document revisions to write per request. The default is 100.")
+
+	return cmd
+}
+
+func (c *restoreDB) RunE(cmd *cobra.Command, _ []string) error {
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+	dbName, err := c.conf.DB()
+	if err != nil {
+		return err
+	}
+	var in io.Reader = os.Stdin
+	if c.input != "-" {
+		f, err := os.Open(c.input)
+		if err != nil {
+			return errors.Code(errors.ErrNoInput, err)
+		}
+		defer f.Close() // nolint:errcheck
+		in = f
+	}
+	if c.create {
+		err := c.retry(func() error {
+			return client.CreateDB(cmd.Context(), dbName)
+		})
+		if err != nil && kivik.HTTPStatus(err) != http.StatusPreconditionFailed {
+			return err
+		}
+	}
+	c.log.Debugf("[restore] Will restore to %s/%s", client.DSN(), dbName)
+	result, err := dump.Restore(cmd.Context(), client.DB(dbName), in, c.opts)
+	if err != nil {
+		return err
+	}
+	format := `Restored {{ .Docs }} document revisions ({{ .Skipped }} already present), {{ .Local }} local documents and {{ .Attachments }} attachments`
+	return c.fmt.Output(output.TemplateReader(format, result, output.JSONReader(result)))
+}
@@ -0,0 +1,92 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"gitlab.com/flimzy/testy"
+
+	"github.com/go-kivik/kivik/v4/cmd/kivik/errors"
+)
+
+// restoreServer serves the requests needed to restore testdata/dump.ndjson to
+// the database db, which already contains the document bar.
+func restoreServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method + " " + r.URL.Path {
+		case "PUT /db":
+			w.WriteHeader(http.StatusPreconditionFailed)
+			_, _ = w.Write([]byte(`{"error":"file_exists","reason":"The database could not be created, the file already exists."}`))
+		case "PUT /db/_security":
+			_, _ = w.Write([]byte(`{"ok":true}`))
+		case "POST /db/_revs_diff":
+			_, _ = w.Write([]byte(`{"foo":{"missing":["1-a"]}}`))
+		case "POST /db/_bulk_docs":
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`[]`))
+		case "HEAD /db/_local/x":
+			w.WriteHeader(http.StatusNotFound)
+		case "PUT /db/_local/x":
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"ok":true,"id":"_local/x","rev":"0-1"}`))
+		default:
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func Test_restore_RunE(t *testing.T) {
+	tests := testy.NewTable()
+
+	tests.Add("missing dsn", cmdTest{
+		args:   []string{"restore"},
+		status: errors.ErrUsage,
+	})
+	tests.Add("missing input", func(t *testing.T) interface{} {
+		s := restoreServer(t)
+		return cmdTest{
+			args:   []string{"restore", s.URL + "/db", "--input", "testdata/missing.ndjson"},
+			status: errors.ErrNoInput,
+		}
+	})
+	tests.Add("restore", func(t *testing.T) interface{} {
+		s := restoreServer(t)
+		return cmdTest{
+			args: []string{"restore", s.URL + "/db", "--input", "testdata/dump.ndjson", "--create"},
+		}
+	})
+	tests.Add("stdin, json output", func(t *testing.T) interface{} {
+		s := restoreServer(t)
+		input, err := os.ReadFile("testdata/dump.ndjson")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return cmdTest{
+			args:  []string{"restore", s.URL + "/db", "--format", "json"},
+			stdin: string(input),
+		}
+	})
+
+	tests.Run(t, func(t *testing.T, tt cmdTest) {
+		tt.Test(t)
+	})
+}
@@ -168,6 +168,11 @@ func rootCmd(lg log.Logger) *root {
 	r.cmd.AddCommand(postPurgeRootCmd(r))
 	r.cmd.AddCommand(copyCmd(r))
 	r.cmd.AddCommand(replicateCmd(r))
+	r.cmd.AddCommand(pushCmd(r))
+	r.cmd.AddCommand(dumpCmd(r))
+	r.cmd.AddCommand(restoreCmd(r))
+	r.cmd.AddCommand(changesCmd(r))
+	r.cmd.AddCommand(dbUpdatesCmd(r))
 	// r.cmd.AddCommand(serveCmd(r))
 
 	return r
@@ -25,7 +25,10 @@ type format struct {
 	tmpl *template.Template
 }
 
-var _ output.Format = &format{}
+var (
+	_ output.Format       = &format{}
+	_ output.StreamFormat = &format{}
+)
 
 // New returns a go-template formatter.
 func New() output.Format {
@@ -47,3 +50,23 @@ func (f *format) Output(w io.Writer, r io.Reader) error {
 	}
 	return f.tmpl.Execute(w, obj)
 }
+
+// OutputStream executes the template once per object, each followed by a
+// newline.
+func (f *format) OutputStream(w io.Writer, next func() (io.Reader, error)) error {
+	for {
+		r, err := next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := f.Output(w, r); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, "\n"); err != nil {
+			return err
+		}
+	}
+}
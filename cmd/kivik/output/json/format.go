@@ -25,8 +25,9 @@ type format struct {
 }
 
 var (
-	_ output.Format    = &format{}
-	_ output.FormatArg = &format{}
+	_ output.Format       = &format{}
+	_ output.FormatArg    = &format{}
+	_ output.StreamFormat = &format{}
 )
 
 // New returns a json formatter.
@@ -52,3 +53,24 @@ func (f *format) Output(w io.Writer, r io.Reader) error {
 	enc.SetIndent("", f.indent)
 	return enc.Encode(obj)
 }
+
+// OutputStream outputs each object on its own line, without indentation.
+func (f *format) OutputStream(w io.Writer, next func() (io.Reader, error)) error {
+	enc := json.NewEncoder(w)
+	for {
+		r, err := next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		var obj interface{}
+		if err := json.NewDecoder(r).Decode(&obj); err != nil {
+			return err
+		}
+		if err := enc.Encode(obj); err != nil {
+			return err
+		}
+	}
+}
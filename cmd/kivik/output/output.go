@@ -54,6 +54,13 @@ type FormatArg interface {
 	Required() bool
 }
 
+// StreamFormat is an optional interface. If implemented by a format, it is
+// used by [Formatter.OutputStream] to output a stream of objects, rather than
+// calling Output once per object.
+type StreamFormat interface {
+	OutputStream(w io.Writer, next func() (io.Reader, error)) error
+}
+
 // Register registers an output formatter.
 func (f *Formatter) Register(name string, fmt Format) {
 	f.mu.Lock()
@@ -107,6 +114,37 @@ func (f *Formatter) Output(r io.Reader) error {
 	return fmter.Output(out, r)
 }
 
+// OutputStream outputs each reader returned by next, until next returns
+// [io.EOF].
+func (f *Formatter) OutputStream(next func() (io.Reader, error)) error {
+	fmter, err := f.formatter()
+	if err != nil {
+		return err
+	}
+	out, err := f.writer()
+	if err != nil {
+		return err
+	}
+	if c, ok := out.(io.Closer); ok {
+		defer c.Close() // nolint:errcheck
+	}
+	if s, ok := fmter.(StreamFormat); ok {
+		return s.OutputStream(out, next)
+	}
+	for {
+		r, err := next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := fmter.Output(out, r); err != nil {
+			return err
+		}
+	}
+}
+
 func (f *Formatter) formatter() (Format, error) {
 	args := strings.SplitN(f.format, "=", 2) //nolint:gomnd
 	name := args[0]
@@ -14,6 +14,7 @@ package output
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -177,3 +178,71 @@ func TestOutput(t *testing.T) {
 		}
 	})
 }
+
+func TestOutputStream(t *testing.T) {
+	type tt struct {
+		args []string
+		objs []string
+	}
+
+	tests := testy.NewTable()
+	tests.Add("defaults", tt{
+		objs: []string{`{"x":"y"}`, `{"x":"z"}`},
+	})
+	tests.Add("json", tt{
+		args: []string{"-f", "json"},
+		objs: []string{`{ "x": "y" }`, `{ "x": "z" }`},
+	})
+	tests.Add("raw", tt{
+		args: []string{"-f", "raw"},
+		objs: []string{"{ \"x\": \"y\" }\n", "{ \"x\": \"z\" }\n"},
+	})
+	tests.Add("yaml", tt{
+		args: []string{"-f", "yaml"},
+		objs: []string{`{ "x": "y" }`, `{ "x": "z" }`},
+	})
+	tests.Add("gotmpl", tt{
+		args: []string{"-f", "go-template={{ .x }}"},
+		objs: []string{`{ "x": "y" }`, `{ "x": "z" }`},
+	})
+	tests.Add("empty", tt{
+		args: []string{"-f", "json"},
+	})
+
+	tests.Run(t, func(t *testing.T, tt tt) {
+		fmt := testFormatter()
+		flags := pflag.NewFlagSet("x", pflag.ContinueOnError)
+		fmt.ConfigFlags(flags)
+
+		set := func(flag *pflag.Flag, value string) error {
+			return flags.Set(flag.Name, value)
+		}
+
+		if err := flags.ParseAll(tt.args, set); err != nil {
+			t.Fatal(err)
+		}
+		objs := tt.objs
+		next := func() (io.Reader, error) {
+			if len(objs) == 0 {
+				return nil, io.EOF
+			}
+			obj := objs[0]
+			objs = objs[1:]
+			return strings.NewReader(obj), nil
+		}
+		var err error
+		stdout, stderr := testy.RedirIO(nil, func() {
+			err = fmt.OutputStream(next)
+		})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d := testy.DiffText(testy.Snapshot(t, "_stdout"), stdout); d != nil {
+			t.Errorf("STDOUT: %s", d)
+		}
+		if d := testy.DiffText("", stderr); d != nil {
+			t.Errorf("STDERR: %s", d)
+		}
+	})
+}
@@ -24,7 +24,10 @@ import (
 
 type format struct{}
 
-var _ output.Format = &format{}
+var (
+	_ output.Format       = &format{}
+	_ output.StreamFormat = &format{}
+)
 
 // New returns the yaml formatter.
 func New() output.Format {
@@ -38,3 +41,24 @@ func (f *format) Output(w io.Writer, r io.Reader) error {
 	}
 	return yaml.NewEncoder(w).Encode(obj)
 }
+
+// OutputStream outputs each object as a separate YAML document.
+func (f *format) OutputStream(w io.Writer, next func() (io.Reader, error)) error {
+	enc := yaml.NewEncoder(w)
+	for {
+		r, err := next()
+		if err == io.EOF {
+			return enc.Close()
+		}
+		if err != nil {
+			return err
+		}
+		var obj interface{}
+		if err := json.NewDecoder(r).Decode(&obj); err != nil {
+			return err
+		}
+		if err := enc.Encode(obj); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,282 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package kivik
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	internal "github.com/go-kivik/kivik/v4/int/errors"
+)
+
+// maxResolveAttempts is the number of times [DB.ResolveConflicts] re-reads a
+// document, when new conflicts appear while resolving it.
+const maxResolveAttempts = 5
+
+// LeafRevision is a single leaf revision of a conflicted document, as passed
+// to a [ConflictResolver].
+type LeafRevision struct {
+	// ID is the document ID.
+	ID string
+	// Rev is the leaf revision.
+	Rev string
+	// Doc is the raw JSON document, including _id and _rev.
+	Doc json.RawMessage
+}
+
+// ScanDoc unmarshals the document into dest.
+func (r *LeafRevision) ScanDoc(dest interface{}) error {
+	return json.Unmarshal(r.Doc, dest)
+}
+
+// ConflictResolver is called by [DB.ResolveConflicts] with the winning
+// revision of a document, and the conflicting leaf revisions which lost. It
+// returns the merged document, which is stored as the new revision of the
+// winner. Any _id and _rev in merged are replaced. If merged is nil, the
+// winning revision is kept unchanged. Returning an error aborts the resolution,
+// leaving the document unchanged.
+type ConflictResolver func(winner *LeafRevision, losers []*LeafRevision) (merged interface{}, err error)
+
+// ResolveConflicts resolves the conflicts of the named document. It fetches
+// the winning revision and all conflicting leaf revisions, passes them to
+// resolver. It then stores the merged document on top of the winner, and
+// only once that has succeeded, deletes the losing leaves. If the document
+// changes meanwhile, the process is retried with the new leaves, so the
+// content of the losers is never deleted before it has been merged. The
+// returned rev is the winning revision after resolution. If the document has
+// no conflicts, resolver is not called, and the current rev is returned. If
+// deleting the losers fails after the merged document has been stored, the
+// merged rev is returned along with the error.
+//
+// options are passed to [DB.BulkDocs].
+func (db *DB) ResolveConflicts(ctx context.Context, docID string, resolver ConflictResolver, options ...Option) (rev string, err error) {
+	rev, _, err = db.resolveConflicts(ctx, docID, resolver, options)
+	return rev, err
+}
+
+func (db *DB) resolveConflicts(ctx context.Context, docID string, resolver ConflictResolver, options []Option) (rev string, resolved bool, err error) {
+	if db.err != nil {
+		return "", false, db.err
+	}
+	if docID == "" {
+		return "", false, missingArg("docID")
+	}
+	var lastErr error
+	for attempt := 0; attempt < maxResolveAttempts; attempt++ {
+		winner, conflicts, err := db.leafWinner(ctx, docID)
+		if err != nil {
+			return "", false, err
+		}
+		if len(conflicts) == 0 {
+			return winner.Rev, resolved, nil
+		}
+		losers, err := db.leafRevisions(ctx, docID, conflicts)
+		if err != nil {
+			return "", false, err
+		}
+		if len(losers) == 0 {
+			// The conflicts were resolved elsewhere since we looked.
+			lastErr = errConflictsChanged
+			continue
+		}
+		merged, err := resolver(winner, losers)
+		if err != nil {
+			return "", false, err
+		}
+		rev = winner.Rev
+		// The merged document and the deletions are written with separate
+		// bulk requests, as _bulk_docs is not atomic: in a single request,
+		// the deletions could succeed while the merge conflicts, losing the
+		// content of the losers. Storing the merge first means a failure at
+		// any point leaves every leaf's content in the database, at worst as
+		// a duplicate of what was merged, which the next attempt merges
+		// again.
+		if merged != nil {
+			doc, err := mergedDoc(merged, docID, winner.Rev)
+			if err != nil {
+				return "", false, err
+			}
+			rev, err = db.bulkUpdate(ctx, []interface{}{doc}, options)
+			if HTTPStatus(err) == http.StatusConflict {
+				// The winner changed meanwhile. Leave the losers in place,
+				// so that the merge is retried with their content.
+				lastErr = err
+				continue
+			}
+			if err != nil {
+				return "", false, err
+			}
+			resolved = true
+		}
+		docs := make([]interface{}, 0, len(losers))
+		for _, loser := range losers {
+			docs = append(docs, map[string]interface{}{
+				"_id":      docID,
+				"_rev":     loser.Rev,
+				"_deleted": true,
+			})
+		}
+		results, err := db.BulkDocs(ctx, docs, options...)
+		if err != nil {
+			return rev, resolved, err
+		}
+		lastErr = nil
+		for _, result := range results {
+			if result.Error != nil {
+				if HTTPStatus(result.Error) != http.StatusConflict {
+					return rev, resolved, result.Error
+				}
+				lastErr = result.Error
+				continue
+			}
+			resolved = true
+		}
+		if lastErr == nil {
+			return rev, true, nil
+		}
+	}
+	return "", false, lastErr
+}
+
+// bulkUpdate stores the single document in docs with [DB.BulkDocs], and
+// returns its new rev.
+func (db *DB) bulkUpdate(ctx context.Context, docs []interface{}, options []Option) (string, error) {
+	results, err := db.BulkDocs(ctx, docs, options...)
+	if err != nil {
+		return "", err
+	}
+	if len(results) != 1 {
+		return "", &internal.Error{Status: http.StatusBadGateway, Message: "kivik: unexpected number of bulk results"}
+	}
+	return results[0].Rev, results[0].Error
+}
+
+// leafWinner returns the winning revision of the document, and its conflicts.
+func (db *DB) leafWinner(ctx context.Context, docID string) (*LeafRevision, []string, error) {
+	var body json.RawMessage
+	if err := db.Get(ctx, docID, Param("conflicts", true)).ScanDoc(&body); err != nil {
+		return nil, nil, err
+	}
+	var meta struct {
+		Rev       string   `json:"_rev"`
+		Conflicts []string `json:"_conflicts"`
+	}
+	if err := json.Unmarshal(body, &meta); err != nil {
+		return nil, nil, err
+	}
+	return &LeafRevision{ID: docID, Rev: meta.Rev, Doc: body}, meta.Conflicts, nil
+}
+
+// leafRevisions fetches the requested revisions of the document. Revisions
+// which no longer exist are omitted. Drivers which don't support
+// [DB.OpenRevs] fall back to fetching each revision with [DB.Get].
+func (db *DB) leafRevisions(ctx context.Context, docID string, revs []string) ([]*LeafRevision, error) {
+	rows := db.OpenRevs(ctx, docID, revs)
+	defer rows.Close() // nolint: errcheck
+	leaves := make([]*LeafRevision, 0, len(revs))
+	for rows.Next() {
+		var body json.RawMessage
+		if err := rows.ScanDoc(&body); err != nil {
+			// The rev is missing
+			continue
+		}
+		leaf, err := newLeafRevision(docID, body)
+		if err != nil {
+			return nil, err
+		}
+		leaves = append(leaves, leaf)
+	}
+	err := rows.Err()
+	if HTTPStatus(err) != http.StatusNotImplemented {
+		return leaves, err
+	}
+	leaves = leaves[:0]
+	for _, rev := range revs {
+		var body json.RawMessage
+		err := db.Get(ctx, docID, Param("rev", rev)).ScanDoc(&body)
+		if HTTPStatus(err) == http.StatusNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		leaf, err := newLeafRevision(docID, body)
+		if err != nil {
+			return nil, err
+		}
+		leaves = append(leaves, leaf)
+	}
+	return leaves, nil
+}
+
+func newLeafRevision(docID string, body json.RawMessage) (*LeafRevision, error) {
+	var meta struct {
+		Rev string `json:"_rev"`
+	}
+	if err := json.Unmarshal(body, &meta); err != nil {
+		return nil, err
+	}
+	return &LeafRevision{ID: docID, Rev: meta.Rev, Doc: body}, nil
+}
+
+// mergedDoc converts the resolver's merged document to a map, with the _id
+// and _rev of the winner.
+func mergedDoc(merged interface{}, docID, rev string) (map[string]interface{}, error) {
+	doc, err := mapBody(merged)
+	if err != nil {
+		return nil, err
+	}
+	delete(doc, "_conflicts")
+	doc["_id"] = docID
+	doc["_rev"] = rev
+	return doc, nil
+}
+
+// ResolveConflictsIn calls [DB.ResolveConflicts] for the document of each row
+// of rows, which is closed on return. rows is typically the result of a view
+// which emits only conflicted documents, such as one with the map function:
+//
+//	function(doc) { if (doc._conflicts) { emit(doc._id); } }
+//
+// It returns the number of documents whose conflicts were resolved. Processing
+// stops at the first error.
+func (db *DB) ResolveConflictsIn(ctx context.Context, rows *ResultSet, resolver ConflictResolver, options ...Option) (int, error) {
+	defer rows.Close() // nolint: errcheck
+	var count int
+	seen := map[string]struct{}{}
+	for rows.Next() {
+		id, err := rows.ID()
+		if err != nil {
+			return count, err
+		}
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		_, resolved, err := db.resolveConflicts(ctx, id, resolver, options)
+		if resolved {
+			count++
+		}
+		if err != nil {
+			return count, err
+		}
+	}
+	return count, rows.Err()
+}
+
+// ResolveAllConflicts calls [DB.ResolveConflicts] for every document in the
+// database, as listed by [DB.AllDocs]. See [DB.ResolveConflictsIn].
+func (db *DB) ResolveAllConflicts(ctx context.Context, resolver ConflictResolver, options ...Option) (int, error) {
+	return db.ResolveConflictsIn(ctx, db.AllDocs(ctx), resolver, options...)
+}
@@ -0,0 +1,434 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package kivik
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"gitlab.com/flimzy/testy"
+
+	"github.com/go-kivik/kivik/v4/driver"
+	internal "github.com/go-kivik/kivik/v4/int/errors"
+	"github.com/go-kivik/kivik/v4/int/mock"
+)
+
+// conflictsDB is a minimal driver, storing only the leaf revisions of each
+// document.
+type conflictsDB struct {
+	*mock.DB
+	// leaves maps doc ID to rev to body, for each non-deleted leaf.
+	leaves map[string]map[string]map[string]interface{}
+	// beforeBulk, if set, is called before each bulk update.
+	beforeBulk func(*conflictsDB)
+	bulkCalls  int
+	// failBulk, if set, is the number of the bulk call which fails.
+	failBulk int
+	// noOpenRevs simulates a driver without OpenRevs support.
+	noOpenRevs bool
+}
+
+var (
+	_ driver.OpenRever = &conflictsDB{}
+	_ driver.BulkDocer = &conflictsDB{}
+)
+
+func (d *conflictsDB) put(id, rev string, doc map[string]interface{}) {
+	if d.leaves[id] == nil {
+		d.leaves[id] = map[string]map[string]interface{}{}
+	}
+	d.leaves[id][rev] = doc
+}
+
+// winner returns the winning rev, and the conflicting revs.
+func (d *conflictsDB) winner(id string) (string, []string) {
+	revs := make([]string, 0, len(d.leaves[id]))
+	for rev := range d.leaves[id] {
+		revs = append(revs, rev)
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(revs)))
+	if len(revs) == 0 {
+		return "", nil
+	}
+	return revs[0], revs[1:]
+}
+
+func (d *conflictsDB) body(id, rev string, conflicts []string) io.ReadCloser {
+	doc := map[string]interface{}{"_id": id, "_rev": rev}
+	for k, v := range d.leaves[id][rev] {
+		doc[k] = v
+	}
+	if len(conflicts) > 0 {
+		doc["_conflicts"] = conflicts
+	}
+	data, _ := json.Marshal(doc)
+	return io.NopCloser(strings.NewReader(string(data)))
+}
+
+func (d *conflictsDB) Get(_ context.Context, id string, options driver.Options) (*driver.Document, error) {
+	opts := map[string]interface{}{}
+	options.Apply(opts)
+	rev, conflicts := d.winner(id)
+	if r, ok := opts["rev"].(string); ok {
+		rev, conflicts = r, nil
+	}
+	if _, ok := d.leaves[id][rev]; !ok {
+		return nil, &internal.Error{Status: http.StatusNotFound, Message: "missing"}
+	}
+	if opts["conflicts"] != true {
+		conflicts = nil
+	}
+	return &driver.Document{Rev: rev, Body: d.body(id, rev, conflicts)}, nil
+}
+
+func (d *conflictsDB) OpenRevs(_ context.Context, id string, revs []string, _ driver.Options) (driver.Rows, error) {
+	if d.noOpenRevs {
+		return nil, errOpenRevsNotImplemented
+	}
+	return &mock.Rows{
+		NextFunc: func(row *driver.Row) error {
+			if len(revs) == 0 {
+				return io.EOF
+			}
+			rev := revs[0]
+			revs = revs[1:]
+			*row = driver.Row{ID: id}
+			if _, ok := d.leaves[id][rev]; !ok {
+				row.Error = &internal.Error{Status: http.StatusNotFound, Message: "missing"}
+				return nil
+			}
+			row.Doc = d.body(id, rev, nil)
+			return nil
+		},
+	}, nil
+}
+
+func (d *conflictsDB) AllDocs(context.Context, driver.Options) (driver.Rows, error) {
+	ids := make([]string, 0, len(d.leaves))
+	for id := range d.leaves {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return &mock.Rows{
+		NextFunc: func(row *driver.Row) error {
+			if len(ids) == 0 {
+				return io.EOF
+			}
+			*row = driver.Row{ID: ids[0], Key: json.RawMessage(strconv.Quote(ids[0]))}
+			ids = ids[1:]
+			return nil
+		},
+	}, nil
+}
+
+func (d *conflictsDB) BulkDocs(_ context.Context, docs []interface{}, _ driver.Options) ([]driver.BulkResult, error) {
+	d.bulkCalls++
+	if d.beforeBulk != nil {
+		d.beforeBulk(d)
+	}
+	if d.bulkCalls == d.failBulk {
+		return nil, &internal.Error{Status: http.StatusInternalServerError, Message: "bulk failure"}
+	}
+	results := make([]driver.BulkResult, 0, len(docs))
+	for _, doc := range docs {
+		data, _ := json.Marshal(doc)
+		var fields map[string]interface{}
+		_ = json.Unmarshal(data, &fields)
+		id, _ := fields["_id"].(string)
+		rev, _ := fields["_rev"].(string)
+		if _, ok := d.leaves[id][rev]; !ok {
+			results = append(results, driver.BulkResult{ID: id, Error: &internal.Error{Status: http.StatusConflict, Message: "Document update conflict."}})
+			continue
+		}
+		delete(d.leaves[id], rev)
+		gen, _ := strconv.Atoi(strings.SplitN(rev, "-", 2)[0])
+		newRev := fmt.Sprintf("%d-%s", gen+1, strings.SplitN(rev, "-", 2)[1])
+		if fields["_deleted"] != true {
+			delete(fields, "_id")
+			delete(fields, "_rev")
+			d.put(id, newRev, fields)
+		}
+		results = append(results, driver.BulkResult{ID: id, Rev: newRev})
+	}
+	return results, nil
+}
+
+func newConflictsDB() *conflictsDB {
+	d := &conflictsDB{
+		DB:     &mock.DB{},
+		leaves: map[string]map[string]map[string]interface{}{},
+	}
+	d.put("foo", "2-c", map[string]interface{}{"tags": []interface{}{"c"}})
+	d.put("foo", "2-b", map[string]interface{}{"tags": []interface{}{"b"}})
+	d.put("foo", "2-a", map[string]interface{}{"tags": []interface{}{"a"}})
+	d.put("bar", "1-a", map[string]interface{}{})
+	return d
+}
+
+// mergeTags is a resolver which merges the tags of all leaves.
+func mergeTags(winner *LeafRevision, losers []*LeafRevision) (interface{}, error) {
+	var tags []string
+	for _, leaf := range append([]*LeafRevision{winner}, losers...) {
+		var doc struct {
+			Tags []string `json:"tags"`
+		}
+		if err := leaf.ScanDoc(&doc); err != nil {
+			return nil, err
+		}
+		tags = append(tags, doc.Tags...)
+	}
+	sort.Strings(tags)
+	return map[string]interface{}{"tags": tags, "_conflicts": []string{"bogus"}}, nil
+}
+
+func TestDBResolveConflicts(t *testing.T) {
+	type tt struct {
+		db         *conflictsDB
+		docID      string
+		resolver   ConflictResolver
+		wantRev    string
+		wantLeaves map[string]map[string]interface{}
+		wantBulk   int
+		status     int
+		err        string
+	}
+
+	tests := testy.NewTable()
+	tests.Add("missing doc ID", tt{
+		db:     newConflictsDB(),
+		status: http.StatusBadRequest,
+		err:    "kivik: docID required",
+	})
+	tests.Add("not found", tt{
+		db:       newConflictsDB(),
+		docID:    "baz",
+		resolver: mergeTags,
+		status:   http.StatusNotFound,
+		err:      "missing",
+	})
+	tests.Add("no conflicts", tt{
+		db:    newConflictsDB(),
+		docID: "bar",
+		resolver: func(*LeafRevision, []*LeafRevision) (interface{}, error) {
+			return nil, errors.New("unexpected call")
+		},
+		wantRev: "1-a",
+		wantLeaves: map[string]map[string]interface{}{
+			"1-a": {},
+		},
+	})
+	tests.Add("merge", tt{
+		db:       newConflictsDB(),
+		docID:    "foo",
+		resolver: mergeTags,
+		wantRev:  "3-c",
+		wantLeaves: map[string]map[string]interface{}{
+			"3-c": {"tags": []interface{}{"a", "b", "c"}},
+		},
+		wantBulk: 2,
+	})
+	tests.Add("merge without OpenRevs", func() interface{} {
+		db := newConflictsDB()
+		db.noOpenRevs = true
+		return tt{
+			db:       db,
+			docID:    "foo",
+			resolver: mergeTags,
+			wantRev:  "3-c",
+			wantLeaves: map[string]map[string]interface{}{
+				"3-c": {"tags": []interface{}{"a", "b", "c"}},
+			},
+			wantBulk: 2,
+		}
+	}())
+	tests.Add("keep winner", tt{
+		db:    newConflictsDB(),
+		docID: "foo",
+		resolver: func(winner *LeafRevision, losers []*LeafRevision) (interface{}, error) {
+			if winner.Rev != "2-c" || len(losers) != 2 {
+				return nil, fmt.Errorf("unexpected leaves: %v %v", winner, losers)
+			}
+			return nil, nil
+		},
+		wantRev: "2-c",
+		wantLeaves: map[string]map[string]interface{}{
+			"2-c": {"tags": []interface{}{"c"}},
+		},
+		wantBulk: 1,
+	})
+	tests.Add("resolver error", tt{
+		db:    newConflictsDB(),
+		docID: "foo",
+		resolver: func(*LeafRevision, []*LeafRevision) (interface{}, error) {
+			return nil, &internal.Error{Status: http.StatusTeapot, Message: "nope"}
+		},
+		wantLeaves: map[string]map[string]interface{}{
+			"2-a": {"tags": []interface{}{"a"}},
+			"2-b": {"tags": []interface{}{"b"}},
+			"2-c": {"tags": []interface{}{"c"}},
+		},
+		status: http.StatusTeapot,
+		err:    "nope",
+	})
+	tests.Add("new conflict during resolution", func() interface{} {
+		db := newConflictsDB()
+		db.beforeBulk = func(d *conflictsDB) {
+			if d.bulkCalls == 1 {
+				// Another node updates a losing leaf meanwhile.
+				delete(d.leaves["foo"], "2-a")
+				d.put("foo", "3-a", map[string]interface{}{"tags": []interface{}{"a2"}})
+			}
+		}
+		return tt{
+			db:       db,
+			docID:    "foo",
+			resolver: mergeTags,
+			wantRev:  "4-c",
+			wantLeaves: map[string]map[string]interface{}{
+				"4-c": {"tags": []interface{}{"a", "a2", "b", "c"}},
+			},
+			wantBulk: 4,
+		}
+	}())
+	tests.Add("winner updated during resolution", func() interface{} {
+		db := newConflictsDB()
+		db.beforeBulk = func(d *conflictsDB) {
+			if d.bulkCalls == 1 {
+				delete(d.leaves["foo"], "2-c")
+				d.put("foo", "3-d", map[string]interface{}{"tags": []interface{}{"d"}})
+			}
+		}
+		return tt{
+			db:       db,
+			docID:    "foo",
+			resolver: mergeTags,
+			wantRev:  "4-d",
+			wantLeaves: map[string]map[string]interface{}{
+				"4-d": {"tags": []interface{}{"a", "b", "d"}},
+			},
+			wantBulk: 3,
+		}
+	}())
+	tests.Add("merge conflict keeps losers", func() interface{} {
+		db := newConflictsDB()
+		db.beforeBulk = func(d *conflictsDB) {
+			delete(d.leaves["foo"], "2-c")
+			d.put("foo", "3-d", map[string]interface{}{"tags": []interface{}{"d"}})
+		}
+		var calls int
+		return tt{
+			db:    db,
+			docID: "foo",
+			resolver: func(winner *LeafRevision, losers []*LeafRevision) (interface{}, error) {
+				if calls++; calls > 1 {
+					return nil, &internal.Error{Status: http.StatusTeapot, Message: "stop"}
+				}
+				return mergeTags(winner, losers)
+			},
+			// The merged write conflicted, so the losers must not have been
+			// deleted.
+			wantLeaves: map[string]map[string]interface{}{
+				"2-a": {"tags": []interface{}{"a"}},
+				"2-b": {"tags": []interface{}{"b"}},
+				"3-d": {"tags": []interface{}{"d"}},
+			},
+			wantBulk: 1,
+			status:   http.StatusTeapot,
+			err:      "stop",
+		}
+	}())
+	tests.Add("delete error after merge", func() interface{} {
+		db := newConflictsDB()
+		db.failBulk = 2
+		return tt{
+			db:       db,
+			docID:    "foo",
+			resolver: mergeTags,
+			// The merge was committed, so its rev is returned with the error.
+			wantRev: "3-c",
+			wantLeaves: map[string]map[string]interface{}{
+				"2-a": {"tags": []interface{}{"a"}},
+				"2-b": {"tags": []interface{}{"b"}},
+				"3-c": {"tags": []interface{}{"a", "b", "c"}},
+			},
+			wantBulk: 2,
+			status:   http.StatusInternalServerError,
+			err:      "bulk failure",
+		}
+	}())
+	tests.Add("non-object merge", tt{
+		db:    newConflictsDB(),
+		docID: "foo",
+		resolver: func(*LeafRevision, []*LeafRevision) (interface{}, error) {
+			return []string{"x"}, nil
+		},
+		// Only the status is compared, as the message depends on the
+		// encoding/json implementation.
+		status: http.StatusBadRequest,
+	})
+
+	tests.Run(t, func(t *testing.T, tt tt) {
+		db := &DB{client: &Client{}, driverDB: tt.db}
+		rev, err := db.ResolveConflicts(context.Background(), tt.docID, tt.resolver)
+		if tt.err == "" && tt.status != 0 {
+			if status := HTTPStatus(err); status != tt.status {
+				t.Errorf("Unexpected error status: %d (expected %d): %s", status, tt.status, err)
+			}
+		} else if d := internal.StatusErrorDiff(tt.err, tt.status, err); d != "" {
+			t.Error(d)
+		}
+		if rev != tt.wantRev {
+			t.Errorf("Unexpected rev: %s", rev)
+		}
+		if tt.db.bulkCalls != tt.wantBulk {
+			t.Errorf("Unexpected number of bulk calls: %d", tt.db.bulkCalls)
+		}
+		if tt.wantLeaves == nil {
+			return
+		}
+		if d := testy.DiffInterface(tt.wantLeaves, tt.db.leaves[tt.docID]); d != nil {
+			t.Error(d)
+		}
+	})
+}
+
+func TestDBResolveAllConflicts(t *testing.T) {
+	d := newConflictsDB()
+	d.put("baz", "5-x", map[string]interface{}{"tags": []interface{}{"x"}})
+	d.put("baz", "5-y", map[string]interface{}{"tags": []interface{}{"y"}})
+	db := &DB{client: &Client{}, driverDB: d}
+
+	count, err := db.ResolveAllConflicts(context.Background(), mergeTags)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 {
+		t.Errorf("Unexpected count: %d", count)
+	}
+	want := map[string]map[string]map[string]interface{}{
+		"bar": {"1-a": {}},
+		"baz": {"6-y": {"tags": []interface{}{"x", "y"}}},
+		"foo": {"3-c": {"tags": []interface{}{"a", "b", "c"}}},
+	}
+	if d := testy.DiffInterface(want, d.leaves); d != nil {
+		t.Error(d)
+	}
+}
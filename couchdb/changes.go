@@ -28,6 +28,22 @@ import (
 func (d *db) Changes(ctx context.Context, options driver.Options) (driver.Changes, error) {
 	opts := map[string]interface{}{}
 	options.Apply(opts)
+	return d.changes(ctx, opts, nil)
+}
+
+var _ driver.SelectorChanger = &db{}
+
+// SelectorChanges returns the changes feed, filtered with filter=_selector.
+func (d *db) SelectorChanges(ctx context.Context, selector json.RawMessage, options driver.Options) (driver.Changes, error) {
+	opts := map[string]interface{}{}
+	options.Apply(opts)
+	opts["filter"] = "_selector"
+	return d.changes(ctx, opts, map[string]interface{}{
+		"selector": selector,
+	})
+}
+
+func (d *db) changes(ctx context.Context, opts, body map[string]interface{}) (driver.Changes, error) {
 	key := "results"
 	if f, ok := opts["feed"]; ok {
 		if f == "eventsource" {
@@ -40,9 +56,13 @@ func (d *db) Changes(ctx context.Context, options driver.Options) (driver.Change
 	chttpOpts := new(chttp.Options)
 	if ids := opts["doc_ids"]; ids != nil {
 		delete(opts, "doc_ids")
-		chttpOpts.GetBody = chttp.BodyEncoder(map[string]interface{}{
-			"doc_ids": ids,
-		})
+		if body == nil {
+			body = map[string]interface{}{}
+		}
+		body["doc_ids"] = ids
+	}
+	if body != nil {
+		chttpOpts.GetBody = chttp.BodyEncoder(body)
 	}
 	var err error
 	chttpOpts.Query, err = optionsToParams(opts)
@@ -61,7 +81,9 @@ func (d *db) Changes(ctx context.Context, options driver.Options) (driver.Change
 	return newChangesRows(ctx, key, resp.Body, etag), nil
 }
 
-type continuousChangesParser struct{}
+type continuousChangesParser struct {
+	meta *changesMeta
+}
 
 func (p *continuousChangesParser) parseMeta(i interface{}, dec *json.Decoder, key string) error {
 	meta := i.(*changesMeta)
@@ -74,6 +96,13 @@ func (p *continuousChangesParser) decodeItem(i interface{}, dec *json.Decoder) e
 	if err := dec.Decode(ch); err != nil {
 		return &internal.Error{Status: http.StatusBadGateway, Err: err}
 	}
+	if ch.LastSeq != "" {
+		// The final line of a continuous feed reports only the last_seq
+		// and pending values.
+		p.meta.lastSeq = ch.LastSeq
+		p.meta.pending = ch.Pending
+		return io.EOF
+	}
 	ch.Change.Seq = string(ch.Seq)
 	return nil
 }
@@ -103,12 +132,9 @@ type changesRows struct {
 }
 
 func newChangesRows(ctx context.Context, key string, r io.ReadCloser, etag string) *changesRows {
-	var meta *changesMeta
-	if key != "" {
-		meta = &changesMeta{}
-	}
+	meta := &changesMeta{}
 	return &changesRows{
-		iter: newIter(ctx, meta, key, r, &continuousChangesParser{}),
+		iter: newIter(ctx, meta, key, r, &continuousChangesParser{meta: meta}),
 		etag: etag,
 	}
 }
@@ -118,6 +144,11 @@ var _ driver.Changes = &changesRows{}
 type change struct {
 	*driver.Change
 	Seq sequenceID `json:"seq"`
+
+	// LastSeq and Pending are only set on the final line of a continuous
+	// feed.
+	LastSeq sequenceID `json:"last_seq"`
+	Pending int64      `json:"pending"`
 }
 
 func (r *changesRows) Next(row *driver.Change) error {
@@ -14,6 +14,7 @@ package couchdb
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -60,6 +61,25 @@ func TestChanges_metadata(t *testing.T) {
 	}
 }
 
+func TestChanges_continuous_metadata(t *testing.T) {
+	changes := newChangesRows(context.TODO(), "", Body(`{"seq":"1-x","id":"foo","changes":[{"rev":"1-a"}]}
+{"last_seq":"1-x","pending":3}
+`), "")
+	ch := &driver.Change{}
+	if err := changes.Next(ch); err != nil {
+		t.Fatal(err)
+	}
+	if err := changes.Next(ch); err != io.EOF {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got, want := changes.LastSeq(), "1-x"; got != want {
+		t.Errorf("want last seq: %s, got: %s", want, got)
+	}
+	if got, want := changes.Pending(), int64(3); got != want {
+		t.Errorf("want pending: %d, got: %d", want, got)
+	}
+}
+
 func TestChanges(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -233,6 +253,47 @@ func TestChanges(t *testing.T) {
 	}
 }
 
+func TestSelectorChanges(t *testing.T) {
+	db := newCustomDB(func(req *http.Request) (*http.Response, error) {
+		if req.Method != http.MethodPost {
+			return nil, fmt.Errorf("Unexpected method %v", req.Method)
+		}
+		if filter := req.URL.Query().Get("filter"); filter != "_selector" {
+			return nil, fmt.Errorf("Unexpected filter: %s", filter)
+		}
+		if include := req.URL.Query().Get("include_docs"); include != "true" {
+			return nil, fmt.Errorf("Unexpected include_docs: %s", include)
+		}
+		defer req.Body.Close()
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d := testy.DiffJSON(`{"selector":{"type":"user"}}`, body); d != nil {
+			return nil, fmt.Errorf("Unexpected request body: %s", d)
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"ETag": {`"etag-foo"`}},
+			Body:       Body(`{"results":[{"seq":"1-x","id":"a","changes":[{"rev":"1-a"}],"doc":{"_id":"a","type":"user"}}],"last_seq":"1-x","pending":0}`),
+		}, nil
+	})
+	ch, err := db.SelectorChanges(context.Background(), json.RawMessage(`{"type":"user"}`), kivik.Param("include_docs", true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = ch.Close()
+	})
+	var change driver.Change
+	if err := ch.Next(&change); err != nil {
+		t.Fatal(err)
+	}
+	if change.ID != "a" {
+		t.Errorf("Unexpected change: %v", change)
+	}
+}
+
 func TestChangesNext(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -264,6 +325,13 @@ func TestChangesNext(t *testing.T) {
 			status:  http.StatusBadGateway,
 			err:     "read error",
 		},
+		{
+			name:     "continuous last_seq",
+			changes:  newChangesRows(context.TODO(), "", Body(`{"last_seq":"3-x","pending":0}`), ""),
+			expected: &driver.Change{},
+			status:   http.StatusInternalServerError,
+			err:      "EOF",
+		},
 		{
 			name:     "end of input",
 			changes:  newChangesRows(context.TODO(), "", Body(``), ""),
@@ -0,0 +1,353 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package chttp
+
+import (
+	"bytes"
+	"container/list"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kivik/kivik/v4"
+)
+
+// CacheConfig configures the client-side response cache enabled by
+// [OptionCache].
+type CacheConfig struct {
+	// MaxEntries is the maximum number of responses to retain. When the limit
+	// is reached, the least recently used entry is discarded. A value of 0
+	// means no limit.
+	MaxEntries int
+
+	// MaxSize is the maximum combined size, in bytes, of all cached response
+	// bodies. Responses larger than MaxSize are never cached. A value of 0
+	// means no limit.
+	MaxSize int64
+
+	// TTL is the maximum age of a cache entry. Expired entries are discarded
+	// rather than revalidated. A value of 0 means entries never expire.
+	TTL time.Duration
+}
+
+type optionCache CacheConfig
+
+var _ kivik.Option = optionCache{}
+
+func (o optionCache) Apply(target interface{}) {
+	if client, ok := target.(*Client); ok {
+		client.cache = newResponseCache(CacheConfig(o))
+	}
+}
+
+func (o optionCache) String() string {
+	return fmt.Sprintf("[Cache{max_entries:%d,max_size:%d,ttl:%s}]", o.MaxEntries, o.MaxSize, o.TTL)
+}
+
+// OptionCache enables an in-memory, ETag-aware cache of document, _all_docs
+// and view responses. Cached responses are revalidated on every request with
+// an If-None-Match header, and the cached body is served when the server
+// responds with 304 Not Modified. Writes to a document made through the same
+// client evict any cached copies of that document.
+//
+// Requests which already carry an If-None-Match header bypass the cache. Only
+// honored when passed to [github.com/go-kivik/kivik/v4.New] or [New].
+func OptionCache(config CacheConfig) kivik.Option {
+	return optionCache(config)
+}
+
+// systemDBs are the system databases whose documents may be cached. Any other
+// path beginning with an underscore is a server endpoint.
+var systemDBs = map[string]bool{
+	"_users":          true,
+	"_replicator":     true,
+	"_global_changes": true,
+}
+
+type cacheEntry struct {
+	key     string
+	db      string
+	docID   string
+	etag    string
+	header  http.Header
+	body    []byte
+	expires time.Time
+}
+
+// responseCache is an http.RoundTripper which caches responses to GET
+// requests that carry an ETag.
+type responseCache struct {
+	CacheConfig
+	basePath  string
+	transport http.RoundTripper
+
+	mu      sync.Mutex
+	size    int64
+	lru     *list.List
+	entries map[string]*list.Element
+}
+
+var _ http.RoundTripper = &responseCache{}
+
+func newResponseCache(config CacheConfig) *responseCache {
+	return &responseCache{
+		CacheConfig: config,
+		lru:         list.New(),
+		entries:     map[string]*list.Element{},
+	}
+}
+
+// install wraps the client's transport with the cache.
+func (c *responseCache) install(client *Client) {
+	c.basePath = client.basePath
+	c.transport = client.Transport
+	if c.transport == nil {
+		c.transport = http.DefaultTransport
+	}
+	client.Transport = c
+}
+
+// resource identifies the database and document addressed by a request. docID
+// is empty for database-level requests. cacheable is true for document,
+// _all_docs and view reads.
+type resource struct {
+	db        string
+	docID     string
+	cacheable bool
+}
+
+func (c *responseCache) resource(u *url.URL) (resource, bool) {
+	path := strings.TrimPrefix(strings.TrimPrefix(u.EscapedPath(), c.basePath), "/")
+	segs := strings.Split(strings.TrimSuffix(path, "/"), "/")
+	for i, seg := range segs {
+		if unescaped, err := url.PathUnescape(seg); err == nil {
+			segs[i] = unescaped
+		}
+	}
+	if segs[0] == "" || (strings.HasPrefix(segs[0], "_") && !systemDBs[segs[0]]) {
+		return resource{}, false
+	}
+	r := resource{db: segs[0]}
+	rest := segs[1:]
+	if len(rest) > 1 && rest[0] == "_partition" {
+		rest = rest[2:]
+		switch {
+		case len(rest) == 1 && rest[0] == "_all_docs":
+			r.cacheable = true
+		case len(rest) == 4 && rest[0] == "_design" && rest[2] == "_view":
+			r.cacheable = true
+		}
+		return r, true
+	}
+	switch {
+	case len(rest) == 0:
+	case len(rest) == 1 && rest[0] == "_all_docs":
+		r.cacheable = true
+	case len(rest) == 4 && rest[0] == "_design" && rest[2] == "_view":
+		r.cacheable = true
+	case len(rest) >= 2 && (rest[0] == "_design" || rest[0] == "_local"):
+		r.docID = rest[0] + "/" + rest[1]
+		r.cacheable = len(rest) == 2
+	case !strings.HasPrefix(rest[0], "_"):
+		r.docID = rest[0]
+		r.cacheable = len(rest) == 1
+	}
+	return r, true
+}
+
+func cacheKey(req *http.Request) string {
+	return req.URL.String() + "\x00" + req.Header.Get("Accept")
+}
+
+// RoundTrip satisfies the http.RoundTripper interface.
+func (c *responseCache) RoundTrip(req *http.Request) (*http.Response, error) {
+	res, ok := c.resource(req.URL)
+	if req.Method != http.MethodGet {
+		resp, err := c.transport.RoundTrip(req)
+		if ok && req.Method != http.MethodHead {
+			c.evict(req, res)
+		}
+		return resp, err
+	}
+	if !ok || !res.cacheable || req.Header.Get("If-None-Match") != "" {
+		return c.transport.RoundTrip(req)
+	}
+	key := cacheKey(req)
+	entry := c.lookup(key)
+	if entry != nil {
+		req = req.Clone(req.Context())
+		req.Header.Set("If-None-Match", `"`+entry.etag+`"`)
+	}
+	resp, err := c.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotModified && entry != nil {
+		CloseBody(resp.Body)
+		return entry.response(req), nil
+	}
+	if entry != nil {
+		c.remove(key)
+	}
+	etag, hasETag := ETag(resp)
+	if resp.StatusCode != http.StatusOK || !hasETag {
+		return resp, nil
+	}
+	if c.MaxSize > 0 && resp.ContentLength > c.MaxSize {
+		return resp, nil
+	}
+	return c.store(resp, &cacheEntry{
+		key:   key,
+		db:    res.db,
+		docID: res.docID,
+		etag:  etag,
+	})
+}
+
+// lookup returns the unexpired entry stored under key, if any.
+func (c *responseCache) lookup(key string) *cacheEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil
+	}
+	entry := elem.Value.(*cacheEntry)
+	if !entry.expires.IsZero() && time.Now().After(entry.expires) {
+		c.removeElement(elem)
+		return nil
+	}
+	c.lru.MoveToFront(elem)
+	return entry
+}
+
+// store reads the response body, and stores it in the cache, unless it
+// exceeds MaxSize. The returned response's body is restored for reading.
+func (c *responseCache) store(resp *http.Response, entry *cacheEntry) (*http.Response, error) {
+	var body io.Reader = resp.Body
+	if c.MaxSize > 0 {
+		body = io.LimitReader(resp.Body, c.MaxSize+1)
+	}
+	content, err := io.ReadAll(body)
+	if err != nil {
+		CloseBody(resp.Body)
+		return nil, err
+	}
+	if c.MaxSize > 0 && int64(len(content)) > c.MaxSize {
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{
+			Reader: io.MultiReader(bytes.NewReader(content), resp.Body),
+			Closer: resp.Body,
+		}
+		return resp, nil
+	}
+	CloseBody(resp.Body)
+	resp.Body = io.NopCloser(bytes.NewReader(content))
+
+	entry.header = resp.Header.Clone()
+	entry.body = content
+	if c.TTL > 0 {
+		entry.expires = time.Now().Add(c.TTL)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.entries[entry.key]; ok {
+		c.removeElement(elem)
+	}
+	c.entries[entry.key] = c.lru.PushFront(entry)
+	c.size += int64(len(content))
+	for c.lru.Len() > 0 && ((c.MaxEntries > 0 && c.lru.Len() > c.MaxEntries) || (c.MaxSize > 0 && c.size > c.MaxSize)) {
+		c.removeElement(c.lru.Back())
+	}
+	return resp, nil
+}
+
+func (c *responseCache) remove(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.entries[key]; ok {
+		c.removeElement(elem)
+	}
+}
+
+// removeElement must be called with c.mu held.
+func (c *responseCache) removeElement(elem *list.Element) {
+	entry := c.lru.Remove(elem).(*cacheEntry)
+	delete(c.entries, entry.key)
+	c.size -= int64(len(entry.body))
+}
+
+// evict discards any entries which may be invalidated by the write request.
+func (c *responseCache) evict(req *http.Request, res resource) {
+	var match func(*cacheEntry) bool
+	switch {
+	case req.Method == "COPY":
+		// Only the destination document is written by a COPY request. Its
+		// ID is escaped as in a path, so is unescaped to match entry.docID.
+		dest := strings.SplitN(req.Header.Get(HeaderDestination), "?", 2)[0] // nolint:gomnd
+		if unescaped, err := url.PathUnescape(dest); err == nil {
+			dest = unescaped
+		}
+		match = func(entry *cacheEntry) bool {
+			return entry.db == res.db && entry.docID == dest
+		}
+	case res.docID != "":
+		// A write to a single document or one of its attachments.
+		match = func(entry *cacheEntry) bool {
+			return entry.db == res.db && entry.docID == res.docID
+		}
+	case req.Method == http.MethodPost:
+		// Bulk operations such as _bulk_docs or _purge may touch any document,
+		// and POST /{db} creates a document with an unknown ID.
+		match = func(entry *cacheEntry) bool {
+			return entry.db == res.db && entry.docID != ""
+		}
+	case req.Method == http.MethodDelete:
+		match = func(entry *cacheEntry) bool {
+			return entry.db == res.db
+		}
+	default:
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for elem := c.lru.Front(); elem != nil; {
+		next := elem.Next()
+		if match(elem.Value.(*cacheEntry)) {
+			c.removeElement(elem)
+		}
+		elem = next
+	}
+}
+
+// response builds a new response from the cached entry.
+func (e *cacheEntry) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         req.Proto,
+		ProtoMajor:    req.ProtoMajor,
+		ProtoMinor:    req.ProtoMinor,
+		Header:        e.header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(e.body)),
+		ContentLength: int64(len(e.body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,243 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package chttp
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kivik/kivik/v4/internal/nettest"
+)
+
+// etagServer serves a small, mutable set of documents, honoring the
+// If-None-Match header.
+type etagServer struct {
+	mu       sync.Mutex
+	docs     map[string]string
+	revs     map[string]int
+	requests []string
+}
+
+func (s *etagServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, r.Method+" "+r.URL.Path+" "+r.Header.Get("If-None-Match"))
+	switch r.Method {
+	case http.MethodPut:
+		body, _ := io.ReadAll(r.Body)
+		s.docs[r.URL.Path] = string(body)
+		s.revs[r.URL.Path]++
+		w.WriteHeader(http.StatusCreated)
+		return
+	case http.MethodGet:
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	doc, ok := s.docs[r.URL.Path]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	etag := fmt.Sprintf(`"%d-x"`, s.revs[r.URL.Path])
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Content-Type", typeJSON)
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	_, _ = io.WriteString(w, doc)
+}
+
+func (s *etagServer) lastRequest() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests[len(s.requests)-1]
+}
+
+func newCacheTestClient(t *testing.T, config CacheConfig) (*Client, *etagServer) {
+	t.Helper()
+	h := &etagServer{
+		docs: map[string]string{
+			"/db/foo":                  `{"_id":"foo"}`,
+			"/db/bar":                  `{"_id":"bar"}`,
+			"/db/_all_docs":            `{"rows":[]}`,
+			"/db/_design/ddoc/_view/v": `{"rows":[]}`,
+			"/_all_dbs":                `["db"]`,
+		},
+		revs: map[string]int{},
+	}
+	s := nettest.NewHTTPTestServer(t, h)
+	t.Cleanup(s.Close)
+	c, err := New(&http.Client{}, s.URL, OptionCache(config))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c, h
+}
+
+func cacheGet(t *testing.T, c *Client, path string) string {
+	t.Helper()
+	resp, err := c.DoReq(context.Background(), http.MethodGet, path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer CloseBody(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Unexpected status: %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(body)
+}
+
+func TestCacheRevalidates(t *testing.T) {
+	c, s := newCacheTestClient(t, CacheConfig{})
+
+	for _, path := range []string{"/db/foo", "/db/_all_docs", "/db/_design/ddoc/_view/v"} {
+		first := cacheGet(t, c, path)
+		if got := s.lastRequest(); got != "GET "+path+" " {
+			t.Errorf("Unexpected first request: %s", got)
+		}
+		second := cacheGet(t, c, path)
+		if got := s.lastRequest(); got != "GET "+path+` "0-x"` {
+			t.Errorf("Unexpected second request: %s", got)
+		}
+		if first != second {
+			t.Errorf("Cached body %q does not match original %q", second, first)
+		}
+	}
+}
+
+func TestCacheIgnoresServerEndpoints(t *testing.T) {
+	c, s := newCacheTestClient(t, CacheConfig{})
+
+	_ = cacheGet(t, c, "/_all_dbs")
+	_ = cacheGet(t, c, "/_all_dbs")
+	if got := s.lastRequest(); got != "GET /_all_dbs " {
+		t.Errorf("Unexpected request: %s", got)
+	}
+}
+
+func TestCacheEvictsOnWrite(t *testing.T) {
+	c, s := newCacheTestClient(t, CacheConfig{})
+
+	_ = cacheGet(t, c, "/db/foo")
+	_ = cacheGet(t, c, "/db/bar")
+	if _, err := c.DoError(context.Background(), http.MethodPut, "/db/foo", &Options{NoGzip: true, Body: io.NopCloser(strings.NewReader(`{"_id":"foo","updated":true}`))}); err != nil {
+		t.Fatal(err)
+	}
+	if got := cacheGet(t, c, "/db/foo"); got != `{"_id":"foo","updated":true}` {
+		t.Errorf("Unexpected body after write: %s", got)
+	}
+	if got := s.lastRequest(); got != "GET /db/foo " {
+		t.Errorf("Expected unconditional request after write, got: %s", got)
+	}
+	_ = cacheGet(t, c, "/db/bar")
+	if got := s.lastRequest(); got != `GET /db/bar "0-x"` {
+		t.Errorf("Expected unrelated document to remain cached, got: %s", got)
+	}
+}
+
+func TestCacheEvictsCopyDestination(t *testing.T) {
+	c, s := newCacheTestClient(t, CacheConfig{})
+	s.docs["/db/foo/bar"] = `{"_id":"foo/bar"}`
+
+	_ = cacheGet(t, c, "/db/foo%2Fbar")
+	opts := &Options{Header: http.Header{HeaderDestination: []string{"foo%2Fbar?rev=1-x"}}}
+	if _, err := c.DoReq(context.Background(), "COPY", "/db/foo", opts); err != nil {
+		t.Fatal(err)
+	}
+	_ = cacheGet(t, c, "/db/foo%2Fbar")
+	if got := s.lastRequest(); got != "GET /db/foo/bar " {
+		t.Errorf("Expected unconditional request after copy, got: %s", got)
+	}
+}
+
+func TestCacheLimits(t *testing.T) {
+	t.Run("max entries", func(t *testing.T) {
+		c, s := newCacheTestClient(t, CacheConfig{MaxEntries: 1})
+
+		_ = cacheGet(t, c, "/db/foo")
+		_ = cacheGet(t, c, "/db/bar")
+		_ = cacheGet(t, c, "/db/foo")
+		if got := s.lastRequest(); got != "GET /db/foo " {
+			t.Errorf("Expected least-recently used entry to be evicted, got: %s", got)
+		}
+	})
+	t.Run("max size", func(t *testing.T) {
+		c, s := newCacheTestClient(t, CacheConfig{MaxSize: 5})
+
+		if got := cacheGet(t, c, "/db/foo"); got != `{"_id":"foo"}` {
+			t.Errorf("Unexpected body: %s", got)
+		}
+		_ = cacheGet(t, c, "/db/foo")
+		if got := s.lastRequest(); got != "GET /db/foo " {
+			t.Errorf("Expected oversized response not to be cached, got: %s", got)
+		}
+	})
+	t.Run("ttl", func(t *testing.T) {
+		c, s := newCacheTestClient(t, CacheConfig{TTL: time.Millisecond})
+
+		_ = cacheGet(t, c, "/db/foo")
+		time.Sleep(5 * time.Millisecond)
+		_ = cacheGet(t, c, "/db/foo")
+		if got := s.lastRequest(); got != "GET /db/foo " {
+			t.Errorf("Expected expired entry to be discarded, got: %s", got)
+		}
+	})
+}
+
+func TestCacheResource(t *testing.T) {
+	c := newResponseCache(CacheConfig{})
+	c.basePath = "/couch"
+	tests := []struct {
+		path      string
+		expected  resource
+		supported bool
+	}{
+		{path: "/couch/_all_dbs"},
+		{path: "/couch/_node/_local/_config"},
+		{path: "/couch/db", expected: resource{db: "db"}, supported: true},
+		{path: "/couch/db/foo", expected: resource{db: "db", docID: "foo", cacheable: true}, supported: true},
+		{path: "/couch/db/foo%2Fbar", expected: resource{db: "db", docID: "foo/bar", cacheable: true}, supported: true},
+		{path: "/couch/db/foo/att.txt", expected: resource{db: "db", docID: "foo"}, supported: true},
+		{path: "/couch/db/_design/foo", expected: resource{db: "db", docID: "_design/foo", cacheable: true}, supported: true},
+		{path: "/couch/db/_local/foo", expected: resource{db: "db", docID: "_local/foo", cacheable: true}, supported: true},
+		{path: "/couch/db/_changes", expected: resource{db: "db"}, supported: true},
+		{path: "/couch/db/_partition/p/_all_docs", expected: resource{db: "db", cacheable: true}, supported: true},
+		{path: "/couch/db/_partition/p/_design/d/_view/v", expected: resource{db: "db", cacheable: true}, supported: true},
+		{path: "/couch/_users/org.couchdb.user:bob", expected: resource{db: "_users", docID: "org.couchdb.user:bob", cacheable: true}, supported: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			u, err := url.Parse(tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, supported := c.resource(u)
+			if supported != tt.supported || got != tt.expected {
+				t.Errorf("Unexpected result: %+v, %t", got, supported)
+			}
+		})
+	}
+}
@@ -55,6 +55,9 @@ type Client struct {
 
 	// noGzip will be set to true if the server fails on gzip-encoded requests.
 	noGzip bool
+
+	// cache, if set by OptionCache, caches document and view responses.
+	cache *responseCache
 }
 
 // New returns a connection to a remote CouchDB server. If credentials are
@@ -93,6 +96,9 @@ func New(client *http.Client, dsn string, options driver.Options) (*Client, erro
 			return nil, err
 		}
 	}
+	if c.cache != nil {
+		c.cache.install(c)
+	}
 	return c, nil
 }
 
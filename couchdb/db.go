@@ -133,7 +133,7 @@ func (d *db) rowsQuery(ctx context.Context, path string, options driver.Options)
 // AllDocs returns all of the documents in the database.
 func (d *db) AllDocs(ctx context.Context, options driver.Options) (driver.Rows, error) {
 	reqPath := partPath("_all_docs")
-	options.Apply(reqPath)
+	options.Apply(&reqPath.part)
 	return d.rowsQuery(ctx, reqPath.String(), options)
 }
 
@@ -150,7 +150,7 @@ func (d *db) LocalDocs(ctx context.Context, options driver.Options) (driver.Rows
 // Query queries a view.
 func (d *db) Query(ctx context.Context, ddoc, view string, options driver.Options) (driver.Rows, error) {
 	reqPath := partPath(fmt.Sprintf("_design/%s/_view/%s", chttp.EncodeDocID(ddoc), chttp.EncodeDocID(view)))
-	options.Apply(reqPath)
+	options.Apply(&reqPath.part)
 	return d.rowsQuery(ctx, reqPath.String(), options)
 }
 
@@ -1028,8 +1028,15 @@ func (d *db) Copy(ctx context.Context, targetID, sourceID string, options driver
 	if err != nil {
 		return "", err
 	}
+	// The destination may carry a query, such as ?rev=, to overwrite an
+	// existing document, so only the ID itself is escaped.
+	parts := strings.SplitN(targetID, "?", 2)
+	destination := chttp.EncodeDocID(parts[0])
+	if len(parts) == 2 {
+		destination += "?" + parts[1]
+	}
 	chttpOpts.Header = http.Header{
-		chttp.HeaderDestination: []string{targetID},
+		chttp.HeaderDestination: []string{destination},
 	}
 
 	resp, err := d.Client.DoReq(ctx, "COPY", d.path(chttp.EncodeDocID(sourceID)), chttpOpts)
@@ -2066,6 +2066,23 @@ func TestCopy(t *testing.T) {
 		}),
 		rev: "2-yyy",
 	})
+	tests.Add("escaped target with rev", tt{
+		source: "foo",
+		target: "bar/baz?rev=1-xxx",
+		db: newCustomDB(func(req *http.Request) (*http.Response, error) {
+			if dest := req.Header.Get("Destination"); dest != "bar%2Fbaz?rev=1-xxx" {
+				return nil, fmt.Errorf("Unexpected destination: %s", dest)
+			}
+			return &http.Response{
+				StatusCode: 201,
+				Header: http.Header{
+					"ETag": {`"2-yyy"`},
+				},
+				Body: Body(`{"ok":true,"id":"bar/baz","rev":"2-yyy"}`),
+			}, nil
+		}),
+		rev: "2-yyy",
+	})
 
 	tests.Run(t, func(t *testing.T, tt tt) {
 		opts := tt.options
@@ -29,6 +29,9 @@ type dbStats struct {
 		External int64 `json:"external"`
 		Active   int64 `json:"active"`
 	} `json:"sizes"`
+	Props struct {
+		Partitioned bool `json:"partitioned"`
+	} `json:"props"`
 	UpdateSeq json.RawMessage `json:"update_seq"` // nolint: govet
 	rawBody   json.RawMessage
 }
@@ -55,6 +58,7 @@ func (s *dbStats) driverStats() *driver.DBStats {
 	if s.Sizes.Active > 0 {
 		stats.ActiveSize = s.Sizes.Active
 	}
+	stats.Partitioned = s.Props.Partitioned
 	stats.UpdateSeq = string(bytes.Trim(s.UpdateSeq, `"`))
 	stats.RawResponse = s.rawBody
 	return stats
@@ -153,6 +153,20 @@ func TestStats(t *testing.T) {
 				RawResponse: []byte(`{"db_name":"_users","update_seq":"13-g1AAAAEzeJzLYWBg4MhgTmHgzcvPy09JdcjLz8gvLskBCjMlMiTJ____PyuRAYeCJAUgmWQPVsOCS40DSE08WA0rLjUJIDX1eO3KYwGSDA1ACqhsPiF1CyDq9mclMuFVdwCi7j4hdQ8g6kDuywIAkRBjAw","sizes":{"file":87323,"external":2495,"active":6082},"purge_seq":0,"other":{"data_size":2495},"doc_del_count":6,"doc_count":1,"disk_size":87323,"disk_format_version":6,"data_size":6082,"compact_running":false,"instance_start_time":"0","cluster":{"n":1,"q":2,"r":3,"w":4}}`),
 			},
 		},
+		{
+			name: "partitioned",
+			db: newTestDB(&http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`{"db_name":"sensors","update_seq":"1-abc","doc_del_count":0,"doc_count":1,"props":{"partitioned":true}}`)),
+			}, nil),
+			expected: &driver.DBStats{
+				Name:        "sensors",
+				DocCount:    1,
+				UpdateSeq:   "1-abc",
+				Partitioned: true,
+				RawResponse: []byte(`{"db_name":"sensors","update_seq":"1-abc","doc_del_count":0,"doc_count":1,"props":{"partitioned":true}}`),
+			},
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
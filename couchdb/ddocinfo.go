@@ -0,0 +1,102 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package couchdb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-kivik/kivik/v4/couchdb/chttp"
+	"github.com/go-kivik/kivik/v4/driver"
+)
+
+var _ driver.DesignDocInfoer = &db{}
+
+type designDocInfo struct {
+	Name      string `json:"name"`
+	ViewIndex struct {
+		Signature string          `json:"signature"`
+		Language  string          `json:"language"`
+		UpdateSeq json.RawMessage `json:"update_seq"`
+		PurgeSeq  json.RawMessage `json:"purge_seq"`
+		Sizes     struct {
+			File     int64 `json:"file"`
+			External int64 `json:"external"`
+			Active   int64 `json:"active"`
+		} `json:"sizes"`
+		// DiskSize and DataSize are reported by CouchDB 1.x, in place of
+		// Sizes.
+		DiskSize       int64 `json:"disk_size"`
+		DataSize       int64 `json:"data_size"`
+		UpdaterRunning bool  `json:"updater_running"`
+		CompactRunning bool  `json:"compact_running"`
+		WaitingClients int64 `json:"waiting_clients"`
+	} `json:"view_index"`
+	rawBody json.RawMessage
+}
+
+func (i *designDocInfo) UnmarshalJSON(p []byte) error {
+	type designDocInfoClone designDocInfo
+	c := designDocInfoClone(*i)
+	if err := json.Unmarshal(p, &c); err != nil {
+		return err
+	}
+	*i = designDocInfo(c)
+	i.rawBody = p
+	return nil
+}
+
+func (i *designDocInfo) driverInfo() *driver.DesignDocInfo {
+	v := i.ViewIndex
+	info := &driver.DesignDocInfo{
+		Name:           i.Name,
+		Signature:      v.Signature,
+		Language:       v.Language,
+		UpdateSeq:      string(bytes.Trim(v.UpdateSeq, `"`)),
+		PurgeSeq:       string(bytes.Trim(v.PurgeSeq, `"`)),
+		DiskSize:       v.DiskSize,
+		ActiveSize:     v.DataSize,
+		ExternalSize:   v.Sizes.External,
+		UpdaterRunning: v.UpdaterRunning,
+		CompactRunning: v.CompactRunning,
+		WaitingClients: v.WaitingClients,
+		RawResponse:    i.rawBody,
+	}
+	if v.Sizes.File > 0 {
+		info.DiskSize = v.Sizes.File
+	}
+	if v.Sizes.Active > 0 {
+		info.ActiveSize = v.Sizes.Active
+	}
+	return info
+}
+
+func (d *db) DesignDocInfo(ctx context.Context, ddoc string, options driver.Options) (*driver.DesignDocInfo, error) {
+	if ddoc == "" {
+		return nil, missingArg("ddoc")
+	}
+	opts := map[string]interface{}{}
+	options.Apply(opts)
+	query, err := optionsToParams(opts)
+	if err != nil {
+		return nil, err
+	}
+	var result designDocInfo
+	err = d.Client.DoJSON(ctx, http.MethodGet, d.path("_design/"+chttp.EncodeDocID(ddoc)+"/_info"), &chttp.Options{Query: query}, &result)
+	if err != nil {
+		return nil, err
+	}
+	return result.driverInfo(), nil
+}
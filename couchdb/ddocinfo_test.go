@@ -0,0 +1,127 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"gitlab.com/flimzy/testy"
+
+	"github.com/go-kivik/kivik/v4/driver"
+	internal "github.com/go-kivik/kivik/v4/int/errors"
+	"github.com/go-kivik/kivik/v4/int/mock"
+)
+
+func TestDesignDocInfo(t *testing.T) {
+	type tst struct {
+		db       *db
+		ddoc     string
+		expected *driver.DesignDocInfo
+		status   int
+		err      string
+	}
+	tests := testy.NewTable()
+	tests.Add("no ddoc", tst{
+		db:     newTestDB(nil, nil),
+		status: http.StatusBadRequest,
+		err:    "kivik: ddoc required",
+	})
+	tests.Add("network error", tst{
+		db:     newTestDB(nil, errors.New("net error")),
+		ddoc:   "foo",
+		status: http.StatusBadGateway,
+		err:    `Get "?http://example.com/testdb/_design/foo/_info"?: net error`,
+	})
+	tests.Add("not found", tst{
+		db: newTestDB(&http.Response{
+			StatusCode: http.StatusNotFound,
+			Header: http.Header{
+				"Content-Type": []string{"application/json"},
+			},
+			Body: io.NopCloser(strings.NewReader(`{"error":"not_found","reason":"missing"}`)),
+		}, nil),
+		ddoc:   "foo",
+		status: http.StatusNotFound,
+		err:    "Not Found",
+	})
+	tests.Add("CouchDB 3.x", func() interface{} {
+		body := `{"name":"foo","view_index":{"compact_running":false,"language":"javascript","purge_seq":0,"signature":"a59a1bb13fdf8a8a584bc477919c97ac","sizes":{"active":926691,"disk":1982704,"external":1535701,"file":1982704},"update_seq":19,"updater_running":true,"waiting_clients":2,"waiting_commit":false}}`
+		return tst{
+			db: newCustomDB(func(r *http.Request) (*http.Response, error) {
+				if r.URL.Path != "/testdb/_design/foo/_info" {
+					return nil, errors.New("unexpected path: " + r.URL.Path)
+				}
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Header: http.Header{
+						"Content-Type": []string{"application/json"},
+					},
+					Body: io.NopCloser(strings.NewReader(body)),
+				}, nil
+			}),
+			ddoc: "foo",
+			expected: &driver.DesignDocInfo{
+				Name:           "foo",
+				Signature:      "a59a1bb13fdf8a8a584bc477919c97ac",
+				Language:       "javascript",
+				UpdateSeq:      "19",
+				PurgeSeq:       "0",
+				DiskSize:       1982704,
+				ActiveSize:     926691,
+				ExternalSize:   1535701,
+				UpdaterRunning: true,
+				WaitingClients: 2,
+				RawResponse:    json.RawMessage(body),
+			},
+		}
+	})
+	tests.Add("CouchDB 1.x", func() interface{} {
+		body := `{"name":"foo","view_index":{"compact_running":false,"data_size":100,"disk_size":200,"language":"javascript","purge_seq":0,"signature":"abc","update_seq":"7-xyz","updater_running":false,"waiting_clients":0,"waiting_commit":false}}`
+		return tst{
+			db: newTestDB(&http.Response{
+				StatusCode: http.StatusOK,
+				Header: http.Header{
+					"Content-Type": []string{"application/json"},
+				},
+				Body: io.NopCloser(strings.NewReader(body)),
+			}, nil),
+			ddoc: "foo",
+			expected: &driver.DesignDocInfo{
+				Name:        "foo",
+				Signature:   "abc",
+				Language:    "javascript",
+				UpdateSeq:   "7-xyz",
+				PurgeSeq:    "0",
+				DiskSize:    200,
+				ActiveSize:  100,
+				RawResponse: json.RawMessage(body),
+			},
+		}
+	})
+
+	tests.Run(t, func(t *testing.T, tt tst) {
+		result, err := tt.db.DesignDocInfo(context.Background(), tt.ddoc, mock.NilOption)
+		if d := internal.StatusErrorDiffRE(tt.err, tt.status, err); d != "" {
+			t.Error(d)
+		}
+		if d := testy.DiffInterface(tt.expected, result); d != nil {
+			t.Error(d)
+		}
+	})
+}
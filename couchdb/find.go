@@ -30,19 +30,22 @@ func (d *db) CreateIndex(ctx context.Context, ddoc, name string, index interface
 	opts := map[string]interface{}{}
 	options.Apply(opts)
 	reqPath := partPath(pathIndex)
-	options.Apply(reqPath)
+	options.Apply(&reqPath.part)
 	indexObj, err := deJSONify(index)
 	if err != nil {
 		return err
 	}
+	indexType, _ := opts["type"].(string)
 	parameters := struct {
 		Index interface{} `json:"index"`
 		Ddoc  string      `json:"ddoc,omitempty"`
 		Name  string      `json:"name,omitempty"`
+		Type  string      `json:"type,omitempty"`
 	}{
 		Index: indexObj,
 		Ddoc:  ddoc,
 		Name:  name,
+		Type:  indexType,
 	}
 	chttpOpts := &chttp.Options{
 		Body: chttp.EncodeBody(parameters),
@@ -55,7 +58,7 @@ func (d *db) GetIndexes(ctx context.Context, options driver.Options) ([]driver.I
 	opts := map[string]interface{}{}
 	options.Apply(opts)
 	reqPath := partPath(pathIndex)
-	options.Apply(reqPath)
+	options.Apply(&reqPath.part)
 	var result struct {
 		Indexes []driver.Index `json:"indexes"`
 	}
@@ -73,7 +76,7 @@ func (d *db) DeleteIndex(ctx context.Context, ddoc, name string, options driver.
 		return missingArg("name")
 	}
 	reqPath := partPath(pathIndex)
-	options.Apply(reqPath)
+	options.Apply(&reqPath.part)
 	path := fmt.Sprintf("%s/%s/json/%s", reqPath, ddoc, name)
 	_, err := d.Client.DoError(ctx, http.MethodDelete, d.path(path), nil)
 	return err
@@ -83,7 +86,7 @@ func (d *db) Find(ctx context.Context, query interface{}, options driver.Options
 	opts := map[string]interface{}{}
 	options.Apply(opts)
 	reqPath := partPath("_find")
-	options.Apply(reqPath)
+	options.Apply(&reqPath.part)
 	chttpOpts := &chttp.Options{
 		GetBody: chttp.BodyEncoder(query),
 		Header: http.Header{
@@ -131,7 +134,7 @@ func (d *db) Explain(ctx context.Context, query interface{}, options driver.Opti
 	opts := map[string]interface{}{}
 	options.Apply(opts)
 	reqPath := partPath("_explain")
-	options.Apply(reqPath)
+	options.Apply(&reqPath.part)
 	chttpOpts := &chttp.Options{
 		GetBody: chttp.BodyEncoder(query),
 		Header: http.Header{
@@ -215,6 +215,28 @@ func TestCreateIndex(t *testing.T) {
 				Body: Body(`{"result":"created","id":"_design/a7ee061f1a2c0c6882258b2f1e148b714e79ccea","name":"a7ee061f1a2c0c6882258b2f1e148b714e79ccea"}`),
 			}, nil),
 		},
+		{
+			name: "text index",
+			db: newCustomDB(func(req *http.Request) (*http.Response, error) {
+				defer req.Body.Close() // nolint: errcheck
+				var result interface{}
+				if err := json.NewDecoder(req.Body).Decode(&result); err != nil {
+					return nil, fmt.Errorf("decode error: %s", err)
+				}
+				expected := map[string]interface{}{
+					"index": map[string]interface{}{"fields": []interface{}{map[string]interface{}{"title": "string"}}},
+					"type":  "text",
+				}
+				if d := testy.DiffInterface(expected, result); d != nil {
+					return nil, fmt.Errorf("unexpected result:\n%s", d)
+				}
+				return nil, errors.New("success")
+			}),
+			index:   `{"fields":[{"title":"string"}]}`,
+			options: kivik.Param("type", "text"),
+			status:  http.StatusBadGateway,
+			err:     `Post "?http://example.com/testdb/_index"?: success`,
+		},
 		{
 			name:    "partitioned query",
 			db:      newTestDB(nil, errors.New("expected")),
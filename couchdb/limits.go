@@ -0,0 +1,65 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package couchdb
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-kivik/kivik/v4/couchdb/chttp"
+	"github.com/go-kivik/kivik/v4/driver"
+)
+
+var (
+	_ driver.RevsLimiter        = &db{}
+	_ driver.PurgedInfosLimiter = &db{}
+)
+
+func (d *db) RevsLimit(ctx context.Context) (int, error) {
+	return d.getLimit(ctx, "/_revs_limit")
+}
+
+func (d *db) SetRevsLimit(ctx context.Context, limit int) error {
+	return d.setLimit(ctx, "/_revs_limit", limit)
+}
+
+func (d *db) PurgedInfosLimit(ctx context.Context) (int, error) {
+	return d.getLimit(ctx, "/_purged_infos_limit")
+}
+
+func (d *db) SetPurgedInfosLimit(ctx context.Context, limit int) error {
+	return d.setLimit(ctx, "/_purged_infos_limit", limit)
+}
+
+// getLimit reads the numeric value at the named database endpoint.
+func (d *db) getLimit(ctx context.Context, endpoint string) (int, error) {
+	var limit int
+	err := d.Client.DoJSON(ctx, http.MethodGet, d.path(endpoint), nil, &limit)
+	return limit, err
+}
+
+// setLimit puts limit to the named database endpoint.
+func (d *db) setLimit(ctx context.Context, endpoint string, limit int) error {
+	opts := &chttp.Options{
+		GetBody: chttp.BodyEncoder(limit),
+		Header: http.Header{
+			chttp.HeaderIdempotencyKey: []string{},
+		},
+	}
+	res, err := d.Client.DoReq(ctx, http.MethodPut, d.path(endpoint), opts)
+	if err != nil {
+		return err
+	}
+	defer chttp.CloseBody(res.Body)
+	return chttp.ResponseError(res)
+}
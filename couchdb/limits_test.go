@@ -0,0 +1,144 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package couchdb
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"gitlab.com/flimzy/testy"
+
+	internal "github.com/go-kivik/kivik/v4/int/errors"
+)
+
+func TestRevsLimit(t *testing.T) {
+	type tt struct {
+		db     *db
+		want   int
+		status int
+		err    string
+	}
+	tests := testy.NewTable()
+	tests.Add("network error", tt{
+		db:     newTestDB(nil, errors.New("net error")),
+		status: http.StatusBadGateway,
+		err:    `Get "?http://example.com/testdb/_revs_limit"?: net error`,
+	})
+	tests.Add("not found", tt{
+		db: newTestDB(&http.Response{
+			StatusCode: http.StatusNotFound,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(`{"error":"not_found","reason":"Database does not exist."}`)),
+		}, nil),
+		status: http.StatusNotFound,
+		err:    "Not Found",
+	})
+	tests.Add("success", tt{
+		db: newTestDB(&http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(strings.NewReader("1000\n")),
+		}, nil),
+		want: 1000,
+	})
+
+	tests.Run(t, func(t *testing.T, tt tt) {
+		got, err := tt.db.RevsLimit(context.Background())
+		if d := internal.StatusErrorDiffRE(tt.err, tt.status, err); d != "" {
+			t.Error(d)
+		}
+		if got != tt.want {
+			t.Errorf("Unexpected result: %d", got)
+		}
+	})
+}
+
+func TestSetRevsLimit(t *testing.T) {
+	type tt struct {
+		db     *db
+		status int
+		err    string
+	}
+	tests := testy.NewTable()
+	tests.Add("network error", tt{
+		db:     newTestDB(nil, errors.New("net error")),
+		status: http.StatusBadGateway,
+		err:    `Put "?http://example.com/testdb/_revs_limit"?: net error`,
+	})
+	tests.Add("success", func(t *testing.T) interface{} {
+		return tt{
+			db: newCustomDB(func(req *http.Request) (*http.Response, error) {
+				defer req.Body.Close() // nolint: errcheck
+				if req.Method != http.MethodPut {
+					t.Errorf("Unexpected method: %s", req.Method)
+				}
+				body, _ := io.ReadAll(req.Body)
+				if got := strings.TrimSpace(string(body)); got != "10" {
+					t.Errorf("Unexpected body: %s", got)
+				}
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Header:     http.Header{"Content-Type": []string{"application/json"}},
+					Body:       io.NopCloser(strings.NewReader(`{"ok":true}`)),
+				}, nil
+			}),
+		}
+	})
+
+	tests.Run(t, func(t *testing.T, tt tt) {
+		err := tt.db.SetRevsLimit(context.Background(), 10)
+		if d := internal.StatusErrorDiffRE(tt.err, tt.status, err); d != "" {
+			t.Error(d)
+		}
+	})
+}
+
+func TestPurgedInfosLimit(t *testing.T) {
+	db := newTestDB(&http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader("1000\n")),
+	}, nil)
+	got, err := db.PurgedInfosLimit(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != 1000 {
+		t.Errorf("Unexpected result: %d", got)
+	}
+}
+
+func TestSetPurgedInfosLimit(t *testing.T) {
+	db := newCustomDB(func(req *http.Request) (*http.Response, error) {
+		defer req.Body.Close() // nolint: errcheck
+		if req.URL.Path != "/testdb/_purged_infos_limit" {
+			t.Errorf("Unexpected path: %s", req.URL.Path)
+		}
+		body, _ := io.ReadAll(req.Body)
+		if got := strings.TrimSpace(string(body)); got != "5" {
+			t.Errorf("Unexpected body: %s", got)
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(`{"ok":true}`)),
+		}, nil
+	})
+	if err := db.SetPurgedInfosLimit(context.Background(), 5); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,72 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package couchdb
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/go-kivik/kivik/v4/couchdb/chttp"
+	"github.com/go-kivik/kivik/v4/driver"
+)
+
+var _ driver.NodeInspector = &client{}
+
+func nodeURL(node string, parts ...string) string {
+	components := append(make([]string, 0, len(parts)+2), "_node", url.PathEscape(node)) // nolint:gomnd
+	for _, part := range parts {
+		components = append(components, url.PathEscape(part))
+	}
+	return "/" + strings.Join(components, "/")
+}
+
+func (c *client) NodeStats(ctx context.Context, node string, path []string) (*driver.NodeStats, error) {
+	stats := new(driver.NodeStats)
+	err := c.DoJSON(ctx, http.MethodGet, nodeURL(node, append([]string{"_stats"}, path...)...), nil, stats)
+	return stats, err
+}
+
+func (c *client) NodeSystem(ctx context.Context, node string) (*driver.NodeSystem, error) {
+	sys := new(driver.NodeSystem)
+	err := c.DoJSON(ctx, http.MethodGet, nodeURL(node, "_system"), nil, sys)
+	return sys, err
+}
+
+func (c *client) NodeVersions(ctx context.Context, node string) (*driver.NodeVersions, error) {
+	versions := new(driver.NodeVersions)
+	err := c.DoJSON(ctx, http.MethodGet, nodeURL(node, "_versions"), nil, versions)
+	return versions, err
+}
+
+func (c *client) NodePrometheus(ctx context.Context, node string) (io.ReadCloser, error) {
+	opts := &chttp.Options{
+		Accept: "text/plain",
+	}
+	res, err := c.DoReq(ctx, http.MethodGet, nodeURL(node, "_prometheus"), opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := chttp.ResponseError(res); err != nil {
+		chttp.CloseBody(res.Body)
+		return nil, err
+	}
+	return res.Body, nil
+}
+
+func (c *client) RestartNode(ctx context.Context, node string) error {
+	_, err := c.DoError(ctx, http.MethodPost, nodeURL(node, "_restart"), nil)
+	return err
+}
@@ -0,0 +1,215 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package couchdb
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"gitlab.com/flimzy/testy"
+
+	"github.com/go-kivik/kivik/v4/driver"
+	internal "github.com/go-kivik/kivik/v4/int/errors"
+)
+
+func TestNodeStats(t *testing.T) {
+	type tt struct {
+		client *client
+		path   []string
+		want   *driver.NodeStats
+		status int
+		err    string
+	}
+	tests := testy.NewTable()
+	tests.Add("network error", tt{
+		client: newTestClient(nil, errors.New("net error")),
+		status: http.StatusBadGateway,
+		err:    `Get "?http://example.com/_node/_local/_stats"?: net error`,
+	})
+	tests.Add("all stats", func(t *testing.T) interface{} {
+		return tt{
+			client: newCustomClient(func(req *http.Request) (*http.Response, error) {
+				if req.URL.Path != "/_node/_local/_stats" {
+					t.Errorf("Unexpected path: %s", req.URL.Path)
+				}
+				return jsonResponse(http.StatusOK, `{"couchdb":{"open_databases":{"value":5,"type":"counter","desc":"number of open databases"}}}`), nil
+			}),
+			want: &driver.NodeStats{Children: map[string]*driver.NodeStats{
+				"couchdb": {Children: map[string]*driver.NodeStats{
+					"open_databases": {Stat: &driver.NodeStat{Type: "counter", Description: "number of open databases", Value: 5}},
+				}},
+			}},
+		}
+	})
+	tests.Add("single stat", func(t *testing.T) interface{} {
+		return tt{
+			client: newCustomClient(func(req *http.Request) (*http.Response, error) {
+				if req.URL.Path != "/_node/_local/_stats/couchdb/open_databases" {
+					t.Errorf("Unexpected path: %s", req.URL.Path)
+				}
+				return jsonResponse(http.StatusOK, `{"value":5,"type":"counter","desc":"number of open databases"}`), nil
+			}),
+			path: []string{"couchdb", "open_databases"},
+			want: &driver.NodeStats{Stat: &driver.NodeStat{Type: "counter", Description: "number of open databases", Value: 5}},
+		}
+	})
+
+	tests.Run(t, func(t *testing.T, tt tt) {
+		got, err := tt.client.NodeStats(context.Background(), "_local", tt.path)
+		if d := internal.StatusErrorDiffRE(tt.err, tt.status, err); d != "" {
+			t.Error(d)
+		}
+		if err != nil {
+			return
+		}
+		if d := testy.DiffInterface(tt.want, got); d != nil {
+			t.Error(d)
+		}
+	})
+}
+
+func TestNodeSystem(t *testing.T) {
+	client := newCustomClient(func(req *http.Request) (*http.Response, error) {
+		if req.URL.Path != "/_node/node1@127.0.0.1/_system" {
+			t.Errorf("Unexpected path: %s", req.URL.Path)
+		}
+		return jsonResponse(http.StatusOK, `{
+			"uptime": 259,
+			"memory": {"other": 10, "atom": 20, "atom_used": 15, "processes": 30, "processes_used": 25, "binary": 40, "code": 50, "ets": 60},
+			"run_queue": 1,
+			"ets_table_count": 157,
+			"process_count": 1000,
+			"process_limit": 262144,
+			"message_queues": {
+				"couch_server": 2,
+				"couch_file": {"count": 3, "min": 0, "max": 4, "50": 0, "90": 1, "99": 4}
+			},
+			"distribution": {"node2@127.0.0.1": {"recv_oct": 100, "send_pend": 0}}
+		}`), nil
+	})
+	got, err := client.NodeSystem(context.Background(), "node1@127.0.0.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &driver.NodeSystem{
+		Uptime:        259,
+		Memory:        driver.SystemMemory{Other: 10, Atom: 20, AtomUsed: 15, Processes: 30, ProcessesUsed: 25, Binary: 40, Code: 50, ETS: 60},
+		RunQueue:      1,
+		ETSTableCount: 157,
+		ProcessCount:  1000,
+		ProcessLimit:  262144,
+		MessageQueues: map[string]driver.MessageQueue{
+			"couch_server": {Count: 1, Min: 2, Max: 2, Median: 2, P90: 2, P99: 2},
+			"couch_file":   {Count: 3, Max: 4, P90: 1, P99: 4},
+		},
+		Distribution: map[string]map[string]int64{"node2@127.0.0.1": {"recv_oct": 100, "send_pend": 0}},
+	}
+	if d := testy.DiffInterface(want, got); d != nil {
+		t.Error(d)
+	}
+}
+
+func TestNodeVersions(t *testing.T) {
+	client := newTestClient(jsonResponse(http.StatusOK, `{
+		"javascript_engine": {"name": "spidermonkey", "version": "91"},
+		"erlang": {"version": "26.2.1", "supported_hashes": ["sha", "sha256"]},
+		"collation_driver": {"name": "libicu", "library_version": "70.1", "collator_version": "153.112", "collation_algorithm_version": "13"}
+	}`), nil)
+	got, err := client.NodeVersions(context.Background(), "_local")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &driver.NodeVersions{
+		JavaScriptEngine: &driver.JavaScriptEngine{Name: "spidermonkey", Version: "91"},
+		Erlang:           driver.ErlangVersion{Version: "26.2.1", SupportedHashes: []string{"sha", "sha256"}},
+		CollationDriver: &driver.CollationDriver{
+			Name:                      "libicu",
+			LibraryVersion:            "70.1",
+			CollatorVersion:           "153.112",
+			CollationAlgorithmVersion: "13",
+		},
+	}
+	if d := testy.DiffInterface(want, got); d != nil {
+		t.Error(d)
+	}
+}
+
+func TestNodePrometheus(t *testing.T) {
+	type tt struct {
+		client *client
+		want   string
+		status int
+		err    string
+	}
+	tests := testy.NewTable()
+	tests.Add("not found", tt{
+		client: newTestClient(&http.Response{
+			StatusCode: http.StatusNotFound,
+			Body:       io.NopCloser(strings.NewReader("")),
+		}, nil),
+		status: http.StatusNotFound,
+		err:    "Not Found",
+	})
+	tests.Add("success", func(t *testing.T) interface{} {
+		return tt{
+			client: newCustomClient(func(req *http.Request) (*http.Response, error) {
+				if req.URL.Path != "/_node/_local/_prometheus" {
+					t.Errorf("Unexpected path: %s", req.URL.Path)
+				}
+				if accept := req.Header.Get("Accept"); accept != "text/plain" {
+					t.Errorf("Unexpected Accept header: %s", accept)
+				}
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Header:     http.Header{"Content-Type": []string{"text/plain; version=2.0"}},
+					Body:       io.NopCloser(strings.NewReader("# TYPE couchdb_uptime_seconds counter\ncouchdb_uptime_seconds 259\n")),
+				}, nil
+			}),
+			want: "# TYPE couchdb_uptime_seconds counter\ncouchdb_uptime_seconds 259\n",
+		}
+	})
+
+	tests.Run(t, func(t *testing.T, tt tt) {
+		body, err := tt.client.NodePrometheus(context.Background(), "_local")
+		if d := internal.StatusErrorDiffRE(tt.err, tt.status, err); d != "" {
+			t.Error(d)
+		}
+		if err != nil {
+			return
+		}
+		defer body.Close() // nolint: errcheck
+		got, err := io.ReadAll(body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != tt.want {
+			t.Errorf("Unexpected result: %s", got)
+		}
+	})
+}
+
+func TestRestartNode(t *testing.T) {
+	client := newCustomClient(func(req *http.Request) (*http.Response, error) {
+		if req.Method != http.MethodPost || req.URL.Path != "/_node/_local/_restart" {
+			t.Errorf("Unexpected request: %s %s", req.Method, req.URL.Path)
+		}
+		return jsonResponse(http.StatusOK, `{"ok":true}`), nil
+	})
+	if err := client.RestartNode(context.Background(), "_local"); err != nil {
+		t.Fatal(err)
+	}
+}
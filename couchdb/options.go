@@ -20,6 +20,7 @@ import (
 
 	kivik "github.com/go-kivik/kivik/v4"
 	"github.com/go-kivik/kivik/v4/couchdb/chttp"
+	"github.com/go-kivik/kivik/v4/driver"
 )
 
 type optionHTTPClient struct {
@@ -66,9 +67,24 @@ func OptionIfNoneMatch(value string) kivik.Option {
 	return chttp.OptionIfNoneMatch(value)
 }
 
+// CacheConfig configures the client-side response cache enabled by
+// [OptionCache].
+type CacheConfig = chttp.CacheConfig
+
+// OptionCache enables an in-memory, ETag-aware cache of the responses to
+// [github.com/go-kivik/kivik/v4.DB.Get], [github.com/go-kivik/kivik/v4.DB.AllDocs]
+// and [github.com/go-kivik/kivik/v4.DB.Query]. Cached responses are
+// revalidated with an If-None-Match header on every request, and served from
+// the cache when CouchDB responds with 304 Not Modified. Writes to a document
+// made through the same client evict any cached copies of that document.
+// Only honored by [github.com/go-kivik/kivik/v4.New].
+func OptionCache(config CacheConfig) kivik.Option {
+	return chttp.OptionCache(config)
+}
+
 type partitionedPath struct {
 	path string
-	part string
+	part driver.Partition
 }
 
 func partPath(path string) *partitionedPath {
@@ -78,33 +94,25 @@ func partPath(path string) *partitionedPath {
 }
 
 func (pp partitionedPath) String() string {
-	if pp.part == "" {
+	if pp.part.Name == "" {
 		return pp.path
 	}
-	return path.Join("_partition", pp.part, pp.path)
-}
-
-type optionPartition string
-
-func (o optionPartition) Apply(target interface{}) {
-	if ppath, ok := target.(*partitionedPath); ok {
-		ppath.part = string(o)
-	}
-}
-
-func (o optionPartition) String() string {
-	return fmt.Sprintf("[partition:%s]", string(o))
+	return path.Join("_partition", pp.part.Name, pp.path)
 }
 
 // OptionPartition instructs supporting methods to limit the query to the
 // specified partition. Supported methods are: Query, AllDocs, Find, and
 // Explain. Only supported by CouchDB 3.0.0 and newer.
 //
+// OptionPartition is equivalent to
+// [github.com/go-kivik/kivik/v4.Partition], which is also understood by other
+// drivers.
+//
 // See the [CouchDB documentation].
 //
 // [CouchDB documentation]: https://docs.couchdb.org/en/stable/api/partitioned-dbs.html
 func OptionPartition(partition string) kivik.Option {
-	return optionPartition(partition)
+	return kivik.Partition(partition)
 }
 
 type optionNoMultipartPut struct{}
@@ -0,0 +1,181 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/go-kivik/kivik/v4/couchdb/chttp"
+	"github.com/go-kivik/kivik/v4/driver"
+	internal "github.com/go-kivik/kivik/v4/int/errors"
+)
+
+var (
+	_ driver.Sharder   = &db{}
+	_ driver.Resharder = &client{}
+)
+
+func (d *db) Shards(ctx context.Context) (map[string][]string, error) {
+	var result struct {
+		Shards map[string][]string `json:"shards"`
+	}
+	err := d.Client.DoJSON(ctx, http.MethodGet, d.path("/_shards"), nil, &result)
+	return result.Shards, err
+}
+
+func (d *db) DocShard(ctx context.Context, docID string) (*driver.DocShard, error) {
+	if docID == "" {
+		return nil, missingArg("docID")
+	}
+	result := new(driver.DocShard)
+	err := d.Client.DoJSON(ctx, http.MethodGet, d.path("/_shards/"+chttp.EncodeDocID(docID)), nil, result)
+	return result, err
+}
+
+func (d *db) SyncShards(ctx context.Context) error {
+	opts := &chttp.Options{
+		Header: http.Header{
+			chttp.HeaderIdempotencyKey: []string{},
+		},
+	}
+	res, err := d.Client.DoReq(ctx, http.MethodPost, d.path("/_sync_shards"), opts)
+	if err != nil {
+		return err
+	}
+	defer chttp.CloseBody(res.Body)
+	return chttp.ResponseError(res)
+}
+
+func (c *client) ReshardSummary(ctx context.Context) (*driver.ReshardSummary, error) {
+	result := new(driver.ReshardSummary)
+	err := c.DoJSON(ctx, http.MethodGet, "/_reshard", nil, result)
+	return result, err
+}
+
+func (c *client) ReshardState(ctx context.Context) (*driver.ReshardState, error) {
+	return c.reshardState(ctx, "/_reshard/state")
+}
+
+func (c *client) SetReshardState(ctx context.Context, state, reason string) error {
+	return c.setReshardState(ctx, "/_reshard/state", state, reason)
+}
+
+func (c *client) ReshardJobs(ctx context.Context) ([]*driver.ReshardJob, error) {
+	var result struct {
+		Jobs []*driver.ReshardJob `json:"jobs"`
+	}
+	err := c.DoJSON(ctx, http.MethodGet, "/_reshard/jobs", nil, &result)
+	return result.Jobs, err
+}
+
+func (c *client) ReshardJob(ctx context.Context, jobID string) (*driver.ReshardJob, error) {
+	if jobID == "" {
+		return nil, missingArg("jobID")
+	}
+	result := new(driver.ReshardJob)
+	err := c.DoJSON(ctx, http.MethodGet, "/_reshard/jobs/"+url.PathEscape(jobID), nil, result)
+	return result, err
+}
+
+type reshardJobResult struct {
+	ID    string `json:"id"`
+	Node  string `json:"node"`
+	Shard string `json:"shard"`
+	Error error
+}
+
+func (r *reshardJobResult) UnmarshalJSON(p []byte) error {
+	target := struct {
+		*reshardJobResult
+		Error         string `json:"error"`
+		Reason        string `json:"reason"`
+		UnmarshalJSON struct{}
+	}{
+		reshardJobResult: r,
+	}
+	if err := json.Unmarshal(p, &target); err != nil {
+		return err
+	}
+	switch target.Error {
+	case "":
+		// No error
+	case "conflict":
+		r.Error = &internal.Error{Status: http.StatusConflict, Err: errors.New(target.Reason)}
+	case "not_found":
+		r.Error = &internal.Error{Status: http.StatusNotFound, Err: errors.New(target.Reason)}
+	default:
+		r.Error = &internal.Error{Status: http.StatusInternalServerError, Err: errors.New(target.Reason)}
+	}
+	return nil
+}
+
+func (c *client) CreateReshardJobs(ctx context.Context, job *driver.ReshardJobRequest) ([]*driver.ReshardJobResult, error) {
+	opts := &chttp.Options{
+		GetBody: chttp.BodyEncoder(job),
+		Header: http.Header{
+			chttp.HeaderIdempotencyKey: []string{},
+		},
+	}
+	var results []*reshardJobResult
+	if err := c.DoJSON(ctx, http.MethodPost, "/_reshard/jobs", opts, &results); err != nil {
+		return nil, err
+	}
+	jobs := make([]*driver.ReshardJobResult, len(results))
+	for i, r := range results {
+		jobs[i] = (*driver.ReshardJobResult)(r)
+	}
+	return jobs, nil
+}
+
+func (c *client) DeleteReshardJob(ctx context.Context, jobID string) error {
+	if jobID == "" {
+		return missingArg("jobID")
+	}
+	_, err := c.DoError(ctx, http.MethodDelete, "/_reshard/jobs/"+url.PathEscape(jobID), nil)
+	return err
+}
+
+func (c *client) ReshardJobState(ctx context.Context, jobID string) (*driver.ReshardState, error) {
+	if jobID == "" {
+		return nil, missingArg("jobID")
+	}
+	return c.reshardState(ctx, "/_reshard/jobs/"+url.PathEscape(jobID)+"/state")
+}
+
+func (c *client) SetReshardJobState(ctx context.Context, jobID, state, reason string) error {
+	if jobID == "" {
+		return missingArg("jobID")
+	}
+	return c.setReshardState(ctx, "/_reshard/jobs/"+url.PathEscape(jobID)+"/state", state, reason)
+}
+
+func (c *client) reshardState(ctx context.Context, path string) (*driver.ReshardState, error) {
+	result := new(driver.ReshardState)
+	err := c.DoJSON(ctx, http.MethodGet, path, nil, result)
+	return result, err
+}
+
+func (c *client) setReshardState(ctx context.Context, path, state, reason string) error {
+	opts := &chttp.Options{
+		GetBody: chttp.BodyEncoder(&driver.ReshardState{State: state, Reason: reason}),
+		Header: http.Header{
+			chttp.HeaderIdempotencyKey: []string{},
+		},
+	}
+	_, err := c.DoError(ctx, http.MethodPut, path, opts)
+	return err
+}
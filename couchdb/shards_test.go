@@ -0,0 +1,310 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package couchdb
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"gitlab.com/flimzy/testy"
+
+	"github.com/go-kivik/kivik/v4/driver"
+	internal "github.com/go-kivik/kivik/v4/int/errors"
+)
+
+func jsonResponse(status int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+}
+
+func TestShards(t *testing.T) {
+	type tt struct {
+		db     *db
+		want   map[string][]string
+		status int
+		err    string
+	}
+	tests := testy.NewTable()
+	tests.Add("network error", tt{
+		db:     newTestDB(nil, errors.New("net error")),
+		status: http.StatusBadGateway,
+		err:    `Get "?http://example.com/testdb/_shards"?: net error`,
+	})
+	tests.Add("success", tt{
+		db: newTestDB(jsonResponse(http.StatusOK, `{"shards":{"00000000-7fffffff":["node1@127.0.0.1"],"80000000-ffffffff":["node2@127.0.0.1"]}}`), nil),
+		want: map[string][]string{
+			"00000000-7fffffff": {"node1@127.0.0.1"},
+			"80000000-ffffffff": {"node2@127.0.0.1"},
+		},
+	})
+
+	tests.Run(t, func(t *testing.T, tt tt) {
+		got, err := tt.db.Shards(context.Background())
+		if d := internal.StatusErrorDiffRE(tt.err, tt.status, err); d != "" {
+			t.Error(d)
+		}
+		if d := testy.DiffInterface(tt.want, got); d != nil {
+			t.Error(d)
+		}
+	})
+}
+
+func TestDocShard(t *testing.T) {
+	type tt struct {
+		db     *db
+		docID  string
+		want   *driver.DocShard
+		status int
+		err    string
+	}
+	tests := testy.NewTable()
+	tests.Add("missing doc ID", tt{
+		db:     newTestDB(nil, nil),
+		status: http.StatusBadRequest,
+		err:    "kivik: docID required",
+	})
+	tests.Add("success", func(t *testing.T) interface{} {
+		return tt{
+			db: newCustomDB(func(req *http.Request) (*http.Response, error) {
+				if req.URL.RawPath != "/testdb/_shards/foo%2Fbar" {
+					t.Errorf("Unexpected path: %s", req.URL.RawPath)
+				}
+				return jsonResponse(http.StatusOK, `{"range":"e0000000-ffffffff","nodes":["node1@127.0.0.1"]}`), nil
+			}),
+			docID: "foo/bar",
+			want:  &driver.DocShard{Range: "e0000000-ffffffff", Nodes: []string{"node1@127.0.0.1"}},
+		}
+	})
+
+	tests.Run(t, func(t *testing.T, tt tt) {
+		got, err := tt.db.DocShard(context.Background(), tt.docID)
+		if d := internal.StatusErrorDiffRE(tt.err, tt.status, err); d != "" {
+			t.Error(d)
+		}
+		if err != nil {
+			return
+		}
+		if d := testy.DiffInterface(tt.want, got); d != nil {
+			t.Error(d)
+		}
+	})
+}
+
+func TestSyncShards(t *testing.T) {
+	db := newCustomDB(func(req *http.Request) (*http.Response, error) {
+		if req.Method != http.MethodPost || req.URL.Path != "/testdb/_sync_shards" {
+			t.Errorf("Unexpected request: %s %s", req.Method, req.URL.Path)
+		}
+		return jsonResponse(http.StatusAccepted, `{"ok":true}`), nil
+	})
+	if err := db.SyncShards(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReshardSummary(t *testing.T) {
+	client := newTestClient(jsonResponse(http.StatusOK, `{"completed":21,"failed":0,"running":3,"state":"running","state_reason":null,"stopped":0,"total":24}`), nil)
+	got, err := client.ReshardSummary(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &driver.ReshardSummary{State: "running", Completed: 21, Running: 3, Total: 24}
+	if d := testy.DiffInterface(want, got); d != nil {
+		t.Error(d)
+	}
+}
+
+func TestSetReshardState(t *testing.T) {
+	client := newCustomClient(func(req *http.Request) (*http.Response, error) {
+		defer req.Body.Close() // nolint: errcheck
+		if req.Method != http.MethodPut || req.URL.Path != "/_reshard/state" {
+			t.Errorf("Unexpected request: %s %s", req.Method, req.URL.Path)
+		}
+		body, _ := io.ReadAll(req.Body)
+		if got := strings.TrimSpace(string(body)); got != `{"state":"stopped","reason":"maintenance"}` {
+			t.Errorf("Unexpected body: %s", got)
+		}
+		return jsonResponse(http.StatusOK, `{"ok":true}`), nil
+	})
+	if err := client.SetReshardState(context.Background(), "stopped", "maintenance"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReshardJobs(t *testing.T) {
+	client := newTestClient(jsonResponse(http.StatusOK, `{
+		"jobs": [
+			{
+				"history": [
+					{"detail": null, "timestamp": "2019-03-28T15:28:02Z", "type": "new"},
+					{"detail": "initial_copy", "timestamp": "2019-03-28T15:28:02Z", "type": "running"}
+				],
+				"id": "001-171d1211418996ff47bd610b1d1257fc4ca2628868def4a05e63e8f8fe50694a",
+				"job_state": "completed",
+				"node": "node1@127.0.0.1",
+				"source": "shards/00000000-1fffffff/d1.1553786862",
+				"split_state": "completed",
+				"start_time": "2019-03-28T15:28:02Z",
+				"state_info": {},
+				"target": [
+					"shards/00000000-0fffffff/d1.1553786862",
+					"shards/10000000-1fffffff/d1.1553786862"
+				],
+				"type": "split",
+				"update_time": "2019-03-28T15:28:08Z"
+			}
+		],
+		"offset": 0,
+		"total_rows": 1
+	}`), nil)
+	got, err := client.ReshardJobs(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2019, 3, 28, 15, 28, 2, 0, time.UTC)
+	want := []*driver.ReshardJob{
+		{
+			ID:         "001-171d1211418996ff47bd610b1d1257fc4ca2628868def4a05e63e8f8fe50694a",
+			Type:       "split",
+			Node:       "node1@127.0.0.1",
+			Source:     "shards/00000000-1fffffff/d1.1553786862",
+			Targets:    []string{"shards/00000000-0fffffff/d1.1553786862", "shards/10000000-1fffffff/d1.1553786862"},
+			JobState:   "completed",
+			SplitState: "completed",
+			StateInfo:  map[string]interface{}{},
+			StartTime:  start,
+			UpdateTime: time.Date(2019, 3, 28, 15, 28, 8, 0, time.UTC),
+			History: []driver.ReshardEvent{
+				{Timestamp: start, Type: "new"},
+				{Timestamp: start, Type: "running", Detail: "initial_copy"},
+			},
+		},
+	}
+	if d := testy.DiffInterface(want, got); d != nil {
+		t.Error(d)
+	}
+}
+
+func TestReshardJob(t *testing.T) {
+	type tt struct {
+		client *client
+		jobID  string
+		status int
+		err    string
+	}
+	tests := testy.NewTable()
+	tests.Add("missing job ID", tt{
+		client: newTestClient(nil, nil),
+		status: http.StatusBadRequest,
+		err:    "kivik: jobID required",
+	})
+	tests.Add("not found", tt{
+		client: newTestClient(jsonResponse(http.StatusNotFound, `{"error":"not_found","reason":"Job not found"}`), nil),
+		jobID:  "001-abc",
+		status: http.StatusNotFound,
+		err:    "Not Found",
+	})
+	tests.Add("success", tt{
+		client: newTestClient(jsonResponse(http.StatusOK, `{"id":"001-abc","type":"split","job_state":"running"}`), nil),
+		jobID:  "001-abc",
+	})
+
+	tests.Run(t, func(t *testing.T, tt tt) {
+		got, err := tt.client.ReshardJob(context.Background(), tt.jobID)
+		if d := internal.StatusErrorDiffRE(tt.err, tt.status, err); d != "" {
+			t.Error(d)
+		}
+		if err == nil && got.ID != tt.jobID {
+			t.Errorf("Unexpected job ID: %s", got.ID)
+		}
+	})
+}
+
+func TestCreateReshardJobs(t *testing.T) {
+	client := newCustomClient(func(req *http.Request) (*http.Response, error) {
+		defer req.Body.Close() // nolint: errcheck
+		if req.Method != http.MethodPost || req.URL.Path != "/_reshard/jobs" {
+			t.Errorf("Unexpected request: %s %s", req.Method, req.URL.Path)
+		}
+		body, _ := io.ReadAll(req.Body)
+		if got := strings.TrimSpace(string(body)); got != `{"type":"split","db":"db3","range":"00000000-7fffffff"}` {
+			t.Errorf("Unexpected body: %s", got)
+		}
+		return jsonResponse(http.StatusCreated, `[
+			{"id":"001-30d7848a6feeb826d5e3ea5bb7773d672af226fd34fd84a8fb1ca736285df557","node":"node1@127.0.0.1","ok":true,"shard":"shards/00000000-7fffffff/db3.1554148353"},
+			{"error":"conflict","node":"node2@127.0.0.1","reason":"Job already exists","shard":"shards/00000000-7fffffff/db3.1554148353"}
+		]`), nil
+	})
+	got, err := client.CreateReshardJobs(context.Background(), &driver.ReshardJobRequest{
+		Type:  "split",
+		DB:    "db3",
+		Range: "00000000-7fffffff",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 { // nolint:gomnd
+		t.Fatalf("Expected 2 results, got %d", len(got))
+	}
+	if got[0].ID == "" || got[0].Error != nil {
+		t.Errorf("Unexpected first result: %+v", got[0])
+	}
+	if d := internal.StatusErrorDiff("Job already exists", http.StatusConflict, got[1].Error); d != "" {
+		t.Error(d)
+	}
+	if got[1].Node != "node2@127.0.0.1" {
+		t.Errorf("Unexpected node: %s", got[1].Node)
+	}
+}
+
+func TestDeleteReshardJob(t *testing.T) {
+	client := newCustomClient(func(req *http.Request) (*http.Response, error) {
+		if req.Method != http.MethodDelete || req.URL.Path != "/_reshard/jobs/001-abc" {
+			t.Errorf("Unexpected request: %s %s", req.Method, req.URL.Path)
+		}
+		return jsonResponse(http.StatusOK, `{"ok":true}`), nil
+	})
+	if err := client.DeleteReshardJob(context.Background(), "001-abc"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReshardJobState(t *testing.T) {
+	client := newCustomClient(func(req *http.Request) (*http.Response, error) {
+		if req.URL.Path != "/_reshard/jobs/001-abc/state" {
+			t.Errorf("Unexpected path: %s", req.URL.Path)
+		}
+		if req.Method == http.MethodPut {
+			return jsonResponse(http.StatusOK, `{"ok":true}`), nil
+		}
+		return jsonResponse(http.StatusOK, `{"state":"stopped","reason":"Pause this job for now"}`), nil
+	})
+	got, err := client.ReshardJobState(context.Background(), "001-abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := testy.DiffInterface(&driver.ReshardState{State: "stopped", Reason: "Pause this job for now"}, got); d != nil {
+		t.Error(d)
+	}
+	if err := client.SetReshardJobState(context.Background(), "001-abc", "running", ""); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,35 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package couchdb
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-kivik/kivik/v4/couchdb/chttp"
+	"github.com/go-kivik/kivik/v4/driver"
+)
+
+var _ driver.ActiveTasker = &client{}
+
+func (c *client) ActiveTasks(ctx context.Context, options driver.Options) ([]*driver.ActiveTask, error) {
+	opts := map[string]interface{}{}
+	options.Apply(opts)
+	query, err := optionsToParams(opts)
+	if err != nil {
+		return nil, err
+	}
+	var tasks []*driver.ActiveTask
+	err = c.DoJSON(ctx, http.MethodGet, "/_active_tasks", &chttp.Options{Query: query}, &tasks)
+	return tasks, err
+}
@@ -0,0 +1,114 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package couchdb
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"gitlab.com/flimzy/testy"
+
+	"github.com/go-kivik/kivik/v4/driver"
+	internal "github.com/go-kivik/kivik/v4/int/errors"
+	"github.com/go-kivik/kivik/v4/int/mock"
+)
+
+func TestActiveTasks(t *testing.T) {
+	type tst struct {
+		client   *client
+		expected []*driver.ActiveTask
+		status   int
+		err      string
+	}
+	tests := testy.NewTable()
+	tests.Add("network error", tst{
+		client: newTestClient(nil, errors.New("network error")),
+		status: http.StatusBadGateway,
+		err:    `Get "?http://example.com/_active_tasks"?: network error`,
+	})
+	tests.Add("unauthorized", tst{
+		client: newTestClient(&http.Response{
+			StatusCode: http.StatusUnauthorized,
+			Header: http.Header{
+				"Content-Type": []string{"application/json"},
+			},
+			Body: io.NopCloser(strings.NewReader(`{"error":"unauthorized","reason":"You are not a server admin."}`)),
+		}, nil),
+		status: http.StatusUnauthorized,
+		err:    "Unauthorized",
+	})
+	tests.Add("no tasks", tst{
+		client: newTestClient(&http.Response{
+			StatusCode: http.StatusOK,
+			Header: http.Header{
+				"Content-Type": []string{"application/json"},
+			},
+			Body: io.NopCloser(strings.NewReader(`[]`)),
+		}, nil),
+		expected: []*driver.ActiveTask{},
+	})
+	tests.Add("mixed tasks", tst{
+		client: newTestClient(&http.Response{
+			StatusCode: http.StatusOK,
+			Header: http.Header{
+				"Content-Type": []string{"application/json"},
+			},
+			Body: io.NopCloser(strings.NewReader(`[
+				{"node":"node1@127.0.0.1","pid":"<0.1.0>","type":"indexer","database":"shards/00000000-1fffffff/foo.1","design_document":"_design/bar","progress":50,"changes_done":5,"total_changes":10,"started_on":1700000000,"updated_on":1700000010},
+				{"node":"node1@127.0.0.1","pid":"<0.2.0>","type":"replication","replication_id":"abc+continuous","doc_id":null,"source":"http://example.com/src/","target":"http://example.com/tgt/","user":null,"continuous":true,"docs_read":3,"docs_written":2,"doc_write_failures":1,"missing_revisions_found":3,"revisions_checked":4,"changes_pending":null,"checkpointed_source_seq":"10-abc"}
+			]`)),
+		}, nil),
+		expected: []*driver.ActiveTask{
+			{
+				Type:           "indexer",
+				Node:           "node1@127.0.0.1",
+				PID:            "<0.1.0>",
+				Database:       "shards/00000000-1fffffff/foo.1",
+				DesignDocument: "_design/bar",
+				Progress:       50,
+				ChangesDone:    5,
+				TotalChanges:   10,
+				StartedOn:      1700000000,
+				UpdatedOn:      1700000010,
+			},
+			{
+				Type:                  "replication",
+				Node:                  "node1@127.0.0.1",
+				PID:                   "<0.2.0>",
+				ReplicationID:         "abc+continuous",
+				Source:                "http://example.com/src/",
+				Target:                "http://example.com/tgt/",
+				Continuous:            true,
+				DocsRead:              3,
+				DocsWritten:           2,
+				DocWriteFailures:      1,
+				MissingRevisionsFound: 3,
+				RevisionsChecked:      4,
+			},
+		},
+	})
+
+	tests.Run(t, func(t *testing.T, tt tst) {
+		result, err := tt.client.ActiveTasks(context.Background(), mock.NilOption)
+		if d := internal.StatusErrorDiffRE(tt.err, tt.status, err); d != "" {
+			t.Error(d)
+		}
+		if d := testy.DiffInterface(tt.expected, result); d != nil {
+			t.Error(d)
+		}
+	})
+}
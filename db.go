@@ -16,6 +16,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"strings"
@@ -461,6 +462,8 @@ type DBStats struct {
 	ExternalSize int64 `json:"-"`
 	// Cluster reports the cluster replication configuration variables.
 	Cluster *ClusterConfig `json:"cluster,omitempty"`
+	// Partitioned is true if the database was created with [Partitioned].
+	Partitioned bool `json:"-"`
 	// RawResponse is the raw response body returned by the server, useful if
 	// you need additional backend-specific information.
 	//
@@ -513,10 +516,71 @@ func driverStats2kivikStats(i *driver.DBStats) *DBStats {
 		ActiveSize:     i.ActiveSize,
 		ExternalSize:   i.ExternalSize,
 		Cluster:        cluster,
+		Partitioned:    i.Partitioned,
 		RawResponse:    i.RawResponse,
 	}
 }
 
+// DesignDocInfo contains information about a design document's view index,
+// as returned by [DB.DesignDocInfo].
+type DesignDocInfo struct {
+	// Name is the name of the design document, without the _design/ prefix.
+	Name string `json:"name"`
+	// Signature is the MD5 signature of the views in the design document. It
+	// changes whenever the view definitions change.
+	Signature string `json:"signature"`
+	// Language is the language of the view functions.
+	Language string `json:"language"`
+	// UpdateSeq is the database sequence up to which the index is built.
+	UpdateSeq string `json:"update_seq"`
+	// PurgeSeq is the purge sequence processed by the index.
+	PurgeSeq string `json:"purge_seq"`
+	// DiskSize is the number of bytes used on-disk by the index.
+	DiskSize int64 `json:"disk_size"`
+	// ActiveSize is the number of bytes of live data in the index.
+	ActiveSize int64 `json:"data_size"`
+	// ExternalSize is the uncompressed size of the index contents.
+	ExternalSize int64 `json:"-"`
+	// UpdaterRunning is true if the index is currently being built.
+	UpdaterRunning bool `json:"updater_running"`
+	// CompactRunning is true if the index is currently being compacted.
+	CompactRunning bool `json:"compact_running"`
+	// WaitingClients is the number of clients waiting on the index.
+	WaitingClients int64 `json:"waiting_clients"`
+	// RawResponse is the raw response body returned by the server, useful if
+	// you need additional backend-specific information.
+	RawResponse json.RawMessage `json:"-"`
+}
+
+// DesignDocInfo returns information about the view index of the named design
+// document. ddoc may be given with or without the _design/ prefix. See the
+// [CouchDB documentation].
+//
+// [CouchDB documentation]: https://docs.couchdb.org/en/stable/api/ddoc/common.html#get--db-_design-ddoc-_info
+func (db *DB) DesignDocInfo(ctx context.Context, ddoc string, options ...Option) (*DesignDocInfo, error) {
+	if db.err != nil {
+		return nil, db.err
+	}
+	ddoc = strings.TrimPrefix(ddoc, "_design/")
+	if ddoc == "" {
+		return nil, missingArg("ddoc")
+	}
+	infoer, ok := db.driverDB.(driver.DesignDocInfoer)
+	if !ok {
+		return nil, errDesignDocInfoNotImplemented
+	}
+	endQuery, err := db.startQuery()
+	if err != nil {
+		return nil, err
+	}
+	defer endQuery()
+	info, err := infoer.DesignDocInfo(ctx, ddoc, multiOptions(options))
+	if err != nil {
+		return nil, err
+	}
+	return (*DesignDocInfo)(info), nil
+}
+
 // Compact begins compaction of the database. Check the CompactRunning field
 // returned by [DB.Stats] to see if the compaction has completed.
 //
@@ -641,6 +705,103 @@ func (db *DB) SetSecurity(ctx context.Context, security *Security) error {
 	return secDB.SetSecurity(ctx, sec)
 }
 
+// RevsLimit returns the maximum number of revisions tracked for each document.
+//
+// See the [CouchDB documentation].
+//
+// [CouchDB documentation]: https://docs.couchdb.org/en/stable/api/database/misc.html#get--db-_revs_limit
+func (db *DB) RevsLimit(ctx context.Context) (int, error) {
+	if db.err != nil {
+		return 0, db.err
+	}
+	limiter, ok := db.driverDB.(driver.RevsLimiter)
+	if !ok {
+		return 0, errRevsLimitNotImplemented
+	}
+	endQuery, err := db.startQuery()
+	if err != nil {
+		return 0, err
+	}
+	defer endQuery()
+	return limiter.RevsLimit(ctx)
+}
+
+// SetRevsLimit sets the maximum number of revisions tracked for each
+// document. Older revisions are forgotten, though not necessarily at once.
+//
+// See the [CouchDB documentation].
+//
+// [CouchDB documentation]: https://docs.couchdb.org/en/stable/api/database/misc.html#put--db-_revs_limit
+func (db *DB) SetRevsLimit(ctx context.Context, limit int) error {
+	if db.err != nil {
+		return db.err
+	}
+	limiter, ok := db.driverDB.(driver.RevsLimiter)
+	if !ok {
+		return errRevsLimitNotImplemented
+	}
+	if limit < 1 {
+		return invalidLimit(limit)
+	}
+	endQuery, err := db.startQuery()
+	if err != nil {
+		return err
+	}
+	defer endQuery()
+	return limiter.SetRevsLimit(ctx, limit)
+}
+
+// PurgedInfosLimit returns the maximum number of purge requests retained by
+// the database.
+//
+// See the [CouchDB documentation].
+//
+// [CouchDB documentation]: https://docs.couchdb.org/en/stable/api/database/misc.html#get--db-_purged_infos_limit
+func (db *DB) PurgedInfosLimit(ctx context.Context) (int, error) {
+	if db.err != nil {
+		return 0, db.err
+	}
+	limiter, ok := db.driverDB.(driver.PurgedInfosLimiter)
+	if !ok {
+		return 0, errPurgedInfosLimitNotImplemented
+	}
+	endQuery, err := db.startQuery()
+	if err != nil {
+		return 0, err
+	}
+	defer endQuery()
+	return limiter.PurgedInfosLimit(ctx)
+}
+
+// SetPurgedInfosLimit sets the maximum number of purge requests retained by
+// the database.
+//
+// See the [CouchDB documentation].
+//
+// [CouchDB documentation]: https://docs.couchdb.org/en/stable/api/database/misc.html#put--db-_purged_infos_limit
+func (db *DB) SetPurgedInfosLimit(ctx context.Context, limit int) error {
+	if db.err != nil {
+		return db.err
+	}
+	limiter, ok := db.driverDB.(driver.PurgedInfosLimiter)
+	if !ok {
+		return errPurgedInfosLimitNotImplemented
+	}
+	if limit < 1 {
+		return invalidLimit(limit)
+	}
+	endQuery, err := db.startQuery()
+	if err != nil {
+		return err
+	}
+	defer endQuery()
+	return limiter.SetPurgedInfosLimit(ctx, limit)
+}
+
+func invalidLimit(limit int) error {
+	return &internal.Error{Status: http.StatusBadRequest, Message: fmt.Sprintf("kivik: limit must be positive, got %d", limit)}
+}
+
 // Copy copies the source document to a new document with an ID of targetID. If
 // the database backend does not support COPY directly, the operation will be
 // emulated with a Get followed by Put. The target will be an exact copy of the
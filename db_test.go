@@ -935,6 +935,266 @@ func TestStats(t *testing.T) {
 	}
 }
 
+func TestDesignDocInfo(t *testing.T) {
+	type tt struct {
+		db     *DB
+		ddoc   string
+		want   *DesignDocInfo
+		status int
+		err    string
+	}
+	tests := testy.NewTable()
+	tests.Add("db error", tt{
+		db:     &DB{err: errors.New("db error")},
+		ddoc:   "foo",
+		status: http.StatusInternalServerError,
+		err:    "db error",
+	})
+	tests.Add("missing ddoc", tt{
+		db:     &DB{client: &Client{}, driverDB: &mock.DesignDocInfoer{}},
+		ddoc:   "_design/",
+		status: http.StatusBadRequest,
+		err:    "kivik: ddoc required",
+	})
+	tests.Add("not supported", tt{
+		db:     &DB{client: &Client{}, driverDB: &mock.DB{}},
+		ddoc:   "foo",
+		status: http.StatusNotImplemented,
+		err:    "kivik: driver does not support DesignDocInfo interface",
+	})
+	tests.Add("driver error", tt{
+		db: &DB{
+			client: &Client{},
+			driverDB: &mock.DesignDocInfoer{
+				DesignDocInfoFunc: func(context.Context, string, driver.Options) (*driver.DesignDocInfo, error) {
+					return nil, &internal.Error{Status: http.StatusNotFound, Message: "missing"}
+				},
+			},
+		},
+		ddoc:   "foo",
+		status: http.StatusNotFound,
+		err:    "missing",
+	})
+	tests.Add("success", tt{
+		db: &DB{
+			client: &Client{},
+			driverDB: &mock.DesignDocInfoer{
+				DesignDocInfoFunc: func(_ context.Context, ddoc string, _ driver.Options) (*driver.DesignDocInfo, error) {
+					if ddoc != "foo" {
+						return nil, fmt.Errorf("Unexpected ddoc: %s", ddoc)
+					}
+					return &driver.DesignDocInfo{
+						Name:           "foo",
+						Signature:      "abc",
+						UpdateSeq:      "5",
+						ActiveSize:     100,
+						UpdaterRunning: true,
+					}, nil
+				},
+			},
+		},
+		ddoc: "_design/foo",
+		want: &DesignDocInfo{
+			Name:           "foo",
+			Signature:      "abc",
+			UpdateSeq:      "5",
+			ActiveSize:     100,
+			UpdaterRunning: true,
+		},
+	})
+
+	tests.Run(t, func(t *testing.T, tt tt) {
+		got, err := tt.db.DesignDocInfo(context.Background(), tt.ddoc)
+		if d := internal.StatusErrorDiff(tt.err, tt.status, err); d != "" {
+			t.Error(d)
+		}
+		if d := testy.DiffInterface(tt.want, got); d != nil {
+			t.Error(d)
+		}
+	})
+}
+
+func TestRevsLimit(t *testing.T) {
+	type tt struct {
+		db     *DB
+		want   int
+		status int
+		err    string
+	}
+	tests := testy.NewTable()
+	tests.Add("db error", tt{
+		db:     &DB{err: errors.New("db error")},
+		status: http.StatusInternalServerError,
+		err:    "db error",
+	})
+	tests.Add("not supported", tt{
+		db:     &DB{client: &Client{}, driverDB: &mock.DB{}},
+		status: http.StatusNotImplemented,
+		err:    "kivik: driver does not support RevsLimit interface",
+	})
+	tests.Add("driver error", tt{
+		db: &DB{
+			client: &Client{},
+			driverDB: &mock.RevsLimiter{
+				RevsLimitFunc: func(context.Context) (int, error) {
+					return 0, &internal.Error{Status: http.StatusNotFound, Message: "missing"}
+				},
+			},
+		},
+		status: http.StatusNotFound,
+		err:    "missing",
+	})
+	tests.Add("success", tt{
+		db: &DB{
+			client: &Client{},
+			driverDB: &mock.RevsLimiter{
+				RevsLimitFunc: func(context.Context) (int, error) {
+					return 1000, nil
+				},
+			},
+		},
+		want: 1000,
+	})
+
+	tests.Run(t, func(t *testing.T, tt tt) {
+		got, err := tt.db.RevsLimit(context.Background())
+		if d := internal.StatusErrorDiff(tt.err, tt.status, err); d != "" {
+			t.Error(d)
+		}
+		if got != tt.want {
+			t.Errorf("Unexpected result: %d", got)
+		}
+	})
+}
+
+func TestSetRevsLimit(t *testing.T) {
+	type tt struct {
+		db     *DB
+		limit  int
+		status int
+		err    string
+	}
+	tests := testy.NewTable()
+	tests.Add("db error", tt{
+		db:     &DB{err: errors.New("db error")},
+		limit:  10,
+		status: http.StatusInternalServerError,
+		err:    "db error",
+	})
+	tests.Add("not supported", tt{
+		db:     &DB{client: &Client{}, driverDB: &mock.DB{}},
+		limit:  10,
+		status: http.StatusNotImplemented,
+		err:    "kivik: driver does not support RevsLimit interface",
+	})
+	tests.Add("invalid limit", tt{
+		db:     &DB{client: &Client{}, driverDB: &mock.RevsLimiter{}},
+		limit:  0,
+		status: http.StatusBadRequest,
+		err:    "kivik: limit must be positive, got 0",
+	})
+	tests.Add("success", tt{
+		db: &DB{
+			client: &Client{},
+			driverDB: &mock.RevsLimiter{
+				SetRevsLimitFunc: func(_ context.Context, limit int) error {
+					if limit != 10 {
+						return fmt.Errorf("Unexpected limit: %d", limit)
+					}
+					return nil
+				},
+			},
+		},
+		limit: 10,
+	})
+
+	tests.Run(t, func(t *testing.T, tt tt) {
+		err := tt.db.SetRevsLimit(context.Background(), tt.limit)
+		if d := internal.StatusErrorDiff(tt.err, tt.status, err); d != "" {
+			t.Error(d)
+		}
+	})
+}
+
+func TestPurgedInfosLimit(t *testing.T) {
+	type tt struct {
+		db     *DB
+		want   int
+		status int
+		err    string
+	}
+	tests := testy.NewTable()
+	tests.Add("not supported", tt{
+		db:     &DB{client: &Client{}, driverDB: &mock.DB{}},
+		status: http.StatusNotImplemented,
+		err:    "kivik: driver does not support PurgedInfosLimit interface",
+	})
+	tests.Add("success", tt{
+		db: &DB{
+			client: &Client{},
+			driverDB: &mock.PurgedInfosLimiter{
+				PurgedInfosLimitFunc: func(context.Context) (int, error) {
+					return 1000, nil
+				},
+			},
+		},
+		want: 1000,
+	})
+
+	tests.Run(t, func(t *testing.T, tt tt) {
+		got, err := tt.db.PurgedInfosLimit(context.Background())
+		if d := internal.StatusErrorDiff(tt.err, tt.status, err); d != "" {
+			t.Error(d)
+		}
+		if got != tt.want {
+			t.Errorf("Unexpected result: %d", got)
+		}
+	})
+}
+
+func TestSetPurgedInfosLimit(t *testing.T) {
+	type tt struct {
+		db     *DB
+		limit  int
+		status int
+		err    string
+	}
+	tests := testy.NewTable()
+	tests.Add("not supported", tt{
+		db:     &DB{client: &Client{}, driverDB: &mock.DB{}},
+		limit:  10,
+		status: http.StatusNotImplemented,
+		err:    "kivik: driver does not support PurgedInfosLimit interface",
+	})
+	tests.Add("invalid limit", tt{
+		db:     &DB{client: &Client{}, driverDB: &mock.PurgedInfosLimiter{}},
+		limit:  -1,
+		status: http.StatusBadRequest,
+		err:    "kivik: limit must be positive, got -1",
+	})
+	tests.Add("success", tt{
+		db: &DB{
+			client: &Client{},
+			driverDB: &mock.PurgedInfosLimiter{
+				SetPurgedInfosLimitFunc: func(_ context.Context, limit int) error {
+					if limit != 10 {
+						return fmt.Errorf("Unexpected limit: %d", limit)
+					}
+					return nil
+				},
+			},
+		},
+		limit: 10,
+	})
+
+	tests.Run(t, func(t *testing.T, tt tt) {
+		err := tt.db.SetPurgedInfosLimit(context.Background(), tt.limit)
+		if d := internal.StatusErrorDiff(tt.err, tt.status, err); d != "" {
+			t.Error(d)
+		}
+	})
+}
+
 func TestCompact(t *testing.T) {
 	t.Run("error", func(t *testing.T) {
 		expected := "compact error"
@@ -12,7 +12,10 @@
 
 package driver
 
-import "encoding/json"
+import (
+	"context"
+	"encoding/json"
+)
 
 // Changes is an iterator of the database changes feed.
 type Changes interface {
@@ -30,6 +33,15 @@ type Changes interface {
 	ETag() string
 }
 
+// SelectorChanger is an optional interface that may be implemented by a [DB]
+// which can filter the changes feed by Mango selector on the server, as with
+// CouchDB's filter=_selector.
+type SelectorChanger interface {
+	// SelectorChanges returns the changes feed, limited to documents matching
+	// selector.
+	SelectorChanges(ctx context.Context, selector json.RawMessage, options Options) (Changes, error)
+}
+
 // Change represents the changes to a single document.
 type Change struct {
 	// ID is the document ID to which the change relates.
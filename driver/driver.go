@@ -90,6 +90,50 @@ type AllDBsStatser interface {
 	AllDBsStats(ctx context.Context, options Options) ([]*DBStats, error)
 }
 
+// ActiveTasker is an optional interface that a [Client] may implement, to
+// support CouchDB's /_active_tasks endpoint.
+type ActiveTasker interface {
+	// ActiveTasks returns the tasks currently running on the server.
+	ActiveTasks(ctx context.Context, options Options) ([]*ActiveTask, error)
+}
+
+// ActiveTask is a single entry returned by [ActiveTasker]. Type determines
+// which of the kind-specific fields are meaningful.
+type ActiveTask struct {
+	Type         string `json:"type"`
+	Node         string `json:"node,omitempty"`
+	PID          string `json:"pid,omitempty"`
+	Database     string `json:"database,omitempty"`
+	Progress     int    `json:"progress,omitempty"`
+	ChangesDone  int64  `json:"changes_done,omitempty"`
+	TotalChanges int64  `json:"total_changes,omitempty"`
+	StartedOn    int64  `json:"started_on,omitempty"`
+	UpdatedOn    int64  `json:"updated_on,omitempty"`
+
+	// DesignDocument is set for indexer, search_indexer and view_compaction
+	// tasks.
+	DesignDocument string `json:"design_document,omitempty"`
+	// Index is set for search_indexer tasks.
+	Index string `json:"index,omitempty"`
+	// Phase and View are set for view_compaction tasks.
+	Phase string `json:"phase,omitempty"`
+	View  string `json:"view,omitempty"`
+
+	// The remaining fields are set for replication tasks.
+	ReplicationID         string `json:"replication_id,omitempty"`
+	DocID                 string `json:"doc_id,omitempty"`
+	Source                string `json:"source,omitempty"`
+	Target                string `json:"target,omitempty"`
+	User                  string `json:"user,omitempty"`
+	Continuous            bool   `json:"continuous,omitempty"`
+	DocsRead              int64  `json:"docs_read,omitempty"`
+	DocsWritten           int64  `json:"docs_written,omitempty"`
+	DocWriteFailures      int64  `json:"doc_write_failures,omitempty"`
+	MissingRevisionsFound int64  `json:"missing_revisions_found,omitempty"`
+	RevisionsChecked      int64  `json:"revisions_checked,omitempty"`
+	ChangesPending        int64  `json:"changes_pending,omitempty"`
+}
+
 // Replication represents a _replicator document.
 type Replication interface {
 	// The following methods are called just once, when the Replication is first
@@ -143,6 +187,7 @@ type DBStats struct {
 	ActiveSize     int64           `json:"data_size"`
 	ExternalSize   int64           `json:"-"`
 	Cluster        *ClusterStats   `json:"cluster,omitempty"`
+	Partitioned    bool            `json:"-"`
 	RawResponse    json.RawMessage `json:"-"`
 }
 
@@ -257,6 +302,44 @@ type SecurityDB interface {
 	SetSecurity(ctx context.Context, security *Security) error
 }
 
+// RevsLimiter is an optional interface that extends a [DB], for backends which
+// limit the number of revisions tracked for each document.
+type RevsLimiter interface {
+	// RevsLimit returns the maximum number of revisions tracked per document.
+	RevsLimit(ctx context.Context) (int, error)
+	// SetRevsLimit sets the maximum number of revisions tracked per document.
+	SetRevsLimit(ctx context.Context, limit int) error
+}
+
+// PurgedInfosLimiter is an optional interface that extends a [DB], for backends
+// which limit the number of purge requests retained.
+type PurgedInfosLimiter interface {
+	// PurgedInfosLimit returns the maximum number of purge requests retained.
+	PurgedInfosLimit(ctx context.Context) (int, error)
+	// SetPurgedInfosLimit sets the maximum number of purge requests retained.
+	SetPurgedInfosLimit(ctx context.Context, limit int) error
+}
+
+// DocShard is the shard range, and the nodes holding it, to which a document
+// ID maps.
+type DocShard struct {
+	Range string   `json:"range"`
+	Nodes []string `json:"nodes"`
+}
+
+// Sharder is an optional interface that extends a [DB], for clustered
+// backends which store a database in shards.
+type Sharder interface {
+	// Shards returns the database's shard map, as a map of shard ranges to the
+	// nodes holding each.
+	Shards(ctx context.Context) (map[string][]string, error)
+	// DocShard returns the shard range, and nodes, which hold docID.
+	DocShard(ctx context.Context, docID string) (*DocShard, error)
+	// SyncShards forces synchronization of all shard replicas of the
+	// database.
+	SyncShards(ctx context.Context) error
+}
+
 // Document represents a single document returned by [DB.Get].
 type Document struct {
 	// Rev is the revision number returned
@@ -421,6 +504,29 @@ type DesignDocer interface {
 	DesignDocs(ctx context.Context, options Options) (Rows, error)
 }
 
+// DesignDocInfoer is an optional interface that may be implemented by a [DB].
+type DesignDocInfoer interface {
+	// DesignDocInfo returns information about the view index of the named
+	// design document. ddoc is passed without the _design/ prefix.
+	DesignDocInfo(ctx context.Context, ddoc string, options Options) (*DesignDocInfo, error)
+}
+
+// DesignDocInfo contains information about a design document's view index.
+type DesignDocInfo struct {
+	Name           string
+	Signature      string
+	Language       string
+	UpdateSeq      string
+	PurgeSeq       string
+	DiskSize       int64
+	ActiveSize     int64
+	ExternalSize   int64
+	UpdaterRunning bool
+	CompactRunning bool
+	WaitingClients int64
+	RawResponse    json.RawMessage
+}
+
 // LocalDocer is an optional interface that may be implemented by a [DB].
 type LocalDocer interface {
 	// LocalDocs returns all of the local documents in the database, subject to
@@ -457,6 +563,95 @@ type Cluster interface {
 	Membership(ctx context.Context) (*ClusterMembership, error)
 }
 
+// ReshardSummary is the summary of shard splitting across the cluster, as
+// returned by the [_reshard endpoint].
+//
+// [_reshard endpoint]: https://docs.couchdb.org/en/stable/api/server/common.html#get--_reshard
+type ReshardSummary struct {
+	State       string `json:"state"`
+	StateReason string `json:"state_reason"`
+	Completed   int    `json:"completed"`
+	Failed      int    `json:"failed"`
+	Running     int    `json:"running"`
+	Stopped     int    `json:"stopped"`
+	Total       int    `json:"total"`
+}
+
+// ReshardState is the state of resharding, either cluster-wide or for a single
+// job. State is one of "running" or "stopped".
+type ReshardState struct {
+	State  string `json:"state"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// ReshardEvent is a single entry in a [ReshardJob]'s history.
+type ReshardEvent struct {
+	Timestamp time.Time `json:"timestamp"`
+	Type      string    `json:"type"`
+	Detail    string    `json:"detail"`
+}
+
+// ReshardJob is a single resharding job.
+type ReshardJob struct {
+	ID         string                 `json:"id"`
+	Type       string                 `json:"type"`
+	Node       string                 `json:"node"`
+	Source     string                 `json:"source"`
+	Targets    []string               `json:"target"`
+	JobState   string                 `json:"job_state"`
+	SplitState string                 `json:"split_state"`
+	StateInfo  map[string]interface{} `json:"state_info,omitempty"`
+	StartTime  time.Time              `json:"start_time"`
+	UpdateTime time.Time              `json:"update_time"`
+	History    []ReshardEvent         `json:"history"`
+}
+
+// ReshardJobRequest describes the resharding jobs to create. Type is required,
+// and currently must be "split". Either DB or Shard must be set, to select the
+// shards to split, which may be further restricted by Node and Range.
+type ReshardJobRequest struct {
+	Type  string `json:"type"`
+	DB    string `json:"db,omitempty"`
+	Node  string `json:"node,omitempty"`
+	Range string `json:"range,omitempty"`
+	Shard string `json:"shard,omitempty"`
+}
+
+// ReshardJobResult is the result of creating a single resharding job. If
+// creation failed, Error is set and ID is empty.
+type ReshardJobResult struct {
+	ID    string
+	Node  string
+	Shard string
+	Error error
+}
+
+// Resharder is an optional interface that may be implemented by a [Client] to
+// support splitting database shards.
+type Resharder interface {
+	// ReshardSummary returns a summary of resharding across the cluster.
+	ReshardSummary(ctx context.Context) (*ReshardSummary, error)
+	// ReshardState returns the cluster-wide resharding state.
+	ReshardState(ctx context.Context) (*ReshardState, error)
+	// SetReshardState starts or stops resharding across the cluster. reason
+	// is optional.
+	SetReshardState(ctx context.Context, state, reason string) error
+	// ReshardJobs returns all resharding jobs.
+	ReshardJobs(ctx context.Context) ([]*ReshardJob, error)
+	// ReshardJob returns a single resharding job.
+	ReshardJob(ctx context.Context, jobID string) (*ReshardJob, error)
+	// CreateReshardJobs creates one or more resharding jobs, as described by
+	// job, and returns the result for each.
+	CreateReshardJobs(ctx context.Context, job *ReshardJobRequest) ([]*ReshardJobResult, error)
+	// DeleteReshardJob stops and removes a resharding job.
+	DeleteReshardJob(ctx context.Context, jobID string) error
+	// ReshardJobState returns the state of a single resharding job.
+	ReshardJobState(ctx context.Context, jobID string) (*ReshardState, error)
+	// SetReshardJobState starts or stops a single resharding job. reason is
+	// optional.
+	SetReshardJobState(ctx context.Context, jobID, state, reason string) error
+}
+
 // ClientCloser is an optional interface that may be implemented by a [Client]
 // to clean up resources when a client is no longer needed.
 type ClientCloser interface {
@@ -0,0 +1,220 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package driver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+)
+
+// NodeStats is a node in the tree of statistics returned by
+// [NodeInspector.NodeStats]. Leaves have Stat set; groups have Children set.
+type NodeStats struct {
+	Stat     *NodeStat
+	Children map[string]*NodeStats
+}
+
+// UnmarshalJSON satisfies the json.Unmarshaler interface. An object with
+// string "type" and a "value" key is treated as a leaf statistic; any other
+// object is treated as a group.
+func (s *NodeStats) UnmarshalJSON(data []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	if isStat(fields) {
+		stat := new(NodeStat)
+		if err := json.Unmarshal(data, stat); err != nil {
+			return err
+		}
+		*s = NodeStats{Stat: stat}
+		return nil
+	}
+	children := make(map[string]*NodeStats, len(fields))
+	for k, v := range fields {
+		child := new(NodeStats)
+		if err := json.Unmarshal(v, child); err != nil {
+			return err
+		}
+		children[k] = child
+	}
+	*s = NodeStats{Children: children}
+	return nil
+}
+
+func isStat(fields map[string]json.RawMessage) bool {
+	typ, ok := fields["type"]
+	if !ok {
+		return false
+	}
+	if _, ok := fields["value"]; !ok {
+		return false
+	}
+	return bytes.HasPrefix(bytes.TrimSpace(typ), []byte(`"`))
+}
+
+// NodeStat is a single statistic. Value is set for counters and gauges;
+// Histogram is set for histograms.
+type NodeStat struct {
+	Type        string
+	Description string
+	Value       float64
+	Histogram   *StatsHistogram
+}
+
+// UnmarshalJSON satisfies the json.Unmarshaler interface.
+func (s *NodeStat) UnmarshalJSON(data []byte) error {
+	var stat struct {
+		Type        string          `json:"type"`
+		Description string          `json:"desc"`
+		Value       json.RawMessage `json:"value"`
+	}
+	if err := json.Unmarshal(data, &stat); err != nil {
+		return err
+	}
+	*s = NodeStat{
+		Type:        stat.Type,
+		Description: stat.Description,
+	}
+	if stat.Type == "histogram" {
+		s.Histogram = new(StatsHistogram)
+		return json.Unmarshal(stat.Value, s.Histogram)
+	}
+	return json.Unmarshal(stat.Value, &s.Value)
+}
+
+// StatsHistogram is the value of a histogram statistic. Percentile and
+// Histogram are lists of [key, value] pairs.
+type StatsHistogram struct {
+	Min               float64      `json:"min"`
+	Max               float64      `json:"max"`
+	ArithmeticMean    float64      `json:"arithmetic_mean"`
+	GeometricMean     float64      `json:"geometric_mean"`
+	HarmonicMean      float64      `json:"harmonic_mean"`
+	Median            float64      `json:"median"`
+	Variance          float64      `json:"variance"`
+	StandardDeviation float64      `json:"standard_deviation"`
+	Skewness          float64      `json:"skewness"`
+	Kurtosis          float64      `json:"kurtosis"`
+	Percentile        [][2]float64 `json:"percentile"`
+	Histogram         [][2]float64 `json:"histogram"`
+	N                 int64        `json:"n"`
+}
+
+// NodeSystem contains the Erlang VM statistics returned by
+// [NodeInspector.NodeSystem].
+type NodeSystem struct {
+	Uptime                  int64                       `json:"uptime"`
+	Memory                  SystemMemory                `json:"memory"`
+	RunQueue                int64                       `json:"run_queue"`
+	RunQueueDirtyCPU        int64                       `json:"run_queue_dirty_cpu"`
+	ETSTableCount           int64                       `json:"ets_table_count"`
+	ContextSwitches         int64                       `json:"context_switches"`
+	Reductions              int64                       `json:"reductions"`
+	GarbageCollectionCount  int64                       `json:"garbage_collection_count"`
+	WordsReclaimed          int64                       `json:"words_reclaimed"`
+	IOInput                 int64                       `json:"io_input"`
+	IOOutput                int64                       `json:"io_output"`
+	OSProcCount             int64                       `json:"os_proc_count"`
+	StaleProcCount          int64                       `json:"stale_proc_count"`
+	ProcessCount            int64                       `json:"process_count"`
+	ProcessLimit            int64                       `json:"process_limit"`
+	MessageQueues           map[string]MessageQueue     `json:"message_queues"`
+	InternalReplicationJobs int64                       `json:"internal_replication_jobs"`
+	Distribution            map[string]map[string]int64 `json:"distribution"`
+}
+
+// SystemMemory is the Erlang VM memory usage, in bytes.
+type SystemMemory struct {
+	Other         int64 `json:"other"`
+	Atom          int64 `json:"atom"`
+	AtomUsed      int64 `json:"atom_used"`
+	Processes     int64 `json:"processes"`
+	ProcessesUsed int64 `json:"processes_used"`
+	Binary        int64 `json:"binary"`
+	Code          int64 `json:"code"`
+	ETS           int64 `json:"ets"`
+}
+
+// MessageQueue describes the message queue of a single process, or the
+// distribution of queue lengths across a pool of processes, in which case
+// Count is the number of processes in the pool.
+type MessageQueue struct {
+	Count  int64 `json:"count"`
+	Min    int64 `json:"min"`
+	Max    int64 `json:"max"`
+	Median int64 `json:"50"`
+	P90    int64 `json:"90"`
+	P99    int64 `json:"99"`
+}
+
+// UnmarshalJSON satisfies the json.Unmarshaler interface. CouchDB reports
+// a single process's queue as a bare number, which is treated as a pool of
+// one.
+func (q *MessageQueue) UnmarshalJSON(data []byte) error {
+	var n int64
+	if err := json.Unmarshal(data, &n); err == nil {
+		*q = MessageQueue{Count: 1, Min: n, Max: n, Median: n, P90: n, P99: n}
+		return nil
+	}
+	type alias MessageQueue
+	return json.Unmarshal(data, (*alias)(q))
+}
+
+// NodeVersions contains the versions of the software components of a node,
+// as returned by [NodeInspector.NodeVersions].
+type NodeVersions struct {
+	JavaScriptEngine *JavaScriptEngine `json:"javascript_engine,omitempty"`
+	Erlang           ErlangVersion     `json:"erlang"`
+	CollationDriver  *CollationDriver  `json:"collation_driver,omitempty"`
+}
+
+// JavaScriptEngine identifies the JavaScript engine used by a node.
+type JavaScriptEngine struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// ErlangVersion identifies the Erlang/OTP release used by a node.
+type ErlangVersion struct {
+	Version         string   `json:"version"`
+	SupportedHashes []string `json:"supported_hashes"`
+}
+
+// CollationDriver identifies the collation library used by a node.
+type CollationDriver struct {
+	Name                      string `json:"name"`
+	LibraryVersion            string `json:"library_version"`
+	CollatorVersion           string `json:"collator_version"`
+	CollationAlgorithmVersion string `json:"collation_algorithm_version"`
+}
+
+// NodeInspector is an optional interface that may be implemented by a
+// [Client] to expose node-level statistics and administration.
+type NodeInspector interface {
+	// NodeStats returns the statistics of the named node. If path is not
+	// empty, only the named group or statistic is returned.
+	NodeStats(ctx context.Context, node string, path []string) (*NodeStats, error)
+	// NodeSystem returns the Erlang VM statistics of the named node.
+	NodeSystem(ctx context.Context, node string) (*NodeSystem, error)
+	// NodeVersions returns the software versions of the named node.
+	NodeVersions(ctx context.Context, node string) (*NodeVersions, error)
+	// NodePrometheus returns the statistics of the named node, in the
+	// Prometheus text exposition format. The caller must close the returned
+	// reader.
+	NodePrometheus(ctx context.Context, node string) (io.ReadCloser, error)
+	// RestartNode restarts the named node.
+	RestartNode(ctx context.Context, node string) error
+}
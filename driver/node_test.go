@@ -0,0 +1,115 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package driver
+
+import (
+	"encoding/json"
+	"testing"
+
+	"gitlab.com/flimzy/testy"
+)
+
+func TestNodeStatsUnmarshal(t *testing.T) {
+	type tt struct {
+		input string
+		want  *NodeStats
+		err   string
+	}
+	tests := testy.NewTable()
+	tests.Add("invalid JSON", tt{
+		input: `[]`,
+		err:   "json: cannot unmarshal array into Go value of type map[string]json.RawMessage",
+	})
+	tests.Add("tree", tt{
+		input: `{
+			"couchdb": {
+				"open_databases": {"value": 5, "type": "counter", "desc": "number of open databases"},
+				"request_time": {
+					"value": {
+						"min": 1, "max": 10, "arithmetic_mean": 4.5, "median": 4,
+						"percentile": [[50, 4], [99.9, 10]],
+						"histogram": [[0, 2]],
+						"n": 2
+					},
+					"type": "histogram",
+					"desc": "length of a request inside CouchDB without MochiWeb"
+				}
+			},
+			"mem3": {
+				"shard_cache": {
+					"hit": {"value": 0, "type": "counter", "desc": "number of shard cache hits"}
+				}
+			}
+		}`,
+		want: &NodeStats{Children: map[string]*NodeStats{
+			"couchdb": {Children: map[string]*NodeStats{
+				"open_databases": {Stat: &NodeStat{Type: "counter", Description: "number of open databases", Value: 5}},
+				"request_time": {Stat: &NodeStat{
+					Type:        "histogram",
+					Description: "length of a request inside CouchDB without MochiWeb",
+					Histogram: &StatsHistogram{
+						Min: 1, Max: 10, ArithmeticMean: 4.5, Median: 4,
+						Percentile: [][2]float64{{50, 4}, {99.9, 10}},
+						Histogram:  [][2]float64{{0, 2}},
+						N:          2,
+					},
+				}},
+			}},
+			"mem3": {Children: map[string]*NodeStats{
+				"shard_cache": {Children: map[string]*NodeStats{
+					"hit": {Stat: &NodeStat{Type: "counter", Description: "number of shard cache hits"}},
+				}},
+			}},
+		}},
+	})
+	tests.Add("single stat", tt{
+		input: `{"value": 3, "type": "gauge", "desc": "number of active compactions"}`,
+		want:  &NodeStats{Stat: &NodeStat{Type: "gauge", Description: "number of active compactions", Value: 3}},
+	})
+	tests.Add("group named type", tt{
+		input: `{"type": {"value": 1, "type": "counter", "desc": "x"}, "value": {"value": 2, "type": "counter", "desc": "y"}}`,
+		want: &NodeStats{Children: map[string]*NodeStats{
+			"type":  {Stat: &NodeStat{Type: "counter", Description: "x", Value: 1}},
+			"value": {Stat: &NodeStat{Type: "counter", Description: "y", Value: 2}},
+		}},
+	})
+
+	tests.Run(t, func(t *testing.T, tt tt) {
+		got := new(NodeStats)
+		err := json.Unmarshal([]byte(tt.input), got)
+		if !testy.ErrorMatches(tt.err, err) {
+			t.Errorf("Unexpected error: %s", err)
+		}
+		if err != nil {
+			return
+		}
+		if d := testy.DiffInterface(tt.want, got); d != nil {
+			t.Error(d)
+		}
+	})
+}
+
+func TestMessageQueueUnmarshal(t *testing.T) {
+	var queues map[string]MessageQueue
+	input := `{"couch_server": 3, "couch_file": {"count": 4, "min": 0, "max": 2, "50": 0, "90": 1, "99": 2}}`
+	if err := json.Unmarshal([]byte(input), &queues); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]MessageQueue{
+		"couch_server": {Count: 1, Min: 3, Max: 3, Median: 3, P90: 3, P99: 3},
+		"couch_file":   {Count: 4, Max: 2, P90: 1, P99: 2},
+	}
+	if d := testy.DiffInterface(want, queues); d != nil {
+		t.Error(d)
+	}
+}
@@ -24,6 +24,14 @@ type PartitionedDB interface {
 	PartitionStats(ctx context.Context, name string) (*PartitionStats, error)
 }
 
+// Partition is the target to which partition options, such as those
+// returned by [github.com/go-kivik/kivik/v4.Partition], are applied. Drivers
+// which support partitioned queries pass a *Partition to [Options.Apply], and
+// limit the query to the named partition if Name is set.
+type Partition struct {
+	Name string
+}
+
 // PartitionStats contains partition statistics.
 type PartitionStats struct {
 	DBName          string
@@ -25,13 +25,21 @@ const (
 	ErrDatabaseClosed = internal.CompositeError("503 database closed")
 
 	// Various not-implemented errors, that are returned, but don't need to be exposed directly.
-	errFindNotImplemented        = internal.CompositeError("501 driver does not support Find interface")
-	errClusterNotImplemented     = internal.CompositeError("501 driver does not support cluster operations")
-	errOpenRevsNotImplemented    = internal.CompositeError("501 driver does not support OpenRevs interface")
-	errSecurityNotImplemented    = internal.CompositeError("501 driver does not support Security interface")
-	errConfigNotImplemented      = internal.CompositeError("501 driver does not support Config interface")
-	errReplicationNotImplemented = internal.CompositeError("501 driver does not support replication")
-	errNoAttachments             = internal.CompositeError("404 no attachments")
+	errFindNotImplemented             = internal.CompositeError("501 driver does not support Find interface")
+	errClusterNotImplemented          = internal.CompositeError("501 driver does not support cluster operations")
+	errOpenRevsNotImplemented         = internal.CompositeError("501 driver does not support OpenRevs interface")
+	errSecurityNotImplemented         = internal.CompositeError("501 driver does not support Security interface")
+	errConfigNotImplemented           = internal.CompositeError("501 driver does not support Config interface")
+	errReplicationNotImplemented      = internal.CompositeError("501 driver does not support replication")
+	errActiveTasksNotImplemented      = internal.CompositeError("501 driver does not support ActiveTasks interface")
+	errDesignDocInfoNotImplemented    = internal.CompositeError("501 driver does not support DesignDocInfo interface")
+	errRevsLimitNotImplemented        = internal.CompositeError("501 driver does not support RevsLimit interface")
+	errPurgedInfosLimitNotImplemented = internal.CompositeError("501 driver does not support PurgedInfosLimit interface")
+	errShardsNotImplemented           = internal.CompositeError("501 driver does not support Shards interface")
+	errReshardNotImplemented          = internal.CompositeError("501 driver does not support resharding")
+	errNodeNotImplemented             = internal.CompositeError("501 driver does not support node introspection")
+	errNoAttachments                  = internal.CompositeError("404 no attachments")
+	errConflictsChanged               = internal.CompositeError("409 conflicts changed while resolving")
 )
 
 // HTTPStatus returns the HTTP status code embedded in the error, or 500
@@ -93,6 +93,8 @@ func toQuery(query interface{}, options ...Option) (json.RawMessage, error) {
 // CreateIndex creates an index if it doesn't already exist. ddoc and name may
 // be empty, in which case they will be auto-generated.  index must be
 // marshalable to a valid index object, as described in the [CouchDB documentation].
+// The index may include a partial_filter_selector. To create a text index,
+// pass the option Param("type", "text").
 //
 // [CouchDB documentation]: http://docs.couchdb.org/en/stable/api/database/find.html#db-index
 func (db *DB) CreateIndex(ctx context.Context, ddoc, name string, index interface{}, options ...Option) error {
@@ -14,6 +14,7 @@ package mock
 
 import (
 	"context"
+	"io"
 
 	"github.com/go-kivik/kivik/v4/driver"
 )
@@ -107,6 +108,80 @@ func (c *DBsStatser) DBsStats(ctx context.Context, dbnames []string) ([]*driver.
 	return c.DBsStatsFunc(ctx, dbnames)
 }
 
+// ActiveTasker mocks driver.Client and driver.ActiveTasker
+type ActiveTasker struct {
+	*Client
+	ActiveTasksFunc func(context.Context, driver.Options) ([]*driver.ActiveTask, error)
+}
+
+var _ driver.ActiveTasker = &ActiveTasker{}
+
+// ActiveTasks calls c.ActiveTasksFunc
+func (c *ActiveTasker) ActiveTasks(ctx context.Context, options driver.Options) ([]*driver.ActiveTask, error) {
+	return c.ActiveTasksFunc(ctx, options)
+}
+
+// Resharder mocks driver.Client and driver.Resharder
+type Resharder struct {
+	*Client
+	ReshardSummaryFunc     func(context.Context) (*driver.ReshardSummary, error)
+	ReshardStateFunc       func(context.Context) (*driver.ReshardState, error)
+	SetReshardStateFunc    func(ctx context.Context, state, reason string) error
+	ReshardJobsFunc        func(context.Context) ([]*driver.ReshardJob, error)
+	ReshardJobFunc         func(ctx context.Context, jobID string) (*driver.ReshardJob, error)
+	CreateReshardJobsFunc  func(context.Context, *driver.ReshardJobRequest) ([]*driver.ReshardJobResult, error)
+	DeleteReshardJobFunc   func(ctx context.Context, jobID string) error
+	ReshardJobStateFunc    func(ctx context.Context, jobID string) (*driver.ReshardState, error)
+	SetReshardJobStateFunc func(ctx context.Context, jobID, state, reason string) error
+}
+
+var _ driver.Resharder = &Resharder{}
+
+// ReshardSummary calls c.ReshardSummaryFunc
+func (c *Resharder) ReshardSummary(ctx context.Context) (*driver.ReshardSummary, error) {
+	return c.ReshardSummaryFunc(ctx)
+}
+
+// ReshardState calls c.ReshardStateFunc
+func (c *Resharder) ReshardState(ctx context.Context) (*driver.ReshardState, error) {
+	return c.ReshardStateFunc(ctx)
+}
+
+// SetReshardState calls c.SetReshardStateFunc
+func (c *Resharder) SetReshardState(ctx context.Context, state, reason string) error {
+	return c.SetReshardStateFunc(ctx, state, reason)
+}
+
+// ReshardJobs calls c.ReshardJobsFunc
+func (c *Resharder) ReshardJobs(ctx context.Context) ([]*driver.ReshardJob, error) {
+	return c.ReshardJobsFunc(ctx)
+}
+
+// ReshardJob calls c.ReshardJobFunc
+func (c *Resharder) ReshardJob(ctx context.Context, jobID string) (*driver.ReshardJob, error) {
+	return c.ReshardJobFunc(ctx, jobID)
+}
+
+// CreateReshardJobs calls c.CreateReshardJobsFunc
+func (c *Resharder) CreateReshardJobs(ctx context.Context, job *driver.ReshardJobRequest) ([]*driver.ReshardJobResult, error) {
+	return c.CreateReshardJobsFunc(ctx, job)
+}
+
+// DeleteReshardJob calls c.DeleteReshardJobFunc
+func (c *Resharder) DeleteReshardJob(ctx context.Context, jobID string) error {
+	return c.DeleteReshardJobFunc(ctx, jobID)
+}
+
+// ReshardJobState calls c.ReshardJobStateFunc
+func (c *Resharder) ReshardJobState(ctx context.Context, jobID string) (*driver.ReshardState, error) {
+	return c.ReshardJobStateFunc(ctx, jobID)
+}
+
+// SetReshardJobState calls c.SetReshardJobStateFunc
+func (c *Resharder) SetReshardJobState(ctx context.Context, jobID, state, reason string) error {
+	return c.SetReshardJobStateFunc(ctx, jobID, state, reason)
+}
+
 // Pinger mocks driver.Client and driver.Pinger
 type Pinger struct {
 	*Client
@@ -194,3 +269,40 @@ func (c *Configer) SetConfigValue(ctx context.Context, node, section, key, value
 func (c *Configer) DeleteConfigKey(ctx context.Context, node, section, key string) (string, error) {
 	return c.DeleteConfigKeyFunc(ctx, node, section, key)
 }
+
+// NodeInspector mocks driver.Client and driver.NodeInspector
+type NodeInspector struct {
+	*Client
+	NodeStatsFunc      func(ctx context.Context, node string, path []string) (*driver.NodeStats, error)
+	NodeSystemFunc     func(ctx context.Context, node string) (*driver.NodeSystem, error)
+	NodeVersionsFunc   func(ctx context.Context, node string) (*driver.NodeVersions, error)
+	NodePrometheusFunc func(ctx context.Context, node string) (io.ReadCloser, error)
+	RestartNodeFunc    func(ctx context.Context, node string) error
+}
+
+var _ driver.NodeInspector = &NodeInspector{}
+
+// NodeStats calls c.NodeStatsFunc
+func (c *NodeInspector) NodeStats(ctx context.Context, node string, path []string) (*driver.NodeStats, error) {
+	return c.NodeStatsFunc(ctx, node, path)
+}
+
+// NodeSystem calls c.NodeSystemFunc
+func (c *NodeInspector) NodeSystem(ctx context.Context, node string) (*driver.NodeSystem, error) {
+	return c.NodeSystemFunc(ctx, node)
+}
+
+// NodeVersions calls c.NodeVersionsFunc
+func (c *NodeInspector) NodeVersions(ctx context.Context, node string) (*driver.NodeVersions, error) {
+	return c.NodeVersionsFunc(ctx, node)
+}
+
+// NodePrometheus calls c.NodePrometheusFunc
+func (c *NodeInspector) NodePrometheus(ctx context.Context, node string) (io.ReadCloser, error) {
+	return c.NodePrometheusFunc(ctx, node)
+}
+
+// RestartNode calls c.RestartNodeFunc
+func (c *NodeInspector) RestartNode(ctx context.Context, node string) error {
+	return c.RestartNodeFunc(ctx, node)
+}
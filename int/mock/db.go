@@ -14,6 +14,7 @@ package mock
 
 import (
 	"context"
+	"encoding/json"
 
 	"github.com/go-kivik/kivik/v4/driver"
 )
@@ -248,6 +249,82 @@ func (db *DesignDocer) DesignDocs(ctx context.Context, options driver.Options) (
 	return db.DesignDocsFunc(ctx, options)
 }
 
+// DesignDocInfoer mocks a driver.DB and driver.DesignDocInfoer
+type DesignDocInfoer struct {
+	*DB
+	DesignDocInfoFunc func(context.Context, string, driver.Options) (*driver.DesignDocInfo, error)
+}
+
+var _ driver.DesignDocInfoer = &DesignDocInfoer{}
+
+// DesignDocInfo calls db.DesignDocInfoFunc
+func (db *DesignDocInfoer) DesignDocInfo(ctx context.Context, ddoc string, options driver.Options) (*driver.DesignDocInfo, error) {
+	return db.DesignDocInfoFunc(ctx, ddoc, options)
+}
+
+// RevsLimiter mocks a driver.DB and driver.RevsLimiter
+type RevsLimiter struct {
+	*DB
+	RevsLimitFunc    func(context.Context) (int, error)
+	SetRevsLimitFunc func(context.Context, int) error
+}
+
+var _ driver.RevsLimiter = &RevsLimiter{}
+
+// RevsLimit calls db.RevsLimitFunc
+func (db *RevsLimiter) RevsLimit(ctx context.Context) (int, error) {
+	return db.RevsLimitFunc(ctx)
+}
+
+// SetRevsLimit calls db.SetRevsLimitFunc
+func (db *RevsLimiter) SetRevsLimit(ctx context.Context, limit int) error {
+	return db.SetRevsLimitFunc(ctx, limit)
+}
+
+// PurgedInfosLimiter mocks a driver.DB and driver.PurgedInfosLimiter
+type PurgedInfosLimiter struct {
+	*DB
+	PurgedInfosLimitFunc    func(context.Context) (int, error)
+	SetPurgedInfosLimitFunc func(context.Context, int) error
+}
+
+var _ driver.PurgedInfosLimiter = &PurgedInfosLimiter{}
+
+// PurgedInfosLimit calls db.PurgedInfosLimitFunc
+func (db *PurgedInfosLimiter) PurgedInfosLimit(ctx context.Context) (int, error) {
+	return db.PurgedInfosLimitFunc(ctx)
+}
+
+// SetPurgedInfosLimit calls db.SetPurgedInfosLimitFunc
+func (db *PurgedInfosLimiter) SetPurgedInfosLimit(ctx context.Context, limit int) error {
+	return db.SetPurgedInfosLimitFunc(ctx, limit)
+}
+
+// Sharder mocks a driver.DB and driver.Sharder
+type Sharder struct {
+	*DB
+	ShardsFunc     func(context.Context) (map[string][]string, error)
+	DocShardFunc   func(context.Context, string) (*driver.DocShard, error)
+	SyncShardsFunc func(context.Context) error
+}
+
+var _ driver.Sharder = &Sharder{}
+
+// Shards calls db.ShardsFunc
+func (db *Sharder) Shards(ctx context.Context) (map[string][]string, error) {
+	return db.ShardsFunc(ctx)
+}
+
+// DocShard calls db.DocShardFunc
+func (db *Sharder) DocShard(ctx context.Context, docID string) (*driver.DocShard, error) {
+	return db.DocShardFunc(ctx, docID)
+}
+
+// SyncShards calls db.SyncShardsFunc
+func (db *Sharder) SyncShards(ctx context.Context) error {
+	return db.SyncShardsFunc(ctx)
+}
+
 // LocalDocer mocks a driver.DB and driver.DesignDocer
 type LocalDocer struct {
 	*DB
@@ -308,6 +385,19 @@ func (db *RevsDiffer) RevsDiff(ctx context.Context, revMap interface{}) (driver.
 	return db.RevsDiffFunc(ctx, revMap)
 }
 
+// SelectorChanger mocks a driver.DB and a driver.SelectorChanger.
+type SelectorChanger struct {
+	*DB
+	SelectorChangesFunc func(context.Context, json.RawMessage, driver.Options) (driver.Changes, error)
+}
+
+var _ driver.SelectorChanger = &SelectorChanger{}
+
+// SelectorChanges calls db.SelectorChangesFunc
+func (db *SelectorChanger) SelectorChanges(ctx context.Context, selector json.RawMessage, options driver.Options) (driver.Changes, error) {
+	return db.SelectorChangesFunc(ctx, selector, options)
+}
+
 // PartitionedDB mocks a driver.DB and a driver.PartitionedDB.
 type PartitionedDB struct {
 	*DB
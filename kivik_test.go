@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"testing"
 	"time"
 
@@ -1060,6 +1061,23 @@ func TestClientClose(t *testing.T) {
 				}
 			},
 		})
+		tests.Add("NodePrometheus", tt{
+			client: &mock.NodeInspector{
+				NodePrometheusFunc: func(context.Context, string) (io.ReadCloser, error) {
+					return io.NopCloser(strings.NewReader("couchdb_uptime_seconds 259\n")), nil
+				},
+			},
+			work: func(t *testing.T, c *Client) { //nolint:thelper // Not a helper
+				body, err := c.NodePrometheus(context.Background(), "_local")
+				if err != nil {
+					t.Fatal(err)
+				}
+				go func() {
+					time.Sleep(delay)
+					_ = body.Close()
+				}()
+			},
+		})
 		tests.Add("RevsDiff", tt{
 			client: &mock.Client{
 				DBFunc: func(string, driver.Options) (driver.DB, error) {
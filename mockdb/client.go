@@ -33,6 +33,9 @@ var (
 	_ driver.Sessioner     = &driverClient{}
 	_ driver.Configer      = &driverClient{}
 	_ driver.AllDBsStatser = &driverClient{}
+	_ driver.ActiveTasker  = &driverClient{}
+	_ driver.Resharder     = &driverClient{}
+	_ driver.NodeInspector = &driverClient{}
 )
 
 func (c *driverClient) CreateDB(ctx context.Context, name string, options driver.Options) error {
@@ -4,11 +4,13 @@ package mockdb
 
 import (
 	"context"
+	"io"
 
 	"github.com/go-kivik/kivik/v4/driver"
 )
 
 var _ = (*driver.Attachment)(nil)
+var _ io.Reader
 
 func (c *driverClient) AllDBs(ctx context.Context, options driver.Options) ([]string, error) {
 	expected := &ExpectedAllDBs{
@@ -110,6 +112,19 @@ func (c *driverClient) DeleteConfigKey(ctx context.Context, arg0 string, arg1 st
 	return expected.ret0, expected.wait(ctx)
 }
 
+func (c *driverClient) DeleteReshardJob(ctx context.Context, arg0 string) error {
+	expected := &ExpectedDeleteReshardJob{
+		arg0: arg0,
+	}
+	if err := c.nextExpectation(expected); err != nil {
+		return err
+	}
+	if expected.callback != nil {
+		return expected.callback(ctx, arg0)
+	}
+	return expected.wait(ctx)
+}
+
 func (c *driverClient) DestroyDB(ctx context.Context, arg0 string, options driver.Options) error {
 	expected := &ExpectedDestroyDB{
 		arg0: arg0,
@@ -126,6 +141,19 @@ func (c *driverClient) DestroyDB(ctx context.Context, arg0 string, options drive
 	return expected.wait(ctx)
 }
 
+func (c *driverClient) NodePrometheus(ctx context.Context, arg0 string) (io.ReadCloser, error) {
+	expected := &ExpectedNodePrometheus{
+		arg0: arg0,
+	}
+	if err := c.nextExpectation(expected); err != nil {
+		return nil, err
+	}
+	if expected.callback != nil {
+		return expected.callback(ctx, arg0)
+	}
+	return expected.ret0, expected.wait(ctx)
+}
+
 func (c *driverClient) Ping(ctx context.Context) (bool, error) {
 	expected := &ExpectedPing{}
 	if err := c.nextExpectation(expected); err != nil {
@@ -137,6 +165,19 @@ func (c *driverClient) Ping(ctx context.Context) (bool, error) {
 	return expected.ret0, expected.wait(ctx)
 }
 
+func (c *driverClient) RestartNode(ctx context.Context, arg0 string) error {
+	expected := &ExpectedRestartNode{
+		arg0: arg0,
+	}
+	if err := c.nextExpectation(expected); err != nil {
+		return err
+	}
+	if expected.callback != nil {
+		return expected.callback(ctx, arg0)
+	}
+	return expected.wait(ctx)
+}
+
 func (c *driverClient) SetConfigValue(ctx context.Context, arg0 string, arg1 string, arg2 string, arg3 string) (string, error) {
 	expected := &ExpectedSetConfigValue{
 		arg0: arg0,
@@ -153,6 +194,50 @@ func (c *driverClient) SetConfigValue(ctx context.Context, arg0 string, arg1 str
 	return expected.ret0, expected.wait(ctx)
 }
 
+func (c *driverClient) SetReshardJobState(ctx context.Context, arg0 string, arg1 string, arg2 string) error {
+	expected := &ExpectedSetReshardJobState{
+		arg0: arg0,
+		arg1: arg1,
+		arg2: arg2,
+	}
+	if err := c.nextExpectation(expected); err != nil {
+		return err
+	}
+	if expected.callback != nil {
+		return expected.callback(ctx, arg0, arg1, arg2)
+	}
+	return expected.wait(ctx)
+}
+
+func (c *driverClient) SetReshardState(ctx context.Context, arg0 string, arg1 string) error {
+	expected := &ExpectedSetReshardState{
+		arg0: arg0,
+		arg1: arg1,
+	}
+	if err := c.nextExpectation(expected); err != nil {
+		return err
+	}
+	if expected.callback != nil {
+		return expected.callback(ctx, arg0, arg1)
+	}
+	return expected.wait(ctx)
+}
+
+func (c *driverClient) ActiveTasks(ctx context.Context, options driver.Options) ([]*driver.ActiveTask, error) {
+	expected := &ExpectedActiveTasks{
+		commonExpectation: commonExpectation{
+			options: options,
+		},
+	}
+	if err := c.nextExpectation(expected); err != nil {
+		return nil, err
+	}
+	if expected.callback != nil {
+		return expected.callback(ctx, options)
+	}
+	return expected.ret0, expected.wait(ctx)
+}
+
 func (c *driverClient) AllDBsStats(ctx context.Context, options driver.Options) ([]*driver.DBStats, error) {
 	expected := &ExpectedAllDBsStats{
 		commonExpectation: commonExpectation{
@@ -195,6 +280,19 @@ func (c *driverClient) ConfigSection(ctx context.Context, arg0 string, arg1 stri
 	return expected.ret0, expected.wait(ctx)
 }
 
+func (c *driverClient) CreateReshardJobs(ctx context.Context, arg0 *driver.ReshardJobRequest) ([]*driver.ReshardJobResult, error) {
+	expected := &ExpectedCreateReshardJobs{
+		arg0: arg0,
+	}
+	if err := c.nextExpectation(expected); err != nil {
+		return nil, err
+	}
+	if expected.callback != nil {
+		return expected.callback(ctx, arg0)
+	}
+	return expected.ret0, expected.wait(ctx)
+}
+
 func (c *driverClient) DB(arg0 string, options driver.Options) (driver.DB, error) {
 	expected := &ExpectedDB{
 		arg0: arg0,
@@ -268,6 +366,46 @@ func (c *driverClient) Membership(ctx context.Context) (*driver.ClusterMembershi
 	return expected.ret0, expected.wait(ctx)
 }
 
+func (c *driverClient) NodeStats(ctx context.Context, arg0 string, arg1 []string) (*driver.NodeStats, error) {
+	expected := &ExpectedNodeStats{
+		arg0: arg0,
+		arg1: arg1,
+	}
+	if err := c.nextExpectation(expected); err != nil {
+		return nil, err
+	}
+	if expected.callback != nil {
+		return expected.callback(ctx, arg0, arg1)
+	}
+	return expected.ret0, expected.wait(ctx)
+}
+
+func (c *driverClient) NodeSystem(ctx context.Context, arg0 string) (*driver.NodeSystem, error) {
+	expected := &ExpectedNodeSystem{
+		arg0: arg0,
+	}
+	if err := c.nextExpectation(expected); err != nil {
+		return nil, err
+	}
+	if expected.callback != nil {
+		return expected.callback(ctx, arg0)
+	}
+	return expected.ret0, expected.wait(ctx)
+}
+
+func (c *driverClient) NodeVersions(ctx context.Context, arg0 string) (*driver.NodeVersions, error) {
+	expected := &ExpectedNodeVersions{
+		arg0: arg0,
+	}
+	if err := c.nextExpectation(expected); err != nil {
+		return nil, err
+	}
+	if expected.callback != nil {
+		return expected.callback(ctx, arg0)
+	}
+	return expected.ret0, expected.wait(ctx)
+}
+
 func (c *driverClient) Replicate(ctx context.Context, arg0 string, arg1 string, options driver.Options) (driver.Replication, error) {
 	expected := &ExpectedReplicate{
 		arg0: arg0,
@@ -285,6 +423,65 @@ func (c *driverClient) Replicate(ctx context.Context, arg0 string, arg1 string,
 	return &driverReplication{Replication: expected.ret0}, expected.wait(ctx)
 }
 
+func (c *driverClient) ReshardJob(ctx context.Context, arg0 string) (*driver.ReshardJob, error) {
+	expected := &ExpectedReshardJob{
+		arg0: arg0,
+	}
+	if err := c.nextExpectation(expected); err != nil {
+		return nil, err
+	}
+	if expected.callback != nil {
+		return expected.callback(ctx, arg0)
+	}
+	return expected.ret0, expected.wait(ctx)
+}
+
+func (c *driverClient) ReshardJobState(ctx context.Context, arg0 string) (*driver.ReshardState, error) {
+	expected := &ExpectedReshardJobState{
+		arg0: arg0,
+	}
+	if err := c.nextExpectation(expected); err != nil {
+		return nil, err
+	}
+	if expected.callback != nil {
+		return expected.callback(ctx, arg0)
+	}
+	return expected.ret0, expected.wait(ctx)
+}
+
+func (c *driverClient) ReshardJobs(ctx context.Context) ([]*driver.ReshardJob, error) {
+	expected := &ExpectedReshardJobs{}
+	if err := c.nextExpectation(expected); err != nil {
+		return nil, err
+	}
+	if expected.callback != nil {
+		return expected.callback(ctx)
+	}
+	return expected.ret0, expected.wait(ctx)
+}
+
+func (c *driverClient) ReshardState(ctx context.Context) (*driver.ReshardState, error) {
+	expected := &ExpectedReshardState{}
+	if err := c.nextExpectation(expected); err != nil {
+		return nil, err
+	}
+	if expected.callback != nil {
+		return expected.callback(ctx)
+	}
+	return expected.ret0, expected.wait(ctx)
+}
+
+func (c *driverClient) ReshardSummary(ctx context.Context) (*driver.ReshardSummary, error) {
+	expected := &ExpectedReshardSummary{}
+	if err := c.nextExpectation(expected); err != nil {
+		return nil, err
+	}
+	if expected.callback != nil {
+		return expected.callback(ctx)
+	}
+	return expected.ret0, expected.wait(ctx)
+}
+
 func (c *driverClient) Session(ctx context.Context) (*driver.Session, error) {
 	expected := &ExpectedSession{}
 	if err := c.nextExpectation(expected); err != nil {
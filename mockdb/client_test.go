@@ -501,6 +501,55 @@ func TestDBsStats(t *testing.T) {
 	tests.Run(t, testMock)
 }
 
+func TestActiveTasks(t *testing.T) {
+	tests := testy.NewTable()
+	tests.Add("error", mockTest{
+		setup: func(m *Client) {
+			m.ExpectActiveTasks().WillReturnError(errors.New("tasks error"))
+		},
+		test: func(t *testing.T, c *kivik.Client) { //nolint:thelper // Not a helper
+			_, err := c.ActiveTasks(context.TODO())
+			if !testy.ErrorMatches("tasks error", err) {
+				t.Errorf("Unexpected error: %s", err)
+			}
+		},
+	})
+	tests.Add("success", mockTest{
+		setup: func(m *Client) {
+			m.ExpectActiveTasks().WillReturn([]*driver.ActiveTask{
+				{Type: "indexer", Database: "foo", DesignDocument: "_design/bar", Progress: 50},
+			})
+		},
+		test: func(t *testing.T, c *kivik.Client) { //nolint:thelper // Not a helper
+			result, err := c.ActiveTasks(context.TODO())
+			if !testy.ErrorMatches("", err) {
+				t.Errorf("Unexpected error: %s", err)
+			}
+			expected := []kivik.ActiveTask{
+				&kivik.IndexerTask{
+					TaskInfo:       kivik.TaskInfo{Type: "indexer", Database: "foo", Progress: 50},
+					DesignDocument: "_design/bar",
+				},
+			}
+			if d := testy.DiffInterface(expected, result); d != nil {
+				t.Error(d)
+			}
+		},
+	})
+	tests.Add("delay", mockTest{
+		setup: func(m *Client) {
+			m.ExpectActiveTasks().WillDelay(time.Second)
+		},
+		test: func(t *testing.T, c *kivik.Client) { //nolint:thelper // Not a helper
+			_, err := c.ActiveTasks(newCanceledContext())
+			if !testy.ErrorMatches("context canceled", err) {
+				t.Errorf("Unexpected error: %s", err)
+			}
+		},
+	})
+	tests.Run(t, testMock)
+}
+
 func TestPing(t *testing.T) {
 	tests := testy.NewTable()
 	tests.Add("unreachable", mockTest{
@@ -1340,3 +1389,156 @@ func TestGetReplications(t *testing.T) {
 	})
 	tests.Run(t, testMock)
 }
+
+func TestCreateReshardJobs(t *testing.T) {
+	tests := testy.NewTable()
+	tests.Add("success", mockTest{
+		setup: func(m *Client) {
+			m.ExpectCreateReshardJobs().
+				WithJob(kivik.ReshardJobRequest{Type: kivik.ReshardTypeSplit, DB: "foo"}).
+				WillReturn([]*driver.ReshardJobResult{{ID: "001-abc", Node: "node1"}})
+		},
+		test: func(t *testing.T, c *kivik.Client) { //nolint:thelper // Not a helper
+			results, err := c.CreateReshardJobs(context.TODO(), kivik.ReshardJobRequest{DB: "foo"})
+			if !testy.ErrorMatches("", err) {
+				t.Errorf("Unexpected error: %s", err)
+			}
+			expected := []*kivik.ReshardJobResult{{ID: "001-abc", Node: "node1"}}
+			if d := testy.DiffInterface(expected, results); d != nil {
+				t.Error(d)
+			}
+		},
+	})
+	tests.Add("unexpected job", mockTest{
+		setup: func(m *Client) {
+			m.ExpectCreateReshardJobs().WithJob(kivik.ReshardJobRequest{Type: kivik.ReshardTypeSplit, DB: "foo"})
+		},
+		test: func(t *testing.T, c *kivik.Client) { //nolint:thelper // Not a helper
+			_, err := c.CreateReshardJobs(context.TODO(), kivik.ReshardJobRequest{DB: "bar"})
+			if !testy.ErrorMatchesRE(`has job: {"type":"split","db":"foo"}`, err) {
+				t.Errorf("Unexpected error: %s", err)
+			}
+		},
+		err: "there is a remaining unmet expectation",
+	})
+	tests.Run(t, testMock)
+}
+
+func TestSetReshardJobState(t *testing.T) {
+	tests := testy.NewTable()
+	tests.Add("success", mockTest{
+		setup: func(m *Client) {
+			m.ExpectSetReshardJobState().WithJobID("001-abc").WithState(kivik.ReshardStopped)
+		},
+		test: func(t *testing.T, c *kivik.Client) { //nolint:thelper // Not a helper
+			err := c.SetReshardJobState(context.TODO(), "001-abc", kivik.ReshardStopped, "any reason")
+			if !testy.ErrorMatches("", err) {
+				t.Errorf("Unexpected error: %s", err)
+			}
+		},
+	})
+	tests.Add("unexpected state", mockTest{
+		setup: func(m *Client) {
+			m.ExpectSetReshardJobState().WithJobID("001-abc").WithState(kivik.ReshardStopped)
+		},
+		test: func(t *testing.T, c *kivik.Client) { //nolint:thelper // Not a helper
+			err := c.SetReshardJobState(context.TODO(), "001-abc", kivik.ReshardRunning, "")
+			if !testy.ErrorMatchesRE("has state: stopped", err) {
+				t.Errorf("Unexpected error: %s", err)
+			}
+		},
+		err: "there is a remaining unmet expectation",
+	})
+	tests.Run(t, testMock)
+}
+
+func TestNodeStats(t *testing.T) {
+	tests := testy.NewTable()
+	tests.Add("success", mockTest{
+		setup: func(m *Client) {
+			m.ExpectNodeStats().WithNode("_local").WithPath("couchdb", "open_databases").
+				WillReturn(&driver.NodeStats{Stat: &driver.NodeStat{Type: "counter", Value: 5}})
+		},
+		test: func(t *testing.T, c *kivik.Client) { //nolint:thelper // Not a helper
+			stats, err := c.NodeStats(context.TODO(), "_local", "couchdb", "open_databases")
+			if !testy.ErrorMatches("", err) {
+				t.Errorf("Unexpected error: %s", err)
+			}
+			expected := &kivik.NodeStats{Stat: &kivik.NodeStat{Type: kivik.StatTypeCounter, Value: 5}}
+			if d := testy.DiffInterface(expected, stats); d != nil {
+				t.Error(d)
+			}
+		},
+	})
+	tests.Add("unexpected path", mockTest{
+		setup: func(m *Client) {
+			m.ExpectNodeStats().WithPath("couchdb")
+		},
+		test: func(t *testing.T, c *kivik.Client) { //nolint:thelper // Not a helper
+			_, err := c.NodeStats(context.TODO(), "_local", "mem3")
+			if !testy.ErrorMatchesRE("has path: couchdb", err) {
+				t.Errorf("Unexpected error: %s", err)
+			}
+		},
+		err: "there is a remaining unmet expectation",
+	})
+	tests.Run(t, testMock)
+}
+
+func TestNodeSystem(t *testing.T) {
+	tests := testy.NewTable()
+	tests.Add("error", mockTest{
+		setup: func(m *Client) {
+			m.ExpectNodeSystem().WithNode("node1").WillReturnError(errors.New("foo err"))
+		},
+		test: func(t *testing.T, c *kivik.Client) { //nolint:thelper // Not a helper
+			_, err := c.NodeSystem(context.TODO(), "node1")
+			if !testy.ErrorMatches("foo err", err) {
+				t.Errorf("Unexpected error: %s", err)
+			}
+		},
+	})
+	tests.Add("success", mockTest{
+		setup: func(m *Client) {
+			m.ExpectNodeSystem().WillReturn(&driver.NodeSystem{Uptime: 259, RunQueue: 2})
+		},
+		test: func(t *testing.T, c *kivik.Client) { //nolint:thelper // Not a helper
+			sys, err := c.NodeSystem(context.TODO(), "_local")
+			if !testy.ErrorMatches("", err) {
+				t.Errorf("Unexpected error: %s", err)
+			}
+			if sys.Uptime != 259 || sys.RunQueue != 2 {
+				t.Errorf("Unexpected result: %+v", sys)
+			}
+		},
+	})
+	tests.Run(t, testMock)
+}
+
+func TestRestartNode(t *testing.T) {
+	tests := testy.NewTable()
+	tests.Add("success", mockTest{
+		setup: func(m *Client) {
+			m.ExpectRestartNode().WithNode("node1")
+		},
+		test: func(t *testing.T, c *kivik.Client) { //nolint:thelper // Not a helper
+			err := c.RestartNode(context.TODO(), "node1")
+			if !testy.ErrorMatches("", err) {
+				t.Errorf("Unexpected error: %s", err)
+			}
+		},
+	})
+	tests.Add("wrong node", mockTest{
+		setup: func(m *Client) {
+			m.ExpectRestartNode().WithNode("node1")
+		},
+		test: func(t *testing.T, c *kivik.Client) { //nolint:thelper // Not a helper
+			err := c.RestartNode(context.TODO(), "node2")
+			if !testy.ErrorMatchesRE("has node: node1", err) {
+				t.Errorf("Unexpected error: %s", err)
+			}
+		},
+		err: "there is a remaining unmet expectation",
+	})
+	tests.Run(t, testMock)
+}
@@ -5,6 +5,7 @@ package mockdb
 import (
 	"context"
 	"fmt"
+	"io"
 	"reflect"
 	"time"
 
@@ -14,6 +15,7 @@ import (
 
 var _ = &driver.Attachment{}
 var _ = reflect.Int
+var _ io.Reader
 
 // ExpectedAllDBs represents an expectation for a call to AllDBs().
 type ExpectedAllDBs struct {
@@ -387,6 +389,52 @@ func (e *ExpectedDeleteConfigKey) method(v bool) string {
 	return fmt.Sprintf("DeleteConfigKey(ctx, %s, %s, %s)", arg0, arg1, arg2)
 }
 
+// ExpectedDeleteReshardJob represents an expectation for a call to DeleteReshardJob().
+type ExpectedDeleteReshardJob struct {
+	commonExpectation
+	callback func(ctx context.Context, arg0 string) error
+	arg0     string
+}
+
+// WillExecute sets a callback function to be called with any inputs to the
+// original function. Any values returned by the callback will be returned as
+// if generated by the driver.
+func (e *ExpectedDeleteReshardJob) WillExecute(cb func(ctx context.Context, arg0 string) error) *ExpectedDeleteReshardJob {
+	e.callback = cb
+	return e
+}
+
+// WillReturnError sets the error value that will be returned by the call to DeleteReshardJob().
+func (e *ExpectedDeleteReshardJob) WillReturnError(err error) *ExpectedDeleteReshardJob {
+	e.err = err
+	return e
+}
+
+// WillDelay causes the call to DeleteReshardJob() to delay.
+func (e *ExpectedDeleteReshardJob) WillDelay(delay time.Duration) *ExpectedDeleteReshardJob {
+	e.delay = delay
+	return e
+}
+
+func (e *ExpectedDeleteReshardJob) met(ex expectation) bool {
+	exp := ex.(*ExpectedDeleteReshardJob)
+	if exp.arg0 != "" && exp.arg0 != e.arg0 {
+		return false
+	}
+	return true
+}
+
+func (e *ExpectedDeleteReshardJob) method(v bool) string {
+	if !v {
+		return "DeleteReshardJob()"
+	}
+	arg0 := "?"
+	if e.arg0 != "" {
+		arg0 = fmt.Sprintf("%q", e.arg0)
+	}
+	return fmt.Sprintf("DeleteReshardJob(ctx, %s)", arg0)
+}
+
 // ExpectedDestroyDB represents an expectation for a call to DestroyDB().
 type ExpectedDestroyDB struct {
 	commonExpectation
@@ -439,6 +487,59 @@ func (e *ExpectedDestroyDB) method(v bool) string {
 	return fmt.Sprintf("DestroyDB(ctx, %s, %s)", arg0, options)
 }
 
+// ExpectedNodePrometheus represents an expectation for a call to NodePrometheus().
+type ExpectedNodePrometheus struct {
+	commonExpectation
+	callback func(ctx context.Context, arg0 string) (io.ReadCloser, error)
+	arg0     string
+	ret0     io.ReadCloser
+}
+
+// WillExecute sets a callback function to be called with any inputs to the
+// original function. Any values returned by the callback will be returned as
+// if generated by the driver.
+func (e *ExpectedNodePrometheus) WillExecute(cb func(ctx context.Context, arg0 string) (io.ReadCloser, error)) *ExpectedNodePrometheus {
+	e.callback = cb
+	return e
+}
+
+// WillReturn sets the values that will be returned by the call to NodePrometheus().
+func (e *ExpectedNodePrometheus) WillReturn(ret0 io.ReadCloser) *ExpectedNodePrometheus {
+	e.ret0 = ret0
+	return e
+}
+
+// WillReturnError sets the error value that will be returned by the call to NodePrometheus().
+func (e *ExpectedNodePrometheus) WillReturnError(err error) *ExpectedNodePrometheus {
+	e.err = err
+	return e
+}
+
+// WillDelay causes the call to NodePrometheus() to delay.
+func (e *ExpectedNodePrometheus) WillDelay(delay time.Duration) *ExpectedNodePrometheus {
+	e.delay = delay
+	return e
+}
+
+func (e *ExpectedNodePrometheus) met(ex expectation) bool {
+	exp := ex.(*ExpectedNodePrometheus)
+	if exp.arg0 != "" && exp.arg0 != e.arg0 {
+		return false
+	}
+	return true
+}
+
+func (e *ExpectedNodePrometheus) method(v bool) string {
+	if !v {
+		return "NodePrometheus()"
+	}
+	arg0 := "?"
+	if e.arg0 != "" {
+		arg0 = fmt.Sprintf("%q", e.arg0)
+	}
+	return fmt.Sprintf("NodePrometheus(ctx, %s)", arg0)
+}
+
 // ExpectedPing represents an expectation for a call to Ping().
 type ExpectedPing struct {
 	commonExpectation
@@ -483,6 +584,52 @@ func (e *ExpectedPing) method(v bool) string {
 	return fmt.Sprintf("Ping(ctx)")
 }
 
+// ExpectedRestartNode represents an expectation for a call to RestartNode().
+type ExpectedRestartNode struct {
+	commonExpectation
+	callback func(ctx context.Context, arg0 string) error
+	arg0     string
+}
+
+// WillExecute sets a callback function to be called with any inputs to the
+// original function. Any values returned by the callback will be returned as
+// if generated by the driver.
+func (e *ExpectedRestartNode) WillExecute(cb func(ctx context.Context, arg0 string) error) *ExpectedRestartNode {
+	e.callback = cb
+	return e
+}
+
+// WillReturnError sets the error value that will be returned by the call to RestartNode().
+func (e *ExpectedRestartNode) WillReturnError(err error) *ExpectedRestartNode {
+	e.err = err
+	return e
+}
+
+// WillDelay causes the call to RestartNode() to delay.
+func (e *ExpectedRestartNode) WillDelay(delay time.Duration) *ExpectedRestartNode {
+	e.delay = delay
+	return e
+}
+
+func (e *ExpectedRestartNode) met(ex expectation) bool {
+	exp := ex.(*ExpectedRestartNode)
+	if exp.arg0 != "" && exp.arg0 != e.arg0 {
+		return false
+	}
+	return true
+}
+
+func (e *ExpectedRestartNode) method(v bool) string {
+	if !v {
+		return "RestartNode()"
+	}
+	arg0 := "?"
+	if e.arg0 != "" {
+		arg0 = fmt.Sprintf("%q", e.arg0)
+	}
+	return fmt.Sprintf("RestartNode(ctx, %s)", arg0)
+}
+
 // ExpectedSetConfigValue represents an expectation for a call to SetConfigValue().
 type ExpectedSetConfigValue struct {
 	commonExpectation
@@ -557,6 +704,170 @@ func (e *ExpectedSetConfigValue) method(v bool) string {
 	return fmt.Sprintf("SetConfigValue(ctx, %s, %s, %s, %s)", arg0, arg1, arg2, arg3)
 }
 
+// ExpectedSetReshardJobState represents an expectation for a call to SetReshardJobState().
+type ExpectedSetReshardJobState struct {
+	commonExpectation
+	callback func(ctx context.Context, arg0 string, arg1 string, arg2 string) error
+	arg0     string
+	arg1     string
+	arg2     string
+}
+
+// WillExecute sets a callback function to be called with any inputs to the
+// original function. Any values returned by the callback will be returned as
+// if generated by the driver.
+func (e *ExpectedSetReshardJobState) WillExecute(cb func(ctx context.Context, arg0 string, arg1 string, arg2 string) error) *ExpectedSetReshardJobState {
+	e.callback = cb
+	return e
+}
+
+// WillReturnError sets the error value that will be returned by the call to SetReshardJobState().
+func (e *ExpectedSetReshardJobState) WillReturnError(err error) *ExpectedSetReshardJobState {
+	e.err = err
+	return e
+}
+
+// WillDelay causes the call to SetReshardJobState() to delay.
+func (e *ExpectedSetReshardJobState) WillDelay(delay time.Duration) *ExpectedSetReshardJobState {
+	e.delay = delay
+	return e
+}
+
+func (e *ExpectedSetReshardJobState) met(ex expectation) bool {
+	exp := ex.(*ExpectedSetReshardJobState)
+	if exp.arg0 != "" && exp.arg0 != e.arg0 {
+		return false
+	}
+	if exp.arg1 != "" && exp.arg1 != e.arg1 {
+		return false
+	}
+	if exp.arg2 != "" && exp.arg2 != e.arg2 {
+		return false
+	}
+	return true
+}
+
+func (e *ExpectedSetReshardJobState) method(v bool) string {
+	if !v {
+		return "SetReshardJobState()"
+	}
+	arg0, arg1, arg2 := "?", "?", "?"
+	if e.arg0 != "" {
+		arg0 = fmt.Sprintf("%q", e.arg0)
+	}
+	if e.arg1 != "" {
+		arg1 = fmt.Sprintf("%q", e.arg1)
+	}
+	if e.arg2 != "" {
+		arg2 = fmt.Sprintf("%q", e.arg2)
+	}
+	return fmt.Sprintf("SetReshardJobState(ctx, %s, %s, %s)", arg0, arg1, arg2)
+}
+
+// ExpectedSetReshardState represents an expectation for a call to SetReshardState().
+type ExpectedSetReshardState struct {
+	commonExpectation
+	callback func(ctx context.Context, arg0 string, arg1 string) error
+	arg0     string
+	arg1     string
+}
+
+// WillExecute sets a callback function to be called with any inputs to the
+// original function. Any values returned by the callback will be returned as
+// if generated by the driver.
+func (e *ExpectedSetReshardState) WillExecute(cb func(ctx context.Context, arg0 string, arg1 string) error) *ExpectedSetReshardState {
+	e.callback = cb
+	return e
+}
+
+// WillReturnError sets the error value that will be returned by the call to SetReshardState().
+func (e *ExpectedSetReshardState) WillReturnError(err error) *ExpectedSetReshardState {
+	e.err = err
+	return e
+}
+
+// WillDelay causes the call to SetReshardState() to delay.
+func (e *ExpectedSetReshardState) WillDelay(delay time.Duration) *ExpectedSetReshardState {
+	e.delay = delay
+	return e
+}
+
+func (e *ExpectedSetReshardState) met(ex expectation) bool {
+	exp := ex.(*ExpectedSetReshardState)
+	if exp.arg0 != "" && exp.arg0 != e.arg0 {
+		return false
+	}
+	if exp.arg1 != "" && exp.arg1 != e.arg1 {
+		return false
+	}
+	return true
+}
+
+func (e *ExpectedSetReshardState) method(v bool) string {
+	if !v {
+		return "SetReshardState()"
+	}
+	arg0, arg1 := "?", "?"
+	if e.arg0 != "" {
+		arg0 = fmt.Sprintf("%q", e.arg0)
+	}
+	if e.arg1 != "" {
+		arg1 = fmt.Sprintf("%q", e.arg1)
+	}
+	return fmt.Sprintf("SetReshardState(ctx, %s, %s)", arg0, arg1)
+}
+
+// ExpectedActiveTasks represents an expectation for a call to ActiveTasks().
+type ExpectedActiveTasks struct {
+	commonExpectation
+	callback func(ctx context.Context, options driver.Options) ([]*driver.ActiveTask, error)
+	ret0     []*driver.ActiveTask
+}
+
+// WithOptions sets the expected options for the call to ActiveTasks().
+func (e *ExpectedActiveTasks) WithOptions(options ...kivik.Option) *ExpectedActiveTasks {
+	e.options = multiOptions{e.options, multiOptions(options)}
+	return e
+}
+
+// WillExecute sets a callback function to be called with any inputs to the
+// original function. Any values returned by the callback will be returned as
+// if generated by the driver.
+func (e *ExpectedActiveTasks) WillExecute(cb func(ctx context.Context, options driver.Options) ([]*driver.ActiveTask, error)) *ExpectedActiveTasks {
+	e.callback = cb
+	return e
+}
+
+// WillReturn sets the values that will be returned by the call to ActiveTasks().
+func (e *ExpectedActiveTasks) WillReturn(ret0 []*driver.ActiveTask) *ExpectedActiveTasks {
+	e.ret0 = ret0
+	return e
+}
+
+// WillReturnError sets the error value that will be returned by the call to ActiveTasks().
+func (e *ExpectedActiveTasks) WillReturnError(err error) *ExpectedActiveTasks {
+	e.err = err
+	return e
+}
+
+// WillDelay causes the call to ActiveTasks() to delay.
+func (e *ExpectedActiveTasks) WillDelay(delay time.Duration) *ExpectedActiveTasks {
+	e.delay = delay
+	return e
+}
+
+func (e *ExpectedActiveTasks) met(_ expectation) bool {
+	return true
+}
+
+func (e *ExpectedActiveTasks) method(v bool) string {
+	if !v {
+		return "ActiveTasks()"
+	}
+	options := formatOptions(e.options)
+	return fmt.Sprintf("ActiveTasks(ctx, %s)", options)
+}
+
 // ExpectedAllDBsStats represents an expectation for a call to AllDBsStats().
 type ExpectedAllDBsStats struct {
 	commonExpectation
@@ -721,55 +1032,108 @@ func (e *ExpectedConfigSection) method(v bool) string {
 	return fmt.Sprintf("ConfigSection(ctx, %s, %s)", arg0, arg1)
 }
 
-// ExpectedDB represents an expectation for a call to DB().
-type ExpectedDB struct {
+// ExpectedCreateReshardJobs represents an expectation for a call to CreateReshardJobs().
+type ExpectedCreateReshardJobs struct {
 	commonExpectation
-	callback func(arg0 string, options driver.Options) (driver.DB, error)
-	arg0     string
-	ret0     *DB
-}
-
-// WithOptions sets the expected options for the call to DB().
-func (e *ExpectedDB) WithOptions(options ...kivik.Option) *ExpectedDB {
-	e.options = multiOptions{e.options, multiOptions(options)}
-	return e
+	callback func(ctx context.Context, arg0 *driver.ReshardJobRequest) ([]*driver.ReshardJobResult, error)
+	arg0     *driver.ReshardJobRequest
+	ret0     []*driver.ReshardJobResult
 }
 
 // WillExecute sets a callback function to be called with any inputs to the
 // original function. Any values returned by the callback will be returned as
 // if generated by the driver.
-func (e *ExpectedDB) WillExecute(cb func(arg0 string, options driver.Options) (driver.DB, error)) *ExpectedDB {
+func (e *ExpectedCreateReshardJobs) WillExecute(cb func(ctx context.Context, arg0 *driver.ReshardJobRequest) ([]*driver.ReshardJobResult, error)) *ExpectedCreateReshardJobs {
 	e.callback = cb
 	return e
 }
 
-// WillReturn sets the values that will be returned by the call to DB().
-func (e *ExpectedDB) WillReturn(ret0 *DB) *ExpectedDB {
+// WillReturn sets the values that will be returned by the call to CreateReshardJobs().
+func (e *ExpectedCreateReshardJobs) WillReturn(ret0 []*driver.ReshardJobResult) *ExpectedCreateReshardJobs {
 	e.ret0 = ret0
 	return e
 }
 
-// WillReturnError sets the error value that will be returned by the call to DB().
-func (e *ExpectedDB) WillReturnError(err error) *ExpectedDB {
+// WillReturnError sets the error value that will be returned by the call to CreateReshardJobs().
+func (e *ExpectedCreateReshardJobs) WillReturnError(err error) *ExpectedCreateReshardJobs {
 	e.err = err
 	return e
 }
 
-func (e *ExpectedDB) met(ex expectation) bool {
-	exp := ex.(*ExpectedDB)
-	if exp.arg0 != "" && exp.arg0 != e.arg0 {
+// WillDelay causes the call to CreateReshardJobs() to delay.
+func (e *ExpectedCreateReshardJobs) WillDelay(delay time.Duration) *ExpectedCreateReshardJobs {
+	e.delay = delay
+	return e
+}
+
+func (e *ExpectedCreateReshardJobs) met(ex expectation) bool {
+	exp := ex.(*ExpectedCreateReshardJobs)
+	if exp.arg0 != nil && !reflect.DeepEqual(exp.arg0, e.arg0) {
 		return false
 	}
 	return true
 }
 
-func (e *ExpectedDB) method(v bool) string {
+func (e *ExpectedCreateReshardJobs) method(v bool) string {
 	if !v {
-		return "DB()"
+		return "CreateReshardJobs()"
 	}
-	arg0, options := "?", formatOptions(e.options)
-	if e.arg0 != "" {
-		arg0 = fmt.Sprintf("%q", e.arg0)
+	arg0 := "?"
+	if e.arg0 != nil {
+		arg0 = fmt.Sprintf("%v", e.arg0)
+	}
+	return fmt.Sprintf("CreateReshardJobs(ctx, %s)", arg0)
+}
+
+// ExpectedDB represents an expectation for a call to DB().
+type ExpectedDB struct {
+	commonExpectation
+	callback func(arg0 string, options driver.Options) (driver.DB, error)
+	arg0     string
+	ret0     *DB
+}
+
+// WithOptions sets the expected options for the call to DB().
+func (e *ExpectedDB) WithOptions(options ...kivik.Option) *ExpectedDB {
+	e.options = multiOptions{e.options, multiOptions(options)}
+	return e
+}
+
+// WillExecute sets a callback function to be called with any inputs to the
+// original function. Any values returned by the callback will be returned as
+// if generated by the driver.
+func (e *ExpectedDB) WillExecute(cb func(arg0 string, options driver.Options) (driver.DB, error)) *ExpectedDB {
+	e.callback = cb
+	return e
+}
+
+// WillReturn sets the values that will be returned by the call to DB().
+func (e *ExpectedDB) WillReturn(ret0 *DB) *ExpectedDB {
+	e.ret0 = ret0
+	return e
+}
+
+// WillReturnError sets the error value that will be returned by the call to DB().
+func (e *ExpectedDB) WillReturnError(err error) *ExpectedDB {
+	e.err = err
+	return e
+}
+
+func (e *ExpectedDB) met(ex expectation) bool {
+	exp := ex.(*ExpectedDB)
+	if exp.arg0 != "" && exp.arg0 != e.arg0 {
+		return false
+	}
+	return true
+}
+
+func (e *ExpectedDB) method(v bool) string {
+	if !v {
+		return "DB()"
+	}
+	arg0, options := "?", formatOptions(e.options)
+	if e.arg0 != "" {
+		arg0 = fmt.Sprintf("%q", e.arg0)
 	}
 	return fmt.Sprintf("DB(%s, %s)", arg0, options)
 }
@@ -973,6 +1337,172 @@ func (e *ExpectedMembership) method(v bool) string {
 	return fmt.Sprintf("Membership(ctx)")
 }
 
+// ExpectedNodeStats represents an expectation for a call to NodeStats().
+type ExpectedNodeStats struct {
+	commonExpectation
+	callback func(ctx context.Context, arg0 string, arg1 []string) (*driver.NodeStats, error)
+	arg0     string
+	arg1     []string
+	ret0     *driver.NodeStats
+}
+
+// WillExecute sets a callback function to be called with any inputs to the
+// original function. Any values returned by the callback will be returned as
+// if generated by the driver.
+func (e *ExpectedNodeStats) WillExecute(cb func(ctx context.Context, arg0 string, arg1 []string) (*driver.NodeStats, error)) *ExpectedNodeStats {
+	e.callback = cb
+	return e
+}
+
+// WillReturn sets the values that will be returned by the call to NodeStats().
+func (e *ExpectedNodeStats) WillReturn(ret0 *driver.NodeStats) *ExpectedNodeStats {
+	e.ret0 = ret0
+	return e
+}
+
+// WillReturnError sets the error value that will be returned by the call to NodeStats().
+func (e *ExpectedNodeStats) WillReturnError(err error) *ExpectedNodeStats {
+	e.err = err
+	return e
+}
+
+// WillDelay causes the call to NodeStats() to delay.
+func (e *ExpectedNodeStats) WillDelay(delay time.Duration) *ExpectedNodeStats {
+	e.delay = delay
+	return e
+}
+
+func (e *ExpectedNodeStats) met(ex expectation) bool {
+	exp := ex.(*ExpectedNodeStats)
+	if exp.arg0 != "" && exp.arg0 != e.arg0 {
+		return false
+	}
+	if exp.arg1 != nil && !reflect.DeepEqual(exp.arg1, e.arg1) {
+		return false
+	}
+	return true
+}
+
+func (e *ExpectedNodeStats) method(v bool) string {
+	if !v {
+		return "NodeStats()"
+	}
+	arg0, arg1 := "?", "?"
+	if e.arg0 != "" {
+		arg0 = fmt.Sprintf("%q", e.arg0)
+	}
+	if e.arg1 != nil {
+		arg1 = fmt.Sprintf("%v", e.arg1)
+	}
+	return fmt.Sprintf("NodeStats(ctx, %s, %s)", arg0, arg1)
+}
+
+// ExpectedNodeSystem represents an expectation for a call to NodeSystem().
+type ExpectedNodeSystem struct {
+	commonExpectation
+	callback func(ctx context.Context, arg0 string) (*driver.NodeSystem, error)
+	arg0     string
+	ret0     *driver.NodeSystem
+}
+
+// WillExecute sets a callback function to be called with any inputs to the
+// original function. Any values returned by the callback will be returned as
+// if generated by the driver.
+func (e *ExpectedNodeSystem) WillExecute(cb func(ctx context.Context, arg0 string) (*driver.NodeSystem, error)) *ExpectedNodeSystem {
+	e.callback = cb
+	return e
+}
+
+// WillReturn sets the values that will be returned by the call to NodeSystem().
+func (e *ExpectedNodeSystem) WillReturn(ret0 *driver.NodeSystem) *ExpectedNodeSystem {
+	e.ret0 = ret0
+	return e
+}
+
+// WillReturnError sets the error value that will be returned by the call to NodeSystem().
+func (e *ExpectedNodeSystem) WillReturnError(err error) *ExpectedNodeSystem {
+	e.err = err
+	return e
+}
+
+// WillDelay causes the call to NodeSystem() to delay.
+func (e *ExpectedNodeSystem) WillDelay(delay time.Duration) *ExpectedNodeSystem {
+	e.delay = delay
+	return e
+}
+
+func (e *ExpectedNodeSystem) met(ex expectation) bool {
+	exp := ex.(*ExpectedNodeSystem)
+	if exp.arg0 != "" && exp.arg0 != e.arg0 {
+		return false
+	}
+	return true
+}
+
+func (e *ExpectedNodeSystem) method(v bool) string {
+	if !v {
+		return "NodeSystem()"
+	}
+	arg0 := "?"
+	if e.arg0 != "" {
+		arg0 = fmt.Sprintf("%q", e.arg0)
+	}
+	return fmt.Sprintf("NodeSystem(ctx, %s)", arg0)
+}
+
+// ExpectedNodeVersions represents an expectation for a call to NodeVersions().
+type ExpectedNodeVersions struct {
+	commonExpectation
+	callback func(ctx context.Context, arg0 string) (*driver.NodeVersions, error)
+	arg0     string
+	ret0     *driver.NodeVersions
+}
+
+// WillExecute sets a callback function to be called with any inputs to the
+// original function. Any values returned by the callback will be returned as
+// if generated by the driver.
+func (e *ExpectedNodeVersions) WillExecute(cb func(ctx context.Context, arg0 string) (*driver.NodeVersions, error)) *ExpectedNodeVersions {
+	e.callback = cb
+	return e
+}
+
+// WillReturn sets the values that will be returned by the call to NodeVersions().
+func (e *ExpectedNodeVersions) WillReturn(ret0 *driver.NodeVersions) *ExpectedNodeVersions {
+	e.ret0 = ret0
+	return e
+}
+
+// WillReturnError sets the error value that will be returned by the call to NodeVersions().
+func (e *ExpectedNodeVersions) WillReturnError(err error) *ExpectedNodeVersions {
+	e.err = err
+	return e
+}
+
+// WillDelay causes the call to NodeVersions() to delay.
+func (e *ExpectedNodeVersions) WillDelay(delay time.Duration) *ExpectedNodeVersions {
+	e.delay = delay
+	return e
+}
+
+func (e *ExpectedNodeVersions) met(ex expectation) bool {
+	exp := ex.(*ExpectedNodeVersions)
+	if exp.arg0 != "" && exp.arg0 != e.arg0 {
+		return false
+	}
+	return true
+}
+
+func (e *ExpectedNodeVersions) method(v bool) string {
+	if !v {
+		return "NodeVersions()"
+	}
+	arg0 := "?"
+	if e.arg0 != "" {
+		arg0 = fmt.Sprintf("%q", e.arg0)
+	}
+	return fmt.Sprintf("NodeVersions(ctx, %s)", arg0)
+}
+
 // ExpectedReplicate represents an expectation for a call to Replicate().
 type ExpectedReplicate struct {
 	commonExpectation
@@ -1039,6 +1569,244 @@ func (e *ExpectedReplicate) method(v bool) string {
 	return fmt.Sprintf("Replicate(ctx, %s, %s, %s)", arg0, arg1, options)
 }
 
+// ExpectedReshardJob represents an expectation for a call to ReshardJob().
+type ExpectedReshardJob struct {
+	commonExpectation
+	callback func(ctx context.Context, arg0 string) (*driver.ReshardJob, error)
+	arg0     string
+	ret0     *driver.ReshardJob
+}
+
+// WillExecute sets a callback function to be called with any inputs to the
+// original function. Any values returned by the callback will be returned as
+// if generated by the driver.
+func (e *ExpectedReshardJob) WillExecute(cb func(ctx context.Context, arg0 string) (*driver.ReshardJob, error)) *ExpectedReshardJob {
+	e.callback = cb
+	return e
+}
+
+// WillReturn sets the values that will be returned by the call to ReshardJob().
+func (e *ExpectedReshardJob) WillReturn(ret0 *driver.ReshardJob) *ExpectedReshardJob {
+	e.ret0 = ret0
+	return e
+}
+
+// WillReturnError sets the error value that will be returned by the call to ReshardJob().
+func (e *ExpectedReshardJob) WillReturnError(err error) *ExpectedReshardJob {
+	e.err = err
+	return e
+}
+
+// WillDelay causes the call to ReshardJob() to delay.
+func (e *ExpectedReshardJob) WillDelay(delay time.Duration) *ExpectedReshardJob {
+	e.delay = delay
+	return e
+}
+
+func (e *ExpectedReshardJob) met(ex expectation) bool {
+	exp := ex.(*ExpectedReshardJob)
+	if exp.arg0 != "" && exp.arg0 != e.arg0 {
+		return false
+	}
+	return true
+}
+
+func (e *ExpectedReshardJob) method(v bool) string {
+	if !v {
+		return "ReshardJob()"
+	}
+	arg0 := "?"
+	if e.arg0 != "" {
+		arg0 = fmt.Sprintf("%q", e.arg0)
+	}
+	return fmt.Sprintf("ReshardJob(ctx, %s)", arg0)
+}
+
+// ExpectedReshardJobState represents an expectation for a call to ReshardJobState().
+type ExpectedReshardJobState struct {
+	commonExpectation
+	callback func(ctx context.Context, arg0 string) (*driver.ReshardState, error)
+	arg0     string
+	ret0     *driver.ReshardState
+}
+
+// WillExecute sets a callback function to be called with any inputs to the
+// original function. Any values returned by the callback will be returned as
+// if generated by the driver.
+func (e *ExpectedReshardJobState) WillExecute(cb func(ctx context.Context, arg0 string) (*driver.ReshardState, error)) *ExpectedReshardJobState {
+	e.callback = cb
+	return e
+}
+
+// WillReturn sets the values that will be returned by the call to ReshardJobState().
+func (e *ExpectedReshardJobState) WillReturn(ret0 *driver.ReshardState) *ExpectedReshardJobState {
+	e.ret0 = ret0
+	return e
+}
+
+// WillReturnError sets the error value that will be returned by the call to ReshardJobState().
+func (e *ExpectedReshardJobState) WillReturnError(err error) *ExpectedReshardJobState {
+	e.err = err
+	return e
+}
+
+// WillDelay causes the call to ReshardJobState() to delay.
+func (e *ExpectedReshardJobState) WillDelay(delay time.Duration) *ExpectedReshardJobState {
+	e.delay = delay
+	return e
+}
+
+func (e *ExpectedReshardJobState) met(ex expectation) bool {
+	exp := ex.(*ExpectedReshardJobState)
+	if exp.arg0 != "" && exp.arg0 != e.arg0 {
+		return false
+	}
+	return true
+}
+
+func (e *ExpectedReshardJobState) method(v bool) string {
+	if !v {
+		return "ReshardJobState()"
+	}
+	arg0 := "?"
+	if e.arg0 != "" {
+		arg0 = fmt.Sprintf("%q", e.arg0)
+	}
+	return fmt.Sprintf("ReshardJobState(ctx, %s)", arg0)
+}
+
+// ExpectedReshardJobs represents an expectation for a call to ReshardJobs().
+type ExpectedReshardJobs struct {
+	commonExpectation
+	callback func(ctx context.Context) ([]*driver.ReshardJob, error)
+	ret0     []*driver.ReshardJob
+}
+
+// WillExecute sets a callback function to be called with any inputs to the
+// original function. Any values returned by the callback will be returned as
+// if generated by the driver.
+func (e *ExpectedReshardJobs) WillExecute(cb func(ctx context.Context) ([]*driver.ReshardJob, error)) *ExpectedReshardJobs {
+	e.callback = cb
+	return e
+}
+
+// WillReturn sets the values that will be returned by the call to ReshardJobs().
+func (e *ExpectedReshardJobs) WillReturn(ret0 []*driver.ReshardJob) *ExpectedReshardJobs {
+	e.ret0 = ret0
+	return e
+}
+
+// WillReturnError sets the error value that will be returned by the call to ReshardJobs().
+func (e *ExpectedReshardJobs) WillReturnError(err error) *ExpectedReshardJobs {
+	e.err = err
+	return e
+}
+
+// WillDelay causes the call to ReshardJobs() to delay.
+func (e *ExpectedReshardJobs) WillDelay(delay time.Duration) *ExpectedReshardJobs {
+	e.delay = delay
+	return e
+}
+
+func (e *ExpectedReshardJobs) met(_ expectation) bool {
+	return true
+}
+
+func (e *ExpectedReshardJobs) method(v bool) string {
+	if !v {
+		return "ReshardJobs()"
+	}
+	return fmt.Sprintf("ReshardJobs(ctx)")
+}
+
+// ExpectedReshardState represents an expectation for a call to ReshardState().
+type ExpectedReshardState struct {
+	commonExpectation
+	callback func(ctx context.Context) (*driver.ReshardState, error)
+	ret0     *driver.ReshardState
+}
+
+// WillExecute sets a callback function to be called with any inputs to the
+// original function. Any values returned by the callback will be returned as
+// if generated by the driver.
+func (e *ExpectedReshardState) WillExecute(cb func(ctx context.Context) (*driver.ReshardState, error)) *ExpectedReshardState {
+	e.callback = cb
+	return e
+}
+
+// WillReturn sets the values that will be returned by the call to ReshardState().
+func (e *ExpectedReshardState) WillReturn(ret0 *driver.ReshardState) *ExpectedReshardState {
+	e.ret0 = ret0
+	return e
+}
+
+// WillReturnError sets the error value that will be returned by the call to ReshardState().
+func (e *ExpectedReshardState) WillReturnError(err error) *ExpectedReshardState {
+	e.err = err
+	return e
+}
+
+// WillDelay causes the call to ReshardState() to delay.
+func (e *ExpectedReshardState) WillDelay(delay time.Duration) *ExpectedReshardState {
+	e.delay = delay
+	return e
+}
+
+func (e *ExpectedReshardState) met(_ expectation) bool {
+	return true
+}
+
+func (e *ExpectedReshardState) method(v bool) string {
+	if !v {
+		return "ReshardState()"
+	}
+	return fmt.Sprintf("ReshardState(ctx)")
+}
+
+// ExpectedReshardSummary represents an expectation for a call to ReshardSummary().
+type ExpectedReshardSummary struct {
+	commonExpectation
+	callback func(ctx context.Context) (*driver.ReshardSummary, error)
+	ret0     *driver.ReshardSummary
+}
+
+// WillExecute sets a callback function to be called with any inputs to the
+// original function. Any values returned by the callback will be returned as
+// if generated by the driver.
+func (e *ExpectedReshardSummary) WillExecute(cb func(ctx context.Context) (*driver.ReshardSummary, error)) *ExpectedReshardSummary {
+	e.callback = cb
+	return e
+}
+
+// WillReturn sets the values that will be returned by the call to ReshardSummary().
+func (e *ExpectedReshardSummary) WillReturn(ret0 *driver.ReshardSummary) *ExpectedReshardSummary {
+	e.ret0 = ret0
+	return e
+}
+
+// WillReturnError sets the error value that will be returned by the call to ReshardSummary().
+func (e *ExpectedReshardSummary) WillReturnError(err error) *ExpectedReshardSummary {
+	e.err = err
+	return e
+}
+
+// WillDelay causes the call to ReshardSummary() to delay.
+func (e *ExpectedReshardSummary) WillDelay(delay time.Duration) *ExpectedReshardSummary {
+	e.delay = delay
+	return e
+}
+
+func (e *ExpectedReshardSummary) met(_ expectation) bool {
+	return true
+}
+
+func (e *ExpectedReshardSummary) method(v bool) string {
+	if !v {
+		return "ReshardSummary()"
+	}
+	return fmt.Sprintf("ReshardSummary(ctx)")
+}
+
 // ExpectedSession represents an expectation for a call to Session().
 type ExpectedSession struct {
 	commonExpectation
@@ -59,6 +59,13 @@ func (c *Client) ExpectDeleteConfigKey() *ExpectedDeleteConfigKey {
 	return e
 }
 
+// ExpectDeleteReshardJob queues an expectation that DeleteReshardJob will be called.
+func (c *Client) ExpectDeleteReshardJob() *ExpectedDeleteReshardJob {
+	e := &ExpectedDeleteReshardJob{}
+	c.expected = append(c.expected, e)
+	return e
+}
+
 // ExpectDestroyDB queues an expectation that DestroyDB will be called.
 func (c *Client) ExpectDestroyDB() *ExpectedDestroyDB {
 	e := &ExpectedDestroyDB{}
@@ -66,6 +73,13 @@ func (c *Client) ExpectDestroyDB() *ExpectedDestroyDB {
 	return e
 }
 
+// ExpectNodePrometheus queues an expectation that NodePrometheus will be called.
+func (c *Client) ExpectNodePrometheus() *ExpectedNodePrometheus {
+	e := &ExpectedNodePrometheus{}
+	c.expected = append(c.expected, e)
+	return e
+}
+
 // ExpectPing queues an expectation that Ping will be called.
 func (c *Client) ExpectPing() *ExpectedPing {
 	e := &ExpectedPing{}
@@ -73,6 +87,13 @@ func (c *Client) ExpectPing() *ExpectedPing {
 	return e
 }
 
+// ExpectRestartNode queues an expectation that RestartNode will be called.
+func (c *Client) ExpectRestartNode() *ExpectedRestartNode {
+	e := &ExpectedRestartNode{}
+	c.expected = append(c.expected, e)
+	return e
+}
+
 // ExpectSetConfigValue queues an expectation that SetConfigValue will be called.
 func (c *Client) ExpectSetConfigValue() *ExpectedSetConfigValue {
 	e := &ExpectedSetConfigValue{}
@@ -80,6 +101,27 @@ func (c *Client) ExpectSetConfigValue() *ExpectedSetConfigValue {
 	return e
 }
 
+// ExpectSetReshardJobState queues an expectation that SetReshardJobState will be called.
+func (c *Client) ExpectSetReshardJobState() *ExpectedSetReshardJobState {
+	e := &ExpectedSetReshardJobState{}
+	c.expected = append(c.expected, e)
+	return e
+}
+
+// ExpectSetReshardState queues an expectation that SetReshardState will be called.
+func (c *Client) ExpectSetReshardState() *ExpectedSetReshardState {
+	e := &ExpectedSetReshardState{}
+	c.expected = append(c.expected, e)
+	return e
+}
+
+// ExpectActiveTasks queues an expectation that ActiveTasks will be called.
+func (c *Client) ExpectActiveTasks() *ExpectedActiveTasks {
+	e := &ExpectedActiveTasks{}
+	c.expected = append(c.expected, e)
+	return e
+}
+
 // ExpectAllDBsStats queues an expectation that AllDBsStats will be called.
 func (c *Client) ExpectAllDBsStats() *ExpectedAllDBsStats {
 	e := &ExpectedAllDBsStats{}
@@ -101,6 +143,13 @@ func (c *Client) ExpectConfigSection() *ExpectedConfigSection {
 	return e
 }
 
+// ExpectCreateReshardJobs queues an expectation that CreateReshardJobs will be called.
+func (c *Client) ExpectCreateReshardJobs() *ExpectedCreateReshardJobs {
+	e := &ExpectedCreateReshardJobs{}
+	c.expected = append(c.expected, e)
+	return e
+}
+
 // ExpectDB queues an expectation that DB will be called.
 func (c *Client) ExpectDB() *ExpectedDB {
 	e := &ExpectedDB{
@@ -140,6 +189,27 @@ func (c *Client) ExpectMembership() *ExpectedMembership {
 	return e
 }
 
+// ExpectNodeStats queues an expectation that NodeStats will be called.
+func (c *Client) ExpectNodeStats() *ExpectedNodeStats {
+	e := &ExpectedNodeStats{}
+	c.expected = append(c.expected, e)
+	return e
+}
+
+// ExpectNodeSystem queues an expectation that NodeSystem will be called.
+func (c *Client) ExpectNodeSystem() *ExpectedNodeSystem {
+	e := &ExpectedNodeSystem{}
+	c.expected = append(c.expected, e)
+	return e
+}
+
+// ExpectNodeVersions queues an expectation that NodeVersions will be called.
+func (c *Client) ExpectNodeVersions() *ExpectedNodeVersions {
+	e := &ExpectedNodeVersions{}
+	c.expected = append(c.expected, e)
+	return e
+}
+
 // ExpectReplicate queues an expectation that Replicate will be called.
 func (c *Client) ExpectReplicate() *ExpectedReplicate {
 	e := &ExpectedReplicate{}
@@ -147,6 +217,41 @@ func (c *Client) ExpectReplicate() *ExpectedReplicate {
 	return e
 }
 
+// ExpectReshardJob queues an expectation that ReshardJob will be called.
+func (c *Client) ExpectReshardJob() *ExpectedReshardJob {
+	e := &ExpectedReshardJob{}
+	c.expected = append(c.expected, e)
+	return e
+}
+
+// ExpectReshardJobState queues an expectation that ReshardJobState will be called.
+func (c *Client) ExpectReshardJobState() *ExpectedReshardJobState {
+	e := &ExpectedReshardJobState{}
+	c.expected = append(c.expected, e)
+	return e
+}
+
+// ExpectReshardJobs queues an expectation that ReshardJobs will be called.
+func (c *Client) ExpectReshardJobs() *ExpectedReshardJobs {
+	e := &ExpectedReshardJobs{}
+	c.expected = append(c.expected, e)
+	return e
+}
+
+// ExpectReshardState queues an expectation that ReshardState will be called.
+func (c *Client) ExpectReshardState() *ExpectedReshardState {
+	e := &ExpectedReshardState{}
+	c.expected = append(c.expected, e)
+	return e
+}
+
+// ExpectReshardSummary queues an expectation that ReshardSummary will be called.
+func (c *Client) ExpectReshardSummary() *ExpectedReshardSummary {
+	e := &ExpectedReshardSummary{}
+	c.expected = append(c.expected, e)
+	return e
+}
+
 // ExpectSession queues an expectation that Session will be called.
 func (c *Client) ExpectSession() *ExpectedSession {
 	e := &ExpectedSession{}
@@ -21,9 +21,13 @@ type driverDB struct {
 }
 
 var (
-	_ driver.DB         = &driverDB{}
-	_ driver.BulkGetter = &driverDB{}
-	_ driver.Finder     = &driverDB{}
+	_ driver.DB                 = &driverDB{}
+	_ driver.BulkGetter         = &driverDB{}
+	_ driver.Finder             = &driverDB{}
+	_ driver.DesignDocInfoer    = &driverDB{}
+	_ driver.RevsLimiter        = &driverDB{}
+	_ driver.PurgedInfosLimiter = &driverDB{}
+	_ driver.Sharder            = &driverDB{}
 )
 
 func (db *driverDB) Close() error {
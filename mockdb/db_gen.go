@@ -4,11 +4,13 @@ package mockdb
 
 import (
 	"context"
+	"io"
 
 	"github.com/go-kivik/kivik/v4/driver"
 )
 
 var _ = (*driver.Attachment)(nil)
+var _ io.Reader
 
 func (db *driverDB) Compact(ctx context.Context) error {
 	expected := &ExpectedCompact{
@@ -145,6 +147,21 @@ func (db *driverDB) GetRev(ctx context.Context, arg0 string, options driver.Opti
 	return expected.ret0, expected.wait(ctx)
 }
 
+func (db *driverDB) PurgedInfosLimit(ctx context.Context) (int, error) {
+	expected := &ExpectedPurgedInfosLimit{
+		commonExpectation: commonExpectation{
+			db: db.DB,
+		},
+	}
+	if err := db.client.nextExpectation(expected); err != nil {
+		return 0, err
+	}
+	if expected.callback != nil {
+		return expected.callback(ctx)
+	}
+	return expected.ret0, expected.wait(ctx)
+}
+
 func (db *driverDB) Put(ctx context.Context, arg0 string, arg1 interface{}, options driver.Options) (string, error) {
 	expected := &ExpectedPut{
 		arg0: arg0,
@@ -163,6 +180,83 @@ func (db *driverDB) Put(ctx context.Context, arg0 string, arg1 interface{}, opti
 	return expected.ret0, expected.wait(ctx)
 }
 
+func (db *driverDB) RevsLimit(ctx context.Context) (int, error) {
+	expected := &ExpectedRevsLimit{
+		commonExpectation: commonExpectation{
+			db: db.DB,
+		},
+	}
+	if err := db.client.nextExpectation(expected); err != nil {
+		return 0, err
+	}
+	if expected.callback != nil {
+		return expected.callback(ctx)
+	}
+	return expected.ret0, expected.wait(ctx)
+}
+
+func (db *driverDB) SetPurgedInfosLimit(ctx context.Context, arg0 int) error {
+	expected := &ExpectedSetPurgedInfosLimit{
+		arg0: arg0,
+		commonExpectation: commonExpectation{
+			db: db.DB,
+		},
+	}
+	if err := db.client.nextExpectation(expected); err != nil {
+		return err
+	}
+	if expected.callback != nil {
+		return expected.callback(ctx, arg0)
+	}
+	return expected.wait(ctx)
+}
+
+func (db *driverDB) SetRevsLimit(ctx context.Context, arg0 int) error {
+	expected := &ExpectedSetRevsLimit{
+		arg0: arg0,
+		commonExpectation: commonExpectation{
+			db: db.DB,
+		},
+	}
+	if err := db.client.nextExpectation(expected); err != nil {
+		return err
+	}
+	if expected.callback != nil {
+		return expected.callback(ctx, arg0)
+	}
+	return expected.wait(ctx)
+}
+
+func (db *driverDB) Shards(ctx context.Context) (map[string][]string, error) {
+	expected := &ExpectedShards{
+		commonExpectation: commonExpectation{
+			db: db.DB,
+		},
+	}
+	if err := db.client.nextExpectation(expected); err != nil {
+		return nil, err
+	}
+	if expected.callback != nil {
+		return expected.callback(ctx)
+	}
+	return expected.ret0, expected.wait(ctx)
+}
+
+func (db *driverDB) SyncShards(ctx context.Context) error {
+	expected := &ExpectedSyncShards{
+		commonExpectation: commonExpectation{
+			db: db.DB,
+		},
+	}
+	if err := db.client.nextExpectation(expected); err != nil {
+		return err
+	}
+	if expected.callback != nil {
+		return expected.callback(ctx)
+	}
+	return expected.wait(ctx)
+}
+
 func (db *driverDB) ViewCleanup(ctx context.Context) error {
 	expected := &ExpectedViewCleanup{
 		commonExpectation: commonExpectation{
@@ -279,6 +373,23 @@ func (db *driverDB) DeleteAttachment(ctx context.Context, arg0 string, arg1 stri
 	return expected.ret0, expected.wait(ctx)
 }
 
+func (db *driverDB) DesignDocInfo(ctx context.Context, arg0 string, options driver.Options) (*driver.DesignDocInfo, error) {
+	expected := &ExpectedDesignDocInfo{
+		arg0: arg0,
+		commonExpectation: commonExpectation{
+			db:      db.DB,
+			options: options,
+		},
+	}
+	if err := db.client.nextExpectation(expected); err != nil {
+		return nil, err
+	}
+	if expected.callback != nil {
+		return expected.callback(ctx, arg0, options)
+	}
+	return expected.ret0, expected.wait(ctx)
+}
+
 func (db *driverDB) DesignDocs(ctx context.Context, options driver.Options) (driver.Rows, error) {
 	expected := &ExpectedDesignDocs{
 		commonExpectation: commonExpectation{
@@ -295,6 +406,22 @@ func (db *driverDB) DesignDocs(ctx context.Context, options driver.Options) (dri
 	return &driverRows{Context: ctx, Rows: coalesceRows(expected.ret0)}, expected.wait(ctx)
 }
 
+func (db *driverDB) DocShard(ctx context.Context, arg0 string) (*driver.DocShard, error) {
+	expected := &ExpectedDocShard{
+		arg0: arg0,
+		commonExpectation: commonExpectation{
+			db: db.DB,
+		},
+	}
+	if err := db.client.nextExpectation(expected); err != nil {
+		return nil, err
+	}
+	if expected.callback != nil {
+		return expected.callback(ctx, arg0)
+	}
+	return expected.ret0, expected.wait(ctx)
+}
+
 func (db *driverDB) Explain(ctx context.Context, arg0 interface{}, options driver.Options) (*driver.QueryPlan, error) {
 	expected := &ExpectedExplain{
 		arg0: arg0,
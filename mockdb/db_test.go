@@ -1071,6 +1071,160 @@ func TestCompact(t *testing.T) {
 	tests.Run(t, testMock)
 }
 
+func TestDesignDocInfo(t *testing.T) {
+	tests := testy.NewTable()
+	tests.Add("error", mockTest{
+		setup: func(m *Client) {
+			db := m.NewDB()
+			m.ExpectDB().WillReturn(db)
+			db.ExpectDesignDocInfo().WillReturnError(errors.New("foo err"))
+		},
+		test: func(t *testing.T, c *kivik.Client) { //nolint:thelper // Not a helper
+			_, err := c.DB("foo").DesignDocInfo(context.TODO(), "foo")
+			if !testy.ErrorMatches("foo err", err) {
+				t.Errorf("Unexpected error: %s", err)
+			}
+		},
+	})
+	tests.Add("success", mockTest{
+		setup: func(m *Client) {
+			db := m.NewDB()
+			m.ExpectDB().WillReturn(db)
+			db.ExpectDesignDocInfo().WithDDoc("foo").WillReturn(&driver.DesignDocInfo{Name: "foo", UpdateSeq: "5"})
+		},
+		test: func(t *testing.T, c *kivik.Client) { //nolint:thelper // Not a helper
+			info, err := c.DB("foo").DesignDocInfo(context.TODO(), "_design/foo")
+			if !testy.ErrorMatches("", err) {
+				t.Errorf("Unexpected error: %s", err)
+			}
+			expected := &kivik.DesignDocInfo{Name: "foo", UpdateSeq: "5"}
+			if d := testy.DiffInterface(expected, info); d != nil {
+				t.Error(d)
+			}
+		},
+	})
+	tests.Add("unexpected ddoc", mockTest{
+		setup: func(m *Client) {
+			db := m.NewDB()
+			m.ExpectDB().WillReturn(db)
+			db.ExpectDesignDocInfo().WithDDoc("foo")
+		},
+		test: func(t *testing.T, c *kivik.Client) { //nolint:thelper // Not a helper
+			_, err := c.DB("foo").DesignDocInfo(context.TODO(), "bar")
+			if !testy.ErrorMatchesRE("has ddoc: foo", err) {
+				t.Errorf("Unexpected error: %s", err)
+			}
+		},
+		err: "there is a remaining unmet expectation",
+	})
+	tests.Run(t, testMock)
+}
+
+func TestRevsLimit(t *testing.T) {
+	tests := testy.NewTable()
+	tests.Add("error", mockTest{
+		setup: func(m *Client) {
+			db := m.NewDB()
+			m.ExpectDB().WillReturn(db)
+			db.ExpectRevsLimit().WillReturnError(errors.New("foo err"))
+		},
+		test: func(t *testing.T, c *kivik.Client) { //nolint:thelper // Not a helper
+			_, err := c.DB("foo").RevsLimit(context.TODO())
+			if !testy.ErrorMatches("foo err", err) {
+				t.Errorf("Unexpected error: %s", err)
+			}
+		},
+	})
+	tests.Add("success", mockTest{
+		setup: func(m *Client) {
+			db := m.NewDB()
+			m.ExpectDB().WillReturn(db)
+			db.ExpectRevsLimit().WillReturn(42)
+		},
+		test: func(t *testing.T, c *kivik.Client) { //nolint:thelper // Not a helper
+			limit, err := c.DB("foo").RevsLimit(context.TODO())
+			if !testy.ErrorMatches("", err) {
+				t.Errorf("Unexpected error: %s", err)
+			}
+			if limit != 42 {
+				t.Errorf("Unexpected limit: %d", limit)
+			}
+		},
+	})
+	tests.Run(t, testMock)
+}
+
+func TestSetRevsLimit(t *testing.T) {
+	tests := testy.NewTable()
+	tests.Add("success", mockTest{
+		setup: func(m *Client) {
+			db := m.NewDB()
+			m.ExpectDB().WillReturn(db)
+			db.ExpectSetRevsLimit().WithLimit(10)
+		},
+		test: func(t *testing.T, c *kivik.Client) { //nolint:thelper // Not a helper
+			err := c.DB("foo").SetRevsLimit(context.TODO(), 10)
+			if !testy.ErrorMatches("", err) {
+				t.Errorf("Unexpected error: %s", err)
+			}
+		},
+	})
+	tests.Add("unexpected limit", mockTest{
+		setup: func(m *Client) {
+			db := m.NewDB()
+			m.ExpectDB().WillReturn(db)
+			db.ExpectSetRevsLimit().WithLimit(10)
+		},
+		test: func(t *testing.T, c *kivik.Client) { //nolint:thelper // Not a helper
+			err := c.DB("foo").SetRevsLimit(context.TODO(), 20)
+			if !testy.ErrorMatchesRE("has limit: 10", err) {
+				t.Errorf("Unexpected error: %s", err)
+			}
+		},
+		err: "there is a remaining unmet expectation",
+	})
+	tests.Run(t, testMock)
+}
+
+func TestPurgedInfosLimit(t *testing.T) {
+	tests := testy.NewTable()
+	tests.Add("success", mockTest{
+		setup: func(m *Client) {
+			db := m.NewDB()
+			m.ExpectDB().WillReturn(db)
+			db.ExpectPurgedInfosLimit().WillReturn(1000)
+		},
+		test: func(t *testing.T, c *kivik.Client) { //nolint:thelper // Not a helper
+			limit, err := c.DB("foo").PurgedInfosLimit(context.TODO())
+			if !testy.ErrorMatches("", err) {
+				t.Errorf("Unexpected error: %s", err)
+			}
+			if limit != 1000 {
+				t.Errorf("Unexpected limit: %d", limit)
+			}
+		},
+	})
+	tests.Run(t, testMock)
+}
+
+func TestSetPurgedInfosLimit(t *testing.T) {
+	tests := testy.NewTable()
+	tests.Add("error", mockTest{
+		setup: func(m *Client) {
+			db := m.NewDB()
+			m.ExpectDB().WillReturn(db)
+			db.ExpectSetPurgedInfosLimit().WillReturnError(errors.New("foo err"))
+		},
+		test: func(t *testing.T, c *kivik.Client) { //nolint:thelper // Not a helper
+			err := c.DB("foo").SetPurgedInfosLimit(context.TODO(), 5)
+			if !testy.ErrorMatches("foo err", err) {
+				t.Errorf("Unexpected error: %s", err)
+			}
+		},
+	})
+	tests.Run(t, testMock)
+}
+
 func TestCompactView(t *testing.T) {
 	tests := testy.NewTable()
 	tests.Add("error", mockTest{
@@ -3253,3 +3407,60 @@ func TestPartitionStats(t *testing.T) {
 
 	tests.Run(t, testMock)
 }
+
+func TestDocShard(t *testing.T) {
+	tests := testy.NewTable()
+	tests.Add("success", mockTest{
+		setup: func(m *Client) {
+			db := m.NewDB()
+			m.ExpectDB().WillReturn(db)
+			db.ExpectDocShard().WithDocID("foo").WillReturn(&driver.DocShard{
+				Range: "00000000-ffffffff",
+				Nodes: []string{"node1"},
+			})
+		},
+		test: func(t *testing.T, c *kivik.Client) { //nolint:thelper // Not a helper
+			shard, err := c.DB("db").DocShard(context.TODO(), "foo")
+			if !testy.ErrorMatches("", err) {
+				t.Errorf("Unexpected error: %s", err)
+			}
+			expected := &kivik.DocShard{Range: "00000000-ffffffff", Nodes: []string{"node1"}}
+			if d := testy.DiffInterface(expected, shard); d != nil {
+				t.Error(d)
+			}
+		},
+	})
+	tests.Add("unexpected doc ID", mockTest{
+		setup: func(m *Client) {
+			db := m.NewDB()
+			m.ExpectDB().WillReturn(db)
+			db.ExpectDocShard().WithDocID("foo")
+		},
+		test: func(t *testing.T, c *kivik.Client) { //nolint:thelper // Not a helper
+			_, err := c.DB("db").DocShard(context.TODO(), "bar")
+			if !testy.ErrorMatchesRE("has docID: foo", err) {
+				t.Errorf("Unexpected error: %s", err)
+			}
+		},
+		err: "there is a remaining unmet expectation",
+	})
+	tests.Run(t, testMock)
+}
+
+func TestSyncShards(t *testing.T) {
+	tests := testy.NewTable()
+	tests.Add("error", mockTest{
+		setup: func(m *Client) {
+			db := m.NewDB()
+			m.ExpectDB().WillReturn(db)
+			db.ExpectSyncShards().WillReturnError(errors.New("sync error"))
+		},
+		test: func(t *testing.T, c *kivik.Client) { //nolint:thelper // Not a helper
+			err := c.DB("db").SyncShards(context.TODO())
+			if !testy.ErrorMatches("sync error", err) {
+				t.Errorf("Unexpected error: %s", err)
+			}
+		},
+	})
+	tests.Run(t, testMock)
+}
@@ -609,6 +609,26 @@ func (e *ExpectedStats) String() string {
 	return dbStringer("Stats", &e.commonExpectation, 0, nil, rets)
 }
 
+func (e *ExpectedDesignDocInfo) String() string {
+	var opts, rets []string
+	if e.arg0 == "" {
+		opts = append(opts, "has any ddoc")
+	} else {
+		opts = append(opts, "has ddoc: "+e.arg0)
+	}
+	if e.ret0 != nil {
+		rets = append(rets, fmt.Sprintf("should return info: %v", e.ret0))
+	}
+	return dbStringer("DesignDocInfo", &e.commonExpectation, withOptions, opts, rets)
+}
+
+// WithDDoc sets the expected design doc name, without the _design/ prefix,
+// for the call to DB.DesignDocInfo().
+func (e *ExpectedDesignDocInfo) WithDDoc(ddoc string) *ExpectedDesignDocInfo {
+	e.arg0 = ddoc
+	return e
+}
+
 func (e *ExpectedBulkDocs) String() string {
 	var opts, rets []string
 	if e.arg0 == nil {
@@ -707,3 +727,82 @@ func (e *ExpectedPartitionStats) WithName(name string) *ExpectedPartitionStats {
 	e.arg0 = name
 	return e
 }
+
+func (e *ExpectedRevsLimit) String() string {
+	var rets []string
+	if e.ret0 != 0 {
+		rets = append(rets, fmt.Sprintf("should return: %d", e.ret0))
+	}
+	return dbStringer("RevsLimit", &e.commonExpectation, 0, nil, rets)
+}
+
+func (e *ExpectedSetRevsLimit) String() string {
+	var opts []string
+	if e.arg0 == 0 {
+		opts = append(opts, "has any limit")
+	} else {
+		opts = append(opts, fmt.Sprintf("has limit: %d", e.arg0))
+	}
+	return dbStringer("SetRevsLimit", &e.commonExpectation, 0, opts, nil)
+}
+
+// WithLimit sets the expected limit for the call to DB.SetRevsLimit().
+func (e *ExpectedSetRevsLimit) WithLimit(limit int) *ExpectedSetRevsLimit {
+	e.arg0 = limit
+	return e
+}
+
+func (e *ExpectedPurgedInfosLimit) String() string {
+	var rets []string
+	if e.ret0 != 0 {
+		rets = append(rets, fmt.Sprintf("should return: %d", e.ret0))
+	}
+	return dbStringer("PurgedInfosLimit", &e.commonExpectation, 0, nil, rets)
+}
+
+func (e *ExpectedSetPurgedInfosLimit) String() string {
+	var opts []string
+	if e.arg0 == 0 {
+		opts = append(opts, "has any limit")
+	} else {
+		opts = append(opts, fmt.Sprintf("has limit: %d", e.arg0))
+	}
+	return dbStringer("SetPurgedInfosLimit", &e.commonExpectation, 0, opts, nil)
+}
+
+// WithLimit sets the expected limit for the call to DB.SetPurgedInfosLimit().
+func (e *ExpectedSetPurgedInfosLimit) WithLimit(limit int) *ExpectedSetPurgedInfosLimit {
+	e.arg0 = limit
+	return e
+}
+
+func (e *ExpectedShards) String() string {
+	var rets []string
+	if e.ret0 != nil {
+		rets = []string{fmt.Sprintf("should return: %s", jsonDoc(e.ret0))}
+	}
+	return dbStringer("Shards", &e.commonExpectation, 0, nil, rets)
+}
+
+func (e *ExpectedDocShard) String() string {
+	var rets, opts []string
+	if e.ret0 != nil {
+		rets = []string{fmt.Sprintf("should return: %s", jsonDoc(e.ret0))}
+	}
+	if e.arg0 == "" {
+		opts = []string{"has any docID"}
+	} else {
+		opts = []string{fmt.Sprintf("has docID: %s", e.arg0)}
+	}
+	return dbStringer("DocShard", &e.commonExpectation, 0, opts, rets)
+}
+
+// WithDocID sets the expected docID for the call to DB.DocShard().
+func (e *ExpectedDocShard) WithDocID(docID string) *ExpectedDocShard {
+	e.arg0 = docID
+	return e
+}
+
+func (e *ExpectedSyncShards) String() string {
+	return dbStringer("SyncShards", &e.commonExpectation, 0, nil, nil)
+}
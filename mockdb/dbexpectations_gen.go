@@ -5,6 +5,7 @@ package mockdb
 import (
 	"context"
 	"fmt"
+	"io"
 	"reflect"
 	"time"
 
@@ -14,6 +15,7 @@ import (
 
 var _ = &driver.Attachment{}
 var _ = reflect.Int
+var _ io.Reader
 
 // ExpectedCompact represents an expectation for a call to DB.Compact().
 type ExpectedCompact struct {
@@ -446,6 +448,50 @@ func (e *ExpectedGetRev) method(v bool) string {
 	return fmt.Sprintf("DB(%s).GetRev(ctx, %s, %s)", e.dbo().name, arg0, options)
 }
 
+// ExpectedPurgedInfosLimit represents an expectation for a call to DB.PurgedInfosLimit().
+type ExpectedPurgedInfosLimit struct {
+	commonExpectation
+	callback func(ctx context.Context) (int, error)
+	ret0     int
+}
+
+// WillExecute sets a callback function to be called with any inputs to the
+// original function. Any values returned by the callback will be returned as
+// if generated by the driver.
+func (e *ExpectedPurgedInfosLimit) WillExecute(cb func(ctx context.Context) (int, error)) *ExpectedPurgedInfosLimit {
+	e.callback = cb
+	return e
+}
+
+// WillReturn sets the values that will be returned by the call to DB.PurgedInfosLimit().
+func (e *ExpectedPurgedInfosLimit) WillReturn(ret0 int) *ExpectedPurgedInfosLimit {
+	e.ret0 = ret0
+	return e
+}
+
+// WillReturnError sets the error value that will be returned by the call to DB.PurgedInfosLimit().
+func (e *ExpectedPurgedInfosLimit) WillReturnError(err error) *ExpectedPurgedInfosLimit {
+	e.err = err
+	return e
+}
+
+// WillDelay causes the call to DB.PurgedInfosLimit() to delay.
+func (e *ExpectedPurgedInfosLimit) WillDelay(delay time.Duration) *ExpectedPurgedInfosLimit {
+	e.delay = delay
+	return e
+}
+
+func (e *ExpectedPurgedInfosLimit) met(_ expectation) bool {
+	return true
+}
+
+func (e *ExpectedPurgedInfosLimit) method(v bool) string {
+	if !v {
+		return "DB.PurgedInfosLimit()"
+	}
+	return fmt.Sprintf("DB(%s).PurgedInfosLimit(ctx)", e.dbo().name)
+}
+
 // ExpectedPut represents an expectation for a call to DB.Put().
 type ExpectedPut struct {
 	commonExpectation
@@ -512,6 +558,223 @@ func (e *ExpectedPut) method(v bool) string {
 	return fmt.Sprintf("DB(%s).Put(ctx, %s, %s, %s)", e.dbo().name, arg0, arg1, options)
 }
 
+// ExpectedRevsLimit represents an expectation for a call to DB.RevsLimit().
+type ExpectedRevsLimit struct {
+	commonExpectation
+	callback func(ctx context.Context) (int, error)
+	ret0     int
+}
+
+// WillExecute sets a callback function to be called with any inputs to the
+// original function. Any values returned by the callback will be returned as
+// if generated by the driver.
+func (e *ExpectedRevsLimit) WillExecute(cb func(ctx context.Context) (int, error)) *ExpectedRevsLimit {
+	e.callback = cb
+	return e
+}
+
+// WillReturn sets the values that will be returned by the call to DB.RevsLimit().
+func (e *ExpectedRevsLimit) WillReturn(ret0 int) *ExpectedRevsLimit {
+	e.ret0 = ret0
+	return e
+}
+
+// WillReturnError sets the error value that will be returned by the call to DB.RevsLimit().
+func (e *ExpectedRevsLimit) WillReturnError(err error) *ExpectedRevsLimit {
+	e.err = err
+	return e
+}
+
+// WillDelay causes the call to DB.RevsLimit() to delay.
+func (e *ExpectedRevsLimit) WillDelay(delay time.Duration) *ExpectedRevsLimit {
+	e.delay = delay
+	return e
+}
+
+func (e *ExpectedRevsLimit) met(_ expectation) bool {
+	return true
+}
+
+func (e *ExpectedRevsLimit) method(v bool) string {
+	if !v {
+		return "DB.RevsLimit()"
+	}
+	return fmt.Sprintf("DB(%s).RevsLimit(ctx)", e.dbo().name)
+}
+
+// ExpectedSetPurgedInfosLimit represents an expectation for a call to DB.SetPurgedInfosLimit().
+type ExpectedSetPurgedInfosLimit struct {
+	commonExpectation
+	callback func(ctx context.Context, arg0 int) error
+	arg0     int
+}
+
+// WillExecute sets a callback function to be called with any inputs to the
+// original function. Any values returned by the callback will be returned as
+// if generated by the driver.
+func (e *ExpectedSetPurgedInfosLimit) WillExecute(cb func(ctx context.Context, arg0 int) error) *ExpectedSetPurgedInfosLimit {
+	e.callback = cb
+	return e
+}
+
+// WillReturnError sets the error value that will be returned by the call to DB.SetPurgedInfosLimit().
+func (e *ExpectedSetPurgedInfosLimit) WillReturnError(err error) *ExpectedSetPurgedInfosLimit {
+	e.err = err
+	return e
+}
+
+// WillDelay causes the call to DB.SetPurgedInfosLimit() to delay.
+func (e *ExpectedSetPurgedInfosLimit) WillDelay(delay time.Duration) *ExpectedSetPurgedInfosLimit {
+	e.delay = delay
+	return e
+}
+
+func (e *ExpectedSetPurgedInfosLimit) met(ex expectation) bool {
+	exp := ex.(*ExpectedSetPurgedInfosLimit)
+	if exp.arg0 != 0 && exp.arg0 != e.arg0 {
+		return false
+	}
+	return true
+}
+
+func (e *ExpectedSetPurgedInfosLimit) method(v bool) string {
+	if !v {
+		return "DB.SetPurgedInfosLimit()"
+	}
+	arg0 := "?"
+	if e.arg0 != 0 {
+		arg0 = fmt.Sprintf("%d", e.arg0)
+	}
+	return fmt.Sprintf("DB(%s).SetPurgedInfosLimit(ctx, %s)", e.dbo().name, arg0)
+}
+
+// ExpectedSetRevsLimit represents an expectation for a call to DB.SetRevsLimit().
+type ExpectedSetRevsLimit struct {
+	commonExpectation
+	callback func(ctx context.Context, arg0 int) error
+	arg0     int
+}
+
+// WillExecute sets a callback function to be called with any inputs to the
+// original function. Any values returned by the callback will be returned as
+// if generated by the driver.
+func (e *ExpectedSetRevsLimit) WillExecute(cb func(ctx context.Context, arg0 int) error) *ExpectedSetRevsLimit {
+	e.callback = cb
+	return e
+}
+
+// WillReturnError sets the error value that will be returned by the call to DB.SetRevsLimit().
+func (e *ExpectedSetRevsLimit) WillReturnError(err error) *ExpectedSetRevsLimit {
+	e.err = err
+	return e
+}
+
+// WillDelay causes the call to DB.SetRevsLimit() to delay.
+func (e *ExpectedSetRevsLimit) WillDelay(delay time.Duration) *ExpectedSetRevsLimit {
+	e.delay = delay
+	return e
+}
+
+func (e *ExpectedSetRevsLimit) met(ex expectation) bool {
+	exp := ex.(*ExpectedSetRevsLimit)
+	if exp.arg0 != 0 && exp.arg0 != e.arg0 {
+		return false
+	}
+	return true
+}
+
+func (e *ExpectedSetRevsLimit) method(v bool) string {
+	if !v {
+		return "DB.SetRevsLimit()"
+	}
+	arg0 := "?"
+	if e.arg0 != 0 {
+		arg0 = fmt.Sprintf("%d", e.arg0)
+	}
+	return fmt.Sprintf("DB(%s).SetRevsLimit(ctx, %s)", e.dbo().name, arg0)
+}
+
+// ExpectedShards represents an expectation for a call to DB.Shards().
+type ExpectedShards struct {
+	commonExpectation
+	callback func(ctx context.Context) (map[string][]string, error)
+	ret0     map[string][]string
+}
+
+// WillExecute sets a callback function to be called with any inputs to the
+// original function. Any values returned by the callback will be returned as
+// if generated by the driver.
+func (e *ExpectedShards) WillExecute(cb func(ctx context.Context) (map[string][]string, error)) *ExpectedShards {
+	e.callback = cb
+	return e
+}
+
+// WillReturn sets the values that will be returned by the call to DB.Shards().
+func (e *ExpectedShards) WillReturn(ret0 map[string][]string) *ExpectedShards {
+	e.ret0 = ret0
+	return e
+}
+
+// WillReturnError sets the error value that will be returned by the call to DB.Shards().
+func (e *ExpectedShards) WillReturnError(err error) *ExpectedShards {
+	e.err = err
+	return e
+}
+
+// WillDelay causes the call to DB.Shards() to delay.
+func (e *ExpectedShards) WillDelay(delay time.Duration) *ExpectedShards {
+	e.delay = delay
+	return e
+}
+
+func (e *ExpectedShards) met(_ expectation) bool {
+	return true
+}
+
+func (e *ExpectedShards) method(v bool) string {
+	if !v {
+		return "DB.Shards()"
+	}
+	return fmt.Sprintf("DB(%s).Shards(ctx)", e.dbo().name)
+}
+
+// ExpectedSyncShards represents an expectation for a call to DB.SyncShards().
+type ExpectedSyncShards struct {
+	commonExpectation
+	callback func(ctx context.Context) error
+}
+
+// WillExecute sets a callback function to be called with any inputs to the
+// original function. Any values returned by the callback will be returned as
+// if generated by the driver.
+func (e *ExpectedSyncShards) WillExecute(cb func(ctx context.Context) error) *ExpectedSyncShards {
+	e.callback = cb
+	return e
+}
+
+// WillReturnError sets the error value that will be returned by the call to DB.SyncShards().
+func (e *ExpectedSyncShards) WillReturnError(err error) *ExpectedSyncShards {
+	e.err = err
+	return e
+}
+
+// WillDelay causes the call to DB.SyncShards() to delay.
+func (e *ExpectedSyncShards) WillDelay(delay time.Duration) *ExpectedSyncShards {
+	e.delay = delay
+	return e
+}
+
+func (e *ExpectedSyncShards) met(_ expectation) bool {
+	return true
+}
+
+func (e *ExpectedSyncShards) method(v bool) string {
+	if !v {
+		return "DB.SyncShards()"
+	}
+	return fmt.Sprintf("DB(%s).SyncShards(ctx)", e.dbo().name)
+}
+
 // ExpectedViewCleanup represents an expectation for a call to DB.ViewCleanup().
 type ExpectedViewCleanup struct {
 	commonExpectation
@@ -894,6 +1157,65 @@ func (e *ExpectedDeleteAttachment) method(v bool) string {
 	return fmt.Sprintf("DB(%s).DeleteAttachment(ctx, %s, %s, %s)", e.dbo().name, arg0, arg1, options)
 }
 
+// ExpectedDesignDocInfo represents an expectation for a call to DB.DesignDocInfo().
+type ExpectedDesignDocInfo struct {
+	commonExpectation
+	callback func(ctx context.Context, arg0 string, options driver.Options) (*driver.DesignDocInfo, error)
+	arg0     string
+	ret0     *driver.DesignDocInfo
+}
+
+// WithOptions sets the expected options for the call to DB.DesignDocInfo().
+func (e *ExpectedDesignDocInfo) WithOptions(options ...kivik.Option) *ExpectedDesignDocInfo {
+	e.options = multiOptions{e.options, multiOptions(options)}
+	return e
+}
+
+// WillExecute sets a callback function to be called with any inputs to the
+// original function. Any values returned by the callback will be returned as
+// if generated by the driver.
+func (e *ExpectedDesignDocInfo) WillExecute(cb func(ctx context.Context, arg0 string, options driver.Options) (*driver.DesignDocInfo, error)) *ExpectedDesignDocInfo {
+	e.callback = cb
+	return e
+}
+
+// WillReturn sets the values that will be returned by the call to DB.DesignDocInfo().
+func (e *ExpectedDesignDocInfo) WillReturn(ret0 *driver.DesignDocInfo) *ExpectedDesignDocInfo {
+	e.ret0 = ret0
+	return e
+}
+
+// WillReturnError sets the error value that will be returned by the call to DB.DesignDocInfo().
+func (e *ExpectedDesignDocInfo) WillReturnError(err error) *ExpectedDesignDocInfo {
+	e.err = err
+	return e
+}
+
+// WillDelay causes the call to DB.DesignDocInfo() to delay.
+func (e *ExpectedDesignDocInfo) WillDelay(delay time.Duration) *ExpectedDesignDocInfo {
+	e.delay = delay
+	return e
+}
+
+func (e *ExpectedDesignDocInfo) met(ex expectation) bool {
+	exp := ex.(*ExpectedDesignDocInfo)
+	if exp.arg0 != "" && exp.arg0 != e.arg0 {
+		return false
+	}
+	return true
+}
+
+func (e *ExpectedDesignDocInfo) method(v bool) string {
+	if !v {
+		return "DB.DesignDocInfo()"
+	}
+	arg0, options := "?", formatOptions(e.options)
+	if e.arg0 != "" {
+		arg0 = fmt.Sprintf("%q", e.arg0)
+	}
+	return fmt.Sprintf("DB(%s).DesignDocInfo(ctx, %s, %s)", e.dbo().name, arg0, options)
+}
+
 // ExpectedDesignDocs represents an expectation for a call to DB.DesignDocs().
 type ExpectedDesignDocs struct {
 	commonExpectation
@@ -945,6 +1267,59 @@ func (e *ExpectedDesignDocs) method(v bool) string {
 	return fmt.Sprintf("DB(%s).DesignDocs(ctx, %s)", e.dbo().name, options)
 }
 
+// ExpectedDocShard represents an expectation for a call to DB.DocShard().
+type ExpectedDocShard struct {
+	commonExpectation
+	callback func(ctx context.Context, arg0 string) (*driver.DocShard, error)
+	arg0     string
+	ret0     *driver.DocShard
+}
+
+// WillExecute sets a callback function to be called with any inputs to the
+// original function. Any values returned by the callback will be returned as
+// if generated by the driver.
+func (e *ExpectedDocShard) WillExecute(cb func(ctx context.Context, arg0 string) (*driver.DocShard, error)) *ExpectedDocShard {
+	e.callback = cb
+	return e
+}
+
+// WillReturn sets the values that will be returned by the call to DB.DocShard().
+func (e *ExpectedDocShard) WillReturn(ret0 *driver.DocShard) *ExpectedDocShard {
+	e.ret0 = ret0
+	return e
+}
+
+// WillReturnError sets the error value that will be returned by the call to DB.DocShard().
+func (e *ExpectedDocShard) WillReturnError(err error) *ExpectedDocShard {
+	e.err = err
+	return e
+}
+
+// WillDelay causes the call to DB.DocShard() to delay.
+func (e *ExpectedDocShard) WillDelay(delay time.Duration) *ExpectedDocShard {
+	e.delay = delay
+	return e
+}
+
+func (e *ExpectedDocShard) met(ex expectation) bool {
+	exp := ex.(*ExpectedDocShard)
+	if exp.arg0 != "" && exp.arg0 != e.arg0 {
+		return false
+	}
+	return true
+}
+
+func (e *ExpectedDocShard) method(v bool) string {
+	if !v {
+		return "DB.DocShard()"
+	}
+	arg0 := "?"
+	if e.arg0 != "" {
+		arg0 = fmt.Sprintf("%q", e.arg0)
+	}
+	return fmt.Sprintf("DB(%s).DocShard(ctx, %s)", e.dbo().name, arg0)
+}
+
 // ExpectedExplain represents an expectation for a call to DB.Explain().
 type ExpectedExplain struct {
 	commonExpectation
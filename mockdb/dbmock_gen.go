@@ -90,6 +90,16 @@ func (db *DB) ExpectGetRev() *ExpectedGetRev {
 	return e
 }
 
+// ExpectPurgedInfosLimit queues an expectation that DB.PurgedInfosLimit will be called.
+func (db *DB) ExpectPurgedInfosLimit() *ExpectedPurgedInfosLimit {
+	e := &ExpectedPurgedInfosLimit{
+		commonExpectation: commonExpectation{db: db},
+	}
+	db.count++
+	db.client.expected = append(db.client.expected, e)
+	return e
+}
+
 // ExpectPut queues an expectation that DB.Put will be called.
 func (db *DB) ExpectPut() *ExpectedPut {
 	e := &ExpectedPut{
@@ -100,6 +110,56 @@ func (db *DB) ExpectPut() *ExpectedPut {
 	return e
 }
 
+// ExpectRevsLimit queues an expectation that DB.RevsLimit will be called.
+func (db *DB) ExpectRevsLimit() *ExpectedRevsLimit {
+	e := &ExpectedRevsLimit{
+		commonExpectation: commonExpectation{db: db},
+	}
+	db.count++
+	db.client.expected = append(db.client.expected, e)
+	return e
+}
+
+// ExpectSetPurgedInfosLimit queues an expectation that DB.SetPurgedInfosLimit will be called.
+func (db *DB) ExpectSetPurgedInfosLimit() *ExpectedSetPurgedInfosLimit {
+	e := &ExpectedSetPurgedInfosLimit{
+		commonExpectation: commonExpectation{db: db},
+	}
+	db.count++
+	db.client.expected = append(db.client.expected, e)
+	return e
+}
+
+// ExpectSetRevsLimit queues an expectation that DB.SetRevsLimit will be called.
+func (db *DB) ExpectSetRevsLimit() *ExpectedSetRevsLimit {
+	e := &ExpectedSetRevsLimit{
+		commonExpectation: commonExpectation{db: db},
+	}
+	db.count++
+	db.client.expected = append(db.client.expected, e)
+	return e
+}
+
+// ExpectShards queues an expectation that DB.Shards will be called.
+func (db *DB) ExpectShards() *ExpectedShards {
+	e := &ExpectedShards{
+		commonExpectation: commonExpectation{db: db},
+	}
+	db.count++
+	db.client.expected = append(db.client.expected, e)
+	return e
+}
+
+// ExpectSyncShards queues an expectation that DB.SyncShards will be called.
+func (db *DB) ExpectSyncShards() *ExpectedSyncShards {
+	e := &ExpectedSyncShards{
+		commonExpectation: commonExpectation{db: db},
+	}
+	db.count++
+	db.client.expected = append(db.client.expected, e)
+	return e
+}
+
 // ExpectViewCleanup queues an expectation that DB.ViewCleanup will be called.
 func (db *DB) ExpectViewCleanup() *ExpectedViewCleanup {
 	e := &ExpectedViewCleanup{
@@ -170,6 +230,16 @@ func (db *DB) ExpectDeleteAttachment() *ExpectedDeleteAttachment {
 	return e
 }
 
+// ExpectDesignDocInfo queues an expectation that DB.DesignDocInfo will be called.
+func (db *DB) ExpectDesignDocInfo() *ExpectedDesignDocInfo {
+	e := &ExpectedDesignDocInfo{
+		commonExpectation: commonExpectation{db: db},
+	}
+	db.count++
+	db.client.expected = append(db.client.expected, e)
+	return e
+}
+
 // ExpectDesignDocs queues an expectation that DB.DesignDocs will be called.
 func (db *DB) ExpectDesignDocs() *ExpectedDesignDocs {
 	e := &ExpectedDesignDocs{
@@ -180,6 +250,16 @@ func (db *DB) ExpectDesignDocs() *ExpectedDesignDocs {
 	return e
 }
 
+// ExpectDocShard queues an expectation that DB.DocShard will be called.
+func (db *DB) ExpectDocShard() *ExpectedDocShard {
+	e := &ExpectedDocShard{
+		commonExpectation: commonExpectation{db: db},
+	}
+	db.count++
+	db.client.expected = append(db.client.expected, e)
+	return e
+}
+
 // ExpectExplain queues an expectation that DB.Explain will be called.
 func (db *DB) ExpectExplain() *ExpectedExplain {
 	e := &ExpectedExplain{
@@ -15,6 +15,7 @@ package mockdb
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	kivik "github.com/go-kivik/kivik/v4"
@@ -132,6 +133,187 @@ func (e *ExpectedAllDBsStats) String() string {
 		errorString(e.err)
 }
 
+func (e *ExpectedActiveTasks) String() string {
+	return "call to ActiveTasks() which:" +
+		optionsString(e.options) +
+		delayString(e.delay) +
+		errorString(e.err)
+}
+
+// clientStringer formats the String() output of a client method expectation
+// with the given argument descriptions.
+func clientStringer(methodName string, e *commonExpectation, args ...string) string {
+	extra := strings.Join(args, "") + delayString(e.delay) + errorString(e.err)
+	msg := "call to " + methodName + "()"
+	if extra != "" {
+		msg += " which:" + extra
+	}
+	return msg
+}
+
+func (e *ExpectedReshardSummary) String() string {
+	return clientStringer("ReshardSummary", &e.commonExpectation)
+}
+
+func (e *ExpectedReshardState) String() string {
+	return clientStringer("ReshardState", &e.commonExpectation)
+}
+
+func (e *ExpectedSetReshardState) String() string {
+	return clientStringer("SetReshardState", &e.commonExpectation,
+		fieldString("state", e.arg0),
+		fieldString("reason", e.arg1))
+}
+
+// WithState sets the expected state for the call to SetReshardState().
+func (e *ExpectedSetReshardState) WithState(state string) *ExpectedSetReshardState {
+	e.arg0 = state
+	return e
+}
+
+// WithReason sets the expected reason for the call to SetReshardState().
+func (e *ExpectedSetReshardState) WithReason(reason string) *ExpectedSetReshardState {
+	e.arg1 = reason
+	return e
+}
+
+func (e *ExpectedReshardJobs) String() string {
+	return clientStringer("ReshardJobs", &e.commonExpectation)
+}
+
+func (e *ExpectedReshardJob) String() string {
+	return clientStringer("ReshardJob", &e.commonExpectation, fieldString("jobID", e.arg0))
+}
+
+// WithJobID sets the expected job ID for the call to ReshardJob().
+func (e *ExpectedReshardJob) WithJobID(jobID string) *ExpectedReshardJob {
+	e.arg0 = jobID
+	return e
+}
+
+func (e *ExpectedCreateReshardJobs) String() string {
+	job := "\n\t- has any job"
+	if e.arg0 != nil {
+		job = "\n\t- has job: " + jsonDoc(e.arg0)
+	}
+	return clientStringer("CreateReshardJobs", &e.commonExpectation, job)
+}
+
+// WithJob sets the expected job request for the call to CreateReshardJobs().
+// The Type field is set to [kivik.ReshardTypeSplit] by the client, if left
+// blank.
+func (e *ExpectedCreateReshardJobs) WithJob(job kivik.ReshardJobRequest) *ExpectedCreateReshardJobs {
+	req := driver.ReshardJobRequest(job)
+	e.arg0 = &req
+	return e
+}
+
+func (e *ExpectedDeleteReshardJob) String() string {
+	return clientStringer("DeleteReshardJob", &e.commonExpectation, fieldString("jobID", e.arg0))
+}
+
+// WithJobID sets the expected job ID for the call to DeleteReshardJob().
+func (e *ExpectedDeleteReshardJob) WithJobID(jobID string) *ExpectedDeleteReshardJob {
+	e.arg0 = jobID
+	return e
+}
+
+func (e *ExpectedReshardJobState) String() string {
+	return clientStringer("ReshardJobState", &e.commonExpectation, fieldString("jobID", e.arg0))
+}
+
+// WithJobID sets the expected job ID for the call to ReshardJobState().
+func (e *ExpectedReshardJobState) WithJobID(jobID string) *ExpectedReshardJobState {
+	e.arg0 = jobID
+	return e
+}
+
+func (e *ExpectedSetReshardJobState) String() string {
+	return clientStringer("SetReshardJobState", &e.commonExpectation,
+		fieldString("jobID", e.arg0),
+		fieldString("state", e.arg1),
+		fieldString("reason", e.arg2))
+}
+
+// WithJobID sets the expected job ID for the call to SetReshardJobState().
+func (e *ExpectedSetReshardJobState) WithJobID(jobID string) *ExpectedSetReshardJobState {
+	e.arg0 = jobID
+	return e
+}
+
+// WithState sets the expected state for the call to SetReshardJobState().
+func (e *ExpectedSetReshardJobState) WithState(state string) *ExpectedSetReshardJobState {
+	e.arg1 = state
+	return e
+}
+
+// WithReason sets the expected reason for the call to SetReshardJobState().
+func (e *ExpectedSetReshardJobState) WithReason(reason string) *ExpectedSetReshardJobState {
+	e.arg2 = reason
+	return e
+}
+
+func (e *ExpectedNodeStats) String() string {
+	path := "\n\t- has any path"
+	if e.arg1 != nil {
+		path = "\n\t- has path: " + strings.Join(e.arg1, "/")
+	}
+	return clientStringer("NodeStats", &e.commonExpectation, fieldString("node", e.arg0), path)
+}
+
+// WithNode sets the expected node for the call to NodeStats().
+func (e *ExpectedNodeStats) WithNode(node string) *ExpectedNodeStats {
+	e.arg0 = node
+	return e
+}
+
+// WithPath sets the expected stats path, such as "couchdb", "request_time",
+// for the call to NodeStats().
+func (e *ExpectedNodeStats) WithPath(path ...string) *ExpectedNodeStats {
+	e.arg1 = path
+	return e
+}
+
+func (e *ExpectedNodeSystem) String() string {
+	return clientStringer("NodeSystem", &e.commonExpectation, fieldString("node", e.arg0))
+}
+
+// WithNode sets the expected node for the call to NodeSystem().
+func (e *ExpectedNodeSystem) WithNode(node string) *ExpectedNodeSystem {
+	e.arg0 = node
+	return e
+}
+
+func (e *ExpectedNodeVersions) String() string {
+	return clientStringer("NodeVersions", &e.commonExpectation, fieldString("node", e.arg0))
+}
+
+// WithNode sets the expected node for the call to NodeVersions().
+func (e *ExpectedNodeVersions) WithNode(node string) *ExpectedNodeVersions {
+	e.arg0 = node
+	return e
+}
+
+func (e *ExpectedNodePrometheus) String() string {
+	return clientStringer("NodePrometheus", &e.commonExpectation, fieldString("node", e.arg0))
+}
+
+// WithNode sets the expected node for the call to NodePrometheus().
+func (e *ExpectedNodePrometheus) WithNode(node string) *ExpectedNodePrometheus {
+	e.arg0 = node
+	return e
+}
+
+func (e *ExpectedRestartNode) String() string {
+	return clientStringer("RestartNode", &e.commonExpectation, fieldString("node", e.arg0))
+}
+
+// WithNode sets the expected node for the call to RestartNode().
+func (e *ExpectedRestartNode) WithNode(node string) *ExpectedRestartNode {
+	e.arg0 = node
+	return e
+}
+
 func (e *ExpectedPing) String() string {
 	msg := "call to Ping()"
 	extra := delayString(e.delay) + errorString(e.err)
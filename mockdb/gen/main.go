@@ -29,13 +29,19 @@ var clientSkips = map[string]struct{}{
 }
 
 var dbSkips = map[string]struct{}{
-	"Close":         {},
-	"Client":        {},
-	"Err":           {},
-	"Name":          {},
-	"Search":        {},
-	"SearchAnalyze": {},
-	"SearchInfo":    {},
+	"Close":               {},
+	"Client":              {},
+	"Err":                 {},
+	"Mutate":              {},
+	"Name":                {},
+	"ResolveAllConflicts": {},
+	"ResolveConflicts":    {},
+	"ResolveConflictsIn":  {},
+	"Search":              {},
+	"SearchAnalyze":       {},
+	"SearchInfo":          {},
+	"WarmViews":           {},
+	"Watch":               {},
 }
 
 func main() {
@@ -54,11 +60,14 @@ func main() {
 
 type fullClient interface {
 	driver.Client
+	driver.ActiveTasker
 	driver.DBsStatser
 	driver.AllDBsStatser
 	driver.Pinger
 	driver.Sessioner
 	driver.Cluster
+	driver.Resharder
+	driver.NodeInspector
 	driver.ClientCloser
 	driver.ClientReplicator
 	driver.DBUpdater
@@ -94,6 +103,7 @@ type fullDB interface {
 	driver.BulkGetter
 	driver.Copier
 	driver.DesignDocer
+	driver.DesignDocInfoer
 	driver.Finder
 	driver.Flusher
 	driver.LocalDocer
@@ -103,6 +113,9 @@ type fullDB interface {
 	driver.PartitionedDB
 	driver.SecurityDB
 	driver.OpenRever
+	driver.RevsLimiter
+	driver.PurgedInfosLimiter
+	driver.Sharder
 }
 
 func db() error {
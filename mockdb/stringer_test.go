@@ -342,6 +342,23 @@ func TestAllDBsStatsString(t *testing.T) {
 	tests.Run(t, testStringer)
 }
 
+func TestActiveTasksString(t *testing.T) {
+	tests := testy.NewTable()
+	tests.Add("empty", stringerTest{
+		input: &ExpectedActiveTasks{},
+		expected: `call to ActiveTasks() which:
+	- has any options`,
+	})
+	tests.Add("error", stringerTest{
+		input: &ExpectedActiveTasks{commonExpectation: commonExpectation{err: errors.New("foo err")}},
+		expected: `call to ActiveTasks() which:
+	- has any options
+	- should return error: foo err`,
+	})
+
+	tests.Run(t, testStringer)
+}
+
 func TestPingString(t *testing.T) {
 	tests := testy.NewTable()
 	tests.Add("empty", stringerTest{
@@ -823,6 +840,62 @@ func TestGetRevString(t *testing.T) {
 	tests.Run(t, testStringer)
 }
 
+func TestDesignDocInfoString(t *testing.T) {
+	tests := testy.NewTable()
+	tests.Add("empty", stringerTest{
+		input: &ExpectedDesignDocInfo{commonExpectation: commonExpectation{db: &DB{name: "foo"}}},
+		expected: `call to DB(foo#0).DesignDocInfo() which:
+	- has any ddoc
+	- has any options`,
+	})
+	tests.Add("ddoc", stringerTest{
+		input: &ExpectedDesignDocInfo{commonExpectation: commonExpectation{db: &DB{name: "foo"}}, arg0: "bar"},
+		expected: `call to DB(foo#0).DesignDocInfo() which:
+	- has ddoc: bar
+	- has any options`,
+	})
+	tests.Run(t, testStringer)
+}
+
+func TestRevsLimitString(t *testing.T) {
+	tests := testy.NewTable()
+	tests.Add("empty", stringerTest{
+		input:    &ExpectedRevsLimit{commonExpectation: commonExpectation{db: &DB{name: "foo"}}},
+		expected: `call to DB(foo#0).RevsLimit()`,
+	})
+	tests.Add("return", stringerTest{
+		input: &ExpectedRevsLimit{commonExpectation: commonExpectation{db: &DB{name: "foo"}}, ret0: 10},
+		expected: `call to DB(foo#0).RevsLimit() which:
+	- should return: 10`,
+	})
+	tests.Run(t, testStringer)
+}
+
+func TestSetRevsLimitString(t *testing.T) {
+	tests := testy.NewTable()
+	tests.Add("empty", stringerTest{
+		input: &ExpectedSetRevsLimit{commonExpectation: commonExpectation{db: &DB{name: "foo"}}},
+		expected: `call to DB(foo#0).SetRevsLimit() which:
+	- has any limit`,
+	})
+	tests.Add("limit", stringerTest{
+		input: &ExpectedSetRevsLimit{commonExpectation: commonExpectation{db: &DB{name: "foo"}}, arg0: 10},
+		expected: `call to DB(foo#0).SetRevsLimit() which:
+	- has limit: 10`,
+	})
+	tests.Run(t, testStringer)
+}
+
+func TestSetPurgedInfosLimitString(t *testing.T) {
+	tests := testy.NewTable()
+	tests.Add("limit", stringerTest{
+		input: &ExpectedSetPurgedInfosLimit{commonExpectation: commonExpectation{db: &DB{name: "foo"}}, arg0: 5},
+		expected: `call to DB(foo#0).SetPurgedInfosLimit() which:
+	- has limit: 5`,
+	})
+	tests.Run(t, testStringer)
+}
+
 func TestCompactViewString(t *testing.T) {
 	tests := testy.NewTable()
 	tests.Add("empty", stringerTest{
@@ -1228,7 +1301,7 @@ func TestStatsString(t *testing.T) {
 	tests.Add("return value", stringerTest{
 		input: &ExpectedStats{ret0: &driver.DBStats{Name: "foo"}, commonExpectation: commonExpectation{db: &DB{name: "foo"}}},
 		expected: `call to DB(foo#0).Stats() which:
-	- should return stats: &{foo false 0 0  0 0 0 <nil> []}`,
+	- should return stats: &{foo false 0 0  0 0 0 <nil> false []}`,
 	})
 	tests.Add("error", stringerTest{
 		input: &ExpectedStats{commonExpectation: commonExpectation{db: &DB{name: "foo"}, err: errors.New("foo err")}},
@@ -1546,3 +1619,84 @@ func TestSecurityString(t *testing.T) {
 	})
 	tests.Run(t, testStringer)
 }
+
+func TestDocShardString(t *testing.T) {
+	tests := testy.NewTable()
+	tests.Add("empty", stringerTest{
+		input: &ExpectedDocShard{commonExpectation: commonExpectation{db: &DB{name: "foo"}}},
+		expected: `call to DB(foo#0).DocShard() which:
+	- has any docID`,
+	})
+	tests.Add("doc ID", stringerTest{
+		input: &ExpectedDocShard{commonExpectation: commonExpectation{db: &DB{name: "foo"}}, arg0: "bar"},
+		expected: `call to DB(foo#0).DocShard() which:
+	- has docID: bar`,
+	})
+	tests.Run(t, testStringer)
+}
+
+func TestReshardSummaryString(t *testing.T) {
+	tests := testy.NewTable()
+	tests.Add("empty", stringerTest{
+		input:    &ExpectedReshardSummary{},
+		expected: `call to ReshardSummary()`,
+	})
+	tests.Add("error", stringerTest{
+		input: &ExpectedReshardSummary{commonExpectation: commonExpectation{err: errors.New("foo err")}},
+		expected: `call to ReshardSummary() which:
+	- should return error: foo err`,
+	})
+	tests.Run(t, testStringer)
+}
+
+func TestSetReshardJobStateString(t *testing.T) {
+	tests := testy.NewTable()
+	tests.Add("empty", stringerTest{
+		input: &ExpectedSetReshardJobState{},
+		expected: `call to SetReshardJobState() which:
+	- has any jobID
+	- has any state
+	- has any reason`,
+	})
+	tests.Add("job and state", stringerTest{
+		input: &ExpectedSetReshardJobState{arg0: "001-abc", arg1: "stopped"},
+		expected: `call to SetReshardJobState() which:
+	- has jobID: 001-abc
+	- has state: stopped
+	- has any reason`,
+	})
+	tests.Run(t, testStringer)
+}
+
+func TestNodeStatsString(t *testing.T) {
+	tests := testy.NewTable()
+	tests.Add("empty", stringerTest{
+		input: &ExpectedNodeStats{},
+		expected: `call to NodeStats() which:
+	- has any node
+	- has any path`,
+	})
+	tests.Add("node and path", stringerTest{
+		input: &ExpectedNodeStats{arg0: "_local", arg1: []string{"couchdb", "request_time"}},
+		expected: `call to NodeStats() which:
+	- has node: _local
+	- has path: couchdb/request_time`,
+	})
+	tests.Run(t, testStringer)
+}
+
+func TestRestartNodeString(t *testing.T) {
+	tests := testy.NewTable()
+	tests.Add("empty", stringerTest{
+		input: &ExpectedRestartNode{},
+		expected: `call to RestartNode() which:
+	- has any node`,
+	})
+	tests.Add("error", stringerTest{
+		input: &ExpectedRestartNode{arg0: "node1", commonExpectation: commonExpectation{err: errors.New("foo err")}},
+		expected: `call to RestartNode() which:
+	- has node: node1
+	- should return error: foo err`,
+	})
+	tests.Run(t, testStringer)
+}
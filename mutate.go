@@ -0,0 +1,203 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package kivik
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"time"
+
+	internal "github.com/go-kivik/kivik/v4/int/errors"
+)
+
+type mutator struct {
+	retries    int
+	minBackoff time.Duration
+	maxBackoff time.Duration
+}
+
+type mutateRetriesOption int
+
+func (o mutateRetriesOption) Apply(target interface{}) {
+	if m, ok := target.(*mutator); ok {
+		m.retries = int(o)
+	}
+}
+
+func (o mutateRetriesOption) String() string {
+	return fmt.Sprintf("[mutate_retries=%d]", int(o))
+}
+
+// MutateRetries sets the number of times [DB.Mutate] retries after a
+// conflict. The default is 5.
+func MutateRetries(n int) Option {
+	return mutateRetriesOption(n)
+}
+
+type mutateBackoffOption struct {
+	min, max time.Duration
+}
+
+func (o mutateBackoffOption) Apply(target interface{}) {
+	if m, ok := target.(*mutator); ok {
+		m.minBackoff, m.maxBackoff = o.min, o.max
+	}
+}
+
+func (o mutateBackoffOption) String() string {
+	return fmt.Sprintf("[mutate_backoff=%s-%s]", o.min, o.max)
+}
+
+// MutateBackoff sets the delay before the first retry of [DB.Mutate], which
+// doubles on each subsequent retry, up to max. The default is 50ms, up to 1s.
+func MutateBackoff(min, max time.Duration) Option {
+	return mutateBackoffOption{min: min, max: max}
+}
+
+// Mutate performs an optimistic read-modify-write of the named document. It
+// reads the document into doc, which must be a non-nil pointer, calls fn to
+// modify it, and stores the result. If the document was updated meanwhile,
+// and the write fails with a conflict, the process is repeated with the new
+// version, up to the limit set by [MutateRetries]. The new rev is returned.
+//
+// If the document does not exist, doc is reset to its zero value (or an
+// empty map) before fn is called, and the document is created. If fn returns
+// an error, nothing is written, and the error is returned.
+//
+// If doc is a pointer to a struct, fields tagged as described in [Save] are
+// honored. The revision is taken from the fetched document, or from
+// [DB.GetRev] if the driver doesn't return one.
+//
+// This function supports the [MutateRetries] and [MutateBackoff] options. All
+// other options are passed to [DB.Put].
+func (db *DB) Mutate(ctx context.Context, docID string, doc interface{}, fn func() error, options ...Option) (rev string, err error) {
+	if db.err != nil {
+		return "", db.err
+	}
+	if docID == "" {
+		return "", missingArg("docID")
+	}
+	v := reflect.ValueOf(doc)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return "", &internal.Error{Status: http.StatusBadRequest, Message: fmt.Sprintf("kivik: doc must be a non-nil pointer, got %T", doc)}
+	}
+	var s *structDoc
+	if v.Elem().Kind() == reflect.Struct {
+		if s, err = newStructDoc(doc); err != nil {
+			return "", err
+		}
+	}
+	m := &mutator{
+		retries:    5,
+		minBackoff: 50 * time.Millisecond,
+		maxBackoff: time.Second,
+	}
+	multiOptions(options).Apply(m)
+
+	backoff := m.minBackoff
+	for attempt := 0; ; attempt++ {
+		rev, err = db.mutate(ctx, docID, v, s, fn, options)
+		if HTTPStatus(err) != http.StatusConflict || attempt >= m.retries {
+			return rev, err
+		}
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > m.maxBackoff {
+			backoff = m.maxBackoff
+		}
+	}
+}
+
+// mutate makes a single attempt at a read-modify-write of the document.
+func (db *DB) mutate(ctx context.Context, docID string, v reflect.Value, s *structDoc, fn func() error, options []Option) (string, error) {
+	elem := v.Elem()
+	if elem.Kind() == reflect.Map {
+		elem.Set(reflect.MakeMap(elem.Type()))
+	} else {
+		elem.Set(reflect.Zero(elem.Type()))
+	}
+	var getOpts []Option
+	if s != nil {
+		if _, ok := s.fields[tagConflicts]; ok {
+			getOpts = append(getOpts, Param("conflicts", true))
+		}
+	}
+	rev, err := loadDoc(db.Get(ctx, docID, getOpts...), v.Interface(), s)
+	switch {
+	case HTTPStatus(err) == http.StatusNotFound:
+		rev = ""
+	case err != nil:
+		return "", err
+	case rev == "":
+		if rev, err = db.GetRev(ctx, docID); err != nil {
+			return "", err
+		}
+	}
+	if err := fn(); err != nil {
+		return "", err
+	}
+	var body map[string]interface{}
+	if s != nil {
+		body, err = s.body()
+	} else {
+		body, err = mapBody(v.Interface())
+	}
+	if err != nil {
+		return "", err
+	}
+	body["_id"] = docID
+	delete(body, "_rev")
+	if rev != "" {
+		body["_rev"] = rev
+	}
+	newRev, err := db.Put(ctx, docID, body, options...)
+	if err != nil {
+		return "", err
+	}
+	if s != nil {
+		s.saved(docID, newRev)
+	}
+	return newRev, nil
+}
+
+// mapBody marshals doc, which may also be raw JSON, to a map of its top-level
+// fields.
+func mapBody(doc interface{}) (map[string]interface{}, error) {
+	var data []byte
+	switch t := doc.(type) {
+	case json.RawMessage:
+		data = t
+	case []byte:
+		data = t
+	default:
+		var err error
+		if data, err = json.Marshal(doc); err != nil {
+			return nil, &internal.Error{Status: http.StatusBadRequest, Err: err}
+		}
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, &internal.Error{Status: http.StatusBadRequest, Message: fmt.Sprintf("kivik: document must be a JSON object: %s", err)}
+	}
+	body := make(map[string]interface{}, len(fields)+2)
+	for k, v := range fields {
+		body[k] = v
+	}
+	return body, nil
+}
@@ -0,0 +1,250 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package kivik
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"gitlab.com/flimzy/testy"
+
+	"github.com/go-kivik/kivik/v4/driver"
+	internal "github.com/go-kivik/kivik/v4/int/errors"
+	"github.com/go-kivik/kivik/v4/int/mock"
+)
+
+// mutateStore is a single-document store for testing DB.Mutate.
+type mutateStore struct {
+	gen  int
+	body map[string]interface{}
+	// beforePut, if set, is called before each write.
+	beforePut func(*mutateStore)
+	puts      int
+	// omitRev simulates a driver which doesn't return the rev.
+	omitRev bool
+}
+
+func (s *mutateStore) rev() string {
+	return fmt.Sprintf("%d-x", s.gen)
+}
+
+func (s *mutateStore) update(body map[string]interface{}) {
+	s.gen++
+	s.body = body
+}
+
+func (s *mutateStore) db() *DB {
+	return &DB{
+		client: &Client{},
+		driverDB: &mock.RevGetter{
+			DB: &mock.DB{
+				GetFunc: func(_ context.Context, docID string, _ driver.Options) (*driver.Document, error) {
+					if s.gen == 0 {
+						return nil, &internal.Error{Status: http.StatusNotFound, Message: "missing"}
+					}
+					doc := map[string]interface{}{"_id": docID}
+					for k, v := range s.body {
+						doc[k] = v
+					}
+					rev := s.rev()
+					if s.omitRev {
+						rev = ""
+					} else {
+						doc["_rev"] = rev
+					}
+					data, _ := json.Marshal(doc)
+					return &driver.Document{Rev: rev, Body: io.NopCloser(strings.NewReader(string(data)))}, nil
+				},
+				PutFunc: func(_ context.Context, _ string, doc interface{}, _ driver.Options) (string, error) {
+					s.puts++
+					if s.beforePut != nil {
+						s.beforePut(s)
+					}
+					data, _ := json.Marshal(doc)
+					var body map[string]interface{}
+					_ = json.Unmarshal(data, &body)
+					rev, _ := body["_rev"].(string)
+					if (s.gen == 0 && rev != "") || (s.gen > 0 && rev != s.rev()) {
+						return "", &internal.Error{Status: http.StatusConflict, Message: "Document update conflict."}
+					}
+					delete(body, "_rev")
+					delete(body, "_id")
+					s.update(body)
+					return s.rev(), nil
+				},
+			},
+			GetRevFunc: func(context.Context, string, driver.Options) (string, error) {
+				return s.rev(), nil
+			},
+		},
+	}
+}
+
+func TestDBMutate(t *testing.T) {
+	type counter struct {
+		ID    string `kivik:"id"`
+		Rev   string `kivik:"rev"`
+		Count int    `json:"count"`
+	}
+	type tt struct {
+		store     *mutateStore
+		docID     string
+		doc       interface{}
+		fn        func(doc interface{}) error
+		options   []Option
+		wantRev   string
+		wantDoc   interface{}
+		wantStore map[string]interface{}
+		wantPuts  int
+		status    int
+		err       string
+	}
+	increment := func(doc interface{}) error {
+		switch t := doc.(type) {
+		case *counter:
+			t.Count++
+		case *map[string]interface{}:
+			count, _ := (*t)["count"].(float64)
+			(*t)["count"] = count + 1
+		}
+		return nil
+	}
+
+	tests := testy.NewTable()
+	tests.Add("missing doc ID", tt{
+		store:  &mutateStore{},
+		doc:    &counter{},
+		status: http.StatusBadRequest,
+		err:    "kivik: docID required",
+	})
+	tests.Add("non-pointer", tt{
+		store:  &mutateStore{},
+		docID:  "foo",
+		doc:    counter{},
+		status: http.StatusBadRequest,
+		err:    "kivik: doc must be a non-nil pointer, got kivik.counter",
+	})
+	tests.Add("create struct", tt{
+		store:     &mutateStore{},
+		docID:     "foo",
+		doc:       &counter{Count: 10},
+		fn:        increment,
+		wantRev:   "1-x",
+		wantDoc:   &counter{ID: "foo", Rev: "1-x", Count: 1},
+		wantStore: map[string]interface{}{"count": float64(1)},
+		wantPuts:  1,
+	})
+	tests.Add("update map", tt{
+		store:   &mutateStore{gen: 3, body: map[string]interface{}{"count": float64(5), "name": "bob"}},
+		docID:   "foo",
+		doc:     &map[string]interface{}{"stale": true},
+		fn:      increment,
+		wantRev: "4-x",
+		wantDoc: &map[string]interface{}{
+			"_id":   "foo",
+			"_rev":  "3-x",
+			"count": float64(6),
+			"name":  "bob",
+		},
+		wantStore: map[string]interface{}{"count": float64(6), "name": "bob"},
+		wantPuts:  1,
+	})
+	tests.Add("retry after conflict", func() interface{} {
+		store := &mutateStore{gen: 1, body: map[string]interface{}{"count": float64(1)}}
+		store.beforePut = func(s *mutateStore) {
+			if s.puts == 1 {
+				// A concurrent update
+				s.update(map[string]interface{}{"count": float64(10)})
+			}
+		}
+		return tt{
+			store:     store,
+			docID:     "foo",
+			doc:       &counter{},
+			fn:        increment,
+			options:   []Option{MutateBackoff(time.Millisecond, time.Millisecond)},
+			wantRev:   "3-x",
+			wantDoc:   &counter{ID: "foo", Rev: "3-x", Count: 11},
+			wantStore: map[string]interface{}{"count": float64(11)},
+			wantPuts:  2,
+		}
+	}())
+	tests.Add("retries exhausted", func() interface{} {
+		store := &mutateStore{gen: 1, body: map[string]interface{}{"count": float64(1)}}
+		store.beforePut = func(s *mutateStore) {
+			s.update(map[string]interface{}{"count": float64(s.gen)})
+		}
+		return tt{
+			store:     store,
+			docID:     "foo",
+			doc:       &counter{},
+			fn:        increment,
+			options:   []Option{MutateRetries(2), MutateBackoff(time.Millisecond, time.Millisecond)},
+			wantStore: map[string]interface{}{"count": float64(3)},
+			wantPuts:  3,
+			status:    http.StatusConflict,
+			err:       "Document update conflict.",
+		}
+	}())
+	tests.Add("fn error", tt{
+		store: &mutateStore{gen: 1, body: map[string]interface{}{"count": float64(1)}},
+		docID: "foo",
+		doc:   &counter{},
+		fn: func(interface{}) error {
+			return errors.New("nope")
+		},
+		wantStore: map[string]interface{}{"count": float64(1)},
+		status:    http.StatusInternalServerError,
+		err:       "nope",
+	})
+	tests.Add("rev from GetRev", tt{
+		store:     &mutateStore{gen: 2, body: map[string]interface{}{"count": float64(1)}, omitRev: true},
+		docID:     "foo",
+		doc:       &counter{},
+		fn:        increment,
+		wantRev:   "3-x",
+		wantDoc:   &counter{ID: "foo", Rev: "3-x", Count: 2},
+		wantStore: map[string]interface{}{"count": float64(2)},
+		wantPuts:  1,
+	})
+
+	tests.Run(t, func(t *testing.T, tt tt) {
+		rev, err := tt.store.db().Mutate(context.Background(), tt.docID, tt.doc, func() error {
+			return tt.fn(tt.doc)
+		}, tt.options...)
+		if d := internal.StatusErrorDiff(tt.err, tt.status, err); d != "" {
+			t.Error(d)
+		}
+		if rev != tt.wantRev {
+			t.Errorf("Unexpected rev: %s", rev)
+		}
+		if tt.store.puts != tt.wantPuts {
+			t.Errorf("Unexpected number of writes: %d", tt.store.puts)
+		}
+		if tt.wantDoc != nil {
+			if d := testy.DiffInterface(tt.wantDoc, tt.doc); d != nil {
+				t.Errorf("Unexpected doc:\n%s", d)
+			}
+		}
+		if d := testy.DiffInterface(tt.wantStore, tt.store.body); d != nil {
+			t.Errorf("Unexpected stored doc:\n%s", d)
+		}
+	})
+}
@@ -0,0 +1,344 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package kivik
+
+import (
+	"context"
+	"io"
+
+	"github.com/go-kivik/kivik/v4/driver"
+)
+
+// Statistic types reported in [NodeStat].Type.
+const (
+	StatTypeCounter   = "counter"
+	StatTypeGauge     = "gauge"
+	StatTypeHistogram = "histogram"
+)
+
+// NodeStats is a node in the tree of statistics returned by
+// [Client.NodeStats]. Leaves have Stat set; groups, such as "couchdb" or
+// "couchdb/httpd", have Children set.
+type NodeStats struct {
+	Stat     *NodeStat
+	Children map[string]*NodeStats
+}
+
+// Lookup returns the descendant of s at path, such as
+// Lookup("couchdb", "request_time"), or nil if there is none.
+func (s *NodeStats) Lookup(path ...string) *NodeStats {
+	for _, name := range path {
+		if s == nil {
+			return nil
+		}
+		s = s.Children[name]
+	}
+	return s
+}
+
+// NodeStat is a single statistic.
+type NodeStat struct {
+	// Type is one of [StatTypeCounter], [StatTypeGauge] or
+	// [StatTypeHistogram].
+	Type        string `json:"type"`
+	Description string `json:"desc"`
+	// Value is the value of a counter or gauge.
+	Value float64 `json:"value,omitempty"`
+	// Histogram is the value of a histogram.
+	Histogram *StatsHistogram `json:"histogram,omitempty"`
+}
+
+// StatsHistogram is the value of a histogram statistic. Percentile and
+// Histogram are lists of [key, value] pairs.
+type StatsHistogram struct {
+	Min               float64      `json:"min"`
+	Max               float64      `json:"max"`
+	ArithmeticMean    float64      `json:"arithmetic_mean"`
+	GeometricMean     float64      `json:"geometric_mean"`
+	HarmonicMean      float64      `json:"harmonic_mean"`
+	Median            float64      `json:"median"`
+	Variance          float64      `json:"variance"`
+	StandardDeviation float64      `json:"standard_deviation"`
+	Skewness          float64      `json:"skewness"`
+	Kurtosis          float64      `json:"kurtosis"`
+	Percentile        [][2]float64 `json:"percentile"`
+	Histogram         [][2]float64 `json:"histogram"`
+	N                 int64        `json:"n"`
+}
+
+// NodeSystem contains the Erlang VM statistics returned by
+// [Client.NodeSystem].
+type NodeSystem struct {
+	// Uptime is the node's uptime, in seconds.
+	Uptime                 int64        `json:"uptime"`
+	Memory                 SystemMemory `json:"memory"`
+	RunQueue               int64        `json:"run_queue"`
+	RunQueueDirtyCPU       int64        `json:"run_queue_dirty_cpu"`
+	ETSTableCount          int64        `json:"ets_table_count"`
+	ContextSwitches        int64        `json:"context_switches"`
+	Reductions             int64        `json:"reductions"`
+	GarbageCollectionCount int64        `json:"garbage_collection_count"`
+	WordsReclaimed         int64        `json:"words_reclaimed"`
+	IOInput                int64        `json:"io_input"`
+	IOOutput               int64        `json:"io_output"`
+	OSProcCount            int64        `json:"os_proc_count"`
+	StaleProcCount         int64        `json:"stale_proc_count"`
+	ProcessCount           int64        `json:"process_count"`
+	ProcessLimit           int64        `json:"process_limit"`
+	// MessageQueues maps process, or process pool, names to their message
+	// queue lengths.
+	MessageQueues           map[string]MessageQueue `json:"message_queues"`
+	InternalReplicationJobs int64                   `json:"internal_replication_jobs"`
+	// Distribution maps connected node names to Erlang distribution
+	// statistics, such as "recv_oct" and "send_pend".
+	Distribution map[string]map[string]int64 `json:"distribution"`
+}
+
+// SystemMemory is the Erlang VM memory usage, in bytes.
+type SystemMemory struct {
+	Other         int64 `json:"other"`
+	Atom          int64 `json:"atom"`
+	AtomUsed      int64 `json:"atom_used"`
+	Processes     int64 `json:"processes"`
+	ProcessesUsed int64 `json:"processes_used"`
+	Binary        int64 `json:"binary"`
+	Code          int64 `json:"code"`
+	ETS           int64 `json:"ets"`
+}
+
+// MessageQueue describes the distribution of message queue lengths across a
+// pool of Count processes. A single process is reported as a pool of one.
+type MessageQueue struct {
+	Count  int64 `json:"count"`
+	Min    int64 `json:"min"`
+	Max    int64 `json:"max"`
+	Median int64 `json:"50"`
+	P90    int64 `json:"90"`
+	P99    int64 `json:"99"`
+}
+
+// NodeVersions contains the versions of a node's software components, as
+// returned by [Client.NodeVersions].
+type NodeVersions struct {
+	JavaScriptEngine *JavaScriptEngine `json:"javascript_engine,omitempty"`
+	Erlang           ErlangVersion     `json:"erlang"`
+	CollationDriver  *CollationDriver  `json:"collation_driver,omitempty"`
+}
+
+// JavaScriptEngine identifies the JavaScript engine used by a node.
+type JavaScriptEngine struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// ErlangVersion identifies the Erlang/OTP release used by a node.
+type ErlangVersion struct {
+	Version         string   `json:"version"`
+	SupportedHashes []string `json:"supported_hashes"`
+}
+
+// CollationDriver identifies the collation library used by a node.
+type CollationDriver struct {
+	Name                      string `json:"name"`
+	LibraryVersion            string `json:"library_version"`
+	CollatorVersion           string `json:"collator_version"`
+	CollationAlgorithmVersion string `json:"collation_algorithm_version"`
+}
+
+func (c *Client) nodeInspector() (driver.NodeInspector, error) {
+	if inspector, ok := c.driverClient.(driver.NodeInspector); ok {
+		return inspector, nil
+	}
+	return nil, errNodeNotImplemented
+}
+
+// NodeStats returns the [statistics] of the named node. Use "_local" for the
+// node handling the request. If path is given, only the named group or
+// statistic, such as "couchdb", "request_time", is returned.
+//
+// [statistics]: https://docs.couchdb.org/en/stable/api/server/common.html#node-node-name-stats
+func (c *Client) NodeStats(ctx context.Context, node string, path ...string) (*NodeStats, error) {
+	if node == "" {
+		return nil, missingArg("node")
+	}
+	inspector, err := c.nodeInspector()
+	if err != nil {
+		return nil, err
+	}
+	endQuery, err := c.startQuery()
+	if err != nil {
+		return nil, err
+	}
+	defer endQuery()
+	stats, err := inspector.NodeStats(ctx, node, path)
+	if err != nil {
+		return nil, err
+	}
+	return driverNodeStats2kivik(stats), nil
+}
+
+func driverNodeStats2kivik(stats *driver.NodeStats) *NodeStats {
+	if stats == nil {
+		return nil
+	}
+	result := &NodeStats{}
+	if stats.Stat != nil {
+		result.Stat = &NodeStat{
+			Type:        stats.Stat.Type,
+			Description: stats.Stat.Description,
+			Value:       stats.Stat.Value,
+			Histogram:   (*StatsHistogram)(stats.Stat.Histogram),
+		}
+	}
+	if stats.Children != nil {
+		result.Children = make(map[string]*NodeStats, len(stats.Children))
+		for name, child := range stats.Children {
+			result.Children[name] = driverNodeStats2kivik(child)
+		}
+	}
+	return result
+}
+
+// NodeSystem returns the Erlang VM [system statistics] of the named node,
+// including memory usage, message queues and run queue length. Use "_local"
+// for the node handling the request.
+//
+// [system statistics]: https://docs.couchdb.org/en/stable/api/server/common.html#node-node-name-system
+func (c *Client) NodeSystem(ctx context.Context, node string) (*NodeSystem, error) {
+	if node == "" {
+		return nil, missingArg("node")
+	}
+	inspector, err := c.nodeInspector()
+	if err != nil {
+		return nil, err
+	}
+	endQuery, err := c.startQuery()
+	if err != nil {
+		return nil, err
+	}
+	defer endQuery()
+	sys, err := inspector.NodeSystem(ctx, node)
+	if err != nil {
+		return nil, err
+	}
+	result := &NodeSystem{
+		Uptime:                  sys.Uptime,
+		Memory:                  SystemMemory(sys.Memory),
+		RunQueue:                sys.RunQueue,
+		RunQueueDirtyCPU:        sys.RunQueueDirtyCPU,
+		ETSTableCount:           sys.ETSTableCount,
+		ContextSwitches:         sys.ContextSwitches,
+		Reductions:              sys.Reductions,
+		GarbageCollectionCount:  sys.GarbageCollectionCount,
+		WordsReclaimed:          sys.WordsReclaimed,
+		IOInput:                 sys.IOInput,
+		IOOutput:                sys.IOOutput,
+		OSProcCount:             sys.OSProcCount,
+		StaleProcCount:          sys.StaleProcCount,
+		ProcessCount:            sys.ProcessCount,
+		ProcessLimit:            sys.ProcessLimit,
+		InternalReplicationJobs: sys.InternalReplicationJobs,
+		Distribution:            sys.Distribution,
+	}
+	if sys.MessageQueues != nil {
+		result.MessageQueues = make(map[string]MessageQueue, len(sys.MessageQueues))
+		for name, queue := range sys.MessageQueues {
+			result.MessageQueues[name] = MessageQueue(queue)
+		}
+	}
+	return result, nil
+}
+
+// NodeVersions returns the [versions] of the named node's software
+// components. Use "_local" for the node handling the request.
+//
+// [versions]: https://docs.couchdb.org/en/stable/api/server/common.html#node-node-name-versions
+func (c *Client) NodeVersions(ctx context.Context, node string) (*NodeVersions, error) {
+	if node == "" {
+		return nil, missingArg("node")
+	}
+	inspector, err := c.nodeInspector()
+	if err != nil {
+		return nil, err
+	}
+	endQuery, err := c.startQuery()
+	if err != nil {
+		return nil, err
+	}
+	defer endQuery()
+	versions, err := inspector.NodeVersions(ctx, node)
+	if err != nil {
+		return nil, err
+	}
+	return &NodeVersions{
+		JavaScriptEngine: (*JavaScriptEngine)(versions.JavaScriptEngine),
+		Erlang:           ErlangVersion(versions.Erlang),
+		CollationDriver:  (*CollationDriver)(versions.CollationDriver),
+	}, nil
+}
+
+// NodePrometheus returns the statistics of the named node in the [Prometheus]
+// text exposition format. Use "_local" for the node handling the request.
+// The caller must close the returned reader.
+//
+// [Prometheus]: https://docs.couchdb.org/en/stable/api/server/common.html#node-node-name-prometheus
+func (c *Client) NodePrometheus(ctx context.Context, node string) (io.ReadCloser, error) {
+	if node == "" {
+		return nil, missingArg("node")
+	}
+	inspector, err := c.nodeInspector()
+	if err != nil {
+		return nil, err
+	}
+	endQuery, err := c.startQuery()
+	if err != nil {
+		return nil, err
+	}
+	body, err := inspector.NodePrometheus(ctx, node)
+	if err != nil {
+		endQuery()
+		return nil, err
+	}
+	return &queryCloser{ReadCloser: body, endQuery: endQuery}, nil
+}
+
+// queryCloser wraps a response body, to end the query when it is closed.
+type queryCloser struct {
+	io.ReadCloser
+	endQuery func()
+}
+
+func (c *queryCloser) Close() error {
+	defer c.endQuery()
+	return c.ReadCloser.Close()
+}
+
+// RestartNode [restarts] the named node. Use "_local" for the node handling
+// the request.
+//
+// [restarts]: https://docs.couchdb.org/en/stable/api/server/common.html#node-node-name-restart
+func (c *Client) RestartNode(ctx context.Context, node string) error {
+	if node == "" {
+		return missingArg("node")
+	}
+	inspector, err := c.nodeInspector()
+	if err != nil {
+		return err
+	}
+	endQuery, err := c.startQuery()
+	if err != nil {
+		return err
+	}
+	defer endQuery()
+	return inspector.RestartNode(ctx, node)
+}
@@ -0,0 +1,313 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package kivik
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"gitlab.com/flimzy/testy"
+
+	"github.com/go-kivik/kivik/v4/driver"
+	internal "github.com/go-kivik/kivik/v4/int/errors"
+	"github.com/go-kivik/kivik/v4/int/mock"
+)
+
+func TestNodeStats(t *testing.T) {
+	type tt struct {
+		client driver.Client
+		closed bool
+		node   string
+		path   []string
+		want   *NodeStats
+		status int
+		err    string
+	}
+	tests := testy.NewTable()
+	tests.Add("missing node", tt{
+		client: &mock.NodeInspector{},
+		status: http.StatusBadRequest,
+		err:    "kivik: node required",
+	})
+	tests.Add("not supported", tt{
+		client: &mock.Client{},
+		node:   "_local",
+		status: http.StatusNotImplemented,
+		err:    "kivik: driver does not support node introspection",
+	})
+	tests.Add("client closed", tt{
+		client: &mock.NodeInspector{},
+		closed: true,
+		node:   "_local",
+		status: http.StatusServiceUnavailable,
+		err:    "kivik: client closed",
+	})
+	tests.Add("error", tt{
+		client: &mock.NodeInspector{
+			NodeStatsFunc: func(context.Context, string, []string) (*driver.NodeStats, error) {
+				return nil, errors.New("stats failed")
+			},
+		},
+		node:   "_local",
+		status: http.StatusInternalServerError,
+		err:    "stats failed",
+	})
+	tests.Add("success", tt{
+		client: &mock.NodeInspector{
+			NodeStatsFunc: func(_ context.Context, node string, path []string) (*driver.NodeStats, error) {
+				if node != "node1" || len(path) != 1 || path[0] != "couchdb" {
+					return nil, errors.New("unexpected arguments")
+				}
+				return &driver.NodeStats{
+					Children: map[string]*driver.NodeStats{
+						"open_databases": {Stat: &driver.NodeStat{Type: "counter", Description: "number of open databases", Value: 5}},
+						"request_time": {Stat: &driver.NodeStat{
+							Type:      "histogram",
+							Histogram: &driver.StatsHistogram{Max: 10, N: 2, Percentile: [][2]float64{{50, 4}}},
+						}},
+					},
+				}, nil
+			},
+		},
+		node: "node1",
+		path: []string{"couchdb"},
+		want: &NodeStats{
+			Children: map[string]*NodeStats{
+				"open_databases": {Stat: &NodeStat{Type: StatTypeCounter, Description: "number of open databases", Value: 5}},
+				"request_time": {Stat: &NodeStat{
+					Type:      StatTypeHistogram,
+					Histogram: &StatsHistogram{Max: 10, N: 2, Percentile: [][2]float64{{50, 4}}},
+				}},
+			},
+		},
+	})
+
+	tests.Run(t, func(t *testing.T, tt tt) {
+		c := &Client{driverClient: tt.client, closed: tt.closed}
+		got, err := c.NodeStats(context.Background(), tt.node, tt.path...)
+		if d := internal.StatusErrorDiff(tt.err, tt.status, err); d != "" {
+			t.Error(d)
+		}
+		if d := testy.DiffInterface(tt.want, got); d != nil {
+			t.Error(d)
+		}
+	})
+}
+
+func TestNodeStatsLookup(t *testing.T) {
+	stats := &NodeStats{
+		Children: map[string]*NodeStats{
+			"couchdb": {Children: map[string]*NodeStats{
+				"open_databases": {Stat: &NodeStat{Type: StatTypeCounter, Value: 5}},
+			}},
+		},
+	}
+	if got := stats.Lookup("couchdb", "open_databases"); got == nil || got.Stat.Value != 5 {
+		t.Errorf("Unexpected result: %v", got)
+	}
+	if got := stats.Lookup("couchdb", "open_databases", "foo"); got != nil {
+		t.Errorf("Expected nil for path beyond a leaf, got %v", got)
+	}
+	if got := stats.Lookup("mem3"); got != nil {
+		t.Errorf("Expected nil for missing group, got %v", got)
+	}
+	if got := stats.Lookup(); got != stats {
+		t.Errorf("Expected empty path to return the receiver")
+	}
+}
+
+func TestNodeSystem(t *testing.T) {
+	type tt struct {
+		client driver.Client
+		node   string
+		want   *NodeSystem
+		status int
+		err    string
+	}
+	tests := testy.NewTable()
+	tests.Add("missing node", tt{
+		client: &mock.NodeInspector{},
+		status: http.StatusBadRequest,
+		err:    "kivik: node required",
+	})
+	tests.Add("not supported", tt{
+		client: &mock.Client{},
+		node:   "_local",
+		status: http.StatusNotImplemented,
+		err:    "kivik: driver does not support node introspection",
+	})
+	tests.Add("success", tt{
+		client: &mock.NodeInspector{
+			NodeSystemFunc: func(context.Context, string) (*driver.NodeSystem, error) {
+				return &driver.NodeSystem{
+					Uptime:        259,
+					Memory:        driver.SystemMemory{Processes: 1024, Binary: 512},
+					RunQueue:      1,
+					MessageQueues: map[string]driver.MessageQueue{"couch_server": {Count: 1, Max: 3}},
+					Distribution:  map[string]map[string]int64{"node2": {"send_pend": 0}},
+				}, nil
+			},
+		},
+		node: "_local",
+		want: &NodeSystem{
+			Uptime:        259,
+			Memory:        SystemMemory{Processes: 1024, Binary: 512},
+			RunQueue:      1,
+			MessageQueues: map[string]MessageQueue{"couch_server": {Count: 1, Max: 3}},
+			Distribution:  map[string]map[string]int64{"node2": {"send_pend": 0}},
+		},
+	})
+
+	tests.Run(t, func(t *testing.T, tt tt) {
+		c := &Client{driverClient: tt.client}
+		got, err := c.NodeSystem(context.Background(), tt.node)
+		if d := internal.StatusErrorDiff(tt.err, tt.status, err); d != "" {
+			t.Error(d)
+		}
+		if d := testy.DiffInterface(tt.want, got); d != nil {
+			t.Error(d)
+		}
+	})
+}
+
+func TestNodeVersions(t *testing.T) {
+	type tt struct {
+		client driver.Client
+		node   string
+		want   *NodeVersions
+		status int
+		err    string
+	}
+	tests := testy.NewTable()
+	tests.Add("not supported", tt{
+		client: &mock.Client{},
+		node:   "_local",
+		status: http.StatusNotImplemented,
+		err:    "kivik: driver does not support node introspection",
+	})
+	tests.Add("success", tt{
+		client: &mock.NodeInspector{
+			NodeVersionsFunc: func(context.Context, string) (*driver.NodeVersions, error) {
+				return &driver.NodeVersions{
+					JavaScriptEngine: &driver.JavaScriptEngine{Name: "spidermonkey", Version: "91"},
+					Erlang:           driver.ErlangVersion{Version: "26.2.1", SupportedHashes: []string{"sha"}},
+				}, nil
+			},
+		},
+		node: "_local",
+		want: &NodeVersions{
+			JavaScriptEngine: &JavaScriptEngine{Name: "spidermonkey", Version: "91"},
+			Erlang:           ErlangVersion{Version: "26.2.1", SupportedHashes: []string{"sha"}},
+		},
+	})
+
+	tests.Run(t, func(t *testing.T, tt tt) {
+		c := &Client{driverClient: tt.client}
+		got, err := c.NodeVersions(context.Background(), tt.node)
+		if d := internal.StatusErrorDiff(tt.err, tt.status, err); d != "" {
+			t.Error(d)
+		}
+		if d := testy.DiffInterface(tt.want, got); d != nil {
+			t.Error(d)
+		}
+	})
+}
+
+func TestNodePrometheus(t *testing.T) {
+	type tt struct {
+		client driver.Client
+		node   string
+		want   string
+		status int
+		err    string
+	}
+	tests := testy.NewTable()
+	tests.Add("not supported", tt{
+		client: &mock.Client{},
+		node:   "_local",
+		status: http.StatusNotImplemented,
+		err:    "kivik: driver does not support node introspection",
+	})
+	tests.Add("success", tt{
+		client: &mock.NodeInspector{
+			NodePrometheusFunc: func(context.Context, string) (io.ReadCloser, error) {
+				return io.NopCloser(strings.NewReader("couchdb_uptime_seconds 259\n")), nil
+			},
+		},
+		node: "_local",
+		want: "couchdb_uptime_seconds 259\n",
+	})
+
+	tests.Run(t, func(t *testing.T, tt tt) {
+		c := &Client{driverClient: tt.client}
+		body, err := c.NodePrometheus(context.Background(), tt.node)
+		if d := internal.StatusErrorDiff(tt.err, tt.status, err); d != "" {
+			t.Error(d)
+		}
+		if err != nil {
+			return
+		}
+		defer body.Close()
+		got, err := io.ReadAll(body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != tt.want {
+			t.Errorf("Unexpected result: %s", got)
+		}
+	})
+}
+
+func TestRestartNode(t *testing.T) {
+	type tt struct {
+		client driver.Client
+		node   string
+		status int
+		err    string
+	}
+	tests := testy.NewTable()
+	tests.Add("missing node", tt{
+		client: &mock.NodeInspector{},
+		status: http.StatusBadRequest,
+		err:    "kivik: node required",
+	})
+	tests.Add("not supported", tt{
+		client: &mock.Client{},
+		node:   "_local",
+		status: http.StatusNotImplemented,
+		err:    "kivik: driver does not support node introspection",
+	})
+	tests.Add("success", tt{
+		client: &mock.NodeInspector{
+			RestartNodeFunc: func(_ context.Context, node string) error {
+				if node != "node1" {
+					return errors.New("unexpected node")
+				}
+				return nil
+			},
+		},
+		node: "node1",
+	})
+
+	tests.Run(t, func(t *testing.T, tt tt) {
+		c := &Client{driverClient: tt.client}
+		err := c.RestartNode(context.Background(), tt.node)
+		if d := internal.StatusErrorDiff(tt.err, tt.status, err); d != "" {
+			t.Error(d)
+		}
+	})
+}
@@ -0,0 +1,56 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package kivik
+
+import (
+	"fmt"
+
+	"github.com/go-kivik/kivik/v4/driver"
+)
+
+// Partitioned is an option for [Client.CreateDB] which creates a
+// [partitioned database]. A less verbose alternative to
+// Param("partitioned", true).
+//
+// Every document in a partitioned database, other than design and local
+// documents, must have an ID of the form "partition:docid".
+//
+// [partitioned database]: https://docs.couchdb.org/en/stable/partitioned-dbs/index.html
+func Partitioned() Option {
+	return params{"partitioned": true}
+}
+
+type partitionOption string
+
+var _ Option = partitionOption("")
+
+// Apply supports *[driver.Partition] targets.
+func (o partitionOption) Apply(target interface{}) {
+	if p, ok := target.(*driver.Partition); ok {
+		p.Name = string(o)
+	}
+}
+
+func (o partitionOption) String() string {
+	return fmt.Sprintf("[partition:%s]", string(o))
+}
+
+// Partition limits [DB.AllDocs], [DB.Query], [DB.Find] and [DB.Explain] to
+// the named partition of a partitioned database.
+//
+// See the [CouchDB documentation].
+//
+// [CouchDB documentation]: https://docs.couchdb.org/en/stable/api/partitioned-dbs.html
+func Partition(name string) Option {
+	return partitionOption(name)
+}
@@ -0,0 +1,45 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package kivik
+
+import (
+	"testing"
+
+	"gitlab.com/flimzy/testy"
+
+	"github.com/go-kivik/kivik/v4/driver"
+)
+
+func TestPartitionOption(t *testing.T) {
+	opt := Partition("sensor-1")
+	part := &driver.Partition{}
+	opt.Apply(part)
+	if part.Name != "sensor-1" {
+		t.Errorf("Unexpected partition: %q", part.Name)
+	}
+
+	params := map[string]interface{}{}
+	opt.Apply(params)
+	if len(params) != 0 {
+		t.Errorf("Partition should not set query parameters, got %v", params)
+	}
+}
+
+func TestPartitioned(t *testing.T) {
+	params := map[string]interface{}{}
+	Partitioned().Apply(params)
+	want := map[string]interface{}{"partitioned": true}
+	if d := testy.DiffInterface(want, params); d != nil {
+		t.Error(d)
+	}
+}
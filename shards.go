@@ -0,0 +1,367 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package kivik
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kivik/kivik/v4/driver"
+)
+
+// DocShard is the shard range, and the nodes holding it, to which a document
+// ID maps, as returned by [DB.DocShard].
+type DocShard struct {
+	Range string   `json:"range"`
+	Nodes []string `json:"nodes"`
+}
+
+// Shards returns the database's [shard map], as a map of shard ranges, such
+// as "00000000-7fffffff", to the nodes holding a replica of each.
+//
+// [shard map]: https://docs.couchdb.org/en/stable/api/database/shard.html#get--db-_shards
+func (db *DB) Shards(ctx context.Context) (map[string][]string, error) {
+	if db.err != nil {
+		return nil, db.err
+	}
+	sharder, ok := db.driverDB.(driver.Sharder)
+	if !ok {
+		return nil, errShardsNotImplemented
+	}
+	endQuery, err := db.startQuery()
+	if err != nil {
+		return nil, err
+	}
+	defer endQuery()
+	return sharder.Shards(ctx)
+}
+
+// DocShard returns the [shard] to which docID maps. The document need not
+// exist.
+//
+// [shard]: https://docs.couchdb.org/en/stable/api/database/shard.html#get--db-_shards-docid
+func (db *DB) DocShard(ctx context.Context, docID string) (*DocShard, error) {
+	if db.err != nil {
+		return nil, db.err
+	}
+	if docID == "" {
+		return nil, missingArg("docID")
+	}
+	sharder, ok := db.driverDB.(driver.Sharder)
+	if !ok {
+		return nil, errShardsNotImplemented
+	}
+	endQuery, err := db.startQuery()
+	if err != nil {
+		return nil, err
+	}
+	defer endQuery()
+	shard, err := sharder.DocShard(ctx, docID)
+	return (*DocShard)(shard), err
+}
+
+// SyncShards forces [synchronization] of all replicas of all of the
+// database's shards. This is normally only needed after shards have been
+// moved between nodes.
+//
+// [synchronization]: https://docs.couchdb.org/en/stable/api/database/shard.html#post--db-_sync_shards
+func (db *DB) SyncShards(ctx context.Context) error {
+	if db.err != nil {
+		return db.err
+	}
+	sharder, ok := db.driverDB.(driver.Sharder)
+	if !ok {
+		return errShardsNotImplemented
+	}
+	endQuery, err := db.startQuery()
+	if err != nil {
+		return err
+	}
+	defer endQuery()
+	return sharder.SyncShards(ctx)
+}
+
+// Resharding states, as used by [Client.SetReshardState] and
+// [Client.SetReshardJobState].
+const (
+	ReshardRunning = "running"
+	ReshardStopped = "stopped"
+)
+
+// ReshardTypeSplit is the only resharding job type currently supported by
+// CouchDB.
+const ReshardTypeSplit = "split"
+
+// ReshardSummary summarizes shard splitting across the cluster, as returned
+// by [Client.ReshardSummary].
+type ReshardSummary struct {
+	// State is the cluster-wide state, either [ReshardRunning] or
+	// [ReshardStopped].
+	State string `json:"state"`
+	// StateReason is the reason given when the state was last set.
+	StateReason string `json:"state_reason"`
+	Completed   int    `json:"completed"`
+	Failed      int    `json:"failed"`
+	Running     int    `json:"running"`
+	Stopped     int    `json:"stopped"`
+	Total       int    `json:"total"`
+}
+
+// ReshardState is the state of resharding, either cluster-wide or for a single
+// job.
+type ReshardState struct {
+	// State is one of [ReshardRunning] or [ReshardStopped].
+	State  string `json:"state"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// ReshardEvent is a single entry in a [ReshardJob]'s history.
+type ReshardEvent struct {
+	Timestamp time.Time `json:"timestamp"`
+	Type      string    `json:"type"`
+	Detail    string    `json:"detail"`
+}
+
+// ReshardJob is a single resharding job.
+type ReshardJob struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	// Node is the node on which the source shard resides.
+	Node string `json:"node"`
+	// Source is the shard being split.
+	Source string `json:"source"`
+	// Targets are the shards being created.
+	Targets []string `json:"target"`
+	// JobState is the job's running state, such as "running", "stopped",
+	// "completed" or "failed".
+	JobState string `json:"job_state"`
+	// SplitState is the job's progress through the split, such as
+	// "initial_copy" or "completed".
+	SplitState string                 `json:"split_state"`
+	StateInfo  map[string]interface{} `json:"state_info,omitempty"`
+	StartTime  time.Time              `json:"start_time"`
+	UpdateTime time.Time              `json:"update_time"`
+	History    []ReshardEvent         `json:"history"`
+}
+
+// ReshardJobRequest describes the resharding jobs to create with
+// [Client.CreateReshardJobs]. Either DB or Shard must be set, to select the
+// shards to split. A DB may be further restricted by Node and Range.
+type ReshardJobRequest struct {
+	// Type defaults to [ReshardTypeSplit].
+	Type  string `json:"type"`
+	DB    string `json:"db,omitempty"`
+	Node  string `json:"node,omitempty"`
+	Range string `json:"range,omitempty"`
+	Shard string `json:"shard,omitempty"`
+}
+
+// ReshardJobResult is the result of creating a single resharding job. If
+// creation failed, Error is set and ID is empty.
+type ReshardJobResult struct {
+	ID    string
+	Node  string
+	Shard string
+	Error error
+}
+
+func (c *Client) resharder() (driver.Resharder, func(), error) {
+	endQuery, err := c.startQuery()
+	if err != nil {
+		return nil, nil, err
+	}
+	resharder, ok := c.driverClient.(driver.Resharder)
+	if !ok {
+		endQuery()
+		return nil, nil, errReshardNotImplemented
+	}
+	return resharder, endQuery, nil
+}
+
+// ReshardSummary returns a [summary] of shard splitting across the cluster.
+//
+// [summary]: https://docs.couchdb.org/en/stable/api/server/common.html#get--_reshard
+func (c *Client) ReshardSummary(ctx context.Context) (*ReshardSummary, error) {
+	resharder, endQuery, err := c.resharder()
+	if err != nil {
+		return nil, err
+	}
+	defer endQuery()
+	summary, err := resharder.ReshardSummary(ctx)
+	return (*ReshardSummary)(summary), err
+}
+
+// ReshardState returns the cluster-wide [resharding state].
+//
+// [resharding state]: https://docs.couchdb.org/en/stable/api/server/common.html#get--_reshard-state
+func (c *Client) ReshardState(ctx context.Context) (*ReshardState, error) {
+	resharder, endQuery, err := c.resharder()
+	if err != nil {
+		return nil, err
+	}
+	defer endQuery()
+	state, err := resharder.ReshardState(ctx)
+	return (*ReshardState)(state), err
+}
+
+// SetReshardState starts or stops resharding across the cluster. state must
+// be one of [ReshardRunning] or [ReshardStopped]. reason is optional, and is
+// reported by [Client.ReshardSummary].
+func (c *Client) SetReshardState(ctx context.Context, state, reason string) error {
+	if state == "" {
+		return missingArg("state")
+	}
+	resharder, endQuery, err := c.resharder()
+	if err != nil {
+		return err
+	}
+	defer endQuery()
+	return resharder.SetReshardState(ctx, state, reason)
+}
+
+// ReshardJobs returns all [resharding jobs], including completed and failed
+// ones.
+//
+// [resharding jobs]: https://docs.couchdb.org/en/stable/api/server/common.html#get--_reshard-jobs
+func (c *Client) ReshardJobs(ctx context.Context) ([]*ReshardJob, error) {
+	resharder, endQuery, err := c.resharder()
+	if err != nil {
+		return nil, err
+	}
+	defer endQuery()
+	jobs, err := resharder.ReshardJobs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*ReshardJob, len(jobs))
+	for i, job := range jobs {
+		result[i] = driverJob2kivikJob(job)
+	}
+	return result, nil
+}
+
+// ReshardJob returns a single resharding job.
+func (c *Client) ReshardJob(ctx context.Context, jobID string) (*ReshardJob, error) {
+	if jobID == "" {
+		return nil, missingArg("jobID")
+	}
+	resharder, endQuery, err := c.resharder()
+	if err != nil {
+		return nil, err
+	}
+	defer endQuery()
+	job, err := resharder.ReshardJob(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	return driverJob2kivikJob(job), nil
+}
+
+func driverJob2kivikJob(job *driver.ReshardJob) *ReshardJob {
+	if job == nil {
+		return nil
+	}
+	var history []ReshardEvent
+	if job.History != nil {
+		history = make([]ReshardEvent, len(job.History))
+		for i, event := range job.History {
+			history[i] = ReshardEvent(event)
+		}
+	}
+	return &ReshardJob{
+		ID:         job.ID,
+		Type:       job.Type,
+		Node:       job.Node,
+		Source:     job.Source,
+		Targets:    job.Targets,
+		JobState:   job.JobState,
+		SplitState: job.SplitState,
+		StateInfo:  job.StateInfo,
+		StartTime:  job.StartTime,
+		UpdateTime: job.UpdateTime,
+		History:    history,
+	}
+}
+
+// CreateReshardJobs [creates] resharding jobs, one for each shard selected by
+// job, and returns the result for each. A failure to create an individual job
+// is reported in its result, rather than as an error.
+//
+// [creates]: https://docs.couchdb.org/en/stable/api/server/common.html#post--_reshard-jobs
+func (c *Client) CreateReshardJobs(ctx context.Context, job ReshardJobRequest) ([]*ReshardJobResult, error) {
+	if job.DB == "" && job.Shard == "" {
+		return nil, missingArg("db or shard")
+	}
+	if job.Type == "" {
+		job.Type = ReshardTypeSplit
+	}
+	resharder, endQuery, err := c.resharder()
+	if err != nil {
+		return nil, err
+	}
+	defer endQuery()
+	req := driver.ReshardJobRequest(job)
+	results, err := resharder.CreateReshardJobs(ctx, &req)
+	if err != nil {
+		return nil, err
+	}
+	kresults := make([]*ReshardJobResult, len(results))
+	for i, result := range results {
+		kresults[i] = (*ReshardJobResult)(result)
+	}
+	return kresults, nil
+}
+
+// DeleteReshardJob stops and removes a resharding job.
+func (c *Client) DeleteReshardJob(ctx context.Context, jobID string) error {
+	if jobID == "" {
+		return missingArg("jobID")
+	}
+	resharder, endQuery, err := c.resharder()
+	if err != nil {
+		return err
+	}
+	defer endQuery()
+	return resharder.DeleteReshardJob(ctx, jobID)
+}
+
+// ReshardJobState returns the state of a single resharding job.
+func (c *Client) ReshardJobState(ctx context.Context, jobID string) (*ReshardState, error) {
+	if jobID == "" {
+		return nil, missingArg("jobID")
+	}
+	resharder, endQuery, err := c.resharder()
+	if err != nil {
+		return nil, err
+	}
+	defer endQuery()
+	state, err := resharder.ReshardJobState(ctx, jobID)
+	return (*ReshardState)(state), err
+}
+
+// SetReshardJobState starts or stops a single resharding job. state must be
+// one of [ReshardRunning] or [ReshardStopped]. reason is optional.
+func (c *Client) SetReshardJobState(ctx context.Context, jobID, state, reason string) error {
+	if jobID == "" {
+		return missingArg("jobID")
+	}
+	if state == "" {
+		return missingArg("state")
+	}
+	resharder, endQuery, err := c.resharder()
+	if err != nil {
+		return err
+	}
+	defer endQuery()
+	return resharder.SetReshardJobState(ctx, jobID, state, reason)
+}
@@ -0,0 +1,367 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package kivik
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"gitlab.com/flimzy/testy"
+
+	"github.com/go-kivik/kivik/v4/driver"
+	internal "github.com/go-kivik/kivik/v4/int/errors"
+	"github.com/go-kivik/kivik/v4/int/mock"
+)
+
+func TestShards(t *testing.T) {
+	type tt struct {
+		db     *DB
+		want   map[string][]string
+		status int
+		err    string
+	}
+	tests := testy.NewTable()
+	tests.Add("db error", tt{
+		db:     &DB{err: errors.New("db error")},
+		status: http.StatusInternalServerError,
+		err:    "db error",
+	})
+	tests.Add("not supported", tt{
+		db:     &DB{client: &Client{}, driverDB: &mock.DB{}},
+		status: http.StatusNotImplemented,
+		err:    "kivik: driver does not support Shards interface",
+	})
+	tests.Add("success", tt{
+		db: &DB{
+			client: &Client{},
+			driverDB: &mock.Sharder{
+				ShardsFunc: func(context.Context) (map[string][]string, error) {
+					return map[string][]string{"00000000-ffffffff": {"node1"}}, nil
+				},
+			},
+		},
+		want: map[string][]string{"00000000-ffffffff": {"node1"}},
+	})
+
+	tests.Run(t, func(t *testing.T, tt tt) {
+		got, err := tt.db.Shards(context.Background())
+		if d := internal.StatusErrorDiff(tt.err, tt.status, err); d != "" {
+			t.Error(d)
+		}
+		if d := testy.DiffInterface(tt.want, got); d != nil {
+			t.Error(d)
+		}
+	})
+}
+
+func TestDocShard(t *testing.T) {
+	type tt struct {
+		db     *DB
+		docID  string
+		want   *DocShard
+		status int
+		err    string
+	}
+	tests := testy.NewTable()
+	tests.Add("missing doc ID", tt{
+		db:     &DB{client: &Client{}, driverDB: &mock.Sharder{}},
+		status: http.StatusBadRequest,
+		err:    "kivik: docID required",
+	})
+	tests.Add("not supported", tt{
+		db:     &DB{client: &Client{}, driverDB: &mock.DB{}},
+		docID:  "foo",
+		status: http.StatusNotImplemented,
+		err:    "kivik: driver does not support Shards interface",
+	})
+	tests.Add("driver error", tt{
+		db: &DB{
+			client: &Client{},
+			driverDB: &mock.Sharder{
+				DocShardFunc: func(context.Context, string) (*driver.DocShard, error) {
+					return nil, &internal.Error{Status: http.StatusNotFound, Message: "missing"}
+				},
+			},
+		},
+		docID:  "foo",
+		status: http.StatusNotFound,
+		err:    "missing",
+	})
+	tests.Add("success", tt{
+		db: &DB{
+			client: &Client{},
+			driverDB: &mock.Sharder{
+				DocShardFunc: func(_ context.Context, docID string) (*driver.DocShard, error) {
+					if docID != "foo" {
+						return nil, errors.New("unexpected doc ID")
+					}
+					return &driver.DocShard{Range: "e0000000-ffffffff", Nodes: []string{"node1", "node2"}}, nil
+				},
+			},
+		},
+		docID: "foo",
+		want:  &DocShard{Range: "e0000000-ffffffff", Nodes: []string{"node1", "node2"}},
+	})
+
+	tests.Run(t, func(t *testing.T, tt tt) {
+		got, err := tt.db.DocShard(context.Background(), tt.docID)
+		if d := internal.StatusErrorDiff(tt.err, tt.status, err); d != "" {
+			t.Error(d)
+		}
+		if d := testy.DiffInterface(tt.want, got); d != nil {
+			t.Error(d)
+		}
+	})
+}
+
+func TestSyncShards(t *testing.T) {
+	type tt struct {
+		db     *DB
+		status int
+		err    string
+	}
+	tests := testy.NewTable()
+	tests.Add("not supported", tt{
+		db:     &DB{client: &Client{}, driverDB: &mock.DB{}},
+		status: http.StatusNotImplemented,
+		err:    "kivik: driver does not support Shards interface",
+	})
+	tests.Add("success", tt{
+		db: &DB{
+			client: &Client{},
+			driverDB: &mock.Sharder{
+				SyncShardsFunc: func(context.Context) error {
+					return nil
+				},
+			},
+		},
+	})
+
+	tests.Run(t, func(t *testing.T, tt tt) {
+		err := tt.db.SyncShards(context.Background())
+		if d := internal.StatusErrorDiff(tt.err, tt.status, err); d != "" {
+			t.Error(d)
+		}
+	})
+}
+
+func TestReshardSummary(t *testing.T) {
+	type tt struct {
+		client driver.Client
+		closed bool
+		want   *ReshardSummary
+		status int
+		err    string
+	}
+	tests := testy.NewTable()
+	tests.Add("not supported", tt{
+		client: &mock.Client{},
+		status: http.StatusNotImplemented,
+		err:    "kivik: driver does not support resharding",
+	})
+	tests.Add("client closed", tt{
+		client: &mock.Resharder{},
+		closed: true,
+		status: http.StatusServiceUnavailable,
+		err:    "kivik: client closed",
+	})
+	tests.Add("success", tt{
+		client: &mock.Resharder{
+			ReshardSummaryFunc: func(context.Context) (*driver.ReshardSummary, error) {
+				return &driver.ReshardSummary{State: "running", Completed: 1, Running: 2, Total: 3}, nil
+			},
+		},
+		want: &ReshardSummary{State: "running", Completed: 1, Running: 2, Total: 3},
+	})
+
+	tests.Run(t, func(t *testing.T, tt tt) {
+		c := &Client{driverClient: tt.client, closed: tt.closed}
+		got, err := c.ReshardSummary(context.Background())
+		if d := internal.StatusErrorDiff(tt.err, tt.status, err); d != "" {
+			t.Error(d)
+		}
+		if d := testy.DiffInterface(tt.want, got); d != nil {
+			t.Error(d)
+		}
+	})
+}
+
+func TestSetReshardState(t *testing.T) {
+	type tt struct {
+		client driver.Client
+		state  string
+		status int
+		err    string
+	}
+	tests := testy.NewTable()
+	tests.Add("missing state", tt{
+		client: &mock.Resharder{},
+		status: http.StatusBadRequest,
+		err:    "kivik: state required",
+	})
+	tests.Add("success", tt{
+		client: &mock.Resharder{
+			SetReshardStateFunc: func(_ context.Context, state, reason string) error {
+				if state != ReshardStopped || reason != "maintenance" {
+					return errors.New("unexpected arguments")
+				}
+				return nil
+			},
+		},
+		state: ReshardStopped,
+	})
+
+	tests.Run(t, func(t *testing.T, tt tt) {
+		c := &Client{driverClient: tt.client}
+		err := c.SetReshardState(context.Background(), tt.state, "maintenance")
+		if d := internal.StatusErrorDiff(tt.err, tt.status, err); d != "" {
+			t.Error(d)
+		}
+	})
+}
+
+func TestReshardJobs(t *testing.T) {
+	start := time.Date(2019, 3, 28, 15, 28, 2, 0, time.UTC)
+	client := &mock.Resharder{
+		ReshardJobsFunc: func(context.Context) ([]*driver.ReshardJob, error) {
+			return []*driver.ReshardJob{
+				{
+					ID:        "001-abc",
+					Type:      "split",
+					Source:    "shards/00000000-1fffffff/db.1553786862",
+					Targets:   []string{"shards/00000000-0fffffff/db.1553786862", "shards/10000000-1fffffff/db.1553786862"},
+					JobState:  "completed",
+					StartTime: start,
+					History:   []driver.ReshardEvent{{Timestamp: start, Type: "new"}},
+				},
+			}, nil
+		},
+		ReshardJobFunc: func(context.Context, string) (*driver.ReshardJob, error) {
+			return nil, &internal.Error{Status: http.StatusNotFound, Message: "not_found"}
+		},
+	}
+	c := &Client{driverClient: client}
+
+	got, err := c.ReshardJobs(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*ReshardJob{
+		{
+			ID:        "001-abc",
+			Type:      "split",
+			Source:    "shards/00000000-1fffffff/db.1553786862",
+			Targets:   []string{"shards/00000000-0fffffff/db.1553786862", "shards/10000000-1fffffff/db.1553786862"},
+			JobState:  "completed",
+			StartTime: start,
+			History:   []ReshardEvent{{Timestamp: start, Type: "new"}},
+		},
+	}
+	if d := testy.DiffInterface(want, got); d != nil {
+		t.Error(d)
+	}
+
+	_, err = c.ReshardJob(context.Background(), "")
+	if d := internal.StatusErrorDiff("kivik: jobID required", http.StatusBadRequest, err); d != "" {
+		t.Error(d)
+	}
+	_, err = c.ReshardJob(context.Background(), "002-def")
+	if d := internal.StatusErrorDiff("not_found", http.StatusNotFound, err); d != "" {
+		t.Error(d)
+	}
+}
+
+func TestCreateReshardJobs(t *testing.T) {
+	type tt struct {
+		client driver.Client
+		job    ReshardJobRequest
+		want   []*ReshardJobResult
+		status int
+		err    string
+	}
+	tests := testy.NewTable()
+	tests.Add("missing db and shard", tt{
+		client: &mock.Resharder{},
+		status: http.StatusBadRequest,
+		err:    "kivik: db or shard required",
+	})
+	tests.Add("type defaults to split", tt{
+		client: &mock.Resharder{
+			CreateReshardJobsFunc: func(_ context.Context, job *driver.ReshardJobRequest) ([]*driver.ReshardJobResult, error) {
+				if job.Type != "split" || job.DB != "db" {
+					return nil, errors.New("unexpected job")
+				}
+				return []*driver.ReshardJobResult{
+					{ID: "001-abc", Node: "node1", Shard: "shards/00000000-ffffffff/db.1"},
+					{Node: "node2", Shard: "shards/00000000-ffffffff/db.1", Error: errors.New("conflict")},
+				}, nil
+			},
+		},
+		job: ReshardJobRequest{DB: "db"},
+		want: []*ReshardJobResult{
+			{ID: "001-abc", Node: "node1", Shard: "shards/00000000-ffffffff/db.1"},
+			{Node: "node2", Shard: "shards/00000000-ffffffff/db.1", Error: errors.New("conflict")},
+		},
+	})
+
+	tests.Run(t, func(t *testing.T, tt tt) {
+		c := &Client{driverClient: tt.client}
+		got, err := c.CreateReshardJobs(context.Background(), tt.job)
+		if d := internal.StatusErrorDiff(tt.err, tt.status, err); d != "" {
+			t.Error(d)
+		}
+		if d := testy.DiffInterface(tt.want, got); d != nil {
+			t.Error(d)
+		}
+	})
+}
+
+func TestReshardJobState(t *testing.T) {
+	client := &mock.Resharder{
+		ReshardJobStateFunc: func(_ context.Context, jobID string) (*driver.ReshardState, error) {
+			return &driver.ReshardState{State: "stopped", Reason: jobID}, nil
+		},
+		SetReshardJobStateFunc: func(context.Context, string, string, string) error {
+			return nil
+		},
+		DeleteReshardJobFunc: func(context.Context, string) error {
+			return nil
+		},
+	}
+	c := &Client{driverClient: client}
+
+	got, err := c.ReshardJobState(context.Background(), "001-abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := testy.DiffInterface(&ReshardState{State: "stopped", Reason: "001-abc"}, got); d != nil {
+		t.Error(d)
+	}
+	err = c.SetReshardJobState(context.Background(), "001-abc", "", "")
+	if d := internal.StatusErrorDiff("kivik: state required", http.StatusBadRequest, err); d != "" {
+		t.Error(d)
+	}
+	if err := c.SetReshardJobState(context.Background(), "001-abc", ReshardRunning, ""); err != nil {
+		t.Error(err)
+	}
+	err = c.DeleteReshardJob(context.Background(), "")
+	if d := internal.StatusErrorDiff("kivik: jobID required", http.StatusBadRequest, err); d != "" {
+		t.Error(d)
+	}
+	if err := c.DeleteReshardJob(context.Background(), "001-abc"); err != nil {
+		t.Error(err)
+	}
+}
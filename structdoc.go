@@ -0,0 +1,317 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package kivik
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+
+	internal "github.com/go-kivik/kivik/v4/int/errors"
+)
+
+// Struct tag values recognized by [Save] and [Load].
+const (
+	tagID          = "id"
+	tagRev         = "rev"
+	tagDeleted     = "deleted"
+	tagAttachments = "attachments"
+	tagConflicts   = "conflicts"
+)
+
+var (
+	stringType      = reflect.TypeOf("")
+	boolType        = reflect.TypeOf(false)
+	stringSliceType = reflect.TypeOf([]string(nil))
+	attachmentsType = reflect.TypeOf(Attachments(nil))
+)
+
+// structDoc describes the special fields of a struct document.
+type structDoc struct {
+	v reflect.Value
+	// fields maps each tag value to the index of the field carrying it.
+	fields map[string]int
+	// jsonKeys are the JSON object keys produced by the tagged fields, which
+	// are replaced by their CouchDB equivalents.
+	jsonKeys []string
+}
+
+func newStructDoc(doc interface{}) (*structDoc, error) {
+	v := reflect.ValueOf(doc)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return nil, &internal.Error{Status: http.StatusBadRequest, Message: fmt.Sprintf("kivik: doc must be a non-nil pointer to a struct, got %T", doc)}
+	}
+	v = v.Elem()
+	t := v.Type()
+	s := &structDoc{v: v, fields: map[string]int{}}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag, ok := field.Tag.Lookup("kivik")
+		if !ok || field.PkgPath != "" {
+			continue
+		}
+		var want reflect.Type
+		switch tag {
+		case tagID, tagRev:
+			want = stringType
+		case tagDeleted:
+			want = boolType
+		case tagAttachments:
+			want = attachmentsType
+		case tagConflicts:
+			want = stringSliceType
+		default:
+			return nil, &internal.Error{Status: http.StatusBadRequest, Message: fmt.Sprintf("kivik: unknown tag kivik:%q on field %s", tag, field.Name)}
+		}
+		if field.Type != want {
+			return nil, &internal.Error{Status: http.StatusBadRequest, Message: fmt.Sprintf("kivik: field %s tagged kivik:%q must be of type %s", field.Name, tag, want)}
+		}
+		if _, dupe := s.fields[tag]; dupe {
+			return nil, &internal.Error{Status: http.StatusBadRequest, Message: fmt.Sprintf("kivik: duplicate tag kivik:%q on field %s", tag, field.Name)}
+		}
+		s.fields[tag] = i
+		if key := jsonKey(field); key != "" {
+			s.jsonKeys = append(s.jsonKeys, key)
+		}
+	}
+	return s, nil
+}
+
+// jsonKey returns the JSON object key of field, or "" if it is omitted.
+func jsonKey(field reflect.StructField) string {
+	name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return field.Name
+	}
+	return name
+}
+
+// field returns the field tagged with tag, or an invalid value if there is
+// none.
+func (s *structDoc) field(tag string) reflect.Value {
+	i, ok := s.fields[tag]
+	if !ok {
+		return reflect.Value{}
+	}
+	return s.v.Field(i)
+}
+
+func (s *structDoc) stringValue(tag string) string {
+	if f := s.field(tag); f.IsValid() {
+		return f.String()
+	}
+	return ""
+}
+
+func (s *structDoc) set(tag string, value interface{}) {
+	if f := s.field(tag); f.IsValid() {
+		f.Set(reflect.ValueOf(value))
+	}
+}
+
+// body returns the document to be stored.
+func (s *structDoc) body() (map[string]interface{}, error) {
+	// Marshal a copy with the tagged fields cleared, so that attachment
+	// content is not consumed here.
+	clone := reflect.New(s.v.Type()).Elem()
+	clone.Set(s.v)
+	for _, i := range s.fields {
+		f := clone.Field(i)
+		f.Set(reflect.Zero(f.Type()))
+	}
+	data, err := json.Marshal(clone.Interface())
+	if err != nil {
+		return nil, &internal.Error{Status: http.StatusBadRequest, Err: err}
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, &internal.Error{Status: http.StatusBadRequest, Err: err}
+	}
+	for _, key := range s.jsonKeys {
+		delete(fields, key)
+	}
+	body := make(map[string]interface{}, len(fields)+4)
+	for k, v := range fields {
+		body[k] = v
+	}
+	if id := s.stringValue(tagID); id != "" {
+		body["_id"] = id
+	}
+	if rev := s.stringValue(tagRev); rev != "" {
+		body["_rev"] = rev
+	}
+	if f := s.field(tagDeleted); f.IsValid() && f.Bool() {
+		body["_deleted"] = true
+	}
+	if f := s.field(tagAttachments); f.IsValid() && f.Len() > 0 {
+		// Drivers may consume the map they are given, so pass a copy.
+		atts := make(Attachments, f.Len())
+		for filename, att := range f.Interface().(Attachments) {
+			atts[filename] = att
+		}
+		body["_attachments"] = atts
+	}
+	return body, nil
+}
+
+// Save stores doc, which must be a pointer to a struct, in db. The struct's
+// CouchDB metadata is read from, and written to, fields with the following
+// kivik tags:
+//
+//   - kivik:"id" (string) is the document ID. If it is empty, Save creates a
+//     new document as with [DB.CreateDoc], and sets the field to the new ID.
+//   - kivik:"rev" (string) is the current revision, which is updated to the new
+//     revision after a successful write.
+//   - kivik:"deleted" (bool), when true, deletes the document.
+//   - kivik:"attachments" ([Attachments]) are stored as _attachments. After a
+//     successful write they are marked as stubs, so that a subsequent Save
+//     retains them without re-uploading the content.
+//   - kivik:"conflicts" ([]string) is populated by [Load], and ignored by Save.
+//
+// Tagged fields replace whatever JSON key encoding/json would give them. All
+// other fields are marshaled with encoding/json as usual. Only fields of the
+// top-level struct are inspected.
+//
+// If the revision is not current, the conflict is returned as an error with
+// status 409, and doc is left unchanged.
+func Save(ctx context.Context, db *DB, doc interface{}, options ...Option) (rev string, err error) {
+	s, err := newStructDoc(doc)
+	if err != nil {
+		return "", err
+	}
+	body, err := s.body()
+	if err != nil {
+		return "", err
+	}
+	id := s.stringValue(tagID)
+	if id == "" {
+		id, rev, err = db.CreateDoc(ctx, body, options...)
+	} else {
+		rev, err = db.Put(ctx, id, body, options...)
+	}
+	if err != nil {
+		return "", err
+	}
+	s.saved(id, rev)
+	return rev, nil
+}
+
+// saved updates the struct after it has been stored with the given ID and
+// rev.
+func (s *structDoc) saved(id, rev string) {
+	s.set(tagID, id)
+	s.set(tagRev, rev)
+	if f := s.field(tagAttachments); f.IsValid() {
+		for _, att := range f.Interface().(Attachments) {
+			att.Stub = true
+			att.Follows = false
+			att.Content = nil
+		}
+	}
+}
+
+// Load fetches the document with the given ID from db, and unmarshals it into
+// doc, which must be a pointer to a struct. Fields tagged as described in
+// [Save] are populated from the corresponding CouchDB metadata. If doc has a
+// kivik:"conflicts" field, conflicts=true is requested, unless overridden by
+// options. Attachments returned in a multipart response, as when requesting
+// attachments=true from CouchDB, are read into memory.
+func Load(ctx context.Context, db *DB, docID string, doc interface{}, options ...Option) error {
+	s, err := newStructDoc(doc)
+	if err != nil {
+		return err
+	}
+	if _, ok := s.fields[tagConflicts]; ok {
+		options = append([]Option{Param("conflicts", true)}, options...)
+	}
+	_, err = loadDoc(db.Get(ctx, docID, options...), doc, s)
+	return err
+}
+
+// loadDoc unmarshals result into doc, and returns its rev. If s is not nil,
+// the tagged fields of doc are populated.
+func loadDoc(result *Document, doc interface{}, s *structDoc) (string, error) {
+	defer result.Close() // nolint: errcheck
+	var raw json.RawMessage
+	if err := result.ScanDoc(&raw); err != nil {
+		return "", err
+	}
+	if err := json.Unmarshal(raw, doc); err != nil {
+		return "", err
+	}
+	var meta struct {
+		ID          string      `json:"_id"`
+		Rev         string      `json:"_rev"`
+		Deleted     bool        `json:"_deleted"`
+		Attachments Attachments `json:"_attachments"`
+		Conflicts   []string    `json:"_conflicts"`
+	}
+	if err := json.Unmarshal(raw, &meta); err != nil {
+		return "", err
+	}
+	if meta.Rev == "" {
+		meta.Rev, _ = result.Rev()
+	}
+	if s == nil {
+		return meta.Rev, nil
+	}
+	if err := readAttachments(result, meta.Attachments); err != nil {
+		return "", err
+	}
+	s.set(tagID, meta.ID)
+	s.set(tagRev, meta.Rev)
+	s.set(tagDeleted, meta.Deleted)
+	s.set(tagAttachments, meta.Attachments)
+	s.set(tagConflicts, meta.Conflicts)
+	return meta.Rev, nil
+}
+
+// readAttachments reads any attachments which follow the document into atts.
+func readAttachments(doc *Document, atts Attachments) error {
+	iter, err := doc.Attachments()
+	if errors.Is(err, errNoAttachments) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for {
+		att, err := iter.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			_ = iter.Close()
+			return err
+		}
+		content, err := io.ReadAll(att.Content)
+		if err != nil {
+			_ = iter.Close()
+			return err
+		}
+		att.Content = io.NopCloser(bytes.NewReader(content))
+		att.Follows = false
+		if atts != nil {
+			atts[att.Filename] = att
+		}
+	}
+}
@@ -0,0 +1,356 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package kivik
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"gitlab.com/flimzy/testy"
+
+	"github.com/go-kivik/kivik/v4/driver"
+	internal "github.com/go-kivik/kivik/v4/int/errors"
+	"github.com/go-kivik/kivik/v4/int/mock"
+)
+
+type taggedDoc struct {
+	ID          string      `kivik:"id"`
+	Rev         string      `kivik:"rev" json:"rev"`
+	Deleted     bool        `kivik:"deleted" json:"-"`
+	Attachments Attachments `kivik:"attachments"`
+	Conflicts   []string    `kivik:"conflicts"`
+	Name        string      `json:"name"`
+	Age         int         `json:"age,omitempty"`
+}
+
+func TestSave(t *testing.T) {
+	type tt struct {
+		db      *DB
+		doc     interface{}
+		wantRev string
+		wantDoc interface{}
+		status  int
+		err     string
+	}
+
+	tests := testy.NewTable()
+	tests.Add("not a pointer", tt{
+		doc:    taggedDoc{},
+		status: http.StatusBadRequest,
+		err:    "kivik: doc must be a non-nil pointer to a struct, got kivik.taggedDoc",
+	})
+	tests.Add("pointer to map", tt{
+		doc:    &map[string]interface{}{},
+		status: http.StatusBadRequest,
+		err:    "kivik: doc must be a non-nil pointer to a struct, got *map[string]interface {}",
+	})
+	tests.Add("unknown tag", tt{
+		doc: &struct {
+			Foo string `kivik:"foo"`
+		}{},
+		status: http.StatusBadRequest,
+		err:    `kivik: unknown tag kivik:"foo" on field Foo`,
+	})
+	tests.Add("wrong type", tt{
+		doc: &struct {
+			Rev int `kivik:"rev"`
+		}{},
+		status: http.StatusBadRequest,
+		err:    `kivik: field Rev tagged kivik:"rev" must be of type string`,
+	})
+	tests.Add("duplicate tag", tt{
+		doc: &struct {
+			ID  string `kivik:"id"`
+			ID2 string `kivik:"id"`
+		}{},
+		status: http.StatusBadRequest,
+		err:    `kivik: duplicate tag kivik:"id" on field ID2`,
+	})
+	tests.Add("update", tt{
+		db: &DB{
+			client: &Client{},
+			driverDB: &mock.DB{
+				PutFunc: func(_ context.Context, docID string, doc interface{}, _ driver.Options) (string, error) {
+					if docID != "foo" {
+						return "", fmt.Errorf("Unexpected docID: %s", docID)
+					}
+					want := map[string]interface{}{
+						"_id":  "foo",
+						"_rev": "1-abc",
+						"name": json.RawMessage(`"Bob"`),
+					}
+					if d := testy.DiffInterface(want, doc); d != nil {
+						return "", fmt.Errorf("Unexpected doc:\n%s", d)
+					}
+					return "2-def", nil
+				},
+			},
+		},
+		doc: &taggedDoc{
+			ID:        "foo",
+			Rev:       "1-abc",
+			Conflicts: []string{"1-xyz"},
+			Name:      "Bob",
+		},
+		wantRev: "2-def",
+		wantDoc: &taggedDoc{
+			ID:        "foo",
+			Rev:       "2-def",
+			Conflicts: []string{"1-xyz"},
+			Name:      "Bob",
+		},
+	})
+	tests.Add("create", tt{
+		db: &DB{
+			client: &Client{},
+			driverDB: &mock.DocCreator{
+				CreateDocFunc: func(_ context.Context, doc interface{}, _ driver.Options) (string, string, error) {
+					want := map[string]interface{}{
+						"name": json.RawMessage(`"Alice"`),
+						"age":  json.RawMessage(`30`),
+					}
+					if d := testy.DiffInterface(want, doc); d != nil {
+						return "", "", fmt.Errorf("Unexpected doc:\n%s", d)
+					}
+					return "newid", "1-abc", nil
+				},
+			},
+		},
+		doc:     &taggedDoc{Name: "Alice", Age: 30},
+		wantRev: "1-abc",
+		wantDoc: &taggedDoc{ID: "newid", Rev: "1-abc", Name: "Alice", Age: 30},
+	})
+	tests.Add("delete with attachments", func(t *testing.T) interface{} {
+		att := &Attachment{
+			Filename:    "foo.txt",
+			ContentType: "text/plain",
+			Content:     io.NopCloser(strings.NewReader("hello")),
+		}
+		return tt{
+			db: &DB{
+				client: &Client{},
+				driverDB: &mock.DB{
+					PutFunc: func(_ context.Context, _ string, doc interface{}, _ driver.Options) (string, error) {
+						body := doc.(map[string]interface{})
+						if body["_deleted"] != true {
+							t.Errorf("Unexpected _deleted: %v", body["_deleted"])
+						}
+						atts, _ := body["_attachments"].(Attachments)
+						if atts.Get("foo.txt") != att {
+							t.Errorf("Unexpected _attachments: %v", body["_attachments"])
+						}
+						// Mimic the CouchDB driver, which empties the map.
+						delete(atts, "foo.txt")
+						return "2-def", nil
+					},
+				},
+			},
+			doc: &taggedDoc{
+				ID:          "foo",
+				Rev:         "1-abc",
+				Deleted:     true,
+				Attachments: Attachments{"foo.txt": att},
+			},
+			wantRev: "2-def",
+			wantDoc: &taggedDoc{
+				ID:      "foo",
+				Rev:     "2-def",
+				Deleted: true,
+				Attachments: Attachments{"foo.txt": &Attachment{
+					Filename:    "foo.txt",
+					ContentType: "text/plain",
+					Stub:        true,
+				}},
+			},
+		}
+	})
+	tests.Add("conflict", tt{
+		db: &DB{
+			client: &Client{},
+			driverDB: &mock.DB{
+				PutFunc: func(context.Context, string, interface{}, driver.Options) (string, error) {
+					return "", &internal.Error{Status: http.StatusConflict, Message: "Document update conflict."}
+				},
+			},
+		},
+		doc:     &taggedDoc{ID: "foo", Rev: "1-abc"},
+		wantDoc: &taggedDoc{ID: "foo", Rev: "1-abc"},
+		status:  http.StatusConflict,
+		err:     "Document update conflict.",
+	})
+
+	tests.Run(t, func(t *testing.T, tt tt) {
+		rev, err := Save(context.Background(), tt.db, tt.doc)
+		if d := internal.StatusErrorDiff(tt.err, tt.status, err); d != "" {
+			t.Error(d)
+		}
+		if rev != tt.wantRev {
+			t.Errorf("Unexpected rev: %s", rev)
+		}
+		if tt.wantDoc == nil {
+			return
+		}
+		if d := testy.DiffInterface(tt.wantDoc, tt.doc); d != nil {
+			t.Error(d)
+		}
+	})
+}
+
+func TestLoad(t *testing.T) {
+	type tt struct {
+		db      *DB
+		doc     interface{}
+		wantDoc interface{}
+		status  int
+		err     string
+	}
+
+	tests := testy.NewTable()
+	tests.Add("invalid doc", tt{
+		doc:    "foo",
+		status: http.StatusBadRequest,
+		err:    "kivik: doc must be a non-nil pointer to a struct, got string",
+	})
+	tests.Add("not found", tt{
+		db: &DB{
+			client: &Client{},
+			driverDB: &mock.DB{
+				GetFunc: func(context.Context, string, driver.Options) (*driver.Document, error) {
+					return nil, &internal.Error{Status: http.StatusNotFound, Message: "missing"}
+				},
+			},
+		},
+		doc:    &taggedDoc{},
+		status: http.StatusNotFound,
+		err:    "missing",
+	})
+	tests.Add("success", tt{
+		db: &DB{
+			client: &Client{},
+			driverDB: &mock.DB{
+				GetFunc: func(_ context.Context, docID string, options driver.Options) (*driver.Document, error) {
+					opts := map[string]interface{}{}
+					options.Apply(opts)
+					if d := testy.DiffInterface(map[string]interface{}{"conflicts": true}, opts); d != nil {
+						return nil, fmt.Errorf("Unexpected options:\n%s", d)
+					}
+					return &driver.Document{
+						Rev: "2-def",
+						Body: body(`{"_id":"` + docID + `","_rev":"2-def","_conflicts":["2-xyz"],"name":"Bob","age":42,` +
+							`"_attachments":{"foo.txt":{"content_type":"text/plain","stub":true,"length":5}}}`),
+					}, nil
+				},
+			},
+		},
+		doc: &taggedDoc{},
+		wantDoc: &taggedDoc{
+			ID:        "foo",
+			Rev:       "2-def",
+			Conflicts: []string{"2-xyz"},
+			Attachments: Attachments{"foo.txt": &Attachment{
+				Filename:    "foo.txt",
+				ContentType: "text/plain",
+				Stub:        true,
+				Size:        5,
+				Content:     nilContent,
+			}},
+			Name: "Bob",
+			Age:  42,
+		},
+	})
+	tests.Add("multipart attachments", func(t *testing.T) interface{} {
+		sent := false
+		return tt{
+			db: &DB{
+				client: &Client{},
+				driverDB: &mock.DB{
+					GetFunc: func(context.Context, string, driver.Options) (*driver.Document, error) {
+						return &driver.Document{
+							Rev:  "1-abc",
+							Body: body(`{"_id":"foo","_rev":"1-abc","_attachments":{"foo.txt":{"content_type":"text/plain","follows":true}}}`),
+							Attachments: &mock.Attachments{
+								NextFunc: func(att *driver.Attachment) error {
+									if sent {
+										return io.EOF
+									}
+									sent = true
+									*att = driver.Attachment{
+										Filename:    "foo.txt",
+										ContentType: "text/plain",
+										Content:     io.NopCloser(strings.NewReader("hello")),
+									}
+									return nil
+								},
+							},
+						}, nil
+					},
+				},
+			},
+			doc: &struct {
+				ID          string      `kivik:"id"`
+				Attachments Attachments `kivik:"attachments"`
+			}{},
+			wantDoc: &struct {
+				ID          string      `kivik:"id"`
+				Attachments Attachments `kivik:"attachments"`
+			}{
+				ID: "foo",
+				Attachments: Attachments{"foo.txt": &Attachment{
+					Filename:    "foo.txt",
+					ContentType: "text/plain",
+				}},
+			},
+		}
+	})
+
+	tests.Run(t, func(t *testing.T, tt tt) {
+		err := Load(context.Background(), tt.db, "foo", tt.doc)
+		if d := internal.StatusErrorDiff(tt.err, tt.status, err); d != "" {
+			t.Error(d)
+		}
+		if err != nil {
+			return
+		}
+		if atts := reflectAttachments(tt.doc); atts != nil {
+			for _, att := range atts {
+				if att.Content != nilContent && att.Content != nil {
+					content, _ := io.ReadAll(att.Content)
+					if string(content) != "hello" {
+						t.Errorf("Unexpected content: %s", content)
+					}
+					att.Content = nil
+				}
+			}
+		}
+		if d := testy.DiffInterface(tt.wantDoc, tt.doc); d != nil {
+			t.Error(d)
+		}
+	})
+}
+
+// reflectAttachments returns the attachments field of a test document.
+func reflectAttachments(doc interface{}) Attachments {
+	s, err := newStructDoc(doc)
+	if err != nil {
+		return nil
+	}
+	if f := s.field(tagAttachments); f.IsValid() {
+		return f.Interface().(Attachments)
+	}
+	return nil
+}
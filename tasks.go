@@ -0,0 +1,191 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package kivik
+
+import (
+	"context"
+
+	"github.com/go-kivik/kivik/v4/driver"
+)
+
+// Task types reported by [Client.ActiveTasks].
+const (
+	TaskTypeDatabaseCompaction = "database_compaction"
+	TaskTypeViewCompaction     = "view_compaction"
+	TaskTypeIndexer            = "indexer"
+	TaskTypeReplication        = "replication"
+	TaskTypeSearchIndexer      = "search_indexer"
+)
+
+// ActiveTask is a single task returned by [Client.ActiveTasks]. Its concrete
+// type is one of [*DatabaseCompactionTask], [*ViewCompactionTask],
+// [*IndexerTask], [*ReplicationTask], [*SearchIndexerTask], or [*OtherTask]
+// for task types not known to Kivik.
+type ActiveTask interface {
+	// Info returns the fields common to all tasks.
+	Info() *TaskInfo
+}
+
+// TaskInfo contains the fields common to all active tasks.
+type TaskInfo struct {
+	// Type is the task type, such as [TaskTypeIndexer].
+	Type string `json:"type"`
+	// Node is the cluster node on which the task is running.
+	Node string `json:"node,omitempty"`
+	// PID is the Erlang process ID of the task.
+	PID string `json:"pid,omitempty"`
+	// Database is the database, or database shard, the task operates on. It
+	// is not set for replication tasks.
+	Database string `json:"database,omitempty"`
+	// Progress is the completion percentage, from 0 to 100.
+	Progress int `json:"progress"`
+	// ChangesDone is the number of changes processed so far.
+	ChangesDone int64 `json:"changes_done"`
+	// TotalChanges is the total number of changes to be processed.
+	TotalChanges int64 `json:"total_changes"`
+	// StartedOn is the time the task started, in Unix seconds.
+	StartedOn int64 `json:"started_on,omitempty"`
+	// UpdatedOn is the time the task last reported its status, in Unix
+	// seconds.
+	UpdatedOn int64 `json:"updated_on,omitempty"`
+}
+
+// Info returns t.
+func (t *TaskInfo) Info() *TaskInfo {
+	return t
+}
+
+// DatabaseCompactionTask is a running database compaction.
+type DatabaseCompactionTask struct {
+	TaskInfo
+}
+
+// ViewCompactionTask is a running view index compaction.
+type ViewCompactionTask struct {
+	TaskInfo
+	DesignDocument string `json:"design_document"`
+	Phase          string `json:"phase,omitempty"`
+	View           string `json:"view,omitempty"`
+}
+
+// IndexerTask is a running view index build.
+type IndexerTask struct {
+	TaskInfo
+	DesignDocument string `json:"design_document"`
+}
+
+// SearchIndexerTask is a running search index build.
+type SearchIndexerTask struct {
+	TaskInfo
+	DesignDocument string `json:"design_document"`
+	Index          string `json:"index"`
+}
+
+// ReplicationTask is a running replication.
+type ReplicationTask struct {
+	TaskInfo
+	ReplicationID         string `json:"replication_id"`
+	DocID                 string `json:"doc_id,omitempty"`
+	Source                string `json:"source"`
+	Target                string `json:"target"`
+	User                  string `json:"user,omitempty"`
+	Continuous            bool   `json:"continuous"`
+	DocsRead              int64  `json:"docs_read"`
+	DocsWritten           int64  `json:"docs_written"`
+	DocWriteFailures      int64  `json:"doc_write_failures"`
+	MissingRevisionsFound int64  `json:"missing_revisions_found"`
+	RevisionsChecked      int64  `json:"revisions_checked"`
+	ChangesPending        int64  `json:"changes_pending"`
+}
+
+// OtherTask is a running task of a type not otherwise known to Kivik.
+type OtherTask struct {
+	TaskInfo
+}
+
+// ActiveTasks returns the [tasks] currently running on the server. Use a type
+// switch on each returned task to access its type-specific fields.
+//
+// [tasks]: https://docs.couchdb.org/en/stable/api/server/common.html#active-tasks
+func (c *Client) ActiveTasks(ctx context.Context, options ...Option) ([]ActiveTask, error) {
+	endQuery, err := c.startQuery()
+	if err != nil {
+		return nil, err
+	}
+	defer endQuery()
+	tasker, ok := c.driverClient.(driver.ActiveTasker)
+	if !ok {
+		return nil, errActiveTasksNotImplemented
+	}
+	tasks, err := tasker.ActiveTasks(ctx, multiOptions(options))
+	if err != nil {
+		return nil, err
+	}
+	result := make([]ActiveTask, 0, len(tasks))
+	for _, task := range tasks {
+		if task != nil {
+			result = append(result, driverTask2kivikTask(task))
+		}
+	}
+	return result, nil
+}
+
+func driverTask2kivikTask(task *driver.ActiveTask) ActiveTask {
+	info := TaskInfo{
+		Type:         task.Type,
+		Node:         task.Node,
+		PID:          task.PID,
+		Database:     task.Database,
+		Progress:     task.Progress,
+		ChangesDone:  task.ChangesDone,
+		TotalChanges: task.TotalChanges,
+		StartedOn:    task.StartedOn,
+		UpdatedOn:    task.UpdatedOn,
+	}
+	switch task.Type {
+	case TaskTypeDatabaseCompaction:
+		return &DatabaseCompactionTask{TaskInfo: info}
+	case TaskTypeViewCompaction:
+		return &ViewCompactionTask{
+			TaskInfo:       info,
+			DesignDocument: task.DesignDocument,
+			Phase:          task.Phase,
+			View:           task.View,
+		}
+	case TaskTypeIndexer:
+		return &IndexerTask{TaskInfo: info, DesignDocument: task.DesignDocument}
+	case TaskTypeSearchIndexer:
+		return &SearchIndexerTask{
+			TaskInfo:       info,
+			DesignDocument: task.DesignDocument,
+			Index:          task.Index,
+		}
+	case TaskTypeReplication:
+		return &ReplicationTask{
+			TaskInfo:              info,
+			ReplicationID:         task.ReplicationID,
+			DocID:                 task.DocID,
+			Source:                task.Source,
+			Target:                task.Target,
+			User:                  task.User,
+			Continuous:            task.Continuous,
+			DocsRead:              task.DocsRead,
+			DocsWritten:           task.DocsWritten,
+			DocWriteFailures:      task.DocWriteFailures,
+			MissingRevisionsFound: task.MissingRevisionsFound,
+			RevisionsChecked:      task.RevisionsChecked,
+			ChangesPending:        task.ChangesPending,
+		}
+	}
+	return &OtherTask{TaskInfo: info}
+}
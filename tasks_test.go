@@ -0,0 +1,93 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package kivik
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"gitlab.com/flimzy/testy"
+
+	"github.com/go-kivik/kivik/v4/driver"
+	internal "github.com/go-kivik/kivik/v4/int/errors"
+	"github.com/go-kivik/kivik/v4/int/mock"
+)
+
+func TestActiveTasks(t *testing.T) {
+	type tst struct {
+		client   driver.Client
+		closed   bool
+		expected []ActiveTask
+		status   int
+		err      string
+	}
+	tests := testy.NewTable()
+	tests.Add("driver doesn't implement ActiveTasker interface", tst{
+		client: &mock.Client{},
+		status: http.StatusNotImplemented,
+		err:    "kivik: driver does not support ActiveTasks interface",
+	})
+	tests.Add("client error", tst{
+		client: &mock.ActiveTasker{
+			ActiveTasksFunc: func(context.Context, driver.Options) ([]*driver.ActiveTask, error) {
+				return nil, errors.New("client error")
+			},
+		},
+		status: http.StatusInternalServerError,
+		err:    "client error",
+	})
+	tests.Add("client closed", tst{
+		client: &mock.ActiveTasker{},
+		closed: true,
+		status: http.StatusServiceUnavailable,
+		err:    "kivik: client closed",
+	})
+	tests.Add("all task types", tst{
+		client: &mock.ActiveTasker{
+			ActiveTasksFunc: func(context.Context, driver.Options) ([]*driver.ActiveTask, error) {
+				return []*driver.ActiveTask{
+					{Type: "database_compaction", Database: "foo", Progress: 10, ChangesDone: 1, TotalChanges: 10},
+					{Type: "view_compaction", Database: "foo", DesignDocument: "_design/bar", Phase: "view", View: "baz", Progress: 20},
+					{Type: "indexer", Database: "foo", DesignDocument: "_design/bar", Progress: 30, ChangesDone: 3, TotalChanges: 10},
+					{Type: "search_indexer", Database: "foo", DesignDocument: "_design/bar", Index: "qux", Progress: 40},
+					{Type: "replication", ReplicationID: "abc+continuous", Source: "src", Target: "tgt", Continuous: true, DocsRead: 5, DocsWritten: 4},
+					{Type: "reshard", Node: "node1", Progress: 50},
+				}, nil
+			},
+		},
+		expected: []ActiveTask{
+			&DatabaseCompactionTask{TaskInfo: TaskInfo{Type: "database_compaction", Database: "foo", Progress: 10, ChangesDone: 1, TotalChanges: 10}},
+			&ViewCompactionTask{TaskInfo: TaskInfo{Type: "view_compaction", Database: "foo", Progress: 20}, DesignDocument: "_design/bar", Phase: "view", View: "baz"},
+			&IndexerTask{TaskInfo: TaskInfo{Type: "indexer", Database: "foo", Progress: 30, ChangesDone: 3, TotalChanges: 10}, DesignDocument: "_design/bar"},
+			&SearchIndexerTask{TaskInfo: TaskInfo{Type: "search_indexer", Database: "foo", Progress: 40}, DesignDocument: "_design/bar", Index: "qux"},
+			&ReplicationTask{TaskInfo: TaskInfo{Type: "replication"}, ReplicationID: "abc+continuous", Source: "src", Target: "tgt", Continuous: true, DocsRead: 5, DocsWritten: 4},
+			&OtherTask{TaskInfo: TaskInfo{Type: "reshard", Node: "node1", Progress: 50}},
+		},
+	})
+
+	tests.Run(t, func(t *testing.T, test tst) {
+		c := &Client{
+			driverClient: test.client,
+			closed:       test.closed,
+		}
+		result, err := c.ActiveTasks(context.Background())
+		if d := internal.StatusErrorDiff(test.err, test.status, err); d != "" {
+			t.Error(d)
+		}
+		if d := testy.DiffInterface(test.expected, result); d != nil {
+			t.Error(d)
+		}
+	})
+}
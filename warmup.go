@@ -0,0 +1,378 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package kivik
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ViewProgress reports the state of a view index build, as passed to the
+// callback set with [WarmProgress].
+type ViewProgress struct {
+	// DDoc is the design document name, without the _design/ prefix.
+	DDoc string
+	// View is the view name.
+	View string
+	// Progress is the completion percentage, from 0 to 100.
+	Progress int
+	// Done is true once the index is built, or the build has failed.
+	Done bool
+	// Err is the error which ended the build, if any.
+	Err error
+}
+
+type warmer struct {
+	parallelism  int
+	pollInterval time.Duration
+	maxAttempts  int
+	progress     func(ViewProgress)
+
+	mu sync.Mutex
+}
+
+// report calls the progress callback, if any. Calls are serialized.
+func (w *warmer) report(p ViewProgress) {
+	if w.progress == nil {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.progress(p)
+}
+
+type warmParallelismOption int
+
+func (o warmParallelismOption) Apply(target interface{}) {
+	if w, ok := target.(*warmer); ok {
+		w.parallelism = int(o)
+	}
+}
+
+func (o warmParallelismOption) String() string {
+	return fmt.Sprintf("[warm_parallelism=%d]", int(o))
+}
+
+// WarmParallelism sets the number of views [DB.WarmViews] builds at once. The
+// default is 4.
+func WarmParallelism(n int) Option {
+	return warmParallelismOption(n)
+}
+
+type warmPollIntervalOption time.Duration
+
+func (o warmPollIntervalOption) Apply(target interface{}) {
+	if w, ok := target.(*warmer); ok {
+		w.pollInterval = time.Duration(o)
+	}
+}
+
+func (o warmPollIntervalOption) String() string {
+	return fmt.Sprintf("[warm_poll_interval=%s]", time.Duration(o))
+}
+
+// WarmPollInterval sets how often [DB.WarmViews] polls for index build
+// progress. The default is 1s.
+func WarmPollInterval(d time.Duration) Option {
+	return warmPollIntervalOption(d)
+}
+
+type warmMaxAttemptsOption int
+
+func (o warmMaxAttemptsOption) Apply(target interface{}) {
+	if w, ok := target.(*warmer); ok {
+		w.maxAttempts = int(o)
+	}
+}
+
+func (o warmMaxAttemptsOption) String() string {
+	return fmt.Sprintf("[warm_max_attempts=%d]", int(o))
+}
+
+// WarmMaxAttempts sets how many times [DB.WarmViews] queries a view which
+// fails with a transient error, before giving up. The default is 10. A value
+// less than 1 retries until the context is cancelled.
+func WarmMaxAttempts(n int) Option {
+	return warmMaxAttemptsOption(n)
+}
+
+type warmProgressOption func(ViewProgress)
+
+func (o warmProgressOption) Apply(target interface{}) {
+	if w, ok := target.(*warmer); ok {
+		w.progress = o
+	}
+}
+
+func (o warmProgressOption) String() string {
+	return "[warm_progress]"
+}
+
+// WarmProgress sets a callback which [DB.WarmViews] calls with the progress
+// of each view build, and once more when the view is done. Calls are never
+// made concurrently.
+func WarmProgress(fn func(ViewProgress)) Option {
+	return warmProgressOption(fn)
+}
+
+// Delays between attempts to query a view, after a transient error.
+const (
+	warmMinBackoff = 100 * time.Millisecond
+	warmMaxBackoff = 30 * time.Second
+)
+
+type viewRef struct {
+	ddoc, view string
+}
+
+// WarmViews builds the indexes of every view in the named design documents,
+// which may be given with or without the _design/ prefix. Each view is built
+// by querying it with limit=0, which blocks until the index is up to date.
+// Queries which fail with a transient error, such as a timeout, are retried
+// up to [WarmMaxAttempts] times in all, after which the last error is
+// reported. Up to [WarmParallelism] views are built at once.
+//
+// While a query is pending, progress is polled, every [WarmPollInterval], from
+// [Client.ActiveTasks], or from [DB.DesignDocInfo] where active tasks are not
+// supported, and passed to the callback set with [WarmProgress]. If the driver
+// supports neither, only completion is reported.
+//
+// WarmViews returns once all views are done, and returns the first error
+// encountered, if any. The design documents are all read before any view is
+// queried, so that a missing design document fails early. All other options
+// are passed to [DB.Query].
+func (db *DB) WarmViews(ctx context.Context, ddocs []string, options ...Option) error {
+	if db.err != nil {
+		return db.err
+	}
+	w := &warmer{
+		parallelism:  4,
+		pollInterval: time.Second,
+		maxAttempts:  10,
+	}
+	multiOptions(options).Apply(w)
+	if w.parallelism < 1 {
+		w.parallelism = 1
+	}
+
+	var refs []viewRef
+	for _, ddoc := range ddocs {
+		views, err := db.viewNames(ctx, ddoc)
+		if err != nil {
+			return err
+		}
+		refs = append(refs, views...)
+	}
+
+	jobs := make(chan viewRef)
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	for i := 0; i < w.parallelism && i < len(refs); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ref := range jobs {
+				if err := db.warmView(ctx, w, ref, options); err != nil {
+					mu.Lock()
+					if firstErr == nil {
+						firstErr = err
+					}
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+queue:
+	for _, ref := range refs {
+		select {
+		case jobs <- ref:
+		case <-ctx.Done():
+			break queue
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
+
+// viewNames returns the views defined in the named design document, in
+// sorted order.
+func (db *DB) viewNames(ctx context.Context, ddoc string) ([]viewRef, error) {
+	ddoc = strings.TrimPrefix(ddoc, "_design/")
+	if ddoc == "" {
+		return nil, missingArg("ddoc")
+	}
+	var doc struct {
+		Views map[string]json.RawMessage `json:"views"`
+	}
+	if err := db.Get(ctx, "_design/"+ddoc).ScanDoc(&doc); err != nil {
+		return nil, err
+	}
+	refs := make([]viewRef, 0, len(doc.Views))
+	for view := range doc.Views {
+		refs = append(refs, viewRef{ddoc: ddoc, view: view})
+	}
+	sort.Slice(refs, func(i, j int) bool {
+		return refs[i].view < refs[j].view
+	})
+	return refs, nil
+}
+
+// warmView builds a single view, reporting progress until it is done.
+func (db *DB) warmView(ctx context.Context, w *warmer, ref viewRef, options []Option) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- db.buildView(ctx, w, ref, options)
+	}()
+	var tick <-chan time.Time
+	if w.progress != nil && w.pollInterval > 0 {
+		ticker := time.NewTicker(w.pollInterval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	for {
+		select {
+		case err := <-done:
+			p := ViewProgress{DDoc: ref.ddoc, View: ref.view, Done: true, Err: err}
+			if err == nil {
+				p.Progress = 100
+			}
+			w.report(p)
+			return err
+		case <-tick:
+			progress, supported := db.indexProgress(ctx, ref.ddoc)
+			if !supported {
+				// Nothing to poll; wait for the query alone.
+				tick = nil
+				continue
+			}
+			if progress >= 0 {
+				w.report(ViewProgress{DDoc: ref.ddoc, View: ref.view, Progress: progress})
+			}
+		}
+	}
+}
+
+// buildView queries the view with limit=0, retrying after transient errors
+// until w.maxAttempts is reached.
+func (db *DB) buildView(ctx context.Context, w *warmer, ref viewRef, options []Option) error {
+	opts := append([]Option{Param("limit", 0)}, options...)
+	backoff := warmMinBackoff
+	for attempt := 1; ; attempt++ {
+		rows := db.Query(ctx, ref.ddoc, ref.view, opts...)
+		for rows.Next() {
+			// Drain the (empty) result.
+		}
+		err := rows.Err()
+		_ = rows.Close()
+		if err == nil || !isTransient(err) || attempt == w.maxAttempts {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > warmMaxBackoff {
+			backoff = warmMaxBackoff
+		}
+	}
+}
+
+// indexProgress returns the build progress of the design document's index, or
+// -1 if it is not known. supported is false if the driver offers no way to
+// track progress.
+func (db *DB) indexProgress(ctx context.Context, ddoc string) (progress int, supported bool) {
+	tasks, err := db.client.ActiveTasks(ctx)
+	switch {
+	case err == nil:
+		return indexerProgress(tasks, db.name, "_design/"+ddoc), true
+	case HTTPStatus(err) != http.StatusNotImplemented:
+		return -1, true
+	}
+	info, err := db.DesignDocInfo(ctx, ddoc)
+	switch {
+	case HTTPStatus(err) == http.StatusNotImplemented:
+		return -1, false
+	case err != nil:
+		return -1, true
+	}
+	stats, err := db.Stats(ctx)
+	if err != nil {
+		return -1, true
+	}
+	indexSeq, dbSeq := seqNum(info.UpdateSeq), seqNum(stats.UpdateSeq)
+	if indexSeq < 0 || dbSeq < 0 {
+		return -1, true
+	}
+	if indexSeq >= dbSeq {
+		return 100, true
+	}
+	return int(indexSeq * 100 / dbSeq), true
+}
+
+// indexerProgress returns the average progress of the indexer tasks for the
+// design document, across all shards of the database, or -1 if there are
+// none.
+func indexerProgress(tasks []ActiveTask, dbName, ddocID string) int {
+	var total, count int
+	for _, task := range tasks {
+		indexer, ok := task.(*IndexerTask)
+		if !ok || indexer.DesignDocument != ddocID || taskDBName(indexer.Database) != dbName {
+			continue
+		}
+		total += indexer.Progress
+		count++
+	}
+	if count == 0 {
+		return -1
+	}
+	return total / count
+}
+
+// taskDBName returns the database name of a task's database, which for
+// CouchDB clusters is a shard such as "shards/00000000-1fffffff/foo.1712345678".
+func taskDBName(database string) string {
+	if !strings.HasPrefix(database, "shards/") {
+		return database
+	}
+	parts := strings.SplitN(database, "/", 3) // nolint:gomnd
+	name := parts[len(parts)-1]
+	if i := strings.LastIndex(name, "."); i > 0 {
+		name = name[:i]
+	}
+	return name
+}
+
+// seqNum returns the numeric prefix of an update sequence, or -1 if there is
+// none.
+func seqNum(seq string) int64 {
+	n, err := strconv.ParseInt(strings.SplitN(seq, "-", 2)[0], 10, 64) // nolint:gomnd
+	if err != nil {
+		return -1
+	}
+	return n
+}
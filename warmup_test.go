@@ -0,0 +1,326 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package kivik
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"gitlab.com/flimzy/testy"
+
+	"github.com/go-kivik/kivik/v4/driver"
+	internal "github.com/go-kivik/kivik/v4/int/errors"
+	"github.com/go-kivik/kivik/v4/int/mock"
+)
+
+// warmDDocs returns a GetFunc serving design documents with the named views.
+func warmDDocs(ddocs map[string][]string) func(context.Context, string, driver.Options) (*driver.Document, error) {
+	return func(_ context.Context, docID string, _ driver.Options) (*driver.Document, error) {
+		views, ok := ddocs[strings.TrimPrefix(docID, "_design/")]
+		if !ok {
+			return nil, &internal.Error{Status: http.StatusNotFound, Message: "missing"}
+		}
+		defs := make([]string, len(views))
+		for i, view := range views {
+			defs[i] = fmt.Sprintf(`%q:{"map":"function(doc){}"}`, view)
+		}
+		body := fmt.Sprintf(`{"_id":%q,"_rev":"1-x","views":{%s}}`, docID, strings.Join(defs, ","))
+		return &driver.Document{Rev: "1-x", Body: io.NopCloser(strings.NewReader(body))}, nil
+	}
+}
+
+func TestDBWarmViews(t *testing.T) {
+	type tt struct {
+		db      *DB
+		ddocs   []string
+		options []Option
+		// release, if set, is closed when the first progress report, before
+		// completion, is received.
+		release     chan struct{}
+		wantReports []string
+		wantQueries []string
+		status      int
+		err         string
+	}
+	var (
+		mu      sync.Mutex
+		queries []string
+	)
+	recordQuery := func(ddoc, view string, options driver.Options) {
+		opts := map[string]interface{}{}
+		options.Apply(opts)
+		mu.Lock()
+		defer mu.Unlock()
+		queries = append(queries, fmt.Sprintf("%s/%s limit=%v", ddoc, view, opts["limit"]))
+	}
+
+	tests := testy.NewTable()
+	tests.Add("db error", tt{
+		db:     &DB{err: &internal.Error{Status: http.StatusBadRequest, Message: "db error"}},
+		ddocs:  []string{"foo"},
+		status: http.StatusBadRequest,
+		err:    "db error",
+	})
+	tests.Add("missing ddoc", tt{
+		db: &DB{
+			client: &Client{},
+			driverDB: &mock.DB{
+				GetFunc: warmDDocs(nil),
+			},
+		},
+		ddocs:  []string{"foo"},
+		status: http.StatusNotFound,
+		err:    "missing",
+	})
+	tests.Add("builds every view", tt{
+		db: &DB{
+			client: &Client{},
+			driverDB: &mock.DB{
+				GetFunc: warmDDocs(map[string][]string{"foo": {"a", "b"}, "bar": {"c"}}),
+				QueryFunc: func(_ context.Context, ddoc, view string, options driver.Options) (driver.Rows, error) {
+					recordQuery(ddoc, view, options)
+					return &mock.Rows{}, nil
+				},
+			},
+		},
+		ddocs:       []string{"_design/foo", "bar"},
+		options:     []Option{WarmParallelism(2)},
+		wantReports: []string{"bar/c done", "foo/a done", "foo/b done"},
+		wantQueries: []string{"bar/c limit=0", "foo/a limit=0", "foo/b limit=0"},
+	})
+	tests.Add("query error", tt{
+		db: &DB{
+			client: &Client{},
+			driverDB: &mock.DB{
+				GetFunc: warmDDocs(map[string][]string{"foo": {"a", "b"}}),
+				QueryFunc: func(_ context.Context, ddoc, view string, options driver.Options) (driver.Rows, error) {
+					recordQuery(ddoc, view, options)
+					if view == "b" {
+						return nil, &internal.Error{Status: http.StatusBadRequest, Message: "bad view"}
+					}
+					return &mock.Rows{}, nil
+				},
+			},
+		},
+		ddocs:       []string{"foo"},
+		wantReports: []string{"foo/a done", "foo/b error: bad view"},
+		wantQueries: []string{"foo/a limit=0", "foo/b limit=0"},
+		status:      http.StatusBadRequest,
+		err:         "bad view",
+	})
+	tests.Add("transient error retried", func() interface{} {
+		var calls int
+		return tt{
+			db: &DB{
+				client: &Client{},
+				driverDB: &mock.DB{
+					GetFunc: warmDDocs(map[string][]string{"foo": {"a"}}),
+					QueryFunc: func(_ context.Context, ddoc, view string, options driver.Options) (driver.Rows, error) {
+						recordQuery(ddoc, view, options)
+						if calls++; calls == 1 {
+							return nil, &internal.Error{Status: http.StatusInternalServerError, Message: "timeout"}
+						}
+						return &mock.Rows{}, nil
+					},
+				},
+			},
+			ddocs:       []string{"foo"},
+			wantReports: []string{"foo/a done"},
+			wantQueries: []string{"foo/a limit=0", "foo/a limit=0"},
+		}
+	})
+	tests.Add("transient error gives up", tt{
+		db: &DB{
+			client: &Client{},
+			driverDB: &mock.DB{
+				GetFunc: warmDDocs(map[string][]string{"foo": {"a"}}),
+				QueryFunc: func(_ context.Context, ddoc, view string, options driver.Options) (driver.Rows, error) {
+					recordQuery(ddoc, view, options)
+					return nil, &internal.Error{Status: http.StatusInternalServerError, Message: "timeout"}
+				},
+			},
+		},
+		ddocs:       []string{"foo"},
+		options:     []Option{WarmMaxAttempts(3)},
+		wantReports: []string{"foo/a error: timeout"},
+		wantQueries: []string{"foo/a limit=0", "foo/a limit=0", "foo/a limit=0"},
+		status:      http.StatusInternalServerError,
+		err:         "timeout",
+	})
+	tests.Add("extra options passed to query", tt{
+		db: &DB{
+			client: &Client{},
+			driverDB: &mock.DB{
+				GetFunc: warmDDocs(map[string][]string{"foo": {"a"}}),
+				QueryFunc: func(_ context.Context, ddoc, view string, options driver.Options) (driver.Rows, error) {
+					opts := map[string]interface{}{}
+					options.Apply(opts)
+					if opts["partition"] != "x" {
+						return nil, fmt.Errorf("unexpected options: %v", opts)
+					}
+					recordQuery(ddoc, view, options)
+					return &mock.Rows{}, nil
+				},
+			},
+		},
+		ddocs:       []string{"foo"},
+		options:     []Option{Param("partition", "x")},
+		wantReports: []string{"foo/a done"},
+		wantQueries: []string{"foo/a limit=0"},
+	})
+	tests.Add("progress from active tasks", func() interface{} {
+		release := make(chan struct{})
+		return tt{
+			db: &DB{
+				name: "testdb",
+				client: &Client{
+					driverClient: &mock.ActiveTasker{
+						ActiveTasksFunc: func(context.Context, driver.Options) ([]*driver.ActiveTask, error) {
+							return []*driver.ActiveTask{
+								{Type: "indexer", Database: "shards/00000000-7fffffff/testdb.1712345678", DesignDocument: "_design/foo", Progress: 40},
+								{Type: "indexer", Database: "shards/80000000-ffffffff/testdb.1712345678", DesignDocument: "_design/foo", Progress: 60},
+								{Type: "indexer", Database: "shards/00000000-ffffffff/otherdb.1712345678", DesignDocument: "_design/foo", Progress: 1},
+								{Type: "indexer", Database: "testdb", DesignDocument: "_design/bar", Progress: 2},
+								{Type: "database_compaction", Database: "testdb", Progress: 3},
+							}, nil
+						},
+					},
+				},
+				driverDB: &mock.DB{
+					GetFunc: warmDDocs(map[string][]string{"foo": {"a"}}),
+					QueryFunc: func(ctx context.Context, ddoc, view string, options driver.Options) (driver.Rows, error) {
+						select {
+						case <-release:
+						case <-ctx.Done():
+							return nil, ctx.Err()
+						}
+						recordQuery(ddoc, view, options)
+						return &mock.Rows{}, nil
+					},
+				},
+			},
+			ddocs:       []string{"foo"},
+			options:     []Option{WarmPollInterval(time.Millisecond)},
+			release:     release,
+			wantReports: []string{"foo/a 50%", "foo/a done"},
+			wantQueries: []string{"foo/a limit=0"},
+		}
+	})
+	tests.Add("progress from design doc info", func() interface{} {
+		release := make(chan struct{})
+		return tt{
+			db: &DB{
+				name:   "testdb",
+				client: &Client{driverClient: &mock.Client{}},
+				driverDB: &mock.DesignDocInfoer{
+					DB: &mock.DB{
+						GetFunc: warmDDocs(map[string][]string{"foo": {"a"}}),
+						QueryFunc: func(ctx context.Context, ddoc, view string, options driver.Options) (driver.Rows, error) {
+							select {
+							case <-release:
+							case <-ctx.Done():
+								return nil, ctx.Err()
+							}
+							recordQuery(ddoc, view, options)
+							return &mock.Rows{}, nil
+						},
+						StatsFunc: func(context.Context) (*driver.DBStats, error) {
+							return &driver.DBStats{UpdateSeq: "12-g1AAAA"}, nil
+						},
+					},
+					DesignDocInfoFunc: func(context.Context, string, driver.Options) (*driver.DesignDocInfo, error) {
+						return &driver.DesignDocInfo{UpdateSeq: "3-g1AAAA"}, nil
+					},
+				},
+			},
+			ddocs:       []string{"foo"},
+			options:     []Option{WarmPollInterval(time.Millisecond)},
+			release:     release,
+			wantReports: []string{"foo/a 25%", "foo/a done"},
+			wantQueries: []string{"foo/a limit=0"},
+		}
+	})
+
+	tests.Run(t, func(t *testing.T, tt tt) {
+		queries = nil
+		var (
+			reports []string
+			once    sync.Once
+		)
+		options := append([]Option{WarmProgress(func(p ViewProgress) {
+			var report string
+			switch {
+			case p.Err != nil:
+				report = fmt.Sprintf("%s/%s error: %s", p.DDoc, p.View, p.Err)
+			case p.Done:
+				report = fmt.Sprintf("%s/%s done", p.DDoc, p.View)
+			default:
+				report = fmt.Sprintf("%s/%s %d%%", p.DDoc, p.View, p.Progress)
+				if tt.release != nil {
+					once.Do(func() { close(tt.release) })
+				}
+			}
+			reports = append(reports, report)
+		})}, tt.options...)
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		err := tt.db.WarmViews(ctx, tt.ddocs, options...)
+		if d := internal.StatusErrorDiff(tt.err, tt.status, err); d != "" {
+			t.Error(d)
+		}
+		if d := testy.DiffInterface(tt.wantReports, dedupe(reports)); d != nil {
+			t.Errorf("Unexpected reports:\n%s", d)
+		}
+		sort.Strings(queries)
+		if d := testy.DiffInterface(tt.wantQueries, queries); d != nil {
+			t.Errorf("Unexpected queries:\n%s", d)
+		}
+	})
+}
+
+// dedupe sorts reports, and removes repeats, as progress may be polled more
+// than once.
+func dedupe(reports []string) []string {
+	if len(reports) == 0 {
+		return nil
+	}
+	sort.Strings(reports)
+	result := reports[:1]
+	for _, report := range reports[1:] {
+		if report != result[len(result)-1] {
+			result = append(result, report)
+		}
+	}
+	return result
+}
+
+func TestTaskDBName(t *testing.T) {
+	tests := map[string]string{
+		"foo": "foo",
+		"shards/00000000-1fffffff/foo.1712345678":     "foo",
+		"shards/00000000-1fffffff/foo.bar.1712345678": "foo.bar",
+		"shards/00000000-1fffffff/a/b.1712345678":     "a/b",
+	}
+	for input, want := range tests {
+		if got := taskDBName(input); got != want {
+			t.Errorf("taskDBName(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package kivik
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/go-kivik/kivik/v4/driver"
+	internal "github.com/go-kivik/kivik/v4/int/errors"
+	"github.com/go-kivik/kivik/v4/x/mango"
+)
+
+// WatchFunc is called by [DB.Watch] for each change to a document matching the
+// selector. Returning a non-nil error stops the watch.
+type WatchFunc func(ctx context.Context, change *Change) error
+
+// Delays between attempts to re-open the changes feed after a transient error.
+const (
+	watchMinBackoff = 100 * time.Millisecond
+	watchMaxBackoff = 30 * time.Second
+)
+
+// Watch follows the continuous changes feed, with include_docs=true, and calls
+// fn for each change to a document matching selector. selector may be a
+// string, []byte, [encoding/json.RawMessage], [github.com/go-kivik/kivik/v4/x/mango.Expr],
+// or any other value which marshals to a valid Mango selector.
+//
+// When the driver supports it, as CouchDB does with filter=_selector, the
+// selector is evaluated by the server. Otherwise documents are matched
+// locally, after they are received.
+//
+// The feed is re-opened from the last seen update sequence after transient
+// errors, such as network failures and 5xx responses. Watch blocks until ctx
+// is cancelled, fn returns an error, or the feed fails permanently, and
+// returns the cause. options are passed to [DB.Changes], and may be used to
+// set the initial since value, or the heartbeat.
+func (db *DB) Watch(ctx context.Context, selector interface{}, fn WatchFunc, options ...Option) error {
+	if db.err != nil {
+		return db.err
+	}
+	rawSelector, err := toSelector(selector)
+	if err != nil {
+		return err
+	}
+	sel := new(mango.Selector)
+	if err := json.Unmarshal(rawSelector, sel); err != nil {
+		return &internal.Error{Status: http.StatusBadRequest, Err: err}
+	}
+	_, serverSide := db.driverDB.(driver.SelectorChanger)
+
+	var since string
+	backoff := watchMinBackoff
+	for {
+		opts := append(append([]Option{}, options...), Params(map[string]interface{}{
+			"feed":         "continuous",
+			"include_docs": true,
+		}))
+		if since != "" {
+			opts = append(opts, Param("since", since))
+		}
+		var changes *Changes
+		if serverSide {
+			changes = db.SelectorChanges(ctx, rawSelector, opts...)
+		} else {
+			changes = db.Changes(ctx, opts...)
+		}
+		progress := false
+		for changes.Next() {
+			dChange := changes.curVal.(*driver.Change)
+			if !serverSide && !sel.Match(docValue(dChange.Doc)) {
+				since = dChange.Seq
+				continue
+			}
+			change := &Change{
+				ID:      dChange.ID,
+				Seq:     dChange.Seq,
+				Deleted: dChange.Deleted,
+				Changes: dChange.Changes,
+				doc:     dChange.Doc,
+			}
+			if err := fn(ctx, change); err != nil {
+				_ = changes.Close()
+				return err
+			}
+			since = change.Seq
+			progress = true
+		}
+		err := changes.Err()
+		if err == nil {
+			if meta, _ := changes.Metadata(); meta != nil && meta.LastSeq != "" {
+				since = meta.LastSeq
+			}
+		}
+		_ = changes.Close()
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if err != nil && !isTransient(err) {
+			return err
+		}
+		if progress {
+			backoff = watchMinBackoff
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > watchMaxBackoff {
+			backoff = watchMaxBackoff
+		}
+	}
+}
+
+// toSelector converts selector to raw JSON.
+func toSelector(selector interface{}) (json.RawMessage, error) {
+	switch t := selector.(type) {
+	case string:
+		return json.RawMessage(t), nil
+	case []byte:
+		return json.RawMessage(t), nil
+	case json.RawMessage:
+		return t, nil
+	}
+	raw, err := json.Marshal(selector)
+	if err != nil {
+		return nil, &internal.Error{Status: http.StatusBadRequest, Err: err}
+	}
+	return raw, nil
+}
+
+// docValue decodes doc for matching against a selector. A missing or invalid
+// document is treated as null.
+func docValue(doc json.RawMessage) interface{} {
+	var v interface{}
+	_ = json.Unmarshal(doc, &v)
+	return v
+}
+
+// isTransient returns true if err may be resolved by retrying the request.
+func isTransient(err error) bool {
+	switch status := HTTPStatus(err); status {
+	case http.StatusRequestTimeout, http.StatusTooManyRequests:
+		return true
+	case http.StatusNotImplemented:
+		return false
+	default:
+		return status >= http.StatusInternalServerError
+	}
+}
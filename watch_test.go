@@ -0,0 +1,233 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package kivik
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"gitlab.com/flimzy/testy"
+
+	"github.com/go-kivik/kivik/v4/driver"
+	internal "github.com/go-kivik/kivik/v4/int/errors"
+	"github.com/go-kivik/kivik/v4/int/mock"
+	"github.com/go-kivik/kivik/v4/x/mango"
+)
+
+// changesFeed returns a mock feed of changes, followed by err, or io.EOF if
+// err is nil.
+func changesFeed(err error, changes ...driver.Change) *mock.Changes {
+	return &mock.Changes{
+		NextFunc: func(change *driver.Change) error {
+			if len(changes) == 0 {
+				if err == nil {
+					return io.EOF
+				}
+				return err
+			}
+			*change = changes[0]
+			changes = changes[1:]
+			return nil
+		},
+	}
+}
+
+func TestDBWatch(t *testing.T) {
+	errStop := errors.New("stop")
+	type tt struct {
+		db       *DB
+		selector interface{}
+		options  Option
+		stopAt   string
+		wantIDs  []string
+		status   int
+		err      string
+	}
+
+	tests := testy.NewTable()
+	tests.Add("db error", tt{
+		db: &DB{
+			client: &Client{},
+			err:    errors.New("db error"),
+		},
+		selector: `{}`,
+		status:   http.StatusInternalServerError,
+		err:      "db error",
+	})
+	tests.Add("invalid selector", tt{
+		db: &DB{
+			client:   &Client{},
+			driverDB: &mock.DB{},
+		},
+		selector: `{"a":{"$mod":[0,1]}}`,
+		status:   http.StatusBadRequest,
+		err:      "Bad argument for operator $mod: [0,1]",
+	})
+	tests.Add("unmarshalable selector", tt{
+		db: &DB{
+			client:   &Client{},
+			driverDB: &mock.DB{},
+		},
+		selector: make(chan int),
+		status:   http.StatusBadRequest,
+		err:      "json: unsupported type: chan int",
+	})
+	tests.Add("not implemented", tt{
+		db: &DB{
+			client: &Client{},
+			driverDB: &mock.DB{
+				ChangesFunc: func(context.Context, driver.Options) (driver.Changes, error) {
+					return nil, &internal.Error{Status: http.StatusNotImplemented, Message: "no changes"}
+				},
+			},
+		},
+		selector: `{}`,
+		status:   http.StatusNotImplemented,
+		err:      "no changes",
+	})
+	tests.Add("client-side filter", tt{
+		db: &DB{
+			client: &Client{},
+			driverDB: &mock.DB{
+				ChangesFunc: func(_ context.Context, options driver.Options) (driver.Changes, error) {
+					opts := map[string]interface{}{}
+					options.Apply(opts)
+					want := map[string]interface{}{
+						"feed":         "continuous",
+						"include_docs": true,
+						"heartbeat":    1000,
+					}
+					if d := testy.DiffInterface(want, opts); d != nil {
+						return nil, fmt.Errorf("Unexpected options:\n%s", d)
+					}
+					return changesFeed(&internal.Error{Status: http.StatusBadRequest, Message: "done"},
+						driver.Change{ID: "a", Seq: "1", Doc: json.RawMessage(`{"type":"user"}`)},
+						driver.Change{ID: "b", Seq: "2", Doc: json.RawMessage(`{"type":"group"}`)},
+						driver.Change{ID: "c", Seq: "3", Doc: json.RawMessage(`{"type":"user"}`)},
+						driver.Change{ID: "d", Seq: "4", Deleted: true, Doc: json.RawMessage(`{"_deleted":true}`)},
+					), nil
+				},
+			},
+		},
+		selector: mango.Field("type").Eq("user"),
+		options:  Param("heartbeat", 1000),
+		wantIDs:  []string{"a", "c"},
+		status:   http.StatusBadRequest,
+		err:      "done",
+	})
+	tests.Add("resume after transient error", func(t *testing.T) interface{} {
+		var calls int
+		return tt{
+			db: &DB{
+				client: &Client{},
+				driverDB: &mock.DB{
+					ChangesFunc: func(_ context.Context, options driver.Options) (driver.Changes, error) {
+						opts := map[string]interface{}{}
+						options.Apply(opts)
+						calls++
+						switch calls {
+						case 1:
+							if _, ok := opts["since"]; ok {
+								t.Errorf("Unexpected since: %v", opts["since"])
+							}
+							return changesFeed(&internal.Error{Status: http.StatusBadGateway, Message: "connection reset"},
+								driver.Change{ID: "a", Seq: "1", Doc: json.RawMessage(`{}`)},
+							), nil
+						case 2:
+							if opts["since"] != "1" {
+								t.Errorf("Unexpected since: %v", opts["since"])
+							}
+							return nil, &internal.Error{Status: http.StatusServiceUnavailable, Message: "unavailable"}
+						default:
+							if opts["since"] != "1" {
+								t.Errorf("Unexpected since: %v", opts["since"])
+							}
+							return changesFeed(nil,
+								driver.Change{ID: "b", Seq: "2", Doc: json.RawMessage(`{}`)},
+							), nil
+						}
+					},
+				},
+			},
+			selector: `{}`,
+			stopAt:   "b",
+			wantIDs:  []string{"a", "b"},
+			status:   http.StatusInternalServerError,
+			err:      "stop",
+		}
+	})
+	tests.Add("server-side filter", tt{
+		db: &DB{
+			client: &Client{},
+			driverDB: &mock.SelectorChanger{
+				DB: &mock.DB{},
+				SelectorChangesFunc: func(_ context.Context, selector json.RawMessage, _ driver.Options) (driver.Changes, error) {
+					if d := testy.DiffJSON([]byte(`{"type":"user"}`), []byte(selector)); d != nil {
+						return nil, fmt.Errorf("Unexpected selector:\n%s", d)
+					}
+					// Documents are not matched again locally.
+					return changesFeed(nil,
+						driver.Change{ID: "a", Seq: "1", Doc: json.RawMessage(`{"type":"user"}`)},
+						driver.Change{ID: "b", Seq: "2", Doc: json.RawMessage(`{}`)},
+					), nil
+				},
+			},
+		},
+		selector: map[string]string{"type": "user"},
+		stopAt:   "b",
+		wantIDs:  []string{"a", "b"},
+		status:   http.StatusInternalServerError,
+		err:      "stop",
+	})
+
+	tests.Run(t, func(t *testing.T, tt tt) {
+		var gotIDs []string
+		err := tt.db.Watch(context.Background(), tt.selector, func(_ context.Context, change *Change) error {
+			gotIDs = append(gotIDs, change.ID)
+			if change.ID == tt.stopAt {
+				return errStop
+			}
+			return nil
+		}, tt.options)
+		if d := internal.StatusErrorDiff(tt.err, tt.status, err); d != "" {
+			t.Error(d)
+		}
+		if d := testy.DiffInterface(tt.wantIDs, gotIDs); d != nil {
+			t.Error(d)
+		}
+	})
+}
+
+func TestDBWatchCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	db := &DB{
+		client: &Client{},
+		driverDB: &mock.DB{
+			ChangesFunc: func(context.Context, driver.Options) (driver.Changes, error) {
+				return changesFeed(nil, driver.Change{ID: "a", Seq: "1", Doc: json.RawMessage(`{}`)}), nil
+			},
+		},
+	}
+	err := db.Watch(ctx, `{}`, func(context.Context, *Change) error {
+		cancel()
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
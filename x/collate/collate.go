@@ -62,6 +62,8 @@ func CompareObject(a, b interface{}) int {
 	}
 
 	switch aType {
+	case jsonTypeNull:
+		return 0
 	case jsonTypeBool:
 		aBool := a.(bool)
 		bBool := b.(bool)
@@ -74,7 +76,13 @@ func CompareObject(a, b interface{}) int {
 		}
 		return 1
 	case jsonTypeNumber:
-		return int(a.(float64) - b.(float64))
+		switch aNum, bNum := a.(float64), b.(float64); {
+		case aNum < bNum:
+			return -1
+		case aNum > bNum:
+			return 1
+		}
+		return 0
 	case jsonTypeString:
 		return CompareString(a.(string), b.(string))
 	case jsonTypeArray:
@@ -96,6 +104,9 @@ func CompareObject(a, b interface{}) int {
 		for k := range bObject {
 			keyMap[k] = struct{}{}
 		}
+		if len(aObject) == 0 || len(bObject) == 0 {
+			return len(aObject) - len(bObject)
+		}
 		keys := make([]string, 0, len(keyMap))
 		for k := range keyMap {
 			keys = append(keys, k)
@@ -120,6 +131,7 @@ func CompareObject(a, b interface{}) int {
 				return len(aObject) - len(bObject)
 			}
 		}
+		return 0
 	}
 	panic("unexpected JSON type")
 }
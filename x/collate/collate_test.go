@@ -60,6 +60,7 @@ func TestCompareObject(t *testing.T) {
 
 		// then numbers
 		float64(1),
+		float64(1.5),
 		float64(2),
 		float64(3.0),
 		float64(4),
@@ -84,6 +85,7 @@ func TestCompareObject(t *testing.T) {
 
 		// then object, compares each key value in the list until different.
 		// larger objects sort after their subset objects.
+		map[string]interface{}{},
 		map[string]interface{}{"a": float64(1)},
 		map[string]interface{}{"a": float64(2)},
 		map[string]interface{}{"b": float64(1)},
@@ -106,3 +108,20 @@ func TestCompareObject(t *testing.T) {
 		t.Errorf("Unexpected result:\n%s", d)
 	}
 }
+
+func TestCompareObjectEqual(t *testing.T) {
+	values := []interface{}{
+		nil,
+		true,
+		float64(1.5),
+		"a",
+		[]interface{}{},
+		map[string]interface{}{},
+		map[string]interface{}{"a": nil},
+	}
+	for _, v := range values {
+		if c := CompareObject(v, v); c != 0 {
+			t.Errorf("Expected %v to equal itself, got %d", v, c)
+		}
+	}
+}
@@ -0,0 +1,193 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+// Package ddocs manages design documents kept as source files in a directory
+// tree, in the style of couchapp.
+//
+// Each top-level directory of the tree is a design document of the same name,
+// laid out as:
+//
+//	{name}/ddoc.json                  optional; any other fields, e.g. language or options
+//	{name}/views/{view}/map.js        map function of the named view
+//	{name}/views/{view}/reduce.js     optional reduce function, or a builtin such as _count
+//	{name}/filters/{filter}.js        filter functions
+//	{name}/validate_doc_update.js     validation function
+//
+// Other files are ignored.
+package ddocs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+)
+
+// DesignDoc is a design document read from source files.
+type DesignDoc struct {
+	// Name is the design document name, without the _design/ prefix.
+	Name string
+	// Views maps view names to their functions.
+	Views map[string]*View
+	// Filters maps filter names to their functions.
+	Filters map[string]string
+	// ValidateDocUpdate is the validation function, if any.
+	ValidateDocUpdate string
+	// Extra holds any other fields, as read from ddoc.json.
+	Extra map[string]interface{}
+}
+
+// View is a single view of a design document.
+type View struct {
+	Map    string `json:"map"`
+	Reduce string `json:"reduce,omitempty"`
+}
+
+// ID returns the document ID of the design document.
+func (d *DesignDoc) ID() string {
+	return "_design/" + d.Name
+}
+
+// Doc returns the body of the design document, without _id or _rev.
+func (d *DesignDoc) Doc() map[string]interface{} {
+	doc := make(map[string]interface{}, len(d.Extra)+3) // nolint:gomnd
+	for k, v := range d.Extra {
+		doc[k] = v
+	}
+	if len(d.Views) > 0 {
+		doc["views"] = d.Views
+	}
+	if len(d.Filters) > 0 {
+		doc["filters"] = d.Filters
+	}
+	if d.ValidateDocUpdate != "" {
+		doc["validate_doc_update"] = d.ValidateDocUpdate
+	}
+	return doc
+}
+
+// LoadDir reads the design documents found in dir. See [Load].
+func LoadDir(dir string) ([]*DesignDoc, error) {
+	if _, err := os.Stat(dir); err != nil {
+		return nil, err
+	}
+	return Load(os.DirFS(dir))
+}
+
+// Load reads the design documents found in fsys, one per top-level directory,
+// ordered by name.
+func Load(fsys fs.FS) ([]*DesignDoc, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+	var ddocs []*DesignDoc
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		ddoc, err := loadDDoc(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		ddocs = append(ddocs, ddoc)
+	}
+	return ddocs, nil
+}
+
+func loadDDoc(fsys fs.FS, name string) (*DesignDoc, error) {
+	ddoc := &DesignDoc{Name: name}
+	if raw, err := fs.ReadFile(fsys, path.Join(name, "ddoc.json")); err == nil {
+		if err := json.Unmarshal(raw, &ddoc.Extra); err != nil {
+			return nil, fmt.Errorf("%s: %w", path.Join(name, "ddoc.json"), err)
+		}
+		delete(ddoc.Extra, "_id")
+		delete(ddoc.Extra, "_rev")
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	views, err := readDir(fsys, path.Join(name, "views"))
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range views {
+		if !entry.IsDir() {
+			continue
+		}
+		dir := path.Join(name, "views", entry.Name())
+		mapFn, err := readFunc(fsys, path.Join(dir, "map.js"))
+		if err != nil {
+			return nil, err
+		}
+		if mapFn == "" {
+			return nil, fmt.Errorf("%s: missing map function", dir)
+		}
+		reduceFn, err := readFunc(fsys, path.Join(dir, "reduce.js"))
+		if err != nil {
+			return nil, err
+		}
+		if ddoc.Views == nil {
+			ddoc.Views = map[string]*View{}
+		}
+		ddoc.Views[entry.Name()] = &View{Map: mapFn, Reduce: reduceFn}
+	}
+
+	filters, err := readDir(fsys, path.Join(name, "filters"))
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range filters {
+		if entry.IsDir() || path.Ext(entry.Name()) != ".js" {
+			continue
+		}
+		fn, err := readFunc(fsys, path.Join(name, "filters", entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		if ddoc.Filters == nil {
+			ddoc.Filters = map[string]string{}
+		}
+		ddoc.Filters[strings.TrimSuffix(entry.Name(), ".js")] = fn
+	}
+
+	ddoc.ValidateDocUpdate, err = readFunc(fsys, path.Join(name, "validate_doc_update.js"))
+	if err != nil {
+		return nil, err
+	}
+	return ddoc, nil
+}
+
+// readDir is like [fs.ReadDir], but a missing directory is not an error.
+func readDir(fsys fs.FS, name string) ([]fs.DirEntry, error) {
+	entries, err := fs.ReadDir(fsys, name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	return entries, err
+}
+
+// readFunc returns the trimmed contents of the named file, or "" if it does not
+// exist.
+func readFunc(fsys fs.FS, name string) (string, error) {
+	raw, err := fs.ReadFile(fsys, name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(raw)), nil
+}
@@ -0,0 +1,115 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package ddocs
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"gitlab.com/flimzy/testy"
+)
+
+func TestLoad(t *testing.T) {
+	type tt struct {
+		fsys fstest.MapFS
+		want []*DesignDoc
+		err  string
+	}
+
+	tests := testy.NewTable()
+	tests.Add("empty", tt{
+		fsys: fstest.MapFS{},
+	})
+	tests.Add("view without map function", tt{
+		fsys: fstest.MapFS{
+			"app/views/count/reduce.js": {Data: []byte("_sum")},
+		},
+		err: "app/views/count: missing map function",
+	})
+	tests.Add("hidden directories ignored", tt{
+		fsys: fstest.MapFS{
+			".git/views/x/map.js": {Data: []byte("ignored")},
+			"README.md":           {Data: []byte("ignored")},
+		},
+	})
+	tests.Add("ordered by name", tt{
+		fsys: fstest.MapFS{
+			"b/views/v/map.js":  {Data: []byte("function(doc) { emit(doc.b); }")},
+			"a/validate.txt":    {Data: []byte("ignored")},
+			"c/ddoc.json":       {Data: []byte(`{"language":"query"}`)},
+			"a/filters/f.js":    {Data: []byte("function(doc) { return true; }")},
+			"b/views/v/extra.x": {Data: []byte("ignored")},
+		},
+		want: []*DesignDoc{
+			{
+				Name:    "a",
+				Filters: map[string]string{"f": "function(doc) { return true; }"},
+			},
+			{
+				Name:  "b",
+				Views: map[string]*View{"v": {Map: "function(doc) { emit(doc.b); }"}},
+			},
+			{
+				Name:  "c",
+				Extra: map[string]interface{}{"language": "query"},
+			},
+		},
+	})
+	tests.Add("invalid ddoc.json", tt{
+		fsys: fstest.MapFS{
+			"app/ddoc.json": {Data: []byte(`not json`)},
+		},
+		err: "app/ddoc.json: invalid character 'o' in literal null (expecting 'u')",
+	})
+
+	tests.Run(t, func(t *testing.T, tt tt) {
+		got, err := Load(tt.fsys)
+		if !testy.ErrorMatches(tt.err, err) {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if d := testy.DiffInterface(tt.want, got); d != nil {
+			t.Error(d)
+		}
+	})
+}
+
+func TestLoad_full(t *testing.T) {
+	got, err := Load(fstest.MapFS{
+		"app/ddoc.json":              {Data: []byte(`{"_id":"_design/other","language":"javascript","options":{"local_seq":true}}`)},
+		"app/views/byName/map.js":    {Data: []byte("function(doc) {\n  emit(doc.name);\n}\n")},
+		"app/views/byName/reduce.js": {Data: []byte("_count\n")},
+		"app/filters/important.js":   {Data: []byte("function(doc, req) { return doc.important; }")},
+		"app/validate_doc_update.js": {Data: []byte("function(newDoc, oldDoc, userCtx) {}")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("Expected 1 design doc, got %d", len(got))
+	}
+	if id := got[0].ID(); id != "_design/app" {
+		t.Errorf("Unexpected ID: %s", id)
+	}
+	want := map[string]interface{}{
+		"language": "javascript",
+		"options":  map[string]interface{}{"local_seq": true},
+		"views": map[string]*View{
+			"byName": {Map: "function(doc) {\n  emit(doc.name);\n}", Reduce: "_count"},
+		},
+		"filters":             map[string]string{"important": "function(doc, req) { return doc.important; }"},
+		"validate_doc_update": "function(newDoc, oldDoc, userCtx) {}",
+	}
+	if d := testy.DiffInterface(want, got[0].Doc()); d != nil {
+		t.Error(d)
+	}
+}
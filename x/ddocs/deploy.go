@@ -0,0 +1,176 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package ddocs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+
+	"github.com/go-kivik/kivik/v4"
+)
+
+// stagingSuffix is appended to the name of a design document, to form the
+// name of its staged copy.
+const stagingSuffix = "-staging"
+
+// Change is a design document which differs from the deployed version.
+type Change struct {
+	DDoc *DesignDoc
+	// Rev is the deployed revision, or "" if the design document is new.
+	Rev string
+	// Diff is a unified diff from the deployed design document to DDoc, as
+	// indented JSON.
+	Diff string
+}
+
+// Plan compares ddocs with the design documents deployed to db, and returns
+// those that differ.
+func Plan(ctx context.Context, db *kivik.DB, ddocs []*DesignDoc) ([]*Change, error) {
+	var changes []*Change
+	for _, ddoc := range ddocs {
+		var deployed map[string]interface{}
+		err := db.Get(ctx, ddoc.ID()).ScanDoc(&deployed)
+		if err != nil && kivik.HTTPStatus(err) != http.StatusNotFound {
+			return nil, err
+		}
+		change := &Change{DDoc: ddoc}
+		var from string
+		if deployed != nil {
+			change.Rev, _ = deployed["_rev"].(string)
+			delete(deployed, "_id")
+			delete(deployed, "_rev")
+			if from, err = formatDoc(deployed); err != nil {
+				return nil, err
+			}
+		}
+		to, err := formatDoc(ddoc.Doc())
+		if err != nil {
+			return nil, err
+		}
+		if from == to {
+			continue
+		}
+		change.Diff, err = difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        splitLines(from),
+			B:        splitLines(to),
+			FromFile: ddoc.ID() + " (deployed)",
+			ToFile:   ddoc.ID() + " (local)",
+			Context:  3, // nolint:gomnd
+		})
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+// splitLines splits s into lines, each retaining its newline.
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.SplitAfter(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// formatDoc returns doc as indented JSON, with keys sorted, suitable for
+// comparison.
+func formatDoc(doc interface{}) (string, error) {
+	raw, err := json.Marshal(doc)
+	if err != nil {
+		return "", err
+	}
+	var v interface{}
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return "", err
+	}
+	buf := &bytes.Buffer{}
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// Deploy applies changes to db, without making views unavailable while they
+// are rebuilt. Each design document is first put as _design/{name}-staging,
+// then the views of all staged documents are built with [kivik.DB.WarmViews],
+// to which options are passed. Finally each staged document is copied over
+// the live one with [kivik.DB.Copy], whose index is already built as the two
+// are identical, and the staged document is deleted. If any step fails, the
+// remaining staged documents are deleted before the error is returned.
+//
+// The live document is replaced entirely, so any fields not present in the
+// local source, such as attachments, are removed. If a live document has been
+// updated since [Plan] was called, the copy fails with a conflict. Copying over
+// an existing document relies on the driver supporting a rev-qualified
+// destination, as CouchDB does.
+//
+// Deploy returns the new revisions of the live design documents, in the same
+// order as changes.
+func Deploy(ctx context.Context, db *kivik.DB, changes []*Change, options ...kivik.Option) ([]string, error) {
+	stagingIDs := make([]string, len(changes))
+	stagingRevs := make([]string, len(changes))
+	for i, change := range changes {
+		stagingIDs[i] = change.DDoc.ID() + stagingSuffix
+		rev, err := db.GetRev(ctx, stagingIDs[i])
+		if err != nil && kivik.HTTPStatus(err) != http.StatusNotFound {
+			return nil, err
+		}
+		doc := change.DDoc.Doc()
+		if rev != "" {
+			doc["_rev"] = rev
+		}
+		if stagingRevs[i], err = db.Put(ctx, stagingIDs[i], doc); err != nil {
+			return nil, err
+		}
+	}
+	if err := db.WarmViews(ctx, stagingIDs, options...); err != nil {
+		deleteStaging(ctx, db, stagingIDs, stagingRevs)
+		return nil, err
+	}
+	revs := make([]string, len(changes))
+	for i, change := range changes {
+		target := change.DDoc.ID()
+		if change.Rev != "" {
+			target += "?rev=" + change.Rev
+		}
+		rev, err := db.Copy(ctx, target, stagingIDs[i])
+		if err != nil {
+			deleteStaging(ctx, db, stagingIDs[i:], stagingRevs[i:])
+			return nil, err
+		}
+		revs[i] = rev
+		if _, err := db.Delete(ctx, stagingIDs[i], stagingRevs[i]); err != nil {
+			deleteStaging(ctx, db, stagingIDs[i+1:], stagingRevs[i+1:])
+			return nil, err
+		}
+	}
+	return revs, nil
+}
+
+// deleteStaging deletes the staged documents ids, at revs, after a failed
+// deployment. The original error is more useful than any cleanup failure, so
+// those are ignored.
+func deleteStaging(ctx context.Context, db *kivik.DB, ids, revs []string) {
+	for i, id := range ids {
+		_, _ = db.Delete(ctx, id, revs[i])
+	}
+}
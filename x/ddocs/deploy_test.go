@@ -0,0 +1,238 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package ddocs
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"gitlab.com/flimzy/testy"
+
+	"github.com/go-kivik/kivik/v4"
+	_ "github.com/go-kivik/kivik/v4/x/memorydb" // memory driver
+)
+
+func newDB(t *testing.T) *kivik.DB {
+	t.Helper()
+	client, err := kivik.New("memory", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.CreateDB(context.Background(), "db"); err != nil {
+		t.Fatal(err)
+	}
+	return client.DB("db")
+}
+
+func byAge(mapFn string) *DesignDoc {
+	return &DesignDoc{
+		Name:  "people",
+		Views: map[string]*View{"byAge": {Map: mapFn}},
+	}
+}
+
+func TestPlan(t *testing.T) {
+	ctx := context.Background()
+	db := newDB(t)
+	rev, err := db.Put(ctx, "_design/people", map[string]interface{}{
+		"views": map[string]interface{}{
+			"byAge": map[string]string{"map": "function(doc) { emit(doc.age); }"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	unchanged := byAge("function(doc) { emit(doc.age); }")
+	changed := byAge("function(doc) { if (doc.age > 0) { emit(doc.age); } }")
+	added := &DesignDoc{Name: "new", ValidateDocUpdate: "function() {}"}
+
+	changes, err := Plan(ctx, db, []*DesignDoc{unchanged})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 0 {
+		t.Errorf("Expected no changes, got %d", len(changes))
+	}
+
+	changes, err = Plan(ctx, db, []*DesignDoc{changed, added})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 2 { // nolint:gomnd
+		t.Fatalf("Expected 2 changes, got %d", len(changes))
+	}
+	if changes[0].Rev != rev {
+		t.Errorf("Unexpected rev: %s", changes[0].Rev)
+	}
+	if changes[1].Rev != "" {
+		t.Errorf("Unexpected rev for new ddoc: %s", changes[1].Rev)
+	}
+	wantDiff := `--- _design/people (deployed)
++++ _design/people (local)
+@@ -1,7 +1,7 @@
+ {
+   "views": {
+     "byAge": {
+-      "map": "function(doc) { emit(doc.age); }"
++      "map": "function(doc) { if (doc.age > 0) { emit(doc.age); } }"
+     }
+   }
+ }
+`
+	if d := testy.DiffText(wantDiff, changes[0].Diff); d != nil {
+		t.Error(d)
+	}
+	wantDiff = `--- _design/new (deployed)
++++ _design/new (local)
+@@ -0,0 +1,3 @@
++{
++  "validate_doc_update": "function() {}"
++}
+`
+	if d := testy.DiffText(wantDiff, changes[1].Diff); d != nil {
+		t.Error(d)
+	}
+}
+
+func TestDeploy(t *testing.T) {
+	ctx := context.Background()
+	db := newDB(t)
+	if _, err := db.Put(ctx, "_design/people", map[string]interface{}{
+		"views": map[string]interface{}{
+			"byAge": map[string]string{"map": "function(doc) { emit(doc.age); }"},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	// A leftover from an earlier, failed deployment.
+	if _, err := db.Put(ctx, "_design/people-staging", map[string]interface{}{}); err != nil {
+		t.Fatal(err)
+	}
+
+	local := []*DesignDoc{
+		byAge("function(doc) { emit(doc.age * 2); }"),
+		{Name: "new", ValidateDocUpdate: "function() {}"},
+	}
+	changes, err := Plan(ctx, db, local)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var warmed []string
+	revs, err := Deploy(ctx, db, changes, kivik.WarmProgress(func(p kivik.ViewProgress) {
+		if p.Done {
+			warmed = append(warmed, p.DDoc+"/"+p.View)
+		}
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(revs) != 2 { // nolint:gomnd
+		t.Fatalf("Expected 2 revs, got %d", len(revs))
+	}
+	if d := testy.DiffInterface([]string{"people-staging/byAge"}, warmed); d != nil {
+		t.Errorf("Unexpected views warmed:\n%s", d)
+	}
+
+	for i, ddoc := range local {
+		var got map[string]interface{}
+		if err := db.Get(ctx, ddoc.ID()).ScanDoc(&got); err != nil {
+			t.Fatal(err)
+		}
+		if got["_rev"] != revs[i] {
+			t.Errorf("%s: unexpected rev %v, expected %s", ddoc.Name, got["_rev"], revs[i])
+		}
+		if _, err := db.GetRev(ctx, ddoc.ID()+stagingSuffix); kivik.HTTPStatus(err) != http.StatusNotFound {
+			t.Errorf("%s: expected staging doc to be deleted, got: %v", ddoc.Name, err)
+		}
+	}
+
+	changes, err = Plan(ctx, db, local)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 0 {
+		t.Errorf("Expected no changes after deploy, got %d", len(changes))
+	}
+}
+
+func TestDeploy_conflict(t *testing.T) {
+	ctx := context.Background()
+	db := newDB(t)
+	if _, err := db.Put(ctx, "_design/people", map[string]interface{}{}); err != nil {
+		t.Fatal(err)
+	}
+	local := []*DesignDoc{
+		byAge("function(doc) { emit(doc.age); }"),
+		{Name: "new", ValidateDocUpdate: "function() {}"},
+	}
+	changes, err := Plan(ctx, db, local)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Put(ctx, "_design/people", map[string]interface{}{"_rev": changes[0].Rev}); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = Deploy(ctx, db, changes)
+	if status := kivik.HTTPStatus(err); status != http.StatusConflict {
+		t.Errorf("Expected conflict, got: %v", err)
+	}
+	for _, ddoc := range local {
+		if _, err := db.GetRev(ctx, ddoc.ID()+stagingSuffix); kivik.HTTPStatus(err) != http.StatusNotFound {
+			t.Errorf("%s: expected staging doc to be deleted, got: %v", ddoc.Name, err)
+		}
+	}
+}
+
+func TestDeploy_warmFailure(t *testing.T) {
+	ctx := context.Background()
+	db := newDB(t)
+	live, err := db.Put(ctx, "_design/people", map[string]interface{}{
+		"views": map[string]interface{}{
+			"byAge": map[string]string{"map": "function(doc) { emit(doc.age); }"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Put(ctx, "bob", map[string]interface{}{"age": 42}); err != nil {
+		t.Fatal(err)
+	}
+	local := []*DesignDoc{
+		byAge("function(doc) { emit(doc.age * 2); }"),
+		{Name: "new", ValidateDocUpdate: "function() {}"},
+	}
+	changes, err := Plan(ctx, db, local)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Reduce is invalid for a map-only view, so building it fails.
+	_, err = Deploy(ctx, db, changes, kivik.Param("reduce", true))
+	if status := kivik.HTTPStatus(err); status != http.StatusBadRequest {
+		t.Fatalf("Expected bad request, got: %v", err)
+	}
+	for _, ddoc := range local {
+		if _, err := db.GetRev(ctx, ddoc.ID()+stagingSuffix); kivik.HTTPStatus(err) != http.StatusNotFound {
+			t.Errorf("%s: expected staging doc to be deleted, got: %v", ddoc.Name, err)
+		}
+	}
+	if rev, err := db.GetRev(ctx, "_design/people"); err != nil || rev != live {
+		t.Errorf("Expected live doc to be untouched, got rev %s: %v", rev, err)
+	}
+	if _, err := db.GetRev(ctx, "_design/new"); kivik.HTTPStatus(err) != http.StatusNotFound {
+		t.Errorf("Expected new doc not to be deployed, got: %v", err)
+	}
+}
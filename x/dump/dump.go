@@ -0,0 +1,309 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+// Package dump streams the contents of a database to and from a file, in the
+// style of pg_dump and pg_restore.
+//
+// A dump is newline-delimited JSON, with one [Record] per line. Every leaf
+// revision of every document is included, along with its revision history,
+// so that conflicts and deletions survive a round trip. Attachments are
+// either inlined as base64 data, or written as separate files to a
+// directory alongside the dump.
+//
+// Only the public kivik API is used, so a dump may be taken from one driver
+// and restored with another, for instance from CouchDB to SQLite.
+package dump
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-kivik/kivik/v4"
+)
+
+// Record types.
+const (
+	// TypeSecurity is the record type of the database's _security object.
+	TypeSecurity = "security"
+	// TypeDoc is the record type of a single document revision.
+	TypeDoc = "doc"
+	// TypeLocal is the record type of a _local document.
+	TypeLocal = "local"
+)
+
+const localPrefix = "_local/"
+
+// Record is a single line of a dump.
+type Record struct {
+	// Type is one of [TypeSecurity], [TypeDoc] or [TypeLocal].
+	Type string `json:"type"`
+	// Doc is the document or _security object. Document revisions include
+	// _revisions, and any attachments.
+	Doc json.RawMessage `json:"doc"`
+}
+
+// Options configures [Dump] and [Restore].
+type Options struct {
+	// Security includes the _security object in a dump.
+	Security bool
+	// Local includes _local documents in a dump.
+	Local bool
+	// AttachmentDir is the directory to which Dump writes attachments, and
+	// from which Restore reads them. If empty, attachments are inlined.
+	AttachmentDir string
+	// BatchSize is the number of document revisions Restore writes per bulk
+	// request. The default is 100.
+	BatchSize int
+}
+
+// Result summarizes a dump or restore.
+type Result struct {
+	// Docs is the number of document revisions dumped or restored.
+	Docs int `json:"docs"`
+	// Skipped is the number of document revisions which Restore found were
+	// already present in the target database.
+	Skipped int `json:"skipped,omitempty"`
+	// Local is the number of _local documents dumped or restored.
+	Local int `json:"local"`
+	// Attachments is the number of attachments dumped or restored.
+	Attachments int `json:"attachments"`
+	// Security is true if the _security object was dumped or restored.
+	Security bool `json:"security"`
+}
+
+// attachment is an entry of a document's _attachments.
+type attachment struct {
+	ContentType string `json:"content_type,omitempty"`
+	Digest      string `json:"digest,omitempty"`
+	Length      int64  `json:"length,omitempty"`
+	RevPos      int    `json:"revpos,omitempty"`
+	Stub        bool   `json:"stub,omitempty"`
+	// Data is the base64-encoded content, as a JSON string.
+	Data json.RawMessage `json:"data,omitempty"`
+	// File is the name of the file in the attachment directory which holds
+	// the content, when attachments are not inlined.
+	File string `json:"file,omitempty"`
+}
+
+type dumper struct {
+	db         *kivik.DB
+	enc        *json.Encoder
+	opts       Options
+	result     Result
+	noOpenRevs bool
+}
+
+// Dump writes every leaf revision of every document in db to w, as described
+// in the package documentation. The _security object and _local documents are
+// written only if requested by opts.
+func Dump(ctx context.Context, db *kivik.DB, w io.Writer, opts Options) (*Result, error) {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	d := &dumper{db: db, enc: enc, opts: opts}
+	if opts.AttachmentDir != "" {
+		if err := os.MkdirAll(opts.AttachmentDir, 0o777); err != nil { // nolint:gomnd
+			return nil, err
+		}
+	}
+	if opts.Security {
+		if err := d.dumpSecurity(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if err := d.dumpDocs(ctx); err != nil {
+		return nil, err
+	}
+	if opts.Local {
+		if err := d.dumpLocal(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return &d.result, nil
+}
+
+func (d *dumper) write(recType string, doc json.RawMessage) error {
+	return d.enc.Encode(Record{Type: recType, Doc: doc})
+}
+
+func (d *dumper) dumpSecurity(ctx context.Context) error {
+	sec, err := d.db.Security(ctx)
+	if err != nil {
+		return fmt.Errorf("read security: %w", err)
+	}
+	doc, err := json.Marshal(sec)
+	if err != nil {
+		return err
+	}
+	d.result.Security = true
+	return d.write(TypeSecurity, doc)
+}
+
+func (d *dumper) dumpDocs(ctx context.Context) error {
+	changes := d.db.Changes(ctx, kivik.Param("style", "all_docs"))
+	defer changes.Close() // nolint:errcheck
+	for changes.Next() {
+		if strings.HasPrefix(changes.ID(), localPrefix) {
+			// Some drivers include _local documents in the changes feed.
+			continue
+		}
+		if err := d.dumpDoc(ctx, changes.ID(), changes.Changes()); err != nil {
+			return fmt.Errorf("dump %s: %w", changes.ID(), err)
+		}
+	}
+	if err := changes.Err(); err != nil {
+		return fmt.Errorf("read changes: %w", err)
+	}
+	return nil
+}
+
+// dumpDoc writes all leaf revisions of the document. revs are the leaf
+// revisions reported by the changes feed, which are used only if the driver
+// cannot report them itself.
+func (d *dumper) dumpDoc(ctx context.Context, id string, revs []string) error {
+	if !d.noOpenRevs {
+		err := d.dumpOpenRevs(ctx, id)
+		if kivik.HTTPStatus(err) != http.StatusNotImplemented {
+			return err
+		}
+		d.noOpenRevs = true
+	}
+	for _, rev := range revs {
+		var doc json.RawMessage
+		err := d.db.Get(ctx, id, kivik.Params(map[string]interface{}{
+			"rev":         rev,
+			"revs":        true,
+			"attachments": true,
+		})).ScanDoc(&doc)
+		if err != nil {
+			return err
+		}
+		if err := d.writeDoc(ctx, TypeDoc, id, rev, doc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *dumper) dumpOpenRevs(ctx context.Context, id string) error {
+	rs := d.db.OpenRevs(ctx, id, []string{"all"}, kivik.Param("revs", true))
+	defer rs.Close() // nolint:errcheck
+	for rs.Next() {
+		var doc json.RawMessage
+		if err := rs.ScanDoc(&doc); err != nil {
+			return err
+		}
+		rev, _ := rs.Rev()
+		if err := d.writeDoc(ctx, TypeDoc, id, rev, doc); err != nil {
+			return err
+		}
+	}
+	return rs.Err()
+}
+
+func (d *dumper) dumpLocal(ctx context.Context) error {
+	rs := d.db.LocalDocs(ctx, kivik.Param("include_docs", true))
+	defer rs.Close() // nolint:errcheck
+	for rs.Next() {
+		var doc json.RawMessage
+		if err := rs.ScanDoc(&doc); err != nil {
+			return err
+		}
+		id, _ := rs.ID()
+		if err := d.writeDoc(ctx, TypeLocal, id, "", doc); err != nil {
+			return fmt.Errorf("dump %s: %w", id, err)
+		}
+	}
+	if err := rs.Err(); err != nil {
+		return fmt.Errorf("read local docs: %w", err)
+	}
+	return nil
+}
+
+// writeDoc writes a document revision, after fetching the content of any
+// attachment stubs, and moving attachment content to the attachment
+// directory if configured.
+func (d *dumper) writeDoc(ctx context.Context, recType, id, rev string, doc json.RawMessage) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(doc, &fields); err != nil {
+		return err
+	}
+	if raw, ok := fields["_attachments"]; ok {
+		var atts map[string]*attachment
+		if err := json.Unmarshal(raw, &atts); err != nil {
+			return err
+		}
+		for filename, att := range atts {
+			if err := d.prepareAttachment(ctx, id, rev, filename, att); err != nil {
+				return fmt.Errorf("attachment %s: %w", filename, err)
+			}
+		}
+		var err error
+		if fields["_attachments"], err = json.Marshal(atts); err != nil {
+			return err
+		}
+		d.result.Attachments += len(atts)
+		if doc, err = json.Marshal(fields); err != nil {
+			return err
+		}
+	}
+	if recType == TypeLocal {
+		d.result.Local++
+	} else {
+		d.result.Docs++
+	}
+	return d.write(recType, doc)
+}
+
+func (d *dumper) prepareAttachment(ctx context.Context, id, rev, filename string, att *attachment) error {
+	if att.Stub {
+		a, err := d.db.GetAttachment(ctx, id, filename, kivik.Rev(rev))
+		if err != nil {
+			return err
+		}
+		defer a.Content.Close() // nolint:errcheck
+		content, err := io.ReadAll(a.Content)
+		if err != nil {
+			return err
+		}
+		if att.Data, err = json.Marshal(content); err != nil {
+			return err
+		}
+		att.Stub = false
+	}
+	if d.opts.AttachmentDir == "" {
+		return nil
+	}
+	var content []byte
+	if err := json.Unmarshal(att.Data, &content); err != nil {
+		return err
+	}
+	att.Data = nil
+	sum := sha256.Sum256(content)
+	att.File = hex.EncodeToString(sum[:])
+	path := filepath.Join(d.opts.AttachmentDir, att.File)
+	if _, err := os.Stat(path); err == nil {
+		// Attachments are named by content, so an existing file is identical.
+		return nil
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return os.WriteFile(path, content, 0o666) // nolint:gomnd
+}
@@ -0,0 +1,195 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package dump
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/go-kivik/kivik/v4"
+	"github.com/go-kivik/kivik/v4/driver"
+	internal "github.com/go-kivik/kivik/v4/int/errors"
+	kivikmock "github.com/go-kivik/kivik/v4/mockdb"
+)
+
+func newMock(t *testing.T) (*kivik.DB, *kivikmock.Client, *kivikmock.DB) {
+	t.Helper()
+	client, mock := kivikmock.NewT(t)
+	db := mock.NewDB()
+	mock.ExpectDB().WillReturn(db)
+	return client.DB("db"), mock, db
+}
+
+// expectDocs sets up expectations for a database with a conflicted document,
+// one of whose revisions has an attachment stub.
+func expectDocs(db *kivikmock.DB) {
+	db.ExpectChanges().
+		WithOptions(kivik.Param("style", "all_docs")).
+		WillReturn(kivikmock.NewChanges().
+			AddChange(&driver.Change{ID: "foo", Changes: []string{"2-b", "2-c"}}).
+			AddChange(&driver.Change{ID: "bar", Changes: []string{"1-a"}}))
+	db.ExpectOpenRevs().
+		WithDocID("foo").
+		WithRevs([]string{"all"}).
+		WillReturn(kivikmock.NewRows().
+			AddRow(&driver.Row{
+				ID:  "foo",
+				Rev: "2-b",
+				Doc: strings.NewReader(`{"_id":"foo","_rev":"2-b","_revisions":{"start":2,"ids":["b","a"]},"x":1}`),
+			}).
+			AddRow(&driver.Row{
+				ID:  "foo",
+				Rev: "2-c",
+				Doc: strings.NewReader(`{"_id":"foo","_rev":"2-c","_revisions":{"start":2,"ids":["c","a"]},"_attachments":{"a.txt":{"content_type":"text/plain","digest":"md5-kAFQmDzST7DWlj99KOF/cg==","length":3,"revpos":2,"stub":true}}}`),
+			}))
+	db.ExpectGetAttachment().
+		WithDocID("foo").
+		WithFilename("a.txt").
+		WithOptions(kivik.Rev("2-c")).
+		WillReturn(&driver.Attachment{
+			ContentType: "text/plain",
+			Content:     io.NopCloser(strings.NewReader("abc")),
+		})
+	db.ExpectOpenRevs().
+		WithDocID("bar").
+		WillReturn(kivikmock.NewRows().
+			AddRow(&driver.Row{
+				ID:  "bar",
+				Rev: "1-a",
+				Doc: strings.NewReader(`{"_id":"bar","_rev":"1-a","_revisions":{"start":1,"ids":["a"]}}`),
+			}))
+}
+
+func TestDump(t *testing.T) {
+	ctx := context.Background()
+	db, mock, mdb := newMock(t)
+	mdb.ExpectSecurity().WillReturn(&driver.Security{
+		Admins: driver.Members{Names: []string{"bob"}},
+	})
+	expectDocs(mdb)
+	mdb.ExpectLocalDocs().
+		WithOptions(kivik.Param("include_docs", true)).
+		WillReturn(kivikmock.NewRows().
+			AddRow(&driver.Row{
+				ID:  "_local/x",
+				Doc: strings.NewReader(`{"_id":"_local/x","_rev":"0-1","seq":5}`),
+			}))
+
+	buf := &bytes.Buffer{}
+	result, err := Dump(ctx, db, buf, Options{Security: true, Local: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"type":"security","doc":{"admins":{"names":["bob"]},"members":{}}}
+{"type":"doc","doc":{"_id":"foo","_rev":"2-b","_revisions":{"start":2,"ids":["b","a"]},"x":1}}
+{"type":"doc","doc":{"_attachments":{"a.txt":{"content_type":"text/plain","digest":"md5-kAFQmDzST7DWlj99KOF/cg==","length":3,"revpos":2,"data":"YWJj"}},"_id":"foo","_rev":"2-c","_revisions":{"start":2,"ids":["c","a"]}}}
+{"type":"doc","doc":{"_id":"bar","_rev":"1-a","_revisions":{"start":1,"ids":["a"]}}}
+{"type":"local","doc":{"_id":"_local/x","_rev":"0-1","seq":5}}
+`
+	if d := cmp.Diff(want, buf.String()); d != "" {
+		t.Errorf("Unexpected dump:\n%s", d)
+	}
+	wantResult := &Result{Docs: 3, Local: 1, Attachments: 1, Security: true}
+	if d := cmp.Diff(wantResult, result); d != "" {
+		t.Errorf("Unexpected result:\n%s", d)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDump_attachment_dir(t *testing.T) {
+	ctx := context.Background()
+	db, mock, mdb := newMock(t)
+	expectDocs(mdb)
+
+	dir := filepath.Join(t.TempDir(), "attachments")
+	buf := &bytes.Buffer{}
+	if _, err := Dump(ctx, db, buf, Options{AttachmentDir: dir}); err != nil {
+		t.Fatal(err)
+	}
+	const file = "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"
+	if !strings.Contains(buf.String(), `"a.txt":{"content_type":"text/plain","digest":"md5-kAFQmDzST7DWlj99KOF/cg==","length":3,"revpos":2,"file":"`+file+`"}`) {
+		t.Errorf("Attachment not written to file:\n%s", buf.String())
+	}
+	content, err := os.ReadFile(filepath.Join(dir, file))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "abc" {
+		t.Errorf("Unexpected attachment content: %s", content)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDump_no_open_revs(t *testing.T) {
+	ctx := context.Background()
+	db, mock, mdb := newMock(t)
+	mdb.ExpectChanges().
+		WillReturn(kivikmock.NewChanges().
+			AddChange(&driver.Change{ID: "foo", Changes: []string{"1-a"}}).
+			AddChange(&driver.Change{ID: "bar", Changes: []string{"1-b"}}))
+	mdb.ExpectOpenRevs().
+		WillReturnError(&internal.Error{Status: http.StatusNotImplemented, Message: "not implemented"})
+	for _, doc := range []struct{ id, rev string }{{"foo", "1-a"}, {"bar", "1-b"}} {
+		mdb.ExpectGet().
+			WithDocID(doc.id).
+			WithOptions(kivik.Params(map[string]interface{}{
+				"rev":         doc.rev,
+				"revs":        true,
+				"attachments": true,
+			})).
+			WillReturn(&driver.Document{
+				Body: io.NopCloser(strings.NewReader(`{"_id":"` + doc.id + `","_rev":"` + doc.rev + `"}`)),
+			})
+	}
+
+	buf := &bytes.Buffer{}
+	result, err := Dump(ctx, db, buf, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"type":"doc","doc":{"_id":"foo","_rev":"1-a"}}
+{"type":"doc","doc":{"_id":"bar","_rev":"1-b"}}
+`
+	if d := cmp.Diff(want, buf.String()); d != "" {
+		t.Errorf("Unexpected dump:\n%s", d)
+	}
+	if result.Docs != 2 { // nolint:gomnd
+		t.Errorf("Unexpected doc count: %d", result.Docs)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDump_error(t *testing.T) {
+	ctx := context.Background()
+	db, _, mdb := newMock(t)
+	mdb.ExpectChanges().WillReturnError(&internal.Error{Status: http.StatusUnauthorized, Message: "unauthorized"})
+
+	_, err := Dump(ctx, db, io.Discard, Options{})
+	if d := internal.StatusErrorDiff("read changes: unauthorized", http.StatusUnauthorized, err); d != "" {
+		t.Error(d)
+	}
+}
@@ -0,0 +1,261 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package dump
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/go-kivik/kivik/v4"
+)
+
+const defaultBatchSize = 100
+
+type restorer struct {
+	db     *kivik.DB
+	opts   Options
+	result Result
+	batch  []*docRev
+	noDiff bool
+}
+
+// docRev is a document revision waiting to be restored.
+type docRev struct {
+	id, rev string
+	doc     json.RawMessage
+	atts    int
+}
+
+// Restore reads a dump from r, as written by [Dump], and writes it to db.
+//
+// Document revisions are written in batches with new_edits=false, so that
+// revision IDs and histories are preserved. Revisions already present in db
+// are skipped, so an interrupted restore may be resumed by running it again
+// with the same dump.
+func Restore(ctx context.Context, db *kivik.DB, r io.Reader, opts Options) (*Result, error) {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = defaultBatchSize
+	}
+	rs := &restorer{db: db, opts: opts}
+	dec := json.NewDecoder(r)
+	for line := 1; ; line++ {
+		var rec Record
+		if err := dec.Decode(&rec); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, fmt.Errorf("read record %d: %w", line, err)
+		}
+		if err := rs.restore(ctx, &rec); err != nil {
+			return nil, err
+		}
+	}
+	if err := rs.flush(ctx); err != nil {
+		return nil, err
+	}
+	return &rs.result, nil
+}
+
+func (rs *restorer) restore(ctx context.Context, rec *Record) error {
+	switch rec.Type {
+	case TypeSecurity:
+		sec := new(kivik.Security)
+		if err := json.Unmarshal(rec.Doc, sec); err != nil {
+			return err
+		}
+		if err := rs.db.SetSecurity(ctx, sec); err != nil {
+			return fmt.Errorf("restore security: %w", err)
+		}
+		rs.result.Security = true
+		return nil
+	case TypeDoc:
+		doc, err := rs.readDoc(rec.Doc)
+		if err != nil {
+			return err
+		}
+		rs.batch = append(rs.batch, doc)
+		if len(rs.batch) < rs.opts.BatchSize {
+			return nil
+		}
+		return rs.flush(ctx)
+	case TypeLocal:
+		doc, err := rs.readDoc(rec.Doc)
+		if err != nil {
+			return err
+		}
+		if err := rs.flush(ctx); err != nil {
+			return err
+		}
+		return rs.restoreLocal(ctx, doc)
+	}
+	return fmt.Errorf("unknown record type %q", rec.Type)
+}
+
+// readDoc parses a document revision, and inlines any attachments stored in
+// the attachment directory.
+func (rs *restorer) readDoc(raw json.RawMessage) (*docRev, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
+	doc := &docRev{doc: raw}
+	if err := json.Unmarshal(fields["_id"], &doc.id); err != nil {
+		return nil, fmt.Errorf("invalid _id: %w", err)
+	}
+	if rev, ok := fields["_rev"]; ok {
+		if err := json.Unmarshal(rev, &doc.rev); err != nil {
+			return nil, fmt.Errorf("%s: invalid _rev: %w", doc.id, err)
+		}
+	}
+	attsRaw, ok := fields["_attachments"]
+	if !ok {
+		return doc, nil
+	}
+	var atts map[string]*attachment
+	if err := json.Unmarshal(attsRaw, &atts); err != nil {
+		return nil, fmt.Errorf("%s: %w", doc.id, err)
+	}
+	doc.atts = len(atts)
+	var inlined bool
+	for filename, att := range atts {
+		if att.File == "" {
+			continue
+		}
+		if rs.opts.AttachmentDir == "" {
+			return nil, fmt.Errorf("%s: attachment %s is stored in a file, but no attachment directory was given", doc.id, filename)
+		}
+		content, err := os.ReadFile(filepath.Join(rs.opts.AttachmentDir, filepath.Base(att.File)))
+		if err != nil {
+			return nil, fmt.Errorf("%s: attachment %s: %w", doc.id, filename, err)
+		}
+		if att.Data, err = json.Marshal(content); err != nil {
+			return nil, err
+		}
+		att.File = ""
+		inlined = true
+	}
+	if !inlined {
+		return doc, nil
+	}
+	var err error
+	if fields["_attachments"], err = json.Marshal(atts); err != nil {
+		return nil, err
+	}
+	doc.doc, err = json.Marshal(fields)
+	return doc, err
+}
+
+// flush writes the pending batch of document revisions, skipping those
+// already present in the database.
+func (rs *restorer) flush(ctx context.Context) error {
+	if len(rs.batch) == 0 {
+		return nil
+	}
+	batch := rs.batch
+	rs.batch = nil
+	missing, err := rs.missing(ctx, batch)
+	if err != nil {
+		return err
+	}
+	docs := make([]interface{}, 0, len(batch))
+	var atts int
+	for _, doc := range batch {
+		if missing != nil && !missing[doc.id][doc.rev] {
+			rs.result.Skipped++
+			continue
+		}
+		docs = append(docs, doc.doc)
+		atts += doc.atts
+	}
+	if len(docs) == 0 {
+		return nil
+	}
+	results, err := rs.db.BulkDocs(ctx, docs, kivik.Param("new_edits", false))
+	if err != nil {
+		return fmt.Errorf("restore documents: %w", err)
+	}
+	for _, result := range results {
+		if result.Error != nil {
+			return fmt.Errorf("restore %s: %w", result.ID, result.Error)
+		}
+	}
+	rs.result.Docs += len(docs)
+	rs.result.Attachments += atts
+	return nil
+}
+
+// missing returns the revisions of batch which are missing from the database,
+// indexed by document ID and revision. A nil map means the database cannot
+// report missing revisions, so all are assumed to be missing.
+func (rs *restorer) missing(ctx context.Context, batch []*docRev) (map[string]map[string]bool, error) {
+	if rs.noDiff {
+		return nil, nil
+	}
+	revMap := make(map[string][]string, len(batch))
+	for _, doc := range batch {
+		revMap[doc.id] = append(revMap[doc.id], doc.rev)
+	}
+	diffs := rs.db.RevsDiff(ctx, revMap)
+	defer diffs.Close() // nolint:errcheck
+	missing := make(map[string]map[string]bool, len(revMap))
+	for diffs.Next() {
+		var diff kivik.RevDiff
+		if err := diffs.ScanValue(&diff); err != nil {
+			return nil, err
+		}
+		id, _ := diffs.ID()
+		revs := make(map[string]bool, len(diff.Missing))
+		for _, rev := range diff.Missing {
+			revs[rev] = true
+		}
+		missing[id] = revs
+	}
+	if err := diffs.Err(); err != nil {
+		if kivik.HTTPStatus(err) == http.StatusNotImplemented {
+			rs.noDiff = true
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read revs diff: %w", err)
+	}
+	return missing, nil
+}
+
+// restoreLocal writes a _local document, replacing any existing version.
+func (rs *restorer) restoreLocal(ctx context.Context, doc *docRev) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(doc.doc, &fields); err != nil {
+		return err
+	}
+	delete(fields, "_rev")
+	rev, err := rs.db.GetRev(ctx, doc.id)
+	switch {
+	case kivik.HTTPStatus(err) == http.StatusNotFound:
+	case err != nil:
+		return fmt.Errorf("restore %s: %w", doc.id, err)
+	default:
+		if fields["_rev"], err = json.Marshal(rev); err != nil {
+			return err
+		}
+	}
+	if _, err := rs.db.Put(ctx, doc.id, fields); err != nil {
+		return fmt.Errorf("restore %s: %w", doc.id, err)
+	}
+	rs.result.Local++
+	rs.result.Attachments += doc.atts
+	return nil
+}
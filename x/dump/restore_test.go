@@ -0,0 +1,190 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package dump
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/go-kivik/kivik/v4/driver"
+	internal "github.com/go-kivik/kivik/v4/int/errors"
+	kivikmock "github.com/go-kivik/kivik/v4/mockdb"
+)
+
+// captureBulkDocs returns a callback for a BulkDocs expectation, which stores
+// the JSON-encoded documents in docs.
+func captureBulkDocs(t *testing.T, docs *[]string) func(context.Context, []interface{}, driver.Options) ([]driver.BulkResult, error) {
+	t.Helper()
+	return func(_ context.Context, in []interface{}, options driver.Options) ([]driver.BulkResult, error) {
+		opts := map[string]interface{}{}
+		options.Apply(opts)
+		if opts["new_edits"] != false {
+			t.Errorf("Expected new_edits=false, got %v", opts["new_edits"])
+		}
+		for _, doc := range in {
+			buf, err := json.Marshal(doc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			*docs = append(*docs, string(buf))
+		}
+		return nil, nil
+	}
+}
+
+func TestRestore(t *testing.T) {
+	ctx := context.Background()
+	db, mock, mdb := newMock(t)
+	input := `{"type":"security","doc":{"admins":{"names":["bob"]},"members":{}}}
+{"type":"doc","doc":{"_id":"foo","_rev":"2-b","_revisions":{"start":2,"ids":["b","a"]},"x":1}}
+{"type":"doc","doc":{"_attachments":{"a.txt":{"content_type":"text/plain","data":"YWJj"}},"_id":"foo","_rev":"2-c","_revisions":{"start":2,"ids":["c","a"]}}}
+{"type":"doc","doc":{"_id":"bar","_rev":"1-a","_revisions":{"start":1,"ids":["a"]}}}
+{"type":"local","doc":{"_id":"_local/x","_rev":"0-1","seq":5}}
+`
+
+	mdb.ExpectSetSecurity().WithSecurity(&driver.Security{
+		Admins: driver.Members{Names: []string{"bob"}},
+	})
+	mdb.ExpectRevsDiff().
+		WithRevLookup(map[string][]string{
+			"foo": {"2-b", "2-c"},
+			"bar": {"1-a"},
+		}).
+		WillReturn(kivikmock.NewRows().
+			AddRow(&driver.Row{ID: "foo", Value: strings.NewReader(`{"missing":["2-c"]}`)}).
+			AddRow(&driver.Row{ID: "bar", Value: strings.NewReader(`{"missing":["1-a"]}`)}))
+	var docs []string
+	mdb.ExpectBulkDocs().WillExecute(captureBulkDocs(t, &docs))
+	mdb.ExpectGetRev().
+		WithDocID("_local/x").
+		WillReturnError(&internal.Error{Status: http.StatusNotFound, Message: "missing"})
+	mdb.ExpectPut().WithDocID("_local/x").WillReturn("0-1")
+
+	result, err := Restore(ctx, db, strings.NewReader(input), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantDocs := []string{
+		`{"_attachments":{"a.txt":{"content_type":"text/plain","data":"YWJj"}},"_id":"foo","_rev":"2-c","_revisions":{"start":2,"ids":["c","a"]}}`,
+		`{"_id":"bar","_rev":"1-a","_revisions":{"start":1,"ids":["a"]}}`,
+	}
+	if d := cmp.Diff(wantDocs, docs); d != "" {
+		t.Errorf("Unexpected docs:\n%s", d)
+	}
+	wantResult := &Result{Docs: 2, Skipped: 1, Local: 1, Attachments: 1, Security: true}
+	if d := cmp.Diff(wantResult, result); d != "" {
+		t.Errorf("Unexpected result:\n%s", d)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRestore_attachment_dir(t *testing.T) {
+	ctx := context.Background()
+	db, mock, mdb := newMock(t)
+	dir := t.TempDir()
+	const file = "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"
+	if err := os.WriteFile(filepath.Join(dir, file), []byte("abc"), 0o666); err != nil {
+		t.Fatal(err)
+	}
+	input := `{"type":"doc","doc":{"_attachments":{"a.txt":{"content_type":"text/plain","file":"` + file + `"}},"_id":"foo","_rev":"1-a"}}`
+
+	mdb.ExpectRevsDiff().WillReturnError(&internal.Error{Status: http.StatusNotImplemented, Message: "not implemented"})
+	var docs []string
+	mdb.ExpectBulkDocs().WillExecute(captureBulkDocs(t, &docs))
+
+	if _, err := Restore(ctx, db, strings.NewReader(input), Options{AttachmentDir: dir}); err != nil {
+		t.Fatal(err)
+	}
+	wantDocs := []string{
+		`{"_attachments":{"a.txt":{"content_type":"text/plain","data":"YWJj"}},"_id":"foo","_rev":"1-a"}`,
+	}
+	if d := cmp.Diff(wantDocs, docs); d != "" {
+		t.Errorf("Unexpected docs:\n%s", d)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRestore_batches(t *testing.T) {
+	ctx := context.Background()
+	db, mock, mdb := newMock(t)
+	input := `{"type":"doc","doc":{"_id":"a","_rev":"1-a"}}
+{"type":"doc","doc":{"_id":"b","_rev":"1-b"}}
+{"type":"doc","doc":{"_id":"c","_rev":"1-c"}}
+`
+	var docs []string
+	mdb.ExpectRevsDiff().WithRevLookup(map[string][]string{"a": {"1-a"}, "b": {"1-b"}}).WillReturn(kivikmock.NewRows())
+	mdb.ExpectRevsDiff().
+		WithRevLookup(map[string][]string{"c": {"1-c"}}).
+		WillReturn(kivikmock.NewRows().AddRow(&driver.Row{ID: "c", Value: strings.NewReader(`{"missing":["1-c"]}`)}))
+	mdb.ExpectBulkDocs().WillExecute(captureBulkDocs(t, &docs))
+
+	result, err := Restore(ctx, db, strings.NewReader(input), Options{BatchSize: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff([]string{`{"_id":"c","_rev":"1-c"}`}, docs); d != "" {
+		t.Errorf("Unexpected docs:\n%s", d)
+	}
+	if d := cmp.Diff(&Result{Docs: 1, Skipped: 2}, result); d != "" {
+		t.Errorf("Unexpected result:\n%s", d)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRestore_errors(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name  string
+		input string
+		opts  Options
+		err   string
+	}{
+		{
+			name:  "invalid JSON",
+			input: `{"type":`,
+			err:   "read record 1: unexpected EOF",
+		},
+		{
+			name:  "unknown record type",
+			input: `{"type":"foo","doc":{}}`,
+			err:   `unknown record type "foo"`,
+		},
+		{
+			name:  "attachment file without directory",
+			input: `{"type":"doc","doc":{"_id":"foo","_rev":"1-a","_attachments":{"a.txt":{"file":"abc"}}}}`,
+			err:   "foo: attachment a.txt is stored in a file, but no attachment directory was given",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, _ := kivikmock.NewT(t)
+			_, err := Restore(ctx, client.DB("db"), strings.NewReader(tt.input), tt.opts)
+			if err == nil || err.Error() != tt.err {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -11,41 +11,61 @@
 // the License.
 
 // Package js provides convenience wrappers around the goja JavaScript runtime.
+//
+// It is shared by the memorydb and sqlite drivers. As the root module uses
+// module graph pruning, goja is only downloaded and built for programs which
+// import this package, directly or through one of those drivers.
 package js
 
 import (
 	"errors"
 	"fmt"
+	"net/http"
 	"reflect"
 
 	"github.com/dop251/goja"
+
+	internal "github.com/go-kivik/kivik/v4/int/errors"
 )
 
+// ErrCompile is wrapped by the errors returned when JavaScript code can't be
+// compiled to a function. As with CouchDB, it is a 500 compilation_error.
+var ErrCompile = &internal.Error{Status: http.StatusInternalServerError, Message: "compilation_error"}
+
+// compile runs code, which should define a function named name, and returns
+// that function.
+func compile(vm *goja.Runtime, name, code string) (goja.Callable, error) {
+	if _, err := vm.RunString("const " + name + " = " + code); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrCompile, err)
+	}
+	fn, ok := goja.AssertFunction(vm.Get(name))
+	if !ok {
+		return nil, fmt.Errorf("%w: %s is not a function", ErrCompile, name)
+	}
+	return fn, nil
+}
+
 // MapFunc is the Go representation of a CouchDB [map function]. Exceptions are
 // converted to errors.
 //
 // [map function]: https://docs.couchdb.org/en/stable/ddocs/views/nosql.html#map-functions
-type MapFunc func(doc any) error
+type MapFunc func(doc interface{}) error
 
 // Map compiles the provided JavaScript code into a MapFunc, and makes emit
 // available to the JavaScript code.
-func Map(code string, emit func(key, value any)) (MapFunc, error) {
+func Map(code string, emit func(key, value interface{})) (MapFunc, error) {
 	vm := goja.New()
 
 	if err := vm.Set("emit", emit); err != nil {
 		return nil, err
 	}
 
-	if _, err := vm.RunString("const map = " + code); err != nil {
+	mapFunc, err := compile(vm, "map", code)
+	if err != nil {
 		return nil, err
 	}
 
-	mapFunc, ok := goja.AssertFunction(vm.Get("map"))
-	if !ok {
-		panic(fmt.Sprintf("expected map to be a function, got %T", vm.Get("map")))
-	}
-
-	return func(doc any) error {
+	return func(doc interface{}) error {
 		_, err := mapFunc(goja.Undefined(), vm.ToValue(doc))
 		return exception(err)
 	}, nil
@@ -55,19 +75,16 @@ func Map(code string, emit func(key, value any)) (MapFunc, error) {
 // to errors.
 //
 // [filter function]: https://docs.couchdb.org/en/stable/ddocs/ddocs.html#filter-functions
-type FilterFunc func(doc, req any) (bool, error)
+type FilterFunc func(doc, req interface{}) (bool, error)
 
 // Filter compiles the provided JavaScript code into a FilterFunc.
 func Filter(code string) (FilterFunc, error) {
 	vm := goja.New()
-	if _, err := vm.RunString("const filter = " + code); err != nil {
-		return nil, fmt.Errorf("failed to compile filter function: %s", err)
-	}
-	filterFunc, ok := goja.AssertFunction(vm.Get("filter"))
-	if !ok {
-		panic(fmt.Sprintf("expected filter to be a function, got %T", vm.Get("filter")))
+	filterFunc, err := compile(vm, "filter", code)
+	if err != nil {
+		return nil, err
 	}
-	return func(doc, req any) (bool, error) {
+	return func(doc, req interface{}) (bool, error) {
 		result, err := filterFunc(goja.Undefined(), vm.ToValue(doc), vm.ToValue(req))
 		if err != nil {
 			return false, exception(err)
@@ -90,13 +107,10 @@ type ReduceFunc func(keys [][2]interface{}, values []interface{}, rereduce bool)
 func Reduce(code string) (ReduceFunc, error) {
 	vm := goja.New()
 
-	if _, err := vm.RunString("const reduce = " + code); err != nil {
+	reduceFunc, err := compile(vm, "reduce", code)
+	if err != nil {
 		return nil, err
 	}
-	reduceFunc, ok := goja.AssertFunction(vm.Get("reduce"))
-	if !ok {
-		return nil, fmt.Errorf("expected reduce to be a function, got %T", vm.Get("reduce"))
-	}
 
 	return func(keys [][2]interface{}, values []interface{}, rereduce bool) ([]interface{}, error) {
 		reduceValue, err := reduceFunc(goja.Undefined(), vm.ToValue(keys), vm.ToValue(values), vm.ToValue(rereduce))
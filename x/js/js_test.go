@@ -0,0 +1,51 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package js
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	internal "github.com/go-kivik/kivik/v4/int/errors"
+)
+
+func TestCompileErrors(t *testing.T) {
+	compilers := map[string]func(code string) error{
+		"map": func(code string) error {
+			_, err := Map(code, func(interface{}, interface{}) {})
+			return err
+		},
+		"filter": func(code string) error {
+			_, err := Filter(code)
+			return err
+		},
+		"reduce": func(code string) error {
+			_, err := Reduce(code)
+			return err
+		},
+	}
+	for name, compile := range compilers {
+		compile := compile
+		t.Run(name, func(t *testing.T) {
+			err := compile(`"not a function"`)
+			if d := internal.StatusErrorDiff("compilation_error: "+name+" is not a function", http.StatusInternalServerError, err); d != "" {
+				t.Error(d)
+			}
+			err = compile(`function(doc) {`)
+			if !errors.Is(err, ErrCompile) || internal.HTTPStatus(err) != http.StatusInternalServerError {
+				t.Errorf("Unexpected syntax error result: %v", err)
+			}
+		})
+	}
+}
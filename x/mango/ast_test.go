@@ -13,6 +13,7 @@
 package mango
 
 import (
+	"net/http"
 	"regexp"
 	"testing"
 
@@ -59,9 +60,31 @@ func TestParse(t *testing.T) {
 			},
 		},
 	})
-	tests.Add("explicit equality with too many object keys", test{
-		input:   `{"foo": {"$eq": "bar", "$ne": "baz"}}`,
-		wantErr: "too many keys in object",
+	tests.Add("multiple operators", test{
+		input: `{"foo": {"$gt": 1, "$lt": 2}}`,
+		want: &combinationNode{
+			op: OpAnd,
+			sel: []Node{
+				&fieldNode{
+					field: "foo",
+					cond: &conditionNode{
+						op:   OpGreaterThan,
+						cond: float64(1),
+					},
+				},
+				&fieldNode{
+					field: "foo",
+					cond: &conditionNode{
+						op:   OpLessThan,
+						cond: float64(2),
+					},
+				},
+			},
+		},
+	})
+	tests.Add("multiple operators with invalid operator", test{
+		input:   `{"foo": {"$eq": "bar", "$invalid": "baz"}}`,
+		wantErr: "Invalid operator: $invalid",
 	})
 	tests.Add("implicit equality with empty object", test{
 		input: `{"foo": {}}`,
@@ -75,7 +98,7 @@ func TestParse(t *testing.T) {
 	})
 	tests.Add("explicit invalid comparison operator", test{
 		input:   `{"foo": {"$invalid": "bar"}}`,
-		wantErr: "invalid operator $invalid",
+		wantErr: "Invalid operator: $invalid",
 	})
 	tests.Add("explicit equality against object", test{
 		input: `{"foo": {"$eq": {"bar": "baz"}}}`,
@@ -149,7 +172,7 @@ func TestParse(t *testing.T) {
 	})
 	tests.Add("exists with non-boolean", test{
 		input:   `{"foo": {"$exists": 42}}`,
-		wantErr: "$exists: json: cannot unmarshal number into Go value of type bool",
+		wantErr: "Bad argument for operator $exists: 42",
 	})
 	tests.Add("type", test{
 		input: `{"foo": {"$type": "string"}}`,
@@ -163,7 +186,11 @@ func TestParse(t *testing.T) {
 	})
 	tests.Add("type with non-string", test{
 		input:   `{"foo": {"$type": 42}}`,
-		wantErr: "$type: json: cannot unmarshal number into Go value of type string",
+		wantErr: "Bad argument for operator $type: 42",
+	})
+	tests.Add("$type with unknown type", test{
+		input:   `{"foo": {"$type": "integer"}}`,
+		wantErr: `Bad argument for operator $type: "integer"`,
 	})
 	tests.Add("in", test{
 		input: `{"foo": {"$in": [1, 2, 3]}}`,
@@ -177,7 +204,7 @@ func TestParse(t *testing.T) {
 	})
 	tests.Add("in with non-array", test{
 		input:   `{"foo": {"$in": 42}}`,
-		wantErr: "$in: json: cannot unmarshal number into Go value of type []interface {}",
+		wantErr: "Bad argument for operator $in: 42",
 	})
 	tests.Add("not in", test{
 		input: `{"foo": {"$nin": [1, 2, 3]}}`,
@@ -191,7 +218,7 @@ func TestParse(t *testing.T) {
 	})
 	tests.Add("not in with non-array", test{
 		input:   `{"foo": {"$nin": 42}}`,
-		wantErr: "$nin: json: cannot unmarshal number into Go value of type []interface {}",
+		wantErr: "Bad argument for operator $nin: 42",
 	})
 	tests.Add("size", test{
 		input: `{"foo": {"$size": 42}}`,
@@ -205,7 +232,7 @@ func TestParse(t *testing.T) {
 	})
 	tests.Add("size with non-integer", test{
 		input:   `{"foo": {"$size": 42.5}}`,
-		wantErr: "$size: json: cannot unmarshal number 42.5 into Go value of type uint",
+		wantErr: "Bad argument for operator $size: 42.5",
 	})
 	tests.Add("mod", test{
 		input: `{"foo": {"$mod": [2, 1]}}`,
@@ -219,11 +246,11 @@ func TestParse(t *testing.T) {
 	})
 	tests.Add("mod with non-array", test{
 		input:   `{"foo": {"$mod": 42}}`,
-		wantErr: "$mod: json: cannot unmarshal number into Go value of type [2]int64",
+		wantErr: "Bad argument for operator $mod: 42",
 	})
 	tests.Add("mod with zero divisor", test{
 		input:   `{"foo": {"$mod": [0, 1]}}`,
-		wantErr: "$mod: divisor must be non-zero",
+		wantErr: "Bad argument for operator $mod: [0, 1]",
 	})
 	tests.Add("regex", test{
 		input: `{"foo": {"$regex": "^bar$"}}`,
@@ -237,11 +264,56 @@ func TestParse(t *testing.T) {
 	})
 	tests.Add("regexp non-string", test{
 		input:   `{"foo": {"$regex": 42}}`,
-		wantErr: "$regex: json: cannot unmarshal number into Go value of type string",
+		wantErr: "Bad argument for operator $regex: 42",
 	})
 	tests.Add("regexp invalid", test{
 		input:   `{"foo": {"$regex": "["}}`,
-		wantErr: "$regex: error parsing regexp: missing closing ]: `[`",
+		wantErr: `Bad argument for operator $regex: "["`,
+	})
+	tests.Add("beginsWith", test{
+		input: `{"foo": {"$beginsWith": "ba"}}`,
+		want: &fieldNode{
+			field: "foo",
+			cond: &conditionNode{
+				op:   OpBeginsWith,
+				cond: "ba",
+			},
+		},
+	})
+	tests.Add("beginsWith non-string", test{
+		input:   `{"foo": {"$beginsWith": ["ba"]}}`,
+		wantErr: `Bad argument for operator $beginsWith: ["ba"]`,
+	})
+	tests.Add("mod with negative divisor", test{
+		input:   `{"foo": {"$mod": [-2, 1]}}`,
+		wantErr: "Bad argument for operator $mod: [-2, 1]",
+	})
+	tests.Add("mod with one element", test{
+		input:   `{"foo": {"$mod": [2]}}`,
+		wantErr: "Bad argument for operator $mod: [2]",
+	})
+	tests.Add("missing field name", test{
+		input:   `{"": "bar"}`,
+		wantErr: "One or more conditions is missing a field name.",
+	})
+	tests.Add("top-level condition alongside field with same operator", test{
+		input: `{"$eq": "bar", "foo": "bar"}`,
+		want: &combinationNode{
+			op: OpAnd,
+			sel: []Node{
+				&fieldNode{
+					field: "foo",
+					cond: &conditionNode{
+						op:   OpEqual,
+						cond: "bar",
+					},
+				},
+				&conditionNode{
+					op:   OpEqual,
+					cond: "bar",
+				},
+			},
+		},
 	})
 	tests.Add("implicit $and", test{
 		input: `{"foo":"bar","baz":"qux"}`,
@@ -352,7 +424,7 @@ func TestParse(t *testing.T) {
 	})
 	tests.Add("invalid operator", test{
 		input:   `{"$invalid": "bar"}`,
-		wantErr: "unknown operator $invalid",
+		wantErr: "Invalid operator: $invalid",
 	})
 	tests.Add("$not", test{
 		input: `{"$not": {"foo":"bar"}}`,
@@ -368,15 +440,15 @@ func TestParse(t *testing.T) {
 	})
 	tests.Add("$not with invalid selector", test{
 		input:   `{"$not": []}`,
-		wantErr: "$not: json: cannot unmarshal array into Go value of type map[string]json.RawMessage",
+		wantErr: "Bad argument for operator $not: []",
 	})
 	tests.Add("$and with invalid selector array", test{
 		input:   `{"$and": {}}`,
-		wantErr: "$and: json: cannot unmarshal object into Go value of type []json.RawMessage",
+		wantErr: "Bad argument for operator $and: {}",
 	})
 	tests.Add("$and with invalid selector", test{
 		input:   `{"$and": [42]}`,
-		wantErr: "$and: json: cannot unmarshal number into Go value of type map[string]json.RawMessage",
+		wantErr: "Bad argument for operator $and: [42]",
 	})
 	tests.Add("$nor", test{
 		input: `{"$nor":[{"foo":"bar"},{"baz":"qux"}]}`,
@@ -412,7 +484,7 @@ func TestParse(t *testing.T) {
 	})
 	tests.Add("$all with non-array", test{
 		input:   `{"foo": {"$all": "bar"}}`,
-		wantErr: "$all: json: cannot unmarshal string into Go value of type []interface {}",
+		wantErr: `Bad argument for operator $all: "bar"`,
 	})
 	tests.Add("$elemMatch", test{
 		input: `{"genre": {"$elemMatch": {"$eq": "Horror"}}}`,
@@ -427,6 +499,87 @@ func TestParse(t *testing.T) {
 			},
 		},
 	})
+	tests.Add("$elemMatch with $gt", test{
+		input: `{"genre": {"$elemMatch": {"$gt": "Horror"}}}`,
+		want: &fieldNode{
+			field: "genre",
+			cond: &elementNode{
+				op: OpElemMatch,
+				cond: &conditionNode{
+					op:   OpGreaterThan,
+					cond: "Horror",
+				},
+			},
+		},
+	})
+	tests.Add("$elemMatch with $in", test{
+		input: `{"genre": {"$elemMatch": {"$in": ["Horror", "Comedy"]}}}`,
+		want: &fieldNode{
+			field: "genre",
+			cond: &elementNode{
+				op: OpElemMatch,
+				cond: &conditionNode{
+					op:   OpIn,
+					cond: []interface{}{"Horror", "Comedy"},
+				},
+			},
+		},
+	})
+	tests.Add("$allMatch with $regex", test{
+		input: `{"genre": {"$allMatch": {"$regex": "^H"}}}`,
+		want: &fieldNode{
+			field: "genre",
+			cond: &elementNode{
+				op: OpAllMatch,
+				cond: &conditionNode{
+					op:   OpRegex,
+					cond: regexp.MustCompile("^H"),
+				},
+			},
+		},
+	})
+	tests.Add("$elemMatch with $type and $beginsWith", test{
+		input: `{"genre": {"$elemMatch": {"$type": "string", "$beginsWith": "Hor"}}}`,
+		want: &fieldNode{
+			field: "genre",
+			cond: &elementNode{
+				op: OpElemMatch,
+				cond: &combinationNode{
+					op: OpAnd,
+					sel: []Node{
+						&conditionNode{
+							op:   OpBeginsWith,
+							cond: "Hor",
+						},
+						&conditionNode{
+							op:   OpType,
+							cond: "string",
+						},
+					},
+				},
+			},
+		},
+	})
+	tests.Add("$elemMatch with field selector", test{
+		input: `{"cast": {"$elemMatch": {"name": "Bond"}}}`,
+		want: &fieldNode{
+			field: "cast",
+			cond: &elementNode{
+				op: OpElemMatch,
+				cond: &fieldNode{
+					field: "name",
+					cond: &conditionNode{
+						op:   OpEqual,
+						cond: "Bond",
+					},
+				},
+			},
+		},
+	})
+	tests.Add("$elemMatch with invalid selector", test{
+		input:   `{"cast": {"$elemMatch": {"name": {"$size": "x"}}}}`,
+		wantErr: `$elemMatch: Bad argument for operator $size: "x"`,
+	})
 	tests.Add("$allMatch", test{
 		input: `{"genre": {"$allMatch": {"$eq": "Horror"}}}`,
 		want: &fieldNode{
@@ -455,7 +608,7 @@ func TestParse(t *testing.T) {
 	})
 	tests.Add("element selector with invalid selector", test{
 		input:   `{"cameras": {"$keyMapMatch": 42}}`,
-		wantErr: "$keyMapMatch: json: cannot unmarshal number into Go value of type map[string]json.RawMessage",
+		wantErr: "Bad argument for operator $keyMapMatch: 42",
 	})
 
 	/*
@@ -470,6 +623,9 @@ func TestParse(t *testing.T) {
 			t.Fatalf("Unexpected error: %s", err)
 		}
 		if err != nil {
+			if status := testy.StatusCode(err); status != http.StatusBadRequest {
+				t.Errorf("Unexpected status: %d", status)
+			}
 			return
 		}
 		if d := cmp.Diff(tt.want.String(), got.String(), cmpOpts...); d != "" {
@@ -0,0 +1,390 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package mango
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+// Expr is a selector expression, built with [Field] and combined with [And],
+// [Or], [Nor] and [Not]. The zero value matches all documents. Any error
+// encountered while building the expression is deferred until it is
+// marshaled, or converted to a [Node].
+type Expr struct {
+	sel map[string]interface{}
+	err error
+}
+
+// MarshalJSON returns the CouchDB JSON representation of the selector.
+func (e Expr) MarshalJSON() ([]byte, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	if e.sel == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(e.sel)
+}
+
+// Node returns the parsed selector tree, for matching documents locally.
+func (e Expr) Node() (Node, error) {
+	data, err := e.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+// Selector returns the expression as a [Selector].
+func (e Expr) Selector() (*Selector, error) {
+	node, err := e.Node()
+	if err != nil {
+		return nil, err
+	}
+	return &Selector{root: node}, nil
+}
+
+// And returns an expression matching documents which match e and all of
+// others.
+func (e Expr) And(others ...Expr) Expr {
+	return And(append([]Expr{e}, others...)...)
+}
+
+// Or returns an expression matching documents which match e or any of others.
+func (e Expr) Or(others ...Expr) Expr {
+	return Or(append([]Expr{e}, others...)...)
+}
+
+func combine(op Operator, exprs []Expr) Expr {
+	sels := make([]interface{}, 0, len(exprs))
+	for _, e := range exprs {
+		if e.err != nil {
+			return e
+		}
+		if e.sel == nil {
+			sels = append(sels, map[string]interface{}{})
+			continue
+		}
+		sels = append(sels, e.sel)
+	}
+	return Expr{sel: map[string]interface{}{string(op): sels}}
+}
+
+// And returns an expression matching documents which match all of exprs.
+func And(exprs ...Expr) Expr {
+	if len(exprs) == 1 {
+		return exprs[0]
+	}
+	return combine(OpAnd, exprs)
+}
+
+// Or returns an expression matching documents which match any of exprs.
+func Or(exprs ...Expr) Expr {
+	if len(exprs) == 0 {
+		return Expr{err: errors.New("$or requires at least one expression")}
+	}
+	return combine(OpOr, exprs)
+}
+
+// Nor returns an expression matching documents which match none of exprs.
+func Nor(exprs ...Expr) Expr {
+	return combine(OpNor, exprs)
+}
+
+// Not returns an expression matching documents which don't match e.
+func Not(e Expr) Expr {
+	if e.err != nil {
+		return e
+	}
+	sel := e.sel
+	if sel == nil {
+		sel = map[string]interface{}{}
+	}
+	return Expr{sel: map[string]interface{}{string(OpNot): sel}}
+}
+
+// Text returns an expression matching documents containing the terms of
+// query. CouchDB requires a text index for such queries.
+func Text(query string) Expr {
+	return Expr{sel: map[string]interface{}{string(OpText): query}}
+}
+
+// FieldExpr builds conditions on the value of a single document field.
+type FieldExpr struct {
+	name string
+}
+
+// Field returns a builder for conditions on the named field.
+func Field(name string) FieldExpr {
+	return FieldExpr{name: name}
+}
+
+// Elem returns a builder for conditions on an array element, for use with
+// [FieldExpr.ElemMatch] and [FieldExpr.AllMatch].
+func Elem() FieldExpr {
+	return FieldExpr{}
+}
+
+func (f FieldExpr) cond(op Operator, value interface{}) Expr {
+	cond := map[string]interface{}{string(op): value}
+	if f.name == "" {
+		return Expr{sel: cond}
+	}
+	return Expr{sel: map[string]interface{}{f.name: cond}}
+}
+
+func (f FieldExpr) fail(op Operator, format string, args ...interface{}) Expr {
+	return Expr{err: fmt.Errorf("%s: %s", op, fmt.Sprintf(format, args...))}
+}
+
+// Eq matches values equal to v.
+func (f FieldExpr) Eq(v interface{}) Expr { return f.cond(OpEqual, v) }
+
+// Ne matches values not equal to v.
+func (f FieldExpr) Ne(v interface{}) Expr { return f.cond(OpNotEqual, v) }
+
+// Lt matches values less than v.
+func (f FieldExpr) Lt(v interface{}) Expr { return f.cond(OpLessThan, v) }
+
+// Lte matches values less than or equal to v.
+func (f FieldExpr) Lte(v interface{}) Expr { return f.cond(OpLessThanOrEqual, v) }
+
+// Gt matches values greater than v.
+func (f FieldExpr) Gt(v interface{}) Expr { return f.cond(OpGreaterThan, v) }
+
+// Gte matches values greater than or equal to v.
+func (f FieldExpr) Gte(v interface{}) Expr { return f.cond(OpGreaterThanOrEqual, v) }
+
+// Exists matches documents in which the field does, or does not, exist.
+func (f FieldExpr) Exists(exists bool) Expr { return f.cond(OpExists, exists) }
+
+// Type matches values of the named JSON type, which must be one of "null",
+// "boolean", "number", "string", "array" or "object".
+func (f FieldExpr) Type(typ string) Expr {
+	switch typ {
+	case "null", "boolean", "number", "string", "array", "object":
+		return f.cond(OpType, typ)
+	}
+	return f.fail(OpType, "invalid type %q", typ)
+}
+
+// In matches values equal to any of values.
+func (f FieldExpr) In(values ...interface{}) Expr {
+	return f.cond(OpIn, append([]interface{}{}, values...))
+}
+
+// Nin matches values equal to none of values.
+func (f FieldExpr) Nin(values ...interface{}) Expr {
+	return f.cond(OpNotIn, append([]interface{}{}, values...))
+}
+
+// Size matches arrays of the given length.
+func (f FieldExpr) Size(n uint) Expr { return f.cond(OpSize, n) }
+
+// Mod matches integers which leave remainder when divided by divisor, which
+// must be positive.
+func (f FieldExpr) Mod(divisor, remainder int64) Expr {
+	if divisor <= 0 {
+		return f.fail(OpMod, "divisor must be positive")
+	}
+	return f.cond(OpMod, [2]int64{divisor, remainder})
+}
+
+// Regex matches strings matching pattern.
+func (f FieldExpr) Regex(pattern string) Expr {
+	if _, err := regexp.Compile(pattern); err != nil {
+		return f.fail(OpRegex, "%s", err)
+	}
+	return f.cond(OpRegex, pattern)
+}
+
+// BeginsWith matches strings beginning with prefix.
+func (f FieldExpr) BeginsWith(prefix string) Expr { return f.cond(OpBeginsWith, prefix) }
+
+// All matches arrays containing all of values.
+func (f FieldExpr) All(values ...interface{}) Expr {
+	return f.cond(OpAll, append([]interface{}{}, values...))
+}
+
+func (f FieldExpr) match(op Operator, e Expr) Expr {
+	if e.err != nil {
+		return e
+	}
+	if e.sel == nil {
+		return f.fail(op, "expression must not be empty")
+	}
+	return f.cond(op, e.sel)
+}
+
+// ElemMatch matches arrays with at least one element matching e, typically
+// built with [Elem].
+func (f FieldExpr) ElemMatch(e Expr) Expr { return f.match(OpElemMatch, e) }
+
+// AllMatch matches arrays whose elements all match e, typically built with
+// [Elem].
+func (f FieldExpr) AllMatch(e Expr) Expr { return f.match(OpAllMatch, e) }
+
+// KeyMapMatch matches objects with at least one key matching e, typically
+// built with [Elem].
+func (f FieldExpr) KeyMapMatch(e Expr) Expr { return f.match(OpKeyMapMatch, e) }
+
+// SortField is a single field of a query's sort specification.
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+// Asc sorts by field, in ascending order.
+func Asc(field string) SortField {
+	return SortField{Field: field}
+}
+
+// Desc sorts by field, in descending order.
+func Desc(field string) SortField {
+	return SortField{Field: field, Desc: true}
+}
+
+// Query is a [_find query], built with [NewQuery]. It may be passed directly
+// as the query argument to [github.com/go-kivik/kivik/v4.DB.Find] and related
+// methods.
+//
+// [_find query]: https://docs.couchdb.org/en/stable/api/database/find.html#db-find
+type Query struct {
+	selector Expr
+	sort     []SortField
+	fields   []string
+	limit    *int
+	skip     int
+	useIndex []string
+	bookmark string
+}
+
+// NewQuery returns a query for documents matching selector.
+func NewQuery(selector Expr) *Query {
+	return &Query{selector: selector}
+}
+
+// Sort sets the sort order of the results. CouchDB requires that all fields
+// be sorted in the same direction.
+func (q *Query) Sort(fields ...SortField) *Query {
+	q.sort = fields
+	return q
+}
+
+// Fields limits the fields returned for each document.
+func (q *Query) Fields(fields ...string) *Query {
+	q.fields = fields
+	return q
+}
+
+// Limit sets the maximum number of results returned.
+func (q *Query) Limit(n int) *Query {
+	q.limit = &n
+	return q
+}
+
+// Skip sets the number of results to skip.
+func (q *Query) Skip(n int) *Query {
+	q.skip = n
+	return q
+}
+
+// UseIndex instructs the query to use the index in the named design document.
+// name may be empty to use any index in the design document.
+func (q *Query) UseIndex(ddoc, name string) *Query {
+	q.useIndex = []string{ddoc}
+	if name != "" {
+		q.useIndex = append(q.useIndex, name)
+	}
+	return q
+}
+
+// Bookmark sets the bookmark from a previous result set, to fetch the next
+// page of results.
+func (q *Query) Bookmark(bookmark string) *Query {
+	q.bookmark = bookmark
+	return q
+}
+
+// Validate returns an error if the query is invalid.
+func (q *Query) Validate() error {
+	if _, err := q.selector.Node(); err != nil {
+		return fmt.Errorf("invalid selector: %w", err)
+	}
+	for i, f := range q.sort {
+		if f.Field == "" {
+			return errors.New("sort field name must not be empty")
+		}
+		if f.Desc != q.sort[0].Desc && i > 0 {
+			return errors.New("sorts currently only support a single direction for all fields")
+		}
+	}
+	for _, f := range q.fields {
+		if f == "" {
+			return errors.New("field name must not be empty")
+		}
+	}
+	if q.limit != nil && *q.limit < 0 {
+		return errors.New("limit must not be negative")
+	}
+	if q.skip < 0 {
+		return errors.New("skip must not be negative")
+	}
+	if len(q.useIndex) > 0 && q.useIndex[0] == "" {
+		return errors.New("use_index design document must not be empty")
+	}
+	return nil
+}
+
+// MarshalJSON returns the CouchDB JSON representation of the query, or an
+// error if it is invalid.
+func (q *Query) MarshalJSON() ([]byte, error) {
+	if err := q.Validate(); err != nil {
+		return nil, err
+	}
+	var sort []map[string]string
+	for _, f := range q.sort {
+		dir := "asc"
+		if f.Desc {
+			dir = "desc"
+		}
+		sort = append(sort, map[string]string{f.Field: dir})
+	}
+	var useIndex interface{}
+	switch len(q.useIndex) {
+	case 1:
+		useIndex = q.useIndex[0]
+	case 2:
+		useIndex = q.useIndex
+	}
+	return json.Marshal(struct {
+		Selector Expr                `json:"selector"`
+		Sort     []map[string]string `json:"sort,omitempty"`
+		Fields   []string            `json:"fields,omitempty"`
+		Limit    *int                `json:"limit,omitempty"`
+		Skip     int                 `json:"skip,omitempty"`
+		UseIndex interface{}         `json:"use_index,omitempty"`
+		Bookmark string              `json:"bookmark,omitempty"`
+	}{
+		Selector: q.selector,
+		Sort:     sort,
+		Fields:   q.fields,
+		Limit:    q.limit,
+		Skip:     q.skip,
+		UseIndex: useIndex,
+		Bookmark: q.bookmark,
+	})
+}
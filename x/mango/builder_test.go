@@ -0,0 +1,201 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package mango
+
+import (
+	"testing"
+
+	"gitlab.com/flimzy/testy"
+)
+
+func TestExpr(t *testing.T) {
+	type test struct {
+		expr    Expr
+		want    string
+		wantErr string
+		match   map[string]interface{}
+		noMatch map[string]interface{}
+	}
+
+	tests := testy.NewTable()
+	tests.Add("zero value", test{
+		expr:  Expr{},
+		want:  `{}`,
+		match: map[string]interface{}{"foo": "bar"},
+	})
+	tests.Add("equality", test{
+		expr:    Field("name").Eq("bob"),
+		want:    `{"name":{"$eq":"bob"}}`,
+		match:   map[string]interface{}{"name": "bob"},
+		noMatch: map[string]interface{}{"name": "alice"},
+	})
+	tests.Add("and", test{
+		expr:    Field("age").Gte(21).And(Field("age").Lt(65)),
+		want:    `{"$and":[{"age":{"$gte":21}},{"age":{"$lt":65}}]}`,
+		match:   map[string]interface{}{"age": 30.0},
+		noMatch: map[string]interface{}{"age": 18.0},
+	})
+	tests.Add("single and", test{
+		expr: And(Field("age").Gt(1)),
+		want: `{"age":{"$gt":1}}`,
+	})
+	tests.Add("or", test{
+		expr:    Or(Field("a").Exists(true), Field("b").Type("string")),
+		want:    `{"$or":[{"a":{"$exists":true}},{"b":{"$type":"string"}}]}`,
+		match:   map[string]interface{}{"b": "x"},
+		noMatch: map[string]interface{}{"b": 1.0},
+	})
+	tests.Add("empty or", test{
+		expr:    Or(),
+		wantErr: "$or requires at least one expression",
+	})
+	tests.Add("nor and not", test{
+		expr:    Nor(Field("a").In(1, 2), Not(Field("b").Nin("x"))),
+		want:    `{"$nor":[{"a":{"$in":[1,2]}},{"$not":{"b":{"$nin":["x"]}}}]}`,
+		match:   map[string]interface{}{"a": 3.0, "b": "y"},
+		noMatch: map[string]interface{}{"a": 1.0, "b": "y"},
+	})
+	tests.Add("array operators", test{
+		expr: And(
+			Field("tags").Size(2),
+			Field("tags").All("x"),
+			Field("tags").ElemMatch(Elem().Gt("x")),
+		),
+		want:    `{"$and":[{"tags":{"$size":2}},{"tags":{"$all":["x"]}},{"tags":{"$elemMatch":{"$gt":"x"}}}]}`,
+		match:   map[string]interface{}{"tags": []interface{}{"x", "y"}},
+		noMatch: map[string]interface{}{"tags": []interface{}{"x", "a"}},
+	})
+	tests.Add("allMatch", test{
+		expr:    Field("n").AllMatch(Elem().Gte(2)),
+		want:    `{"n":{"$allMatch":{"$gte":2}}}`,
+		match:   map[string]interface{}{"n": []interface{}{2.0, 4.0}},
+		noMatch: map[string]interface{}{"n": []interface{}{2.0, 1.0}},
+	})
+	tests.Add("beginsWith", test{
+		expr:    Field("name").BeginsWith("al"),
+		want:    `{"name":{"$beginsWith":"al"}}`,
+		match:   map[string]interface{}{"name": "alice"},
+		noMatch: map[string]interface{}{"name": "bob"},
+	})
+	tests.Add("elemMatch with field", test{
+		expr:    Field("cast").ElemMatch(Field("name").Eq("bob")),
+		want:    `{"cast":{"$elemMatch":{"name":{"$eq":"bob"}}}}`,
+		match:   map[string]interface{}{"cast": []interface{}{map[string]interface{}{"name": "bob"}}},
+		noMatch: map[string]interface{}{"cast": []interface{}{map[string]interface{}{"name": "eve"}}},
+	})
+	tests.Add("keyMapMatch", test{
+		expr:  Field("cameras").KeyMapMatch(Elem().Eq("secondary")),
+		want:  `{"cameras":{"$keyMapMatch":{"$eq":"secondary"}}}`,
+		match: map[string]interface{}{"cameras": map[string]interface{}{"secondary": "x"}},
+	})
+	tests.Add("invalid type", test{
+		expr:    Field("a").Type("integer"),
+		wantErr: `$type: invalid type "integer"`,
+	})
+	tests.Add("zero divisor", test{
+		expr:    Field("a").Mod(0, 1),
+		wantErr: "$mod: divisor must be positive",
+	})
+	tests.Add("invalid regex", test{
+		expr:    Field("a").Regex("("),
+		wantErr: "$regex: error parsing regexp: missing closing ): `(`",
+	})
+	tests.Add("empty elemMatch", test{
+		expr:    Field("a").ElemMatch(Expr{}),
+		wantErr: "$elemMatch: expression must not be empty",
+	})
+	tests.Add("error propagates", test{
+		expr:    Field("a").Eq(1).And(Not(Field("b").Type("foo"))),
+		wantErr: `$type: invalid type "foo"`,
+	})
+
+	tests.Run(t, func(t *testing.T, tt test) {
+		got, err := tt.expr.MarshalJSON()
+		if !testy.ErrorMatches(tt.wantErr, err) {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if err != nil {
+			return
+		}
+		if string(got) != tt.want {
+			t.Errorf("Unexpected JSON:\nwant: %s\n got: %s", tt.want, got)
+		}
+		sel, err := tt.expr.Selector()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tt.match != nil && !sel.Match(tt.match) {
+			t.Errorf("Expected %v to match", tt.match)
+		}
+		if tt.noMatch != nil && sel.Match(tt.noMatch) {
+			t.Errorf("Expected %v not to match", tt.noMatch)
+		}
+	})
+}
+
+func TestQuery(t *testing.T) {
+	type test struct {
+		query   *Query
+		want    string
+		wantErr string
+	}
+
+	tests := testy.NewTable()
+	tests.Add("selector only", test{
+		query: NewQuery(Field("a").Eq(1)),
+		want:  `{"selector":{"a":{"$eq":1}}}`,
+	})
+	tests.Add("all options", test{
+		query: NewQuery(Field("a").Gt(1)).
+			Sort(Desc("a"), Desc("b")).
+			Fields("_id", "a").
+			Limit(10).
+			Skip(5).
+			UseIndex("_design/idx", "by-a").
+			Bookmark("xyz"),
+		want: `{"selector":{"a":{"$gt":1}},"sort":[{"a":"desc"},{"b":"desc"}],"fields":["_id","a"],"limit":10,"skip":5,"use_index":["_design/idx","by-a"],"bookmark":"xyz"}`,
+	})
+	tests.Add("zero limit", test{
+		query: NewQuery(Expr{}).Limit(0).UseIndex("_design/idx", ""),
+		want:  `{"selector":{},"limit":0,"use_index":"_design/idx"}`,
+	})
+	tests.Add("mixed sort directions", test{
+		query:   NewQuery(Expr{}).Sort(Asc("a"), Desc("b")),
+		wantErr: "sorts currently only support a single direction for all fields",
+	})
+	tests.Add("negative limit", test{
+		query:   NewQuery(Expr{}).Limit(-1),
+		wantErr: "limit must not be negative",
+	})
+	tests.Add("empty field", test{
+		query:   NewQuery(Expr{}).Fields(""),
+		wantErr: "field name must not be empty",
+	})
+	tests.Add("invalid selector", test{
+		query:   NewQuery(Field("a").Type("foo")),
+		wantErr: `invalid selector: $type: invalid type "foo"`,
+	})
+
+	tests.Run(t, func(t *testing.T, tt test) {
+		got, err := tt.query.MarshalJSON()
+		if !testy.ErrorMatches(tt.wantErr, err) {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if err != nil {
+			return
+		}
+		if string(got) != tt.want {
+			t.Errorf("Unexpected JSON:\nwant: %s\n got: %s", tt.want, got)
+		}
+	})
+}
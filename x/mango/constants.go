@@ -48,4 +48,10 @@ const (
 	OpRegex              = Operator("$regex")
 	OpAll                = Operator("$all")
 	OpElemMatch          = Operator("$elemMatch")
+	OpBeginsWith         = Operator("$beginsWith")
 )
+
+// [Text Operator], which requires a text index.
+//
+// [Text Operator]: https://docs.couchdb.org/en/stable/api/database/find.html#text-operators
+const OpText = Operator("$text")
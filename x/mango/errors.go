@@ -0,0 +1,47 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package mango
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// Error is returned by [Parse] for an invalid selector. As with CouchDB, it
+// results in a 400 Bad Request status, so that selectors from untrusted input
+// may be passed straight through to clients.
+type Error struct {
+	err error
+}
+
+func (e *Error) Error() string {
+	return e.err.Error()
+}
+
+// HTTPStatus returns 400 Bad Request.
+func (e *Error) HTTPStatus() int {
+	return http.StatusBadRequest
+}
+
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+func badArg(op Operator, arg json.RawMessage) error {
+	return fmt.Errorf("Bad argument for operator %s: %s", op, arg)
+}
+
+func invalidOperator(op Operator) error {
+	return fmt.Errorf("Invalid operator: %s", op)
+}
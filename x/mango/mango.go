@@ -30,6 +30,21 @@ func (s *Selector) Match(doc interface{}) bool {
 	return s.root.Match(doc)
 }
 
+// Root returns the root node of the selector tree, which may be nil for a
+// zero-value Selector. The nodes' [Node.Op] and [Node.Value] methods, along
+// with [FieldNode], describe the full tree:
+//
+//   - $and, $or and $nor nodes have a []Node value
+//   - $not nodes have a Node value
+//   - $elemMatch, $allMatch and $keyMapMatch nodes have a Node value, the
+//     selector applied to each element, or key
+//   - $text nodes have the query string as their value
+//   - all other condition nodes have the operand as their value, which for
+//     $regex is a *regexp.Regexp, and for $mod is a [2]int64
+func (s *Selector) Root() Node {
+	return s.root
+}
+
 // UnmarshalJSON parses the JSON-encoded data and stores the result in s.
 func (s *Selector) UnmarshalJSON(data []byte) error {
 	node, err := Parse(data)
@@ -40,8 +55,17 @@ func (s *Selector) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
-// Parse parses s into a Mango Selector tree.
+// Parse parses s into a Mango Selector tree. An invalid selector results in
+// an [*Error].
 func Parse(input []byte) (Node, error) {
+	node, err := parse(input)
+	if err != nil {
+		return nil, &Error{err: err}
+	}
+	return node, nil
+}
+
+func parse(input []byte) (Node, error) {
 	var tmp map[string]json.RawMessage
 	if err := json.Unmarshal(input, &tmp); err != nil {
 		return nil, err
@@ -58,12 +82,15 @@ func Parse(input []byte) (Node, error) {
 		switch op := Operator(k); op {
 		case OpAnd, OpOr, OpNor:
 			var sel []json.RawMessage
-			if err := json.Unmarshal(v, &sel); err != nil {
-				return nil, fmt.Errorf("%s: %w", k, err)
+			if err := json.Unmarshal(v, &sel); err != nil || sel == nil {
+				return nil, badArg(op, v)
 			}
 			subsels := make([]Node, 0, len(sel))
 			for _, s := range sel {
-				sel, err := Parse(s)
+				if !isObject(s) {
+					return nil, badArg(op, v)
+				}
+				sel, err := parse(s)
 				if err != nil {
 					return nil, fmt.Errorf("%s: %w", k, err)
 				}
@@ -75,48 +102,41 @@ func Parse(input []byte) (Node, error) {
 				sel: subsels,
 			})
 		case OpNot:
-			sel, err := Parse(v)
+			sel, err := parseArg(op, v)
 			if err != nil {
-				return nil, fmt.Errorf("%s: %w", k, err)
+				return nil, err
 			}
 			sels = append(sels, &notNode{
 				sel: sel,
 			})
-		case OpEqual, OpLessThan, OpLessThanOrEqual, OpNotEqual,
-			OpGreaterThan, OpGreaterThanOrEqual:
-			op, value, err := opAndValue(v)
-			if err != nil {
-				return nil, err
+		case OpText:
+			var query string
+			if err := json.Unmarshal(v, &query); err != nil {
+				return nil, badArg(op, v)
 			}
-			sels = append(sels, &conditionNode{
-				op:   op,
-				cond: value,
-			})
+			sels = append(sels, newTextNode(query))
 		default:
+			if k == "" {
+				return nil, errors.New("One or more conditions is missing a field name.")
+			}
 			if op[0] == '$' {
-				return nil, fmt.Errorf("unknown operator %s", op)
+				// An operator without a field applies to the value itself,
+				// as within $elemMatch.
+				cond, err := parseCond(op, v)
+				if err != nil {
+					return nil, err
+				}
+				sels = append(sels, cond)
+				continue
 			}
-			op, value, err := opAndValue(v)
+			conds, err := fieldConds(v)
 			if err != nil {
 				return nil, err
 			}
-
-			switch op {
-			case OpElemMatch, OpAllMatch, OpKeyMapMatch:
-				sels = append(sels, &fieldNode{
-					field: k,
-					cond: &elementNode{
-						op:   op,
-						cond: value.(*conditionNode),
-					},
-				})
-			default:
+			for _, cond := range conds {
 				sels = append(sels, &fieldNode{
 					field: k,
-					cond: &conditionNode{
-						op:   op,
-						cond: value,
-					},
+					cond:  cond,
 				})
 			}
 		}
@@ -136,92 +156,125 @@ func Parse(input []byte) (Node, error) {
 	}, nil
 }
 
-// opAndValue is called when the input is an object in a context where a
-// comparison operator is expected. It returns the operator and value,
-// defaulting to [OpEqual] if no operator is specified.
-func opAndValue(input json.RawMessage) (Operator, interface{}, error) {
+// isObject returns true if input is a JSON object.
+func isObject(input json.RawMessage) bool {
+	var tmp map[string]json.RawMessage
+	return json.Unmarshal(input, &tmp) == nil && tmp != nil
+}
+
+// parseArg parses input, the argument to op, as a selector.
+func parseArg(op Operator, input json.RawMessage) (Node, error) {
+	if !isObject(input) {
+		return nil, badArg(op, input)
+	}
+	sel, err := parse(input)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return sel, nil
+}
+
+// fieldConds is called with the value of a field in a selector. It returns
+// the conditions to apply to the field, defaulting to [OpEqual] if no
+// operator is specified. Multiple operators form an implicit $and.
+func fieldConds(input json.RawMessage) ([]Node, error) {
 	if input[0] != '{' {
 		var value interface{}
 		if err := json.Unmarshal(input, &value); err != nil {
-			return "", nil, err
+			return nil, err
 		}
-		return OpEqual, value, nil
+		return []Node{&conditionNode{op: OpEqual, cond: value}}, nil
 	}
 	var tmp map[string]json.RawMessage
 	if err := json.Unmarshal(input, &tmp); err != nil {
-		return "", nil, err
+		return nil, err
 	}
-	switch len(tmp) {
-	case 0:
-		return OpEqual, map[string]interface{}{}, nil
-	case 1:
-		for k, v := range tmp {
-			switch op := Operator(k); op {
-			case OpEqual, OpLessThan, OpLessThanOrEqual, OpNotEqual,
-				OpGreaterThan, OpGreaterThanOrEqual:
-				var value interface{}
-				err := json.Unmarshal(v, &value)
-				return op, value, err
-			case OpExists:
-				var value bool
-				if err := json.Unmarshal(v, &value); err != nil {
-					return "", nil, fmt.Errorf("%s: %w", k, err)
-				}
-				return OpExists, value, nil
-			case OpType:
-				var value string
-				if err := json.Unmarshal(v, &value); err != nil {
-					return "", nil, fmt.Errorf("%s: %w", k, err)
-				}
-				return OpType, value, nil
-			case OpIn, OpNotIn:
-				var value []interface{}
-				if err := json.Unmarshal(v, &value); err != nil {
-					return "", nil, fmt.Errorf("%s: %w", k, err)
-				}
-				return op, value, nil
-			case OpSize:
-				var value uint
-				if err := json.Unmarshal(v, &value); err != nil {
-					return "", nil, fmt.Errorf("%s: %w", k, err)
-				}
-				return OpSize, float64(value), nil
-			case OpMod:
-				var value [2]int64
-				if err := json.Unmarshal(v, &value); err != nil {
-					return "", nil, fmt.Errorf("%s: %w", k, err)
-				}
-				if value[0] == 0 {
-					return "", nil, errors.New("$mod: divisor must be non-zero")
-				}
-				return OpMod, value, nil
-			case OpRegex:
-				var pattern string
-				if err := json.Unmarshal(v, &pattern); err != nil {
-					return "", nil, fmt.Errorf("%s: %w", k, err)
-				}
-				re, err := regexp.Compile(pattern)
-				if err != nil {
-					return "", nil, fmt.Errorf("%s: %w", k, err)
-				}
-				return OpRegex, re, nil
-			case OpAll:
-				var value []interface{}
-				if err := json.Unmarshal(v, &value); err != nil {
-					return "", nil, fmt.Errorf("%s: %w", k, err)
-				}
-				return OpAll, value, nil
-			case OpElemMatch, OpAllMatch, OpKeyMapMatch:
-				sel, err := Parse(v)
-				if err != nil {
-					return "", nil, fmt.Errorf("%s: %w", k, err)
-				}
-				return op, sel, nil
-			}
-			return "", nil, fmt.Errorf("invalid operator %s", k)
+	if len(tmp) == 0 {
+		return []Node{&conditionNode{op: OpEqual, cond: map[string]interface{}{}}}, nil
+	}
+	ops := make([]string, 0, len(tmp))
+	for k := range tmp {
+		ops = append(ops, k)
+	}
+	sort.Strings(ops)
+	conds := make([]Node, 0, len(ops))
+	for _, k := range ops {
+		cond, err := parseCond(Operator(k), tmp[k])
+		if err != nil {
+			return nil, err
+		}
+		conds = append(conds, cond)
+	}
+	return conds, nil
+}
+
+// parseCond parses a single condition operator and its argument.
+func parseCond(op Operator, v json.RawMessage) (Node, error) {
+	switch op {
+	case OpEqual, OpLessThan, OpLessThanOrEqual, OpNotEqual,
+		OpGreaterThan, OpGreaterThanOrEqual:
+		var value interface{}
+		if err := json.Unmarshal(v, &value); err != nil {
+			return nil, badArg(op, v)
+		}
+		return &conditionNode{op: op, cond: value}, nil
+	case OpExists:
+		var value bool
+		if err := json.Unmarshal(v, &value); err != nil {
+			return nil, badArg(op, v)
+		}
+		return &conditionNode{op: op, cond: value}, nil
+	case OpType:
+		var value string
+		if err := json.Unmarshal(v, &value); err != nil {
+			return nil, badArg(op, v)
+		}
+		switch value {
+		case "null", "boolean", "number", "string", "array", "object":
+		default:
+			return nil, badArg(op, v)
+		}
+		return &conditionNode{op: op, cond: value}, nil
+	case OpIn, OpNotIn, OpAll:
+		var value []interface{}
+		if err := json.Unmarshal(v, &value); err != nil || value == nil {
+			return nil, badArg(op, v)
+		}
+		return &conditionNode{op: op, cond: value}, nil
+	case OpSize:
+		var value uint
+		if err := json.Unmarshal(v, &value); err != nil {
+			return nil, badArg(op, v)
+		}
+		return &conditionNode{op: op, cond: float64(value)}, nil
+	case OpMod:
+		var value []int64
+		if err := json.Unmarshal(v, &value); err != nil || len(value) != 2 || value[0] <= 0 {
+			return nil, badArg(op, v)
+		}
+		return &conditionNode{op: op, cond: [2]int64{value[0], value[1]}}, nil
+	case OpRegex:
+		var pattern string
+		if err := json.Unmarshal(v, &pattern); err != nil {
+			return nil, badArg(op, v)
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, badArg(op, v)
+		}
+		return &conditionNode{op: op, cond: re}, nil
+	case OpBeginsWith:
+		var prefix string
+		if err := json.Unmarshal(v, &prefix); err != nil {
+			return nil, badArg(op, v)
+		}
+		return &conditionNode{op: op, cond: prefix}, nil
+	case OpElemMatch, OpAllMatch, OpKeyMapMatch:
+		sel, err := parseArg(op, v)
+		if err != nil {
+			return nil, err
 		}
-	default:
-		return "", nil, errors.New("too many keys in object")
+		return &elementNode{op: op, cond: sel}, nil
 	}
-	panic("impossible")
+	return nil, invalidOperator(op)
 }
@@ -0,0 +1,102 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package mango
+
+import "github.com/go-kivik/kivik/v4/x/collate"
+
+// Bound is one end of a [Range].
+type Bound struct {
+	Value     interface{}
+	Inclusive bool
+}
+
+// Range describes the values a selector permits for a single field, as
+// implied by its comparison operators. A nil Start or End means the range is
+// unbounded in that direction.
+type Range struct {
+	Start *Bound
+	End   *Bound
+}
+
+// Ranges returns the range of values permitted for each field which the
+// selector requires to exist. Only conditions in the top-level conjunction
+// are considered, so the ranges may be wider than what the selector actually
+// matches, but never narrower. This makes them suitable for choosing and
+// scanning an index, with [Selector.Match] applied to the results.
+func (s *Selector) Ranges() map[string]*Range {
+	ranges := map[string]*Range{}
+	if s != nil && s.root != nil {
+		addRanges(ranges, s.root)
+	}
+	return ranges
+}
+
+func addRanges(ranges map[string]*Range, node Node) {
+	switch t := node.(type) {
+	case *combinationNode:
+		if t.op != OpAnd {
+			return
+		}
+		for _, sel := range t.sel {
+			addRanges(ranges, sel)
+		}
+	case *fieldNode:
+		cond, isCond := t.cond.(*conditionNode)
+		if isCond && cond.op == OpExists && cond.cond == false {
+			return
+		}
+		r, ok := ranges[t.field]
+		if !ok {
+			r = &Range{}
+			ranges[t.field] = r
+		}
+		if !isCond {
+			return
+		}
+		switch cond.op {
+		case OpEqual:
+			r.narrowStart(&Bound{Value: cond.cond, Inclusive: true})
+			r.narrowEnd(&Bound{Value: cond.cond, Inclusive: true})
+		case OpGreaterThan:
+			r.narrowStart(&Bound{Value: cond.cond})
+		case OpGreaterThanOrEqual:
+			r.narrowStart(&Bound{Value: cond.cond, Inclusive: true})
+		case OpLessThan:
+			r.narrowEnd(&Bound{Value: cond.cond})
+		case OpLessThanOrEqual:
+			r.narrowEnd(&Bound{Value: cond.cond, Inclusive: true})
+		}
+	}
+}
+
+func (r *Range) narrowStart(b *Bound) {
+	if r.Start == nil {
+		r.Start = b
+		return
+	}
+	c := collate.CompareObject(b.Value, r.Start.Value)
+	if c > 0 || (c == 0 && !b.Inclusive) {
+		r.Start = b
+	}
+}
+
+func (r *Range) narrowEnd(b *Bound) {
+	if r.End == nil {
+		r.End = b
+		return
+	}
+	c := collate.CompareObject(b.Value, r.End.Value)
+	if c < 0 || (c == 0 && !b.Inclusive) {
+		r.End = b
+	}
+}
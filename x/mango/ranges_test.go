@@ -0,0 +1,79 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package mango
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"gitlab.com/flimzy/testy"
+)
+
+func TestSelectorRanges(t *testing.T) {
+	type test struct {
+		input string
+		want  map[string]*Range
+	}
+
+	tests := testy.NewTable()
+	tests.Add("empty", test{
+		input: `{}`,
+		want:  map[string]*Range{},
+	})
+	tests.Add("equality", test{
+		input: `{"foo": "bar"}`,
+		want: map[string]*Range{
+			"foo": {
+				Start: &Bound{Value: "bar", Inclusive: true},
+				End:   &Bound{Value: "bar", Inclusive: true},
+			},
+		},
+	})
+	tests.Add("null lower bound", test{
+		input: `{"_id": {"$gt": null}}`,
+		want: map[string]*Range{
+			"_id": {Start: &Bound{Value: nil}},
+		},
+	})
+	tests.Add("narrowed by $and", test{
+		input: `{"$and": [{"foo": {"$gte": 1}}, {"foo": {"$gt": 3}}, {"foo": {"$lte": 10}}, {"foo": {"$lt": 10}}]}`,
+		want: map[string]*Range{
+			"foo": {
+				Start: &Bound{Value: float64(3)},
+				End:   &Bound{Value: float64(10)},
+			},
+		},
+	})
+	tests.Add("non-range operators require the field", test{
+		input: `{"foo": {"$regex": "^a"}, "bar": {"$exists": true}, "baz": {"$exists": false}}`,
+		want: map[string]*Range{
+			"foo": {},
+			"bar": {},
+		},
+	})
+	tests.Add("$or is ignored", test{
+		input: `{"$or": [{"foo": 1}, {"bar": 2}]}`,
+		want:  map[string]*Range{},
+	})
+
+	tests.Run(t, func(t *testing.T, tt test) {
+		var sel Selector
+		if err := json.Unmarshal([]byte(tt.input), &sel); err != nil {
+			t.Fatal(err)
+		}
+		if d := cmp.Diff(tt.want, sel.Ranges()); d != "" {
+			t.Errorf("Unexpected result (-want +got):\n%s", d)
+		}
+	})
+}
@@ -29,6 +29,17 @@ type Node interface {
 	Match(interface{}) bool
 }
 
+// FieldNode is a [Node] which applies a condition to the value of a single
+// field of a document. It allows consumers, such as drivers which translate
+// selectors to native queries, to walk the selector tree.
+type FieldNode interface {
+	Node
+	// Field returns the name of the field.
+	Field() string
+	// Condition returns the condition applied to the field's value.
+	Condition() Node
+}
+
 type notNode struct {
 	sel Node
 }
@@ -104,7 +115,7 @@ func (c *combinationNode) Match(doc interface{}) bool {
 		}
 		return true
 	}
-	panic("not implemented")
+	return false
 }
 
 type fieldNode struct {
@@ -112,7 +123,15 @@ type fieldNode struct {
 	cond  Node
 }
 
-var _ Node = (*fieldNode)(nil)
+var _ FieldNode = (*fieldNode)(nil)
+
+func (f *fieldNode) Field() string {
+	return f.field
+}
+
+func (f *fieldNode) Condition() Node {
+	return f.cond
+}
 
 func (f *fieldNode) Op() Operator {
 	return f.cond.Op()
@@ -193,7 +212,7 @@ func (e *conditionNode) Match(doc interface{}) bool {
 			_, ok := doc.(map[string]interface{})
 			return ok
 		default:
-			panic("unexpected $type value: " + tp)
+			return false
 		}
 	case OpIn:
 		for _, v := range e.cond.([]interface{}) {
@@ -231,6 +250,12 @@ func (e *conditionNode) Match(doc interface{}) bool {
 			return false
 		}
 		return e.cond.(*regexp.Regexp).MatchString(str)
+	case OpBeginsWith:
+		str, ok := doc.(string)
+		if !ok {
+			return false
+		}
+		return strings.HasPrefix(str, e.cond.(string))
 	case OpAll:
 		array, ok := doc.([]interface{})
 		if !ok {
@@ -257,7 +282,7 @@ func contains(haystack []interface{}, needle interface{}) bool {
 
 type elementNode struct {
 	op   Operator
-	cond *conditionNode
+	cond Node
 }
 
 var _ Node = (*elementNode)(nil)
@@ -304,13 +329,13 @@ func (e *elementNode) Match(doc interface{}) bool {
 			return false
 		}
 		for k := range object {
-			if k == e.cond.cond.(string) {
-				return e.cond.Match(k)
+			if e.cond.Match(k) {
+				return true
 			}
 		}
 		return false
 	}
-	panic("unready")
+	return false
 }
 
 // cmpValues compares two arbitrary values by converting them to strings.
@@ -324,6 +349,9 @@ func cmpSelectors(a, b Node) int {
 	if c := strings.Compare(string(a.Op()), string(b.Op())); c != 0 {
 		return c
 	}
+	if c := nodeRank(a) - nodeRank(b); c != 0 {
+		return c
+	}
 	switch t := a.(type) {
 	case *notNode:
 		u := b.(*notNode)
@@ -336,6 +364,8 @@ func cmpSelectors(a, b Node) int {
 			}
 		}
 		return len(t.sel) - len(u.sel)
+	case *textNode:
+		return strings.Compare(t.query, b.(*textNode).query)
 	case *fieldNode:
 		u := b.(*fieldNode)
 		if c := strings.Compare(t.field, u.field); c != 0 {
@@ -353,15 +383,48 @@ func cmpSelectors(a, b Node) int {
 			}
 			return len(t.cond.([]interface{})) - len(u.cond.([]interface{}))
 		case OpMod:
-			tm := t.cond.([2]int)
-			um := u.cond.([2]int)
+			tm := t.cond.([2]int64)
+			um := u.cond.([2]int64)
 			if tm[0] != um[0] {
-				return tm[0] - um[0]
+				return cmpInt64(tm[0], um[0])
 			}
-			return tm[1] - um[1]
+			return cmpInt64(tm[1], um[1])
+		case OpRegex:
+			return strings.Compare(t.cond.(*regexp.Regexp).String(), u.cond.(*regexp.Regexp).String())
 		default:
 			return cmpValues(t.cond, u.cond)
 		}
+	case *elementNode:
+		return cmpSelectors(t.cond, b.(*elementNode).cond)
+	}
+	return 0
+}
+
+// nodeRank orders nodes of different types, which may share an operator.
+func nodeRank(n Node) int {
+	switch n.(type) {
+	case *notNode:
+		return 1
+	case *combinationNode:
+		return 2
+	case *fieldNode:
+		return 3
+	case *conditionNode:
+		return 4
+	case *elementNode:
+		return 5
+	case *textNode:
+		return 6
+	}
+	return 0
+}
+
+func cmpInt64(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
 	}
 	return 0
 }
@@ -0,0 +1,115 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package mango
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// textNode is a $text search. CouchDB evaluates $text using Lucene, via a
+// text index. This is a simple stand-in, which matches documents containing
+// any of the query's terms in any string value.
+type textNode struct {
+	query string
+	terms []string
+}
+
+var _ Node = (*textNode)(nil)
+
+func newTextNode(query string) *textNode {
+	return &textNode{
+		query: query,
+		terms: Tokenize(query),
+	}
+}
+
+func (*textNode) Op() Operator {
+	return OpText
+}
+
+func (t *textNode) Value() interface{} {
+	return t.query
+}
+
+func (t *textNode) String() string {
+	return fmt.Sprintf("%s %q", OpText, t.query)
+}
+
+func (t *textNode) Match(doc interface{}) bool {
+	if len(t.terms) == 0 {
+		return false
+	}
+	tokens := make(map[string]struct{})
+	for _, value := range TextValues(doc) {
+		for _, token := range Tokenize(value) {
+			tokens[token] = struct{}{}
+		}
+	}
+	for _, term := range t.terms {
+		if _, ok := tokens[term]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Tokenize splits s into lower-case terms, separated by any characters other
+// than letters and digits. It is used to build and query text indexes.
+func Tokenize(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// TextValues returns all string values found in v, recursing into arrays and
+// objects.
+func TextValues(v interface{}) []string {
+	switch t := v.(type) {
+	case string:
+		return []string{t}
+	case []interface{}:
+		var values []string
+		for _, elem := range t {
+			values = append(values, TextValues(elem)...)
+		}
+		return values
+	case map[string]interface{}:
+		var values []string
+		for _, elem := range t {
+			values = append(values, TextValues(elem)...)
+		}
+		return values
+	}
+	return nil
+}
+
+// TextQuery returns the query of the selector's $text operator, if the
+// selector requires one, either at the top level, or within a top-level $and.
+func (s *Selector) TextQuery() (string, bool) {
+	switch t := s.root.(type) {
+	case *textNode:
+		return t.query, true
+	case *combinationNode:
+		if t.op != OpAnd {
+			return "", false
+		}
+		for _, sel := range t.sel {
+			if text, ok := sel.(*textNode); ok {
+				return text.query, true
+			}
+		}
+	}
+	return "", false
+}
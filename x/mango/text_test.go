@@ -0,0 +1,124 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package mango
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"gitlab.com/flimzy/testy"
+)
+
+func TestTokenize(t *testing.T) {
+	got := Tokenize("The quick-brown fox, 2nd Édition!")
+	want := []string{"the", "quick", "brown", "fox", "2nd", "édition"}
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("Unexpected tokens (-want +got):\n%s", d)
+	}
+}
+
+func TestText(t *testing.T) {
+	type test struct {
+		selector  string
+		doc       interface{}
+		want      bool
+		wantQuery string
+		hasText   bool
+		wantErr   string
+	}
+
+	doc := map[string]interface{}{
+		"title": "Bond, James Bond",
+		"cast":  []interface{}{"Sean Connery", map[string]interface{}{"name": "Ursula Andress"}},
+		"year":  1962.0,
+	}
+
+	tests := testy.NewTable()
+	tests.Add("single term", test{
+		selector:  `{"$text": "bond"}`,
+		doc:       doc,
+		want:      true,
+		wantQuery: "bond",
+		hasText:   true,
+	})
+	tests.Add("nested value", test{
+		selector:  `{"$text": "ANDRESS"}`,
+		doc:       doc,
+		want:      true,
+		wantQuery: "ANDRESS",
+		hasText:   true,
+	})
+	tests.Add("any term", test{
+		selector:  `{"$text": "moore connery"}`,
+		doc:       doc,
+		want:      true,
+		wantQuery: "moore connery",
+		hasText:   true,
+	})
+	tests.Add("no matching term", test{
+		selector:  `{"$text": "moore"}`,
+		doc:       doc,
+		want:      false,
+		wantQuery: "moore",
+		hasText:   true,
+	})
+	tests.Add("numbers are not indexed", test{
+		selector:  `{"$text": "1962"}`,
+		doc:       doc,
+		want:      false,
+		wantQuery: "1962",
+		hasText:   true,
+	})
+	tests.Add("empty query", test{
+		selector:  `{"$text": ""}`,
+		doc:       doc,
+		want:      false,
+		wantQuery: "",
+		hasText:   true,
+	})
+	tests.Add("within $and", test{
+		selector:  `{"$text": "bond", "year": {"$gt": 1960}}`,
+		doc:       doc,
+		want:      true,
+		wantQuery: "bond",
+		hasText:   true,
+	})
+	tests.Add("within $or", test{
+		selector: `{"$or": [{"$text": "bond"}, {"year": 1970}]}`,
+		doc:      doc,
+		want:     true,
+	})
+	tests.Add("non-string query", test{
+		selector: `{"$text": 42}`,
+		wantErr:  "Bad argument for operator $text: 42",
+	})
+
+	tests.Run(t, func(t *testing.T, tt test) {
+		var sel Selector
+		err := json.Unmarshal([]byte(tt.selector), &sel)
+		if !testy.ErrorMatches(tt.wantErr, err) {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if err != nil {
+			return
+		}
+		if got := sel.Match(tt.doc); got != tt.want {
+			t.Errorf("Unexpected match result: %t", got)
+		}
+		query, ok := sel.TextQuery()
+		if ok != tt.hasText || query != tt.wantQuery {
+			t.Errorf("Unexpected text query: %q, %t", query, ok)
+		}
+	})
+}
@@ -0,0 +1,292 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package memorydb
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/go-kivik/kivik/v4/driver"
+)
+
+var _ driver.AttachmentMetaGetter = &db{}
+
+// jsonAttachment is the representation of an attachment in a document's
+// _attachments map.
+type jsonAttachment struct {
+	ContentType string `json:"content_type"`
+	Digest      string `json:"digest,omitempty"`
+	Length      int64  `json:"length,omitempty"`
+	RevPos      int64  `json:"revpos,omitempty"`
+	Stub        bool   `json:"stub,omitempty"`
+	// Data is a pointer, so that an empty attachment can be told apart from
+	// a missing data member.
+	Data *[]byte `json:"data,omitempty"`
+}
+
+func newFile(contentType string, data []byte) file {
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	sum := md5.Sum(data)
+	return file{
+		ContentType: contentType,
+		Data:        data,
+		Digest:      "md5-" + base64.StdEncoding.EncodeToString(sum[:]),
+	}
+}
+
+func (f file) stub() *jsonAttachment {
+	return &jsonAttachment{
+		ContentType: f.ContentType,
+		Digest:      f.Digest,
+		Length:      int64(len(f.Data)),
+		RevPos:      f.RevPos,
+		Stub:        true,
+	}
+}
+
+func attachmentStubs(atts map[string]file) map[string]*jsonAttachment {
+	stubs := make(map[string]*jsonAttachment, len(atts))
+	for filename, att := range atts {
+		stubs[filename] = att.stub()
+	}
+	return stubs
+}
+
+// attachments parses the document's _attachments member. Inline attachments
+// are decoded, and stubs are resolved against last, the revision being
+// updated.
+func (d couchDoc) attachments(last *revision) (map[string]file, error) {
+	raw, ok := d["_attachments"]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+	buf, err := json.Marshal(raw)
+	if err != nil {
+		return nil, statusError{status: http.StatusBadRequest, error: err}
+	}
+	var in map[string]jsonAttachment
+	if err := json.Unmarshal(buf, &in); err != nil {
+		return nil, statusError{status: http.StatusBadRequest, error: errors.New("bad special document member: _attachments")}
+	}
+	atts := make(map[string]file, len(in))
+	for filename, att := range in {
+		switch {
+		case att.Stub:
+			var existing file
+			var found bool
+			if last != nil && !last.Deleted {
+				existing, found = last.Attachments[filename]
+			}
+			if !found {
+				return nil, statusError{status: http.StatusPreconditionFailed, error: fmt.Errorf("invalid attachment stub in %s for %s", d.ID(), filename)}
+			}
+			atts[filename] = existing
+		case att.Data == nil:
+			return nil, statusError{status: http.StatusBadRequest, error: fmt.Errorf("invalid attachment data for %s", filename)}
+		default:
+			atts[filename] = newFile(att.ContentType, *att.Data)
+		}
+	}
+	return atts, nil
+}
+
+// withAttachments returns the revision's body, with attachment content
+// included as requested by the attachments and atts_since options.
+func (d *database) withAttachments(docID string, rev *revision, opts map[string]interface{}) ([]byte, error) {
+	if len(rev.Attachments) == 0 {
+		return rev.data, nil
+	}
+	all, _ := opts["attachments"].(bool)
+	since, hasSince := attsSince(opts)
+	if !all && !hasSince {
+		return rev.data, nil
+	}
+	// Only attachments added after the most recent revision known to the
+	// client are included. If none of the revisions are known, all are.
+	var knownPos int64
+	if !all {
+		for _, r := range since {
+			if known, ok := d.getRevision(docID, r); ok && known.ID > knownPos {
+				knownPos = known.ID
+			}
+		}
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(rev.data, &doc); err != nil {
+		return nil, err
+	}
+	atts := attachmentStubs(rev.Attachments)
+	for filename, att := range rev.Attachments {
+		if att.RevPos > knownPos {
+			data := att.Data
+			atts[filename] = &jsonAttachment{
+				ContentType: att.ContentType,
+				Digest:      att.Digest,
+				RevPos:      att.RevPos,
+				Data:        &data,
+			}
+		}
+	}
+	doc["_attachments"] = atts
+	return json.Marshal(doc)
+}
+
+func attsSince(opts map[string]interface{}) ([]string, bool) {
+	switch t := opts["atts_since"].(type) {
+	case []string:
+		return t, true
+	case []interface{}:
+		revs := make([]string, 0, len(t))
+		for _, rev := range t {
+			if r, ok := rev.(string); ok {
+				revs = append(revs, r)
+			}
+		}
+		return revs, true
+	case string:
+		var revs []string
+		if err := json.Unmarshal([]byte(t), &revs); err != nil {
+			return nil, false
+		}
+		return revs, true
+	}
+	return nil, false
+}
+
+func (d *db) PutAttachment(ctx context.Context, docID string, att *driver.Attachment, options driver.Options) (string, error) {
+	if exists, _ := d.client.DBExists(ctx, d.dbName, nil); !exists {
+		return "", statusError{status: http.StatusPreconditionFailed, error: errors.New("database does not exist")}
+	}
+	opts := map[string]interface{}{}
+	options.Apply(opts)
+	content, err := io.ReadAll(att.Content)
+	if err != nil {
+		return "", err
+	}
+	doc := couchDoc{}
+	if last, ok := d.db.latestRevision(docID); ok && !last.Deleted {
+		if err := json.Unmarshal(last.data, &doc); err != nil {
+			return "", err
+		}
+	}
+	delete(doc, "_rev")
+	if rev, _ := opts["rev"].(string); rev != "" {
+		doc["_rev"] = rev
+	}
+	atts, _ := doc["_attachments"].(map[string]interface{})
+	if atts == nil {
+		atts = map[string]interface{}{}
+	}
+	atts[att.Filename] = &jsonAttachment{
+		ContentType: att.ContentType,
+		Data:        &content,
+	}
+	doc["_attachments"] = atts
+	return d.Put(ctx, docID, doc, nil)
+}
+
+// revision returns the requested revision of a document, or the current one
+// if no rev option is set.
+func (d *db) revision(docID string, opts map[string]interface{}) (*revision, error) {
+	if rev, ok := opts["rev"].(string); ok && rev != "" {
+		if r, found := d.db.getRevision(docID, rev); found {
+			return r, nil
+		}
+		return nil, statusError{status: http.StatusNotFound, error: errors.New("missing")}
+	}
+	last, ok := d.db.latestRevision(docID)
+	if !ok || last.Deleted {
+		return nil, statusError{status: http.StatusNotFound, error: errors.New("missing")}
+	}
+	return last, nil
+}
+
+func (d *db) attachment(ctx context.Context, docID, filename string, options driver.Options) (*file, error) {
+	if exists, _ := d.client.DBExists(ctx, d.dbName, nil); !exists {
+		return nil, statusError{status: http.StatusPreconditionFailed, error: errors.New("database does not exist")}
+	}
+	opts := map[string]interface{}{}
+	options.Apply(opts)
+	rev, err := d.revision(docID, opts)
+	if err != nil {
+		return nil, err
+	}
+	att, ok := rev.Attachments[filename]
+	if !ok {
+		return nil, statusError{status: http.StatusNotFound, error: errors.New("document is missing attachment")}
+	}
+	return &att, nil
+}
+
+func (d *db) GetAttachment(ctx context.Context, docID, filename string, options driver.Options) (*driver.Attachment, error) {
+	f, err := d.attachment(ctx, docID, filename, options)
+	if err != nil {
+		return nil, err
+	}
+	att := f.meta(filename)
+	att.Content = io.NopCloser(bytes.NewReader(f.Data))
+	return att, nil
+}
+
+func (d *db) GetAttachmentMeta(ctx context.Context, docID, filename string, options driver.Options) (*driver.Attachment, error) {
+	f, err := d.attachment(ctx, docID, filename, options)
+	if err != nil {
+		return nil, err
+	}
+	return f.meta(filename), nil
+}
+
+func (f file) meta(filename string) *driver.Attachment {
+	return &driver.Attachment{
+		Filename:    filename,
+		ContentType: f.ContentType,
+		Size:        int64(len(f.Data)),
+		RevPos:      f.RevPos,
+		Digest:      f.Digest,
+	}
+}
+
+func (d *db) DeleteAttachment(ctx context.Context, docID, filename string, options driver.Options) (string, error) {
+	if exists, _ := d.client.DBExists(ctx, d.dbName, nil); !exists {
+		return "", statusError{status: http.StatusPreconditionFailed, error: errors.New("database does not exist")}
+	}
+	opts := map[string]interface{}{}
+	options.Apply(opts)
+	rev, _ := opts["rev"].(string)
+	last, ok := d.db.latestRevision(docID)
+	if !ok || last.Deleted || rev != fmt.Sprintf("%d-%s", last.ID, last.Rev) {
+		return "", statusError{status: http.StatusConflict, error: errors.New("document update conflict")}
+	}
+	if _, ok := last.Attachments[filename]; !ok {
+		return "", statusError{status: http.StatusNotFound, error: errors.New("document is missing attachment")}
+	}
+	doc := couchDoc{}
+	if err := json.Unmarshal(last.data, &doc); err != nil {
+		return "", err
+	}
+	atts, _ := doc["_attachments"].(map[string]interface{})
+	delete(atts, filename)
+	if len(atts) == 0 {
+		delete(doc, "_attachments")
+	}
+	return d.Put(ctx, docID, doc, nil)
+}
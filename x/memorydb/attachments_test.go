@@ -0,0 +1,317 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package memorydb
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"gitlab.com/flimzy/testy"
+
+	"github.com/go-kivik/kivik/v4"
+	"github.com/go-kivik/kivik/v4/driver"
+	internal "github.com/go-kivik/kivik/v4/int/errors"
+)
+
+func putAttachment(t *testing.T, d *db, docID, rev, filename, content string) string {
+	t.Helper()
+	newRev, err := d.PutAttachment(context.Background(), docID, &driver.Attachment{
+		Filename:    filename,
+		ContentType: "text/plain",
+		Content:     io.NopCloser(strings.NewReader(content)),
+	}, kivik.Rev(rev))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return newRev
+}
+
+func getDoc(t *testing.T, d *db, docID string, options driver.Options) map[string]interface{} {
+	t.Helper()
+	doc, err := d.Get(context.Background(), docID, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var result map[string]interface{}
+	if err := json.NewDecoder(doc.Body).Decode(&result); err != nil {
+		t.Fatal(err)
+	}
+	delete(result, "_rev")
+	return result
+}
+
+func TestPutAttachment(t *testing.T) {
+	t.Run("new doc", func(t *testing.T) {
+		d := setupDB(t)
+		rev := putAttachment(t, d, "foo", "", "foo.txt", "Hello")
+		if !strings.HasPrefix(rev, "1-") {
+			t.Errorf("Unexpected rev: %s", rev)
+		}
+		expected := map[string]interface{}{
+			"_id": "foo",
+			"_attachments": map[string]interface{}{
+				"foo.txt": map[string]interface{}{
+					"content_type": "text/plain",
+					"digest":       "md5-ixqZU8RhEpaoJ6v4xHgE1w==",
+					"length":       5,
+					"revpos":       1,
+					"stub":         true,
+				},
+			},
+		}
+		if d := testy.DiffAsJSON(expected, getDoc(t, d, "foo", kivik.Params(nil))); d != nil {
+			t.Error(d)
+		}
+	})
+	t.Run("existing attachments are kept", func(t *testing.T) {
+		d := setupDB(t)
+		rev, err := d.Put(context.Background(), "foo", map[string]string{"foo": "bar"}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rev = putAttachment(t, d, "foo", rev, "foo.txt", "Hello")
+		_ = putAttachment(t, d, "foo", rev, "bar.txt", "World")
+		expected := map[string]interface{}{
+			"_id": "foo",
+			"foo": "bar",
+			"_attachments": map[string]interface{}{
+				"foo.txt": map[string]interface{}{
+					"content_type": "text/plain",
+					"digest":       "md5-ixqZU8RhEpaoJ6v4xHgE1w==",
+					"length":       5,
+					"revpos":       2,
+					"stub":         true,
+				},
+				"bar.txt": map[string]interface{}{
+					"content_type": "text/plain",
+					"digest":       "md5-9aeSTmIehMkoCpon4by39g==",
+					"length":       5,
+					"revpos":       3,
+					"stub":         true,
+				},
+			},
+		}
+		if d := testy.DiffAsJSON(expected, getDoc(t, d, "foo", kivik.Params(nil))); d != nil {
+			t.Error(d)
+		}
+	})
+	t.Run("empty attachment", func(t *testing.T) {
+		d := setupDB(t)
+		_ = putAttachment(t, d, "foo", "", "empty.txt", "")
+		expected := map[string]interface{}{
+			"_id": "foo",
+			"_attachments": map[string]interface{}{
+				"empty.txt": map[string]interface{}{
+					"content_type": "text/plain",
+					"digest":       "md5-1B2M2Y8AsgTpgAmY7PhCfg==",
+					"revpos":       1,
+					"stub":         true,
+				},
+			},
+		}
+		if d := testy.DiffAsJSON(expected, getDoc(t, d, "foo", kivik.Params(nil))); d != nil {
+			t.Error(d)
+		}
+	})
+	t.Run("empty inline attachment", func(t *testing.T) {
+		d := setupDB(t)
+		_, err := d.Put(context.Background(), "foo", map[string]interface{}{
+			"_attachments": map[string]interface{}{
+				"empty.txt": map[string]interface{}{
+					"content_type": "text/plain",
+					"data":         "",
+				},
+			},
+		}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := d.Put(context.Background(), "bar", map[string]interface{}{
+			"_attachments": map[string]interface{}{
+				"missing.txt": map[string]interface{}{
+					"content_type": "text/plain",
+				},
+			},
+		}, nil); kivik.HTTPStatus(err) != http.StatusBadRequest {
+			t.Errorf("Expected 400 for missing data, got: %v", err)
+		}
+	})
+	t.Run("conflict", func(t *testing.T) {
+		d := setupDB(t)
+		if _, err := d.Put(context.Background(), "foo", map[string]string{"foo": "bar"}, nil); err != nil {
+			t.Fatal(err)
+		}
+		_, err := d.PutAttachment(context.Background(), "foo", &driver.Attachment{
+			Filename: "foo.txt",
+			Content:  io.NopCloser(strings.NewReader("Hello")),
+		}, kivik.Params(nil))
+		if d := internal.StatusErrorDiff("document update conflict", http.StatusConflict, err); d != "" {
+			t.Error(d)
+		}
+	})
+}
+
+func TestGetAttachment(t *testing.T) {
+	d := setupDB(t)
+	rev1 := putAttachment(t, d, "foo", "", "foo.txt", "Hello")
+	_ = putAttachment(t, d, "foo", rev1, "foo.txt", "Goodbye")
+
+	tests := []struct {
+		name     string
+		filename string
+		options  driver.Options
+		content  string
+		revPos   int64
+		status   int
+		err      string
+	}{
+		{
+			name:     "current revision",
+			filename: "foo.txt",
+			options:  kivik.Params(nil),
+			content:  "Goodbye",
+			revPos:   2,
+		},
+		{
+			name:     "old revision",
+			filename: "foo.txt",
+			options:  kivik.Rev(rev1),
+			content:  "Hello",
+			revPos:   1,
+		},
+		{
+			name:     "missing attachment",
+			filename: "bar.txt",
+			options:  kivik.Params(nil),
+			status:   http.StatusNotFound,
+			err:      "document is missing attachment",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			att, err := d.GetAttachment(context.Background(), "foo", tt.filename, tt.options)
+			if d := internal.StatusErrorDiff(tt.err, tt.status, err); d != "" {
+				t.Error(d)
+			}
+			if err != nil {
+				return
+			}
+			content, err := io.ReadAll(att.Content)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(content) != tt.content {
+				t.Errorf("Unexpected content: %s", content)
+			}
+			if att.RevPos != tt.revPos {
+				t.Errorf("Unexpected revpos: %d", att.RevPos)
+			}
+			if att.Size != int64(len(tt.content)) {
+				t.Errorf("Unexpected size: %d", att.Size)
+			}
+			meta, err := d.GetAttachmentMeta(context.Background(), "foo", tt.filename, tt.options)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if meta.Digest != att.Digest || meta.Content != nil {
+				t.Errorf("Unexpected meta: %+v", meta)
+			}
+		})
+	}
+}
+
+func TestDeleteAttachment(t *testing.T) {
+	d := setupDB(t)
+	rev := putAttachment(t, d, "foo", "", "foo.txt", "Hello")
+	rev = putAttachment(t, d, "foo", rev, "bar.txt", "World")
+
+	_, err := d.DeleteAttachment(context.Background(), "foo", "foo.txt", kivik.Rev("1-xxx"))
+	if d := internal.StatusErrorDiff("document update conflict", http.StatusConflict, err); d != "" {
+		t.Error(d)
+	}
+	_, err = d.DeleteAttachment(context.Background(), "foo", "baz.txt", kivik.Rev(rev))
+	if d := internal.StatusErrorDiff("document is missing attachment", http.StatusNotFound, err); d != "" {
+		t.Error(d)
+	}
+	_, err = d.DeleteAttachment(context.Background(), "bar", "foo.txt", kivik.Rev(rev))
+	if d := internal.StatusErrorDiff("document update conflict", http.StatusConflict, err); d != "" {
+		t.Error(d)
+	}
+	if _, err := d.DeleteAttachment(context.Background(), "foo", "foo.txt", kivik.Rev(rev)); err != nil {
+		t.Fatal(err)
+	}
+	atts := getDoc(t, d, "foo", kivik.Params(nil))["_attachments"].(map[string]interface{})
+	if _, ok := atts["foo.txt"]; ok || len(atts) != 1 {
+		t.Errorf("Unexpected attachments: %v", atts)
+	}
+}
+
+func TestGetWithAttachments(t *testing.T) {
+	d := setupDB(t)
+	rev1 := putAttachment(t, d, "foo", "", "foo.txt", "Hello")
+	_ = putAttachment(t, d, "foo", rev1, "bar.txt", "World")
+
+	fooStub := map[string]interface{}{
+		"content_type": "text/plain",
+		"digest":       "md5-ixqZU8RhEpaoJ6v4xHgE1w==",
+		"length":       5,
+		"revpos":       1,
+		"stub":         true,
+	}
+	fooData := map[string]interface{}{
+		"content_type": "text/plain",
+		"digest":       "md5-ixqZU8RhEpaoJ6v4xHgE1w==",
+		"revpos":       1,
+		"data":         "SGVsbG8=",
+	}
+	barData := map[string]interface{}{
+		"content_type": "text/plain",
+		"digest":       "md5-9aeSTmIehMkoCpon4by39g==",
+		"revpos":       2,
+		"data":         "V29ybGQ=",
+	}
+
+	tests := []struct {
+		name     string
+		options  driver.Options
+		expected map[string]interface{}
+	}{
+		{
+			name:     "attachments=true",
+			options:  kivik.Param("attachments", true),
+			expected: map[string]interface{}{"foo.txt": fooData, "bar.txt": barData},
+		},
+		{
+			name:     "atts_since known rev",
+			options:  kivik.Param("atts_since", []string{rev1}),
+			expected: map[string]interface{}{"foo.txt": fooStub, "bar.txt": barData},
+		},
+		{
+			name:     "atts_since unknown rev",
+			options:  kivik.Param("atts_since", []string{"1-abc"}),
+			expected: map[string]interface{}{"foo.txt": fooData, "bar.txt": barData},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := getDoc(t, d, "foo", tt.options)
+			if d := testy.DiffAsJSON(tt.expected, result["_attachments"]); d != nil {
+				t.Error(d)
+			}
+		})
+	}
+}
@@ -15,10 +15,12 @@ package memorydb
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"regexp"
 	"strings"
 
@@ -35,11 +37,6 @@ type db struct {
 	db     *database
 }
 
-func (d *db) Query(context.Context, string, string, driver.Options) (driver.Rows, error) {
-	// FIXME: Unimplemented
-	return nil, notYetImplemented
-}
-
 func (d *db) Get(ctx context.Context, docID string, options driver.Options) (*driver.Document, error) {
 	if exists, _ := d.client.DBExists(ctx, d.dbName, nil); !exists {
 		return nil, statusError{status: http.StatusPreconditionFailed, error: errors.New("database does not exist")}
@@ -51,9 +48,13 @@ func (d *db) Get(ctx context.Context, docID string, options driver.Options) (*dr
 	options.Apply(opts)
 	if rev, ok := opts["rev"].(string); ok {
 		if doc, found := d.db.getRevision(docID, rev); found {
+			body, err := d.db.withAttachments(docID, doc, opts)
+			if err != nil {
+				return nil, err
+			}
 			return &driver.Document{
 				Rev:  rev,
-				Body: io.NopCloser(bytes.NewReader(doc.data)),
+				Body: io.NopCloser(bytes.NewReader(body)),
 			}, nil
 		}
 		return nil, statusError{status: http.StatusNotFound, error: errors.New("missing")}
@@ -62,9 +63,13 @@ func (d *db) Get(ctx context.Context, docID string, options driver.Options) (*dr
 	if last.Deleted {
 		return nil, statusError{status: http.StatusNotFound, error: errors.New("missing")}
 	}
+	body, err := d.db.withAttachments(docID, last, opts)
+	if err != nil {
+		return nil, err
+	}
 	return &driver.Document{
 		Rev:  fmt.Sprintf("%d-%s", last.ID, last.Rev),
-		Body: io.NopCloser(bytes.NewReader(last.data)),
+		Body: io.NopCloser(bytes.NewReader(body)),
 	}, nil
 }
 
@@ -81,21 +86,73 @@ func (d *db) Put(ctx context.Context, docID string, doc interface{}, _ driver.Op
 		return "", err
 	}
 	couchDoc["_id"] = docID
-	// TODO: Add support for storing attachments.
-	delete(couchDoc, "_attachments")
 
-	if last, ok := d.db.latestRevision(docID); ok {
-		if !last.Deleted && !isLocal && couchDoc.Rev() != fmt.Sprintf("%d-%s", last.ID, last.Rev) {
-			return "", statusError{status: http.StatusConflict, error: errors.New("document update conflict")}
-		}
-		return d.db.addRevision(couchDoc), nil
+	last, err := d.checkRev(docID, couchDoc.Rev())
+	if err != nil {
+		return "", err
+	}
+	atts, err := couchDoc.attachments(last)
+	if err != nil {
+		return "", err
 	}
+	return d.db.addRevision(couchDoc, atts), nil
+}
 
-	if couchDoc.Rev() != "" {
+// checkRev returns the latest revision of docID, or a conflict error if rev
+// does not match it.
+func (d *db) checkRev(docID, rev string) (*revision, error) {
+	last, ok := d.db.latestRevision(docID)
+	if ok {
+		if !last.Deleted && !strings.HasPrefix(docID, "_local/") && rev != fmt.Sprintf("%d-%s", last.ID, last.Rev) {
+			return nil, statusError{status: http.StatusConflict, error: errors.New("document update conflict")}
+		}
+	} else if rev != "" {
 		// Rev should not be set for a new document
-		return "", statusError{status: http.StatusConflict, error: errors.New("document update conflict")}
+		return nil, statusError{status: http.StatusConflict, error: errors.New("document update conflict")}
+	}
+	return last, nil
+}
+
+var _ driver.Copier = &db{}
+
+// Copy copies sourceID to targetID, which may be suffixed with ?rev= to
+// overwrite an existing document, as with CouchDB's Destination header.
+func (d *db) Copy(ctx context.Context, targetID, sourceID string, options driver.Options) (string, error) {
+	if exists, _ := d.client.DBExists(ctx, d.dbName, nil); !exists {
+		return "", statusError{status: http.StatusPreconditionFailed, error: errors.New("database does not exist")}
+	}
+	var targetRev string
+	if parts := strings.SplitN(targetID, "?", 2); len(parts) == 2 {
+		query, err := url.ParseQuery(parts[1])
+		if err != nil {
+			return "", statusError{status: http.StatusBadRequest, error: err}
+		}
+		targetID, targetRev = parts[0], query.Get("rev")
 	}
-	return d.db.addRevision(couchDoc), nil
+	if !strings.HasPrefix(targetID, "_local/") && targetID[0] == '_' && !strings.HasPrefix(targetID, "_design/") {
+		return "", statusError{status: http.StatusBadRequest, error: errors.New("only reserved document ids may start with underscore")}
+	}
+	opts := map[string]interface{}{}
+	options.Apply(opts)
+	src, err := d.revision(sourceID, opts)
+	if err != nil {
+		return "", err
+	}
+	var doc couchDoc
+	if err := json.Unmarshal(src.data, &doc); err != nil {
+		return "", err
+	}
+	if _, err := d.checkRev(targetID, targetRev); err != nil {
+		return "", err
+	}
+	doc["_id"] = targetID
+	delete(doc, "_rev")
+	atts := make(map[string]file, len(src.Attachments))
+	for filename, att := range src.Attachments {
+		att.RevPos = 0
+		atts[filename] = att
+	}
+	return d.db.addRevision(doc, atts), nil
 }
 
 var revRE = regexp.MustCompile("^[0-9]+-[a-f0-9]{32}$")
@@ -156,19 +213,4 @@ func (d *db) Changes(context.Context, driver.Options) (driver.Changes, error) {
 	return nil, notYetImplemented
 }
 
-func (d *db) PutAttachment(context.Context, string, *driver.Attachment, driver.Options) (string, error) {
-	// FIXME: Unimplemented
-	return "", notYetImplemented
-}
-
-func (d *db) GetAttachment(context.Context, string, string, driver.Options) (*driver.Attachment, error) {
-	// FIXME: Unimplemented
-	return nil, notYetImplemented
-}
-
-func (d *db) DeleteAttachment(context.Context, string, string, driver.Options) (newRev string, err error) {
-	// FIXME: Unimplemented
-	return "", notYetImplemented
-}
-
 func (d *db) Close() error { return nil }
@@ -211,18 +211,47 @@ func TestPut(t *testing.T) {
 			Doc: map[string]interface{}{
 				"_id":   "duck",
 				"value": "quack",
-				"_attachments": []map[string]interface{}{
-					{"foo.css": map[string]string{
+				"_attachments": map[string]interface{}{
+					"foo.css": map[string]string{
 						"content_type": "text/css",
 						"data":         "LyogYW4gZW1wdHkgQ1NTIGZpbGUgKi8=",
-					}},
+					},
 				},
 			},
-			Expected: map[string]string{
+			Expected: map[string]interface{}{
 				"_id":   "duck",
 				"_rev":  "1-xxx",
 				"value": "quack",
+				"_attachments": map[string]interface{}{
+					"foo.css": map[string]interface{}{
+						"content_type": "text/css",
+						"digest":       "md5-eArvtVNNcDDKz2vSYztqPA==",
+						"length":       23,
+						"revpos":       1,
+						"stub":         true,
+					},
+				},
+			},
+		},
+		{
+			Name:  "InvalidAttachments",
+			DocID: "duck",
+			Doc: map[string]interface{}{
+				"_attachments": []string{"foo.css"},
+			},
+			Status: http.StatusBadRequest,
+			Error:  "bad special document member: _attachments",
+		},
+		{
+			Name:  "MissingStub",
+			DocID: "duck",
+			Doc: map[string]interface{}{
+				"_attachments": map[string]interface{}{
+					"foo.css": map[string]interface{}{"stub": true},
+				},
 			},
+			Status: http.StatusPreconditionFailed,
+			Error:  "invalid attachment stub in duck for foo.css",
 		},
 		{
 			Name: "Deleted DB",
@@ -559,3 +588,74 @@ func TestDeleteDoc(t *testing.T) {
 		}(test)
 	}
 }
+
+func TestCopy(t *testing.T) {
+	t.Run("new target", func(t *testing.T) {
+		d := setupDB(t)
+		rev, err := d.Put(context.Background(), "foo", map[string]string{"name": "Bob"}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		putAttachment(t, d, "foo", rev, "foo.txt", "Hello")
+		if _, err := d.Copy(context.Background(), "bar", "foo", kivik.Params(nil)); err != nil {
+			t.Fatal(err)
+		}
+		doc := getDoc(t, d, "bar", kivik.Param("attachments", true))
+		expected := map[string]interface{}{
+			"_id":  "bar",
+			"name": "Bob",
+			"_attachments": map[string]interface{}{
+				"foo.txt": map[string]interface{}{
+					"content_type": "text/plain",
+					"digest":       "md5-ixqZU8RhEpaoJ6v4xHgE1w==",
+					"revpos":       1,
+					"data":         "SGVsbG8=",
+				},
+			},
+		}
+		if d := testy.DiffAsJSON(expected, doc); d != nil {
+			t.Error(d)
+		}
+	})
+	t.Run("overwrite with rev", func(t *testing.T) {
+		d := setupDB(t)
+		if _, err := d.Put(context.Background(), "foo", map[string]string{"name": "Bob"}, nil); err != nil {
+			t.Fatal(err)
+		}
+		rev, err := d.Put(context.Background(), "bar", map[string]string{"name": "Alice"}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		newRev, err := d.Copy(context.Background(), "bar?rev="+rev, "foo", kivik.Params(nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(newRev, "2-") {
+			t.Errorf("Unexpected rev: %s", newRev)
+		}
+		doc := getDoc(t, d, "bar", kivik.Params(nil))
+		if d := testy.DiffAsJSON(map[string]interface{}{"_id": "bar", "name": "Bob"}, doc); d != nil {
+			t.Error(d)
+		}
+	})
+	t.Run("overwrite without rev", func(t *testing.T) {
+		d := setupDB(t)
+		if _, err := d.Put(context.Background(), "foo", map[string]string{"name": "Bob"}, nil); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := d.Put(context.Background(), "bar", map[string]string{"name": "Alice"}, nil); err != nil {
+			t.Fatal(err)
+		}
+		_, err := d.Copy(context.Background(), "bar", "foo", kivik.Params(nil))
+		if d := internal.StatusErrorDiff("document update conflict", http.StatusConflict, err); d != "" {
+			t.Error(d)
+		}
+	})
+	t.Run("missing source", func(t *testing.T) {
+		d := setupDB(t)
+		_, err := d.Copy(context.Background(), "bar", "foo", kivik.Params(nil))
+		if d := internal.StatusErrorDiff("missing", http.StatusNotFound, err); d != "" {
+			t.Error(d)
+		}
+	})
+}
@@ -15,25 +15,32 @@ package memorydb
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
+	"sort"
 	"strings"
 
 	"github.com/go-kivik/kivik/v4/driver"
+	"github.com/go-kivik/kivik/v4/x/collate"
 	"github.com/go-kivik/kivik/v4/x/mango"
 )
 
-var errFindNotImplemented = errors.New("find feature not yet implemented")
+// defaultFindLimit is the limit applied to Find queries which don't specify
+// one, as with CouchDB.
+const defaultFindLimit = 25
 
 type findQuery struct {
 	Selector *mango.Selector `json:"selector"`
 	Limit    int64           `json:"limit"`
 	Skip     int64           `json:"skip"`
-	Sort     []string        `json:"sort"`
+	Sort     []sortField     `json:"sort"`
 	Fields   []string        `json:"fields"`
 	UseIndex indexSpec       `json:"use_index"`
+	Bookmark string          `json:"bookmark"`
 }
 
 type indexSpec struct {
@@ -62,79 +69,437 @@ func (i *indexSpec) UnmarshalJSON(data []byte) error {
 
 var _ driver.Finder = &db{}
 
-func (d *db) CreateIndex(context.Context, string, string, interface{}, driver.Options) error {
-	return errFindNotImplemented
+// findPlan describes how a Find query is executed.
+type findPlan struct {
+	query    *findQuery
+	selector map[string]interface{}
+	index    *mangoIndex
+	// rng is the range of values scanned for the first field of the index.
+	rng *mango.Range
+	// terms are the tokenized terms of a $text query, for which a text index
+	// is scanned.
+	terms    []string
+	desc     bool
+	warnings []string
 }
 
-func (d *db) GetIndexes(context.Context, driver.Options) ([]driver.Index, error) {
-	return nil, errFindNotImplemented
+func parseFindQuery(query interface{}) (*findQuery, map[string]interface{}, error) {
+	queryJSON, err := toJSON(query)
+	if err != nil {
+		return nil, nil, statusError{status: http.StatusBadRequest, error: err}
+	}
+	buf, err := io.ReadAll(queryJSON)
+	if err != nil {
+		return nil, nil, err
+	}
+	fq := &findQuery{Limit: defaultFindLimit}
+	if err := json.Unmarshal(buf, &fq); err != nil {
+		return nil, nil, statusError{status: http.StatusBadRequest, error: err}
+	}
+	if fq == nil || fq.Selector == nil {
+		return nil, nil, statusError{status: http.StatusBadRequest, error: errors.New("Missing required key: selector")}
+	}
+	var raw struct {
+		Selector map[string]interface{} `json:"selector"`
+	}
+	_ = json.Unmarshal(buf, &raw)
+	for _, f := range fq.Sort {
+		if f.desc != fq.Sort[0].desc {
+			return nil, nil, statusError{status: http.StatusBadRequest, error: errors.New("Sorts currently only support a single direction for all fields.")}
+		}
+	}
+	return fq, raw.Selector, nil
+}
+
+// usable returns true if the index can be used to satisfy a query with the
+// given field ranges and sort order.
+func (i *mangoIndex) usable(ranges map[string]*mango.Range, sortFields []sortField) bool {
+	if i.text {
+		return false
+	}
+	if len(sortFields) > len(i.fields) {
+		return false
+	}
+	for j, f := range sortFields {
+		if f.field != i.fields[j].field {
+			return false
+		}
+	}
+	if i.special {
+		return true
+	}
+	// Documents lacking any indexed field are not in the index, so the query
+	// must require all of them.
+	for _, f := range i.fields {
+		if _, ok := ranges[f.field]; !ok {
+			return false
+		}
+	}
+	return true
 }
 
-func (d *db) DeleteIndex(context.Context, string, string, driver.Options) error {
-	return errFindNotImplemented
+func (i *mangoIndex) matches(spec indexSpec) bool {
+	return !i.special &&
+		strings.TrimPrefix(i.ddoc, "_design/") == strings.TrimPrefix(spec.ddoc, "_design/") &&
+		(spec.index == "" || spec.index == i.name)
 }
 
-func (d *db) Find(ctx context.Context, query interface{}, _ driver.Options) (driver.Rows, error) {
+func (d *db) plan(ctx context.Context, query interface{}) (*findPlan, error) {
 	if exists, _ := d.DBExists(ctx, d.dbName, nil); !exists {
 		return nil, statusError{status: http.StatusNotFound, error: errors.New("database does not exist")}
 	}
-	queryJSON, err := toJSON(query)
+	fq, selector, err := parseFindQuery(query)
 	if err != nil {
 		return nil, err
 	}
-	fq := &findQuery{}
-	if err := json.NewDecoder(queryJSON).Decode(&fq); err != nil {
+	plan := &findPlan{
+		query:    fq,
+		selector: selector,
+		desc:     len(fq.Sort) > 0 && fq.Sort[0].desc,
+	}
+	indexes := d.db.indexes()
+	if query, ok := fq.Selector.TextQuery(); ok {
+		if err := plan.planText(indexes, query); err != nil {
+			return nil, err
+		}
+		return plan, nil
+	}
+	ranges := fq.Selector.Ranges()
+	if fq.UseIndex.ddoc != "" {
+		for _, idx := range indexes {
+			if idx.matches(fq.UseIndex) && idx.usable(ranges, fq.Sort) {
+				plan.index = idx
+				break
+			}
+		}
+		if plan.index == nil {
+			name := fq.UseIndex.ddoc
+			if fq.UseIndex.index != "" {
+				name += ", " + fq.UseIndex.index
+			}
+			plan.warnings = append(plan.warnings, name+" was not used because it is not a valid index for this query.")
+		}
+	}
+	if plan.index == nil {
+		// Prefer the json index which covers the most fields. As with CouchDB,
+		// partial indexes are only used when requested with use_index.
+		for _, idx := range indexes[1:] {
+			if idx.partial == nil && idx.usable(ranges, fq.Sort) && (plan.index == nil || len(idx.fields) > len(plan.index.fields)) {
+				plan.index = idx
+			}
+		}
+	}
+	if plan.index == nil {
+		if !allDocsIndex.usable(ranges, fq.Sort) {
+			return nil, statusError{status: http.StatusBadRequest, error: errors.New("No index exists for this sort, try indexing by the sort fields.")}
+		}
+		plan.index = allDocsIndex
+	}
+	plan.rng = ranges[plan.index.fields[0].field]
+	return plan, nil
+}
+
+// planText plans a query using the $text operator, which requires a text
+// index.
+func (p *findPlan) planText(indexes []*mangoIndex, query string) error {
+	if len(p.query.Sort) > 0 {
+		return statusError{status: http.StatusBadRequest, error: errors.New("Sorting is not supported for $text queries.")}
+	}
+	for _, idx := range indexes {
+		if idx.text && (p.query.UseIndex.ddoc == "" || idx.matches(p.query.UseIndex)) {
+			p.index = idx
+			p.terms = mango.Tokenize(query)
+			return nil
+		}
+	}
+	return statusError{status: http.StatusBadRequest, error: errors.New("No text index exists for this query, try creating a text index.")}
+}
+
+// inRange returns true if value falls within the range.
+func inRange(r *mango.Range, value interface{}) bool {
+	if r == nil {
+		return true
+	}
+	if r.Start != nil {
+		if c := collate.CompareObject(value, r.Start.Value); c < 0 || (c == 0 && !r.Start.Inclusive) {
+			return false
+		}
+	}
+	if r.End != nil {
+		if c := collate.CompareObject(value, r.End.Value); c > 0 || (c == 0 && !r.End.Inclusive) {
+			return false
+		}
+	}
+	return true
+}
+
+// scan returns the index rows within the planned range, in the requested
+// order.
+func (p *findPlan) scan(db *database) ([]viewRow, error) {
+	var all []viewRow
+	switch {
+	case p.index.special:
+		all = db.allDocsRows()
+	case p.index.text:
+		return p.scanText(db)
+	default:
+		var err error
+		if all, err = db.indexRows(p.index); err != nil {
+			return nil, err
+		}
+	}
+	rows := make([]viewRow, 0, len(all))
+	for _, row := range all {
+		if inRange(p.rng, row.Key.([]interface{})[0]) {
+			rows = append(rows, row)
+		}
+	}
+	if p.desc {
+		for i, j := 0, len(rows)-1; i < j; i, j = i+1, j-1 {
+			rows[i], rows[j] = rows[j], rows[i]
+		}
+	}
+	return rows, nil
+}
+
+// scanText returns a row for each document in the text index which contains
+// any of the query terms, in document ID order.
+func (p *findPlan) scanText(db *database) ([]viewRow, error) {
+	all, err := db.indexRows(p.index)
+	if err != nil {
 		return nil, err
 	}
-	if fq == nil || fq.Selector == nil {
-		return nil, errors.New("Missing required key: selector")
+	terms := make(map[string]struct{}, len(p.terms))
+	for _, term := range p.terms {
+		terms[term] = struct{}{}
+	}
+	seen := map[string]struct{}{}
+	rows := make([]viewRow, 0, len(all))
+	for _, row := range all {
+		if _, ok := terms[row.Key.([]interface{})[0].(string)]; !ok {
+			continue
+		}
+		if _, ok := seen[row.ID]; !ok {
+			seen[row.ID] = struct{}{}
+			rows = append(rows, viewRow{ID: row.ID, Key: []interface{}{row.ID}})
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		return compareRows(rows[i], rows[j]) < 0
+	})
+	return rows, nil
+}
+
+// allDocsRows returns the rows of the special _all_docs index, which covers
+// all live documents other than design and local documents.
+func (d *database) allDocsRows() []viewRow {
+	d.mu.RLock()
+	rows := make([]viewRow, 0, len(d.docs))
+	for docID, doc := range d.docs {
+		if strings.HasPrefix(docID, "_design/") || strings.HasPrefix(docID, "_local/") || doc.revs[len(doc.revs)-1].Deleted {
+			continue
+		}
+		rows = append(rows, viewRow{ID: docID, Key: []interface{}{docID}})
+	}
+	d.mu.RUnlock()
+	sort.Slice(rows, func(i, j int) bool {
+		return compareRows(rows[i], rows[j]) < 0
+	})
+	return rows
+}
+
+func encodeBookmark(row viewRow) string {
+	buf, _ := json.Marshal([]interface{}{row.Key, row.ID})
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
+
+func decodeBookmark(bookmark string) (viewRow, error) {
+	invalid := statusError{status: http.StatusBadRequest, error: fmt.Errorf("Invalid bookmark value: %s", bookmark)}
+	buf, err := base64.RawURLEncoding.DecodeString(bookmark)
+	if err != nil {
+		return viewRow{}, invalid
+	}
+	var parts []interface{}
+	if err := json.Unmarshal(buf, &parts); err != nil || len(parts) != 2 {
+		return viewRow{}, invalid
+	}
+	id, ok := parts[1].(string)
+	if !ok {
+		return viewRow{}, invalid
+	}
+	return viewRow{ID: id, Key: parts[0]}, nil
+}
+
+func (d *db) Find(ctx context.Context, query interface{}, _ driver.Options) (driver.Rows, error) {
+	plan, err := d.plan(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	fq := plan.query
+	rows, err := plan.scan(d.db)
+	if err != nil {
+		return nil, err
+	}
+	if fq.Bookmark != "" && fq.Bookmark != "nil" {
+		last, err := decodeBookmark(fq.Bookmark)
+		if err != nil {
+			return nil, err
+		}
+		for len(rows) > 0 {
+			c := compareRows(rows[0], last)
+			if plan.desc {
+				c = -c
+			}
+			if c > 0 {
+				break
+			}
+			rows = rows[1:]
+		}
 	}
 	fields := make(map[string]struct{}, len(fq.Fields))
 	for _, field := range fq.Fields {
 		fields[field] = struct{}{}
 	}
-	rows := &findResults{
+	results := &findResults{
 		resultSet: resultSet{
 			docIDs: make([]string, 0),
 			revs:   make([]*revision, 0),
 		},
-		fields: fields,
+		fields:   fields,
+		index:    plan.index,
+		warnings: plan.warnings,
+		bookmark: fq.Bookmark,
 	}
-	for docID := range d.db.docs {
-		if doc, found := d.db.latestRevision(docID); found {
-			var cd couchDoc
-			if err := json.Unmarshal(doc.data, &cd); err != nil {
-				panic(err)
-			}
-			if fq.Selector.Match(map[string]interface{}(cd)) {
-				rows.docIDs = append(rows.docIDs, docID)
-				rows.revs = append(rows.revs, doc)
-			}
+	skip := fq.Skip
+	for _, row := range rows {
+		if int64(len(results.docIDs)) >= fq.Limit {
+			break
+		}
+		rev, found := d.db.latestRevision(row.ID)
+		if !found || rev.Deleted {
+			continue
 		}
+		var cd couchDoc
+		if err := json.Unmarshal(rev.data, &cd); err != nil {
+			return nil, err
+		}
+		if !fq.Selector.Match(map[string]interface{}(cd)) {
+			continue
+		}
+		if skip > 0 {
+			skip--
+			continue
+		}
+		results.docIDs = append(results.docIDs, row.ID)
+		results.revs = append(results.revs, rev)
+		results.bookmark = encodeBookmark(row)
 	}
-	rows.offset = 0
-	rows.totalRows = int64(len(rows.docIDs))
-	return rows, nil
+	if results.bookmark == "" {
+		results.bookmark = "nil"
+	}
+	results.offset = 0
+	results.totalRows = int64(len(results.docIDs))
+	return results, nil
 }
 
-func (d *db) Explain(context.Context, interface{}, driver.Options) (*driver.QueryPlan, error) {
-	return nil, errFindNotImplemented
+func (d *db) Explain(ctx context.Context, query interface{}, _ driver.Options) (*driver.QueryPlan, error) {
+	plan, err := d.plan(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	fq := plan.query
+	useIndex := []interface{}{}
+	if fq.UseIndex.ddoc != "" {
+		useIndex = append(useIndex, fq.UseIndex.ddoc)
+		if fq.UseIndex.index != "" {
+			useIndex = append(useIndex, fq.UseIndex.index)
+		}
+	}
+	bookmark := fq.Bookmark
+	if bookmark == "" {
+		bookmark = "nil"
+	}
+	sortOpt := make(map[string]interface{}, len(fq.Sort))
+	for _, f := range fq.Sort {
+		dir := "asc"
+		if f.desc {
+			dir = "desc"
+		}
+		sortOpt[f.field] = dir
+	}
+	var fields interface{} = "all_fields"
+	var planFields []interface{}
+	if len(fq.Fields) > 0 {
+		fields = fq.Fields
+		for _, f := range fq.Fields {
+			planFields = append(planFields, f)
+		}
+	}
+	return &driver.QueryPlan{
+		DBName:   d.dbName,
+		Index:    plan.index.planIndex(),
+		Selector: plan.selector,
+		Options: map[string]interface{}{
+			"bookmark":  bookmark,
+			"conflicts": false,
+			"fields":    fields,
+			"limit":     fq.Limit,
+			"skip":      fq.Skip,
+			"sort":      sortOpt,
+			"use_index": useIndex,
+		},
+		Limit:  fq.Limit,
+		Skip:   fq.Skip,
+		Fields: planFields,
+		Range:  plan.planRange(),
+	}, nil
+}
+
+// planRange returns the scanned range, as represented in a query plan.
+func (p *findPlan) planRange() map[string]interface{} {
+	if p.rng == nil || (p.rng.Start == nil && p.rng.End == nil) {
+		return nil
+	}
+	bound := func(b *mango.Bound) interface{} {
+		if p.index.special {
+			return b.Value
+		}
+		return []interface{}{b.Value}
+	}
+	r := map[string]interface{}{}
+	if p.rng.Start != nil {
+		r["start_key"] = bound(p.rng.Start)
+	}
+	if p.rng.End != nil {
+		r["end_key"] = bound(p.rng.End)
+	}
+	return r
 }
 
 type findResults struct {
 	resultSet
-	fields map[string]struct{}
+	fields   map[string]struct{}
+	index    *mangoIndex
+	warnings []string
+	bookmark string
 }
 
 var (
 	_ driver.Rows       = &findResults{}
 	_ driver.RowsWarner = &findResults{}
+	_ driver.Bookmarker = &findResults{}
 )
 
 func (r *findResults) Warning() string {
-	return "no matching index found, create an index to optimize query time"
+	warnings := append([]string{}, r.warnings...)
+	if r.index == nil || r.index.special {
+		warnings = append(warnings, "no matching index found, create an index to optimize query time")
+	}
+	return strings.Join(warnings, "\n")
 }
 
+func (r *findResults) Bookmark() string { return r.bookmark }
+
 func (r *findResults) Next(row *driver.Row) error {
 	if r.revs == nil || len(r.revs) == 0 {
 		return io.EOF
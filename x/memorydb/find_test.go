@@ -16,12 +16,16 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"io"
+	"net/http"
 	"strings"
 	"testing"
 
 	"gitlab.com/flimzy/testy"
 
+	"github.com/go-kivik/kivik/v4"
 	"github.com/go-kivik/kivik/v4/driver"
+	internal "github.com/go-kivik/kivik/v4/int/errors"
 )
 
 func TestIndexSpecUnmarshalJSON(t *testing.T) {
@@ -84,30 +88,6 @@ func TestIndexSpecUnmarshalJSON(t *testing.T) {
 	}
 }
 
-func TestCreateIndex(t *testing.T) {
-	d := &db{}
-	err := d.CreateIndex(context.Background(), "foo", "bar", "baz", nil)
-	if err != errFindNotImplemented {
-		t.Errorf("Unexpected error: %s", err)
-	}
-}
-
-func TestGetIndexes(t *testing.T) {
-	d := &db{}
-	_, err := d.GetIndexes(context.Background(), nil)
-	if err != errFindNotImplemented {
-		t.Errorf("Unexpected error: %s", err)
-	}
-}
-
-func TestDeleteIndex(t *testing.T) {
-	d := &db{}
-	err := d.DeleteIndex(context.Background(), "foo", "bar", nil)
-	if err != errFindNotImplemented {
-		t.Errorf("Unexpected error: %s", err)
-	}
-}
-
 // TestFind tests selectors, to see that the proper doc IDs are returned.
 func TestFind(t *testing.T) {
 	type findTest struct {
@@ -133,6 +113,16 @@ func TestFind(t *testing.T) {
 			name: "No query",
 			err:  "Missing required key: selector",
 		},
+		{
+			name:  "invalid selector",
+			query: `{"selector":{"value":{"$mod":[0,1]}}}`,
+			err:   "Bad argument for operator $mod: [0,1]",
+		},
+		{
+			name:  "unknown operator",
+			query: `{"selector":{"value":{"$foo":1}}}`,
+			err:   "Invalid operator: $foo",
+		},
 		{
 			name:  "empty selector",
 			query: `{"selector":{}}`,
@@ -358,3 +348,325 @@ func TestToJSON(t *testing.T) {
 		})
 	}
 }
+
+func setupFindDB(t *testing.T) *db {
+	t.Helper()
+	d := setupDB(t)
+	for i, name := range []string{"dave", "alice", "carol", "bob", "eve"} {
+		doc := map[string]interface{}{"name": name, "age": 20 + i*5}
+		if _, err := d.Put(context.Background(), name, doc, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := d.Put(context.Background(), "noage", map[string]interface{}{"name": "noage"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.CreateIndex(context.Background(), "ages", "age", `{"fields":["age"]}`, kivik.Params(nil)); err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func findIDs(t *testing.T, rows driver.Rows) []string {
+	t.Helper()
+	var ids []string
+	for {
+		var row driver.Row
+		if err := rows.Next(&row); err != nil {
+			if err == io.EOF {
+				break
+			}
+			t.Fatal(err)
+		}
+		ids = append(ids, row.ID)
+	}
+	return ids
+}
+
+func TestFindWithIndex(t *testing.T) {
+	d := setupFindDB(t)
+
+	tests := []struct {
+		name    string
+		query   string
+		want    []string
+		warning string
+		status  int
+		err     string
+	}{
+		{
+			name:    "no index, _id order",
+			query:   `{"selector":{"name":{"$gt":"b"}}}`,
+			want:    []string{"bob", "carol", "dave", "eve", "noage"},
+			warning: "no matching index found, create an index to optimize query time",
+		},
+		{
+			name:  "range scan",
+			query: `{"selector":{"$and":[{"age":{"$gte":25}},{"age":{"$lt":35}}]}}`,
+			want:  []string{"alice", "carol"},
+		},
+		{
+			name:  "sort ascending",
+			query: `{"selector":{"age":{"$gt":null}},"sort":["age"]}`,
+			want:  []string{"dave", "alice", "carol", "bob", "eve"},
+		},
+		{
+			name:  "sort descending with skip and limit",
+			query: `{"selector":{"age":{"$gt":null}},"sort":[{"age":"desc"}],"skip":1,"limit":2}`,
+			want:  []string{"bob", "carol"},
+		},
+		{
+			name:    "sort on _id",
+			query:   `{"selector":{},"sort":[{"_id":"desc"}],"limit":2}`,
+			want:    []string{"noage", "eve"},
+			warning: "no matching index found, create an index to optimize query time",
+		},
+		{
+			name:   "sort without index",
+			query:  `{"selector":{"name":{"$gt":null}},"sort":["name"]}`,
+			status: http.StatusBadRequest,
+			err:    "No index exists for this sort, try indexing by the sort fields.",
+		},
+		{
+			name:   "sort on index field not required by selector",
+			query:  `{"selector":{},"sort":["age"]}`,
+			status: http.StatusBadRequest,
+			err:    "No index exists for this sort, try indexing by the sort fields.",
+		},
+		{
+			name:   "mixed sort directions",
+			query:  `{"selector":{"age":{"$gt":null}},"sort":[{"age":"asc"},{"name":"desc"}]}`,
+			status: http.StatusBadRequest,
+			err:    "Sorts currently only support a single direction for all fields.",
+		},
+		{
+			name:    "unusable use_index",
+			query:   `{"selector":{"name":"eve"},"use_index":["ages","age"]}`,
+			want:    []string{"eve"},
+			warning: "ages, age was not used because it is not a valid index for this query.\nno matching index found, create an index to optimize query time",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := d.Find(context.Background(), tt.query, nil)
+			if d := internal.StatusErrorDiff(tt.err, tt.status, err); d != "" {
+				t.Error(d)
+			}
+			if err != nil {
+				return
+			}
+			if d := testy.DiffTextSlices(tt.want, findIDs(t, rows)); d != nil {
+				t.Error(d)
+			}
+			if w := rows.(driver.RowsWarner).Warning(); w != tt.warning {
+				t.Errorf("Unexpected warning: %q", w)
+			}
+		})
+	}
+}
+
+func TestFindPartialAndTextIndexes(t *testing.T) {
+	d := setupDB(t)
+	docs := map[string]map[string]interface{}{
+		"a": {"title": "The Quick Fox", "n": 1, "deleted": false},
+		"b": {"title": "A lazy dog", "n": 2, "deleted": true},
+		"c": {"title": "Quick thinking", "n": 3, "deleted": false, "tags": []interface{}{"dog"}},
+		"d": {"body": "quick", "n": 4, "deleted": false},
+	}
+	for id, doc := range docs {
+		if _, err := d.Put(context.Background(), id, doc, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := d.CreateIndex(context.Background(), "live", "n", `{"fields":["n"],"partial_filter_selector":{"deleted":{"$ne":true}}}`, kivik.Params(nil)); err != nil {
+		t.Fatal(err)
+	}
+
+	type findTest struct {
+		name    string
+		query   string
+		want    []string
+		warning string
+		status  int
+		err     string
+	}
+	tests := []findTest{
+		{
+			name:    "partial index not used by default",
+			query:   `{"selector":{"n":{"$gt":0}}}`,
+			want:    []string{"a", "b", "c", "d"},
+			warning: "no matching index found, create an index to optimize query time",
+		},
+		{
+			name:  "partial index excludes filtered documents",
+			query: `{"selector":{"n":{"$gt":0}},"use_index":"live"}`,
+			want:  []string{"a", "c", "d"},
+		},
+		{
+			name:   "$text without text index",
+			query:  `{"selector":{"$text":"quick"}}`,
+			status: http.StatusBadRequest,
+			err:    "No text index exists for this query, try creating a text index.",
+		},
+	}
+	run := func(t *testing.T, tests []findTest) {
+		t.Helper()
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				rows, err := d.Find(context.Background(), tt.query, nil)
+				if d := internal.StatusErrorDiff(tt.err, tt.status, err); d != "" {
+					t.Error(d)
+				}
+				if err != nil {
+					return
+				}
+				if d := testy.DiffTextSlices(tt.want, findIDs(t, rows)); d != nil {
+					t.Error(d)
+				}
+				if w := rows.(driver.RowsWarner).Warning(); w != tt.warning {
+					t.Errorf("Unexpected warning: %q", w)
+				}
+			})
+		}
+	}
+	run(t, tests)
+
+	if err := d.CreateIndex(context.Background(), "search", "title", `{"fields":[{"title":"string"}],"partial_filter_selector":{"deleted":{"$ne":true}}}`, kivik.Param("type", "text")); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.CreateIndex(context.Background(), "search", "all", nil, kivik.Param("type", "text")); err != nil {
+		t.Fatal(err)
+	}
+	run(t, []findTest{
+		{
+			name:  "$text searches indexed fields",
+			query: `{"selector":{"$text":"quick"},"use_index":["search","title"]}`,
+			want:  []string{"a", "c"},
+		},
+		{
+			name:  "$text honors partial filter",
+			query: `{"selector":{"$text":"dog"},"use_index":["search","title"]}`,
+			want:  []string{},
+		},
+		{
+			name:  "$text over all fields",
+			query: `{"selector":{"$text":"dog"},"use_index":["search","all"]}`,
+			want:  []string{"b", "c"},
+		},
+		{
+			name:  "$text combined with other conditions",
+			query: `{"selector":{"$text":"quick","n":{"$gt":1}},"use_index":["search","all"]}`,
+			want:  []string{"c", "d"},
+		},
+		{
+			name:   "$text with sort",
+			query:  `{"selector":{"$text":"quick"},"sort":["n"]}`,
+			status: http.StatusBadRequest,
+			err:    "Sorting is not supported for $text queries.",
+		},
+	})
+}
+
+func TestFindBookmark(t *testing.T) {
+	d := setupFindDB(t)
+	query := map[string]interface{}{
+		"selector": map[string]interface{}{"age": map[string]interface{}{"$gt": nil}},
+		"sort":     []interface{}{map[string]string{"age": "desc"}},
+		"limit":    2,
+	}
+	var got []string
+	for i := 0; i < 4; i++ {
+		rows, err := d.Find(context.Background(), query, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids := findIDs(t, rows)
+		got = append(got, ids...)
+		query["bookmark"] = rows.(driver.Bookmarker).Bookmark()
+		if len(ids) == 0 {
+			break
+		}
+	}
+	if d := testy.DiffTextSlices([]string{"eve", "bob", "carol", "alice", "dave"}, got); d != nil {
+		t.Error(d)
+	}
+
+	query["bookmark"] = "invalid!"
+	_, err := d.Find(context.Background(), query, nil)
+	if d := internal.StatusErrorDiff("Invalid bookmark value: invalid!", http.StatusBadRequest, err); d != "" {
+		t.Error(d)
+	}
+}
+
+func TestExplain(t *testing.T) {
+	d := setupFindDB(t)
+
+	tests := []struct {
+		name  string
+		query string
+		want  *driver.QueryPlan
+	}{
+		{
+			name:  "all docs",
+			query: `{"selector":{"_id":{"$gt":null}}}`,
+			want: &driver.QueryPlan{
+				DBName: "foo",
+				Index: map[string]interface{}{
+					"ddoc": nil,
+					"name": "_all_docs",
+					"type": "special",
+					"def":  map[string]interface{}{"fields": []interface{}{map[string]string{"_id": "asc"}}},
+				},
+				Selector: map[string]interface{}{"_id": map[string]interface{}{"$gt": nil}},
+				Options: map[string]interface{}{
+					"bookmark":  "nil",
+					"conflicts": false,
+					"fields":    "all_fields",
+					"limit":     25,
+					"skip":      0,
+					"sort":      map[string]interface{}{},
+					"use_index": []interface{}{},
+				},
+				Limit: 25,
+				Range: map[string]interface{}{"start_key": nil},
+			},
+		},
+		{
+			name:  "json index",
+			query: `{"selector":{"age":{"$lte":30}},"fields":["name"],"sort":["age"],"limit":10,"use_index":"ages"}`,
+			want: &driver.QueryPlan{
+				DBName: "foo",
+				Index: map[string]interface{}{
+					"ddoc": "_design/ages",
+					"name": "age",
+					"type": "json",
+					"def":  map[string]interface{}{"fields": []interface{}{map[string]string{"age": "asc"}}},
+				},
+				Selector: map[string]interface{}{"age": map[string]interface{}{"$lte": 30}},
+				Options: map[string]interface{}{
+					"bookmark":  "nil",
+					"conflicts": false,
+					"fields":    []string{"name"},
+					"limit":     10,
+					"skip":      0,
+					"sort":      map[string]interface{}{"age": "asc"},
+					"use_index": []interface{}{"ages"},
+				},
+				Limit:  10,
+				Fields: []interface{}{"name"},
+				Range:  map[string]interface{}{"end_key": []interface{}{30}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := d.Explain(context.Background(), tt.query, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if d := testy.DiffAsJSON(tt.want, plan); d != nil {
+				t.Error(d)
+			}
+		})
+	}
+}
@@ -0,0 +1,496 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package memorydb
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/go-kivik/kivik/v4/driver"
+	"github.com/go-kivik/kivik/v4/x/mango"
+)
+
+// sortField is a single field of an index definition or sort specification.
+type sortField struct {
+	field string
+	desc  bool
+}
+
+func (f *sortField) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		return json.Unmarshal(data, &f.field)
+	}
+	var obj map[string]string
+	if err := json.Unmarshal(data, &obj); err != nil || len(obj) != 1 {
+		return errors.New("invalid sort field")
+	}
+	for field, dir := range obj {
+		switch dir {
+		case "asc":
+		case "desc":
+			f.desc = true
+		default:
+			return fmt.Errorf("invalid sort direction: %s", dir)
+		}
+		f.field = field
+	}
+	return nil
+}
+
+func (f sortField) MarshalJSON() ([]byte, error) {
+	dir := "asc"
+	if f.desc {
+		dir = "desc"
+	}
+	return json.Marshal(map[string]string{f.field: dir})
+}
+
+// textField is a single field of a text index definition, of the form
+// {"name": "type"}.
+type textField struct {
+	name string
+	typ  string
+}
+
+func (f *textField) UnmarshalJSON(data []byte) error {
+	var obj map[string]string
+	if err := json.Unmarshal(data, &obj); err != nil || len(obj) != 1 {
+		return errors.New("invalid text index field")
+	}
+	for name, typ := range obj {
+		switch typ {
+		case "string", "number", "boolean":
+		default:
+			return fmt.Errorf("invalid text index field type: %s", typ)
+		}
+		f.name, f.typ = name, typ
+	}
+	return nil
+}
+
+func (f textField) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]string{f.name: f.typ})
+}
+
+// mangoIndex is a json or text index. As with CouchDB, json indexes are
+// stored as views in a design document with the "query" language, and text
+// indexes under the "indexes" key of the same design document.
+type mangoIndex struct {
+	ddoc       string // empty for the special _all_docs index
+	name       string
+	rev        string
+	fields     []sortField
+	textFields []textField
+	// partialDef is the raw partial_filter_selector, and partial its parsed
+	// form. Documents which don't match it are excluded from the index.
+	partialDef json.RawMessage
+	partial    *mango.Selector
+	text       bool
+	special    bool
+}
+
+var allDocsIndex = &mangoIndex{
+	name:    "_all_docs",
+	fields:  []sortField{{field: "_id"}},
+	special: true,
+}
+
+func (i *mangoIndex) driverIndex() driver.Index {
+	def := map[string]interface{}{"fields": i.fields}
+	idx := driver.Index{
+		DesignDoc:  i.ddoc,
+		Name:       i.name,
+		Type:       "json",
+		Definition: def,
+	}
+	switch {
+	case i.special:
+		idx.Type = "special"
+	case i.text:
+		idx.Type = "text"
+		def["fields"] = i.textFields
+		if i.textFields == nil {
+			def["fields"] = []textField{}
+		}
+	}
+	if i.partialDef != nil {
+		def["partial_filter_selector"] = i.partialDef
+	}
+	return idx
+}
+
+// planIndex returns the index as represented in a query plan.
+func (i *mangoIndex) planIndex() map[string]interface{} {
+	idx := i.driverIndex()
+	var ddoc interface{}
+	if !i.special {
+		ddoc = i.ddoc
+	}
+	return map[string]interface{}{
+		"ddoc": ddoc,
+		"name": idx.Name,
+		"type": idx.Type,
+		"def":  idx.Definition,
+	}
+}
+
+// queryView is the representation of a json index in a design document.
+type queryView struct {
+	Map struct {
+		Fields map[string]string `json:"fields"`
+	} `json:"map"`
+	Reduce  string `json:"reduce"`
+	Options struct {
+		Def jsonIndexDef `json:"def"`
+	} `json:"options"`
+}
+
+// jsonIndexDef is the definition of a json index.
+type jsonIndexDef struct {
+	Fields  []sortField     `json:"fields"`
+	Partial json.RawMessage `json:"partial_filter_selector,omitempty"`
+}
+
+// textIndexDef is the definition of a text index. With no fields, all string
+// values in the document are indexed.
+type textIndexDef struct {
+	Fields  []textField     `json:"fields,omitempty"`
+	Partial json.RawMessage `json:"partial_filter_selector,omitempty"`
+}
+
+// textIndex is the representation of a text index in a design document.
+type textIndex struct {
+	Index textIndexDef `json:"index"`
+}
+
+func newQueryView(def jsonIndexDef) *queryView {
+	v := &queryView{Reduce: "_count"}
+	v.Map.Fields = make(map[string]string, len(def.Fields))
+	for _, f := range def.Fields {
+		dir := "asc"
+		if f.desc {
+			dir = "desc"
+		}
+		v.Map.Fields[f.field] = dir
+	}
+	v.Options.Def = def
+	return v
+}
+
+// includes returns true if the document passes the index's partial filter.
+func (i *mangoIndex) includes(doc map[string]interface{}) bool {
+	return i.partial == nil || i.partial.Match(doc)
+}
+
+// mapFunc returns a function which indexes the document. For a json index,
+// it indexes the values of the index's fields, for documents which contain all
+// of them. For a text index, it emits each distinct token found in the
+// indexed fields.
+func (i *mangoIndex) mapFunc() (mapFunc, error) {
+	if i.text {
+		return i.textMapFunc, nil
+	}
+	return func(doc map[string]interface{}) ([]viewRow, error) {
+		if !i.includes(doc) {
+			return nil, nil
+		}
+		key := make([]interface{}, len(i.fields))
+		for j, f := range i.fields {
+			value, ok := fieldValue(doc, f.field)
+			if !ok {
+				return nil, nil
+			}
+			key[j] = value
+		}
+		return []viewRow{{Key: key}}, nil
+	}, nil
+}
+
+func (i *mangoIndex) textMapFunc(doc map[string]interface{}) ([]viewRow, error) {
+	if !i.includes(doc) {
+		return nil, nil
+	}
+	var values []string
+	if len(i.textFields) == 0 {
+		for field, value := range doc {
+			if !strings.HasPrefix(field, "_") {
+				values = append(values, mango.TextValues(value)...)
+			}
+		}
+	}
+	for _, f := range i.textFields {
+		if value, ok := fieldValue(doc, f.name); ok {
+			values = append(values, mango.TextValues(value)...)
+		}
+	}
+	seen := map[string]struct{}{}
+	var rows []viewRow
+	for _, value := range values {
+		for _, token := range mango.Tokenize(value) {
+			if _, ok := seen[token]; !ok {
+				seen[token] = struct{}{}
+				rows = append(rows, viewRow{Key: []interface{}{token}})
+			}
+		}
+	}
+	return rows, nil
+}
+
+// fieldValue returns the value of the named field, which may refer to a
+// nested field using dot notation.
+func fieldValue(doc map[string]interface{}, field string) (interface{}, bool) {
+	var value interface{} = doc
+	for _, part := range strings.Split(field, ".") {
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if value, ok = obj[part]; !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// indexes returns the special _all_docs index, followed by all json and text
+// indexes, sorted by design document and name.
+func (d *database) indexes() []*mangoIndex {
+	d.mu.RLock()
+	ddocs := make(map[string]*revision)
+	for docID, doc := range d.docs {
+		if last := doc.revs[len(doc.revs)-1]; strings.HasPrefix(docID, "_design/") && !last.Deleted {
+			ddocs[docID] = last
+		}
+	}
+	d.mu.RUnlock()
+
+	indexes := []*mangoIndex{allDocsIndex}
+	for docID, rev := range ddocs {
+		var ddoc designDoc
+		if err := json.Unmarshal(rev.data, &ddoc); err != nil || ddoc.Language != "query" {
+			continue
+		}
+		for name, raw := range ddoc.Views {
+			var view queryView
+			if err := json.Unmarshal(raw, &view); err != nil || len(view.Options.Def.Fields) == 0 {
+				continue
+			}
+			partial, err := parsePartial(view.Options.Def.Partial)
+			if err != nil {
+				continue
+			}
+			indexes = append(indexes, &mangoIndex{
+				ddoc:       docID,
+				name:       name,
+				rev:        fmt.Sprintf("%d-%s", rev.ID, rev.Rev),
+				fields:     view.Options.Def.Fields,
+				partialDef: view.Options.Def.Partial,
+				partial:    partial,
+			})
+		}
+		for name, raw := range ddoc.Indexes {
+			var index textIndex
+			if err := json.Unmarshal(raw, &index); err != nil {
+				continue
+			}
+			partial, err := parsePartial(index.Index.Partial)
+			if err != nil {
+				continue
+			}
+			indexes = append(indexes, &mangoIndex{
+				ddoc:       docID,
+				name:       name,
+				rev:        fmt.Sprintf("%d-%s", rev.ID, rev.Rev),
+				textFields: index.Index.Fields,
+				partialDef: index.Index.Partial,
+				partial:    partial,
+				text:       true,
+			})
+		}
+	}
+	sort.Slice(indexes[1:], func(i, j int) bool {
+		a, b := indexes[i+1], indexes[j+1]
+		if a.ddoc != b.ddoc {
+			return a.ddoc < b.ddoc
+		}
+		return a.name < b.name
+	})
+	return indexes
+}
+
+// indexRows returns the up-to-date, sorted contents of the index.
+func (d *database) indexRows(idx *mangoIndex) ([]viewRow, error) {
+	return d.viewIndex(idx.ddoc+"/"+idx.name).update(d, idx.rev, idx.mapFunc)
+}
+
+// parsePartial parses a partial_filter_selector, which may be empty.
+func parsePartial(raw json.RawMessage) (*mango.Selector, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	var sel mango.Selector
+	if err := json.Unmarshal(raw, &sel); err != nil {
+		return nil, err
+	}
+	return &sel, nil
+}
+
+// parseIndexDef parses the index definition, into a jsonIndexDef or a
+// textIndexDef, depending on typ.
+func parseIndexDef(index interface{}, typ string) (interface{}, error) {
+	if index == nil {
+		if typ == "text" {
+			return textIndexDef{}, nil
+		}
+		return nil, statusError{status: http.StatusBadRequest, error: errors.New("Missing required key: fields")}
+	}
+	indexJSON, err := toJSON(index)
+	if err != nil {
+		return nil, statusError{status: http.StatusBadRequest, error: err}
+	}
+	invalid := func(err error) error {
+		return statusError{status: http.StatusBadRequest, error: fmt.Errorf("invalid index definition: %w", err)}
+	}
+	switch typ {
+	case "text":
+		var def textIndexDef
+		if err := json.NewDecoder(indexJSON).Decode(&def); err != nil {
+			return nil, invalid(err)
+		}
+		if _, err := parsePartial(def.Partial); err != nil {
+			return nil, invalid(err)
+		}
+		return def, nil
+	case "", "json":
+		var def jsonIndexDef
+		if err := json.NewDecoder(indexJSON).Decode(&def); err != nil {
+			return nil, invalid(err)
+		}
+		if len(def.Fields) == 0 {
+			return nil, statusError{status: http.StatusBadRequest, error: errors.New("Missing required key: fields")}
+		}
+		if _, err := parsePartial(def.Partial); err != nil {
+			return nil, invalid(err)
+		}
+		return def, nil
+	}
+	return nil, statusError{status: http.StatusBadRequest, error: fmt.Errorf("Invalid index type: %s", typ)}
+}
+
+func (d *db) CreateIndex(ctx context.Context, ddoc, name string, index interface{}, options driver.Options) error {
+	if exists, _ := d.DBExists(ctx, d.dbName, nil); !exists {
+		return statusError{status: http.StatusNotFound, error: errors.New("database does not exist")}
+	}
+	opts := map[string]interface{}{}
+	options.Apply(opts)
+	typ, _ := opts["type"].(string)
+	def, err := parseIndexDef(index, typ)
+	if err != nil {
+		return err
+	}
+	if ddoc == "" || name == "" {
+		var hashed interface{} = def
+		if jsonDef, ok := def.(jsonIndexDef); ok && jsonDef.Partial == nil {
+			// Plain json indexes are named for their fields alone.
+			hashed = jsonDef.Fields
+		}
+		defJSON, _ := json.Marshal(hashed)
+		if typ == "text" {
+			defJSON = append([]byte("text:"), defJSON...)
+		}
+		sum := sha1.Sum(defJSON)
+		hash := hex.EncodeToString(sum[:])
+		if ddoc == "" {
+			ddoc = hash
+		}
+		if name == "" {
+			name = hash
+		}
+	}
+	ddocID := "_design/" + strings.TrimPrefix(ddoc, "_design/")
+	doc := map[string]interface{}{}
+	if last, ok := d.db.latestRevision(ddocID); ok && !last.Deleted {
+		if err := json.Unmarshal(last.data, &doc); err != nil {
+			return err
+		}
+		if lang, _ := doc["language"].(string); lang != "query" {
+			return statusError{status: http.StatusBadRequest, error: fmt.Errorf("%s is not a query design document", ddocID)}
+		}
+	}
+	doc["language"] = "query"
+	switch t := def.(type) {
+	case jsonIndexDef:
+		views, _ := doc["views"].(map[string]interface{})
+		if views == nil {
+			views = map[string]interface{}{}
+		}
+		views[name] = newQueryView(t)
+		doc["views"] = views
+	case textIndexDef:
+		indexes, _ := doc["indexes"].(map[string]interface{})
+		if indexes == nil {
+			indexes = map[string]interface{}{}
+		}
+		indexes[name] = textIndex{Index: t}
+		doc["indexes"] = indexes
+	}
+	_, err = d.Put(ctx, ddocID, doc, nil)
+	return err
+}
+
+func (d *db) GetIndexes(ctx context.Context, _ driver.Options) ([]driver.Index, error) {
+	if exists, _ := d.DBExists(ctx, d.dbName, nil); !exists {
+		return nil, statusError{status: http.StatusNotFound, error: errors.New("database does not exist")}
+	}
+	indexes := d.db.indexes()
+	result := make([]driver.Index, len(indexes))
+	for i, idx := range indexes {
+		result[i] = idx.driverIndex()
+	}
+	return result, nil
+}
+
+func (d *db) DeleteIndex(ctx context.Context, ddoc, name string, _ driver.Options) error {
+	if exists, _ := d.DBExists(ctx, d.dbName, nil); !exists {
+		return statusError{status: http.StatusNotFound, error: errors.New("database does not exist")}
+	}
+	notFound := statusError{status: http.StatusNotFound, error: errors.New("Index not found")}
+	ddocID := "_design/" + strings.TrimPrefix(ddoc, "_design/")
+	last, ok := d.db.latestRevision(ddocID)
+	if !ok || last.Deleted {
+		return notFound
+	}
+	doc := map[string]interface{}{}
+	if err := json.Unmarshal(last.data, &doc); err != nil {
+		return err
+	}
+	views, _ := doc["views"].(map[string]interface{})
+	indexes, _ := doc["indexes"].(map[string]interface{})
+	if lang, _ := doc["language"].(string); lang != "query" || (views[name] == nil && indexes[name] == nil) {
+		return notFound
+	}
+	delete(views, name)
+	delete(indexes, name)
+	if len(views) == 0 && len(indexes) == 0 {
+		doc["_deleted"] = true
+	}
+	_, err := d.Put(ctx, ddocID, doc, nil)
+	return err
+}
@@ -0,0 +1,213 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package memorydb
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"gitlab.com/flimzy/testy"
+
+	"github.com/go-kivik/kivik/v4"
+	internal "github.com/go-kivik/kivik/v4/int/errors"
+)
+
+func TestCreateIndex(t *testing.T) {
+	tests := []struct {
+		name   string
+		index  interface{}
+		typ    string
+		status int
+		err    string
+	}{
+		{
+			name:  "valid",
+			index: `{"fields":["foo"]}`,
+		},
+		{
+			name:  "explicit direction",
+			index: map[string]interface{}{"fields": []interface{}{map[string]string{"foo": "asc"}}},
+		},
+		{
+			name:   "nil index",
+			status: http.StatusBadRequest,
+			err:    "Missing required key: fields",
+		},
+		{
+			name:   "empty index",
+			index:  `{}`,
+			status: http.StatusBadRequest,
+			err:    "Missing required key: fields",
+		},
+		{
+			name:   "invalid JSON",
+			index:  `chicken`,
+			status: http.StatusBadRequest,
+			err:    "invalid index definition: invalid character 'c' looking for beginning of value",
+		},
+		{
+			name:  "partial index",
+			index: `{"fields":["foo"],"partial_filter_selector":{"deleted":{"$ne":true}}}`,
+		},
+		{
+			name:   "invalid partial filter selector",
+			index:  `{"fields":["foo"],"partial_filter_selector":{"foo":{"$bogus":1}}}`,
+			status: http.StatusBadRequest,
+			err:    "invalid index definition: Invalid operator: $bogus",
+		},
+		{
+			name:  "text index",
+			index: `{"fields":[{"title":"string"}]}`,
+			typ:   "text",
+		},
+		{
+			name: "text index of all fields",
+			typ:  "text",
+		},
+		{
+			name:   "invalid text field",
+			index:  `{"fields":["title"]}`,
+			typ:    "text",
+			status: http.StatusBadRequest,
+			err:    "invalid index definition: invalid text index field",
+		},
+		{
+			name:   "invalid type",
+			index:  `{"fields":["foo"]}`,
+			typ:    "geo",
+			status: http.StatusBadRequest,
+			err:    "Invalid index type: geo",
+		},
+		{
+			name:   "invalid direction",
+			index:  `{"fields":[{"foo":"up"}]}`,
+			status: http.StatusBadRequest,
+			err:    "invalid index definition: invalid sort direction: up",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := setupDB(t)
+			opts := map[string]interface{}{}
+			if tt.typ != "" {
+				opts["type"] = tt.typ
+			}
+			err := d.CreateIndex(context.Background(), "foo", "bar", tt.index, kivik.Params(opts))
+			if d := internal.StatusErrorDiff(tt.err, tt.status, err); d != "" {
+				t.Error(d)
+			}
+		})
+	}
+	t.Run("not a query ddoc", func(t *testing.T) {
+		d := setupDB(t)
+		if _, err := d.Put(context.Background(), "_design/foo", map[string]interface{}{"views": map[string]interface{}{}}, nil); err != nil {
+			t.Fatal(err)
+		}
+		err := d.CreateIndex(context.Background(), "foo", "bar", `{"fields":["foo"]}`, kivik.Params(nil))
+		if d := internal.StatusErrorDiff("_design/foo is not a query design document", http.StatusBadRequest, err); d != "" {
+			t.Error(d)
+		}
+	})
+}
+
+func TestGetIndexes(t *testing.T) {
+	d := setupDB(t)
+	if err := d.CreateIndex(context.Background(), "foo", "bar", `{"fields":["foo",{"bar":"asc"}]}`, kivik.Params(nil)); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.CreateIndex(context.Background(), "", "", `{"fields":["baz"]}`, kivik.Params(nil)); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.CreateIndex(context.Background(), "foo", "live", `{"fields":["qux"],"partial_filter_selector":{"deleted":{"$ne":true}}}`, kivik.Params(nil)); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.CreateIndex(context.Background(), "foo", "search", `{"fields":[{"title":"string"}]}`, kivik.Param("type", "text")); err != nil {
+		t.Fatal(err)
+	}
+	indexes, err := d.GetIndexes(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []interface{}{
+		map[string]interface{}{
+			"name": "_all_docs",
+			"type": "special",
+			"def":  map[string]interface{}{"fields": []interface{}{map[string]string{"_id": "asc"}}},
+		},
+		map[string]interface{}{
+			"ddoc": "_design/4d058bbf2ac1cba47fb777cdddcae3d5b2a20c43",
+			"name": "4d058bbf2ac1cba47fb777cdddcae3d5b2a20c43",
+			"type": "json",
+			"def":  map[string]interface{}{"fields": []interface{}{map[string]string{"baz": "asc"}}},
+		},
+		map[string]interface{}{
+			"ddoc": "_design/foo",
+			"name": "bar",
+			"type": "json",
+			"def": map[string]interface{}{"fields": []interface{}{
+				map[string]string{"foo": "asc"},
+				map[string]string{"bar": "asc"},
+			}},
+		},
+		map[string]interface{}{
+			"ddoc": "_design/foo",
+			"name": "live",
+			"type": "json",
+			"def": map[string]interface{}{
+				"fields":                  []interface{}{map[string]string{"qux": "asc"}},
+				"partial_filter_selector": map[string]interface{}{"deleted": map[string]interface{}{"$ne": true}},
+			},
+		},
+		map[string]interface{}{
+			"ddoc": "_design/foo",
+			"name": "search",
+			"type": "text",
+			"def":  map[string]interface{}{"fields": []interface{}{map[string]string{"title": "string"}}},
+		},
+	}
+	if d := testy.DiffAsJSON(expected, indexes); d != nil {
+		t.Error(d)
+	}
+}
+
+func TestDeleteIndex(t *testing.T) {
+	d := setupDB(t)
+	if err := d.CreateIndex(context.Background(), "foo", "bar", `{"fields":["foo"]}`, kivik.Params(nil)); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.CreateIndex(context.Background(), "foo", "baz", `{"fields":["baz"]}`, kivik.Params(nil)); err != nil {
+		t.Fatal(err)
+	}
+
+	err := d.DeleteIndex(context.Background(), "notfound", "bar", nil)
+	if d := internal.StatusErrorDiff("Index not found", http.StatusNotFound, err); d != "" {
+		t.Error(d)
+	}
+	err = d.DeleteIndex(context.Background(), "foo", "notfound", nil)
+	if d := internal.StatusErrorDiff("Index not found", http.StatusNotFound, err); d != "" {
+		t.Error(d)
+	}
+	if err := d.DeleteIndex(context.Background(), "_design/foo", "bar", nil); err != nil {
+		t.Fatal(err)
+	}
+	if indexes := d.db.indexes(); len(indexes) != 2 || indexes[1].name != "baz" {
+		t.Errorf("Unexpected indexes after first delete: %v", indexes)
+	}
+	if err := d.DeleteIndex(context.Background(), "foo", "baz", nil); err != nil {
+		t.Fatal(err)
+	}
+	if last, _ := d.db.latestRevision("_design/foo"); !last.Deleted {
+		t.Error("Expected empty design doc to be deleted")
+	}
+}
@@ -28,6 +28,8 @@ import (
 type file struct {
 	ContentType string
 	Data        []byte
+	Digest      string
+	RevPos      int64
 }
 
 type document struct {
@@ -47,6 +49,13 @@ type database struct {
 	docs     map[string]*document
 	deleted  bool
 	security *driver.Security
+	// seq is incremented for every new revision.
+	seq int64
+	// changes[seq-1] is the ID of the document updated at seq.
+	changes []string
+
+	viewsMu sync.Mutex
+	views   map[string]*viewIndex
 }
 
 var (
@@ -118,7 +127,9 @@ func toCouchDoc(i interface{}) (couchDoc, error) {
 	return m, nil
 }
 
-func (d *database) addRevision(doc couchDoc) string {
+// addRevision stores doc as a new revision, along with atts. Attachments with
+// a zero RevPos are new, and are assigned the new revision's position.
+func (d *database) addRevision(doc couchDoc, atts map[string]file) string {
 	d.mu.Lock()
 	defer d.mu.Unlock()
 	id, ok := doc["_id"].(string)
@@ -147,17 +158,30 @@ func (d *database) addRevision(doc couchDoc) string {
 	}
 	rev := fmt.Sprintf("%d-%s", revID, revStr)
 	doc["_rev"] = rev
+	delete(doc, "_attachments")
+	for filename, att := range atts {
+		if att.RevPos == 0 {
+			att.RevPos = revID
+			atts[filename] = att
+		}
+	}
+	if len(atts) > 0 {
+		doc["_attachments"] = attachmentStubs(atts)
+	}
 	data, err := json.Marshal(doc)
 	if err != nil {
 		panic(err)
 	}
 	deleted, _ := doc["_deleted"].(bool)
 	newRev := &revision{
-		data:    data,
-		ID:      revID,
-		Rev:     revStr,
-		Deleted: deleted,
+		data:        data,
+		ID:          revID,
+		Rev:         revStr,
+		Deleted:     deleted,
+		Attachments: atts,
 	}
+	d.seq++
+	d.changes = append(d.changes, id)
 	if isLocal {
 		d.docs[id].revs = []*revision{newRev}
 	} else {
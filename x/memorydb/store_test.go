@@ -78,14 +78,14 @@ func TestAddRevision(t *testing.T) {
 	d := &database{
 		docs: make(map[string]*document),
 	}
-	r := d.addRevision(couchDoc{"_id": "bar"})
+	r := d.addRevision(couchDoc{"_id": "bar"}, nil)
 	if !strings.HasPrefix(r, "1-") {
 		t.Errorf("Expected initial revision to start with '1-', but got '%s'", r)
 	}
 	if len(r) != 34 {
 		t.Errorf("rev (%s) is %d chars long, expected 34", r, len(r))
 	}
-	r = d.addRevision(couchDoc{"_id": "bar"})
+	r = d.addRevision(couchDoc{"_id": "bar"}, nil)
 	if !strings.HasPrefix(r, "2-") {
 		t.Errorf("Expected second revision to start with '2-', but got '%s'", r)
 	}
@@ -97,7 +97,7 @@ func TestAddRevision(t *testing.T) {
 			defer func() {
 				i = recover()
 			}()
-			d.addRevision(nil)
+			d.addRevision(nil, nil)
 			return nil
 		}()
 		if r == nil {
@@ -109,7 +109,7 @@ func TestAddRevision(t *testing.T) {
 			defer func() {
 				i = recover()
 			}()
-			d.addRevision(couchDoc{"_id": "foo", "invalid": make(chan int)})
+			d.addRevision(couchDoc{"_id": "foo", "invalid": make(chan int)}, nil)
 			return nil
 		}()
 		if r == nil {
@@ -122,11 +122,11 @@ func TestAddLocalRevision(t *testing.T) {
 	d := &database{
 		docs: make(map[string]*document),
 	}
-	r := d.addRevision(couchDoc{"_id": "_local/foo"})
+	r := d.addRevision(couchDoc{"_id": "_local/foo"}, nil)
 	if r != "1-0" {
 		t.Errorf("Expected local revision, got %s", r)
 	}
-	r = d.addRevision(couchDoc{"_id": "_local/foo"})
+	r = d.addRevision(couchDoc{"_id": "_local/foo"}, nil)
 	if r != "1-0" {
 		t.Errorf("Expected local revision, got %s", r)
 	}
@@ -146,8 +146,8 @@ func TestGetRevisionFound(t *testing.T) {
 	d := &database{
 		docs: make(map[string]*document),
 	}
-	r := d.addRevision(map[string]interface{}{"_id": "foo", "a": 1})
-	_ = d.addRevision(map[string]interface{}{"_id": "foo", "a": 2})
+	r := d.addRevision(map[string]interface{}{"_id": "foo", "a": 1}, nil)
+	_ = d.addRevision(map[string]interface{}{"_id": "foo", "a": 2}, nil)
 	result, found := d.getRevision("foo", r)
 	if !found {
 		t.Errorf("Should have found revision")
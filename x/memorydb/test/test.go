@@ -86,22 +86,56 @@ func RegisterMemoryDBSuite() {
 		"Find/RW/group/Admin/Warning.warning":  "no matching index found, create an index to optimize query time",
 		"Find/RW/group/NoAuth/Warning.warning": "no matching index found, create an index to optimize query time",
 
-		"Explain.skip":           true,                      // FIXME: Unimplemented
-		"Stats.skip":             true,                      // FIXME: Unimplemented
-		"Compact.skip":           true,                      // FIXME: Unimplemented
-		"DBUpdates.status":       http.StatusNotImplemented, // FIXME: Unimplemented
-		"Changes.skip":           true,                      // FIXME: Unimplemented
-		"Copy.skip":              true,                      // FIXME: Unimplemented, depends on Get/Put or Copy
-		"GetAttachment.skip":     true,                      // FIXME: Unimplemented
-		"GetAttachmentMeta.skip": true,                      // FIXME: Unimplemented
-		"PutAttachment.skip":     true,                      // FIXME: Unimplemented
-		"DeleteAttachment.skip":  true,                      // FIXME: Unimplemented
-		"Query.skip":             true,                      // FIXME: Unimplemented
-		"CreateIndex.skip":       true,                      // FIXME: Unimplemented
-		"GetIndexes.skip":        true,                      // FIXME: Unimplemented
-		"DeleteIndex.skip":       true,                      // FIXME: Unimplemented
-		"SetSecurity.skip":       true,                      // FIXME: Unimplemented
-		"ViewCleanup.skip":       true,                      // FIXME: Unimplemented
+		"Query/RW/group/Admin/WithoutDocs/ScanDoc.status": http.StatusBadRequest,
+
+		"CreateIndex/RW/Admin/group/EmptyIndex.status":   http.StatusBadRequest,
+		"CreateIndex/RW/Admin/group/BlankIndex.status":   http.StatusBadRequest,
+		"CreateIndex/RW/Admin/group/InvalidIndex.status": http.StatusBadRequest,
+		"CreateIndex/RW/Admin/group/NilIndex.status":     http.StatusBadRequest,
+		"CreateIndex/RW/Admin/group/InvalidJSON.status":  http.StatusBadRequest,
+
+		"GetIndexes.databases":            []string{"_users", "chicken"},
+		"GetIndexes/Admin/_users.indexes": []kivik.Index{kt.AllDocsIndex},
+		"GetIndexes/Admin/chicken.status": http.StatusNotFound,
+
+		"DeleteIndex/RW/Admin/group/NotFoundDdoc.status": http.StatusNotFound,
+		"DeleteIndex/RW/Admin/group/NotFoundName.status": http.StatusNotFound,
+
+		"Explain.databases":            []string{"chicken"},
+		"Explain/Admin/chicken.status": http.StatusNotFound,
+		"Explain.plan": &kivik.QueryPlan{
+			Index: map[string]interface{}{
+				"ddoc": nil,
+				"name": "_all_docs",
+				"type": "special",
+				"def":  map[string]interface{}{"fields": []interface{}{map[string]string{"_id": "asc"}}},
+			},
+			Selector: map[string]interface{}{"_id": map[string]interface{}{"$gt": nil}},
+			Options: map[string]interface{}{
+				"bookmark":  "nil",
+				"conflicts": false,
+				"fields":    "all_fields",
+				"limit":     25,
+				"skip":      0,
+				"sort":      map[string]interface{}{},
+				"use_index": []interface{}{},
+			},
+			Range: map[string]interface{}{"start_key": nil},
+			Limit: 25,
+		},
+
+		"GetAttachment/RW/group/Admin/foo/NotFound.status":     http.StatusNotFound,
+		"GetAttachmentMeta/RW/group/Admin/foo/NotFound.status": http.StatusNotFound,
+		"PutAttachment/RW/group/Admin/Conflict.status":         http.StatusConflict,
+		"DeleteAttachment/RW/group/Admin/NotFound.status":      http.StatusNotFound,
+		"DeleteAttachment/RW/group/Admin/NoDoc.status":         http.StatusConflict,
+
+		"Stats.skip":       true,                      // FIXME: Unimplemented
+		"Compact.skip":     true,                      // FIXME: Unimplemented
+		"DBUpdates.status": http.StatusNotImplemented, // FIXME: Unimplemented
+		"Changes.skip":     true,                      // FIXME: Unimplemented
+		"SetSecurity.skip": true,                      // FIXME: Unimplemented
+		"ViewCleanup.skip": true,                      // FIXME: Unimplemented
 	})
 }
 
@@ -0,0 +1,587 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package memorydb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/go-kivik/kivik/v4/driver"
+	"github.com/go-kivik/kivik/v4/x/collate"
+	"github.com/go-kivik/kivik/v4/x/js"
+	"github.com/go-kivik/kivik/v4/x/reduce"
+)
+
+// viewRow is a single row emitted by a map function.
+type viewRow struct {
+	ID    string
+	Key   interface{}
+	Value interface{}
+}
+
+// viewIndex is the in-memory index of a single view. It is updated
+// incrementally: only the documents changed since the indexed update sequence
+// are re-mapped, and their rows are merged into the sorted index.
+type viewIndex struct {
+	mu      sync.Mutex
+	version string
+	// seq is the database update sequence the index reflects.
+	seq int64
+	// rows is the sorted index. It is replaced, never modified in place, so
+	// that a reference may be safely read after the lock is released.
+	rows []viewRow
+}
+
+type viewFuncs struct {
+	Map    string `json:"map"`
+	Reduce string `json:"reduce"`
+}
+
+type designDoc struct {
+	Language string                     `json:"language"`
+	Views    map[string]json.RawMessage `json:"views"`
+	Indexes  map[string]json.RawMessage `json:"indexes"`
+}
+
+func (d *database) viewIndex(name string) *viewIndex {
+	d.viewsMu.Lock()
+	defer d.viewsMu.Unlock()
+	if d.views == nil {
+		d.views = map[string]*viewIndex{}
+	}
+	idx, ok := d.views[name]
+	if !ok {
+		idx = &viewIndex{}
+		d.views[name] = idx
+	}
+	return idx
+}
+
+func (d *database) updateSeq() int64 {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.seq
+}
+
+// changedSince returns the latest revision of every non-design, non-local
+// document updated after seq, along with the current update sequence.
+func (d *database) changedSince(seq int64) (map[string]*revision, int64) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	changed := map[string]*revision{}
+	for _, docID := range d.changes[seq:] {
+		if strings.HasPrefix(docID, "_local/") || strings.HasPrefix(docID, "_design/") {
+			continue
+		}
+		if _, ok := changed[docID]; ok {
+			continue
+		}
+		doc := d.docs[docID]
+		changed[docID] = doc.revs[len(doc.revs)-1]
+	}
+	return changed, d.seq
+}
+
+// mapFunc returns the rows to be indexed for a single document.
+type mapFunc func(doc map[string]interface{}) ([]viewRow, error)
+
+// jsMapFunc compiles a JavaScript map function. As with CouchDB, a document
+// which causes the map function to throw is simply excluded from the index.
+func jsMapFunc(code string) func() (mapFunc, error) {
+	return func() (mapFunc, error) {
+		var emitted []viewRow
+		fn, err := js.Map(code, func(key, value interface{}) {
+			emitted = append(emitted, viewRow{Key: normalize(key), Value: normalize(value)})
+		})
+		if err != nil {
+			return nil, err
+		}
+		return func(doc map[string]interface{}) ([]viewRow, error) {
+			emitted = nil
+			if err := fn(doc); err != nil {
+				return nil, nil
+			}
+			return emitted, nil
+		}, nil
+	}
+}
+
+// update brings the index up to date, and returns the sorted rows. version
+// identifies the index definition; when it changes, the index is rebuilt from
+// scratch. compile is only called if any documents need to be mapped.
+func (v *viewIndex) update(db *database, version string, compile func() (mapFunc, error)) ([]viewRow, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.version != version {
+		v.version = version
+		v.seq = 0
+		v.rows = nil
+	}
+	changed, seq := db.changedSince(v.seq)
+	if len(changed) == 0 {
+		v.seq = seq
+		return v.rows, nil
+	}
+	fn, err := compile()
+	if err != nil {
+		return nil, err
+	}
+	var added []viewRow
+	for docID, rev := range changed {
+		var emitted []viewRow
+		if !rev.Deleted {
+			var doc map[string]interface{}
+			if err := json.Unmarshal(rev.data, &doc); err != nil {
+				return nil, err
+			}
+			if emitted, err = fn(doc); err != nil {
+				return nil, err
+			}
+		}
+		for i := range emitted {
+			emitted[i].ID = docID
+		}
+		added = append(added, emitted...)
+	}
+	sort.SliceStable(added, func(i, j int) bool {
+		return compareRows(added[i], added[j]) < 0
+	})
+	v.rows = mergeRows(v.rows, changed, added)
+	v.seq = seq
+	return v.rows, nil
+}
+
+// mergeRows returns a new sorted index, in which the rows of the changed
+// documents are replaced with added, which must already be sorted.
+func mergeRows(rows []viewRow, changed map[string]*revision, added []viewRow) []viewRow {
+	merged := make([]viewRow, 0, len(rows)+len(added))
+	for _, row := range rows {
+		if _, ok := changed[row.ID]; ok {
+			continue
+		}
+		for len(added) > 0 && compareRows(added[0], row) < 0 {
+			merged = append(merged, added[0])
+			added = added[1:]
+		}
+		merged = append(merged, row)
+	}
+	return append(merged, added...)
+}
+
+// normalize converts v to the types produced by decoding JSON, so that keys
+// and values compare and reduce the same way they would after a round trip
+// through CouchDB.
+func normalize(v interface{}) interface{} {
+	if raw, ok := v.(json.RawMessage); ok {
+		var out interface{}
+		_ = json.Unmarshal(raw, &out)
+		return out
+	}
+	buf, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var out interface{}
+	_ = json.Unmarshal(buf, &out)
+	return out
+}
+
+func compareRows(a, b viewRow) int {
+	if c := collate.CompareObject(a.Key, b.Key); c != 0 {
+		return c
+	}
+	return collate.CompareString(a.ID, b.ID)
+}
+
+// viewOptions are the parsed query parameters for a view query.
+type viewOptions struct {
+	keys          []interface{}
+	startKey      interface{}
+	endKey        interface{}
+	startKeyDocID string
+	endKeyDocID   string
+	hasStartKey   bool
+	hasEndKey     bool
+	inclusiveEnd  bool
+	descending    bool
+	includeDocs   bool
+	updateSeq     bool
+	reduce        *bool
+	group         bool
+	groupLevel    int64
+	limit         int64
+	skip          int64
+}
+
+func badRequest(format string, args ...interface{}) error {
+	return statusError{status: http.StatusBadRequest, error: fmt.Errorf(format, args...)}
+}
+
+func optionBool(opts map[string]interface{}, name string, def bool) (bool, error) {
+	value, ok := opts[name]
+	if !ok {
+		return def, nil
+	}
+	switch t := value.(type) {
+	case bool:
+		return t, nil
+	case string:
+		if b, err := strconv.ParseBool(t); err == nil {
+			return b, nil
+		}
+	}
+	return false, badRequest("invalid value for '%s': %v", name, value)
+}
+
+func optionInt(opts map[string]interface{}, name string, def int64) (int64, error) {
+	value, ok := opts[name]
+	if !ok {
+		return def, nil
+	}
+	var i int64
+	switch t := value.(type) {
+	case int:
+		i = int64(t)
+	case int64:
+		i = t
+	case float64:
+		i = int64(t)
+	case string:
+		var err error
+		if i, err = strconv.ParseInt(t, 10, 64); err != nil {
+			return 0, badRequest("invalid value for '%s': %v", name, value)
+		}
+	default:
+		return 0, badRequest("invalid value for '%s': %v", name, value)
+	}
+	if i < 0 {
+		return 0, badRequest("invalid value for '%s': %v", name, value)
+	}
+	return i, nil
+}
+
+// optionKey returns the first of names found in opts, normalized.
+func optionKey(opts map[string]interface{}, names ...string) (interface{}, bool) {
+	for _, name := range names {
+		if value, ok := opts[name]; ok {
+			return normalize(value), true
+		}
+	}
+	return nil, false
+}
+
+func optionString(opts map[string]interface{}, names ...string) (string, error) {
+	for _, name := range names {
+		if value, ok := opts[name]; ok {
+			s, ok := value.(string)
+			if !ok {
+				return "", badRequest("invalid value for '%s': %v", name, value)
+			}
+			return s, nil
+		}
+	}
+	return "", nil
+}
+
+func parseViewOptions(options driver.Options) (*viewOptions, error) {
+	opts := map[string]interface{}{}
+	options.Apply(opts)
+	var (
+		v   viewOptions
+		err error
+	)
+	if key, ok := optionKey(opts, "key"); ok {
+		v.keys = []interface{}{key}
+	}
+	if keys, ok := optionKey(opts, "keys"); ok {
+		list, ok := keys.([]interface{})
+		if !ok {
+			return nil, badRequest("`keys` member must be an array")
+		}
+		v.keys = list
+	}
+	v.startKey, v.hasStartKey = optionKey(opts, "startkey", "start_key")
+	v.endKey, v.hasEndKey = optionKey(opts, "endkey", "end_key")
+	if v.startKeyDocID, err = optionString(opts, "startkey_docid", "start_key_doc_id"); err != nil {
+		return nil, err
+	}
+	if v.endKeyDocID, err = optionString(opts, "endkey_docid", "end_key_doc_id"); err != nil {
+		return nil, err
+	}
+	if v.inclusiveEnd, err = optionBool(opts, "inclusive_end", true); err != nil {
+		return nil, err
+	}
+	if v.descending, err = optionBool(opts, "descending", false); err != nil {
+		return nil, err
+	}
+	if v.includeDocs, err = optionBool(opts, "include_docs", false); err != nil {
+		return nil, err
+	}
+	if v.updateSeq, err = optionBool(opts, "update_seq", false); err != nil {
+		return nil, err
+	}
+	if _, ok := opts["reduce"]; ok {
+		r, err := optionBool(opts, "reduce", true)
+		if err != nil {
+			return nil, err
+		}
+		v.reduce = &r
+	}
+	if v.group, err = optionBool(opts, "group", false); err != nil {
+		return nil, err
+	}
+	if v.groupLevel, err = optionInt(opts, "group_level", 0); err != nil {
+		return nil, err
+	}
+	if v.limit, err = optionInt(opts, "limit", -1); err != nil {
+		return nil, err
+	}
+	if v.skip, err = optionInt(opts, "skip", 0); err != nil {
+		return nil, err
+	}
+	if v.hasStartKey && v.hasEndKey {
+		c := collate.CompareObject(v.startKey, v.endKey)
+		if (c > 0 && !v.descending) || (c < 0 && v.descending) {
+			return nil, badRequest("No rows can match your key range, reverse your start_key and end_key or set descending=%t", !v.descending)
+		}
+	}
+	return &v, nil
+}
+
+// reduceGroupLevel returns the group level as understood by
+// [github.com/go-kivik/kivik/v4/x/reduce.Reduce].
+func (v *viewOptions) reduceGroupLevel() int {
+	switch {
+	case v.groupLevel > 0:
+		return int(v.groupLevel)
+	case v.group:
+		return -1
+	}
+	return 0
+}
+
+// filter returns the rows which fall within the requested key range, or match
+// the requested keys, in the requested order. The second return value is the
+// number of rows preceding the range.
+func (v *viewOptions) filter(rows []viewRow) ([]viewRow, int64) {
+	if v.descending {
+		reversed := make([]viewRow, len(rows))
+		for i, row := range rows {
+			reversed[len(rows)-1-i] = row
+		}
+		rows = reversed
+	}
+	if v.keys != nil {
+		var out []viewRow
+		for _, key := range v.keys {
+			for _, row := range rows {
+				if collate.CompareObject(key, row.Key) == 0 {
+					out = append(out, row)
+				}
+			}
+		}
+		return out, 0
+	}
+	dir := 1
+	if v.descending {
+		dir = -1
+	}
+	compare := func(row viewRow, key interface{}, docID string) int {
+		c := collate.CompareObject(row.Key, key)
+		if c == 0 && docID != "" {
+			c = collate.CompareString(row.ID, docID)
+		}
+		return c * dir
+	}
+	var (
+		out    = make([]viewRow, 0, len(rows))
+		offset int64
+	)
+	for _, row := range rows {
+		if v.hasStartKey && compare(row, v.startKey, v.startKeyDocID) < 0 {
+			offset++
+			continue
+		}
+		if v.hasEndKey {
+			c := compare(row, v.endKey, v.endKeyDocID)
+			if c > 0 || (c == 0 && !v.inclusiveEnd) {
+				break
+			}
+		}
+		out = append(out, row)
+	}
+	return out, offset
+}
+
+func (d *db) Query(ctx context.Context, ddoc, view string, options driver.Options) (driver.Rows, error) {
+	if exists, _ := d.client.DBExists(ctx, d.dbName, nil); !exists {
+		return nil, statusError{status: http.StatusNotFound, error: errors.New("database does not exist")}
+	}
+	ddocID := "_design/" + strings.TrimPrefix(ddoc, "_design/")
+	rev, ok := d.db.latestRevision(ddocID)
+	if !ok || rev.Deleted {
+		return nil, statusError{status: http.StatusNotFound, error: errors.New("missing")}
+	}
+	var design designDoc
+	if err := json.Unmarshal(rev.data, &design); err != nil {
+		return nil, err
+	}
+	if design.Language != "" && design.Language != "javascript" {
+		return nil, statusError{status: http.StatusNotImplemented, error: fmt.Errorf("unsupported language: %s", design.Language)}
+	}
+	var funcs viewFuncs
+	if raw, ok := design.Views[view]; ok {
+		if err := json.Unmarshal(raw, &funcs); err != nil {
+			return nil, err
+		}
+	}
+	if funcs.Map == "" {
+		return nil, statusError{status: http.StatusNotFound, error: errors.New("missing_named_view")}
+	}
+	opts, err := parseViewOptions(options)
+	if err != nil {
+		return nil, err
+	}
+	useReduce := funcs.Reduce != "" && (opts.reduce == nil || *opts.reduce)
+	switch {
+	case funcs.Reduce == "" && opts.reduce != nil && *opts.reduce:
+		return nil, badRequest("reduce is invalid for map-only views")
+	case !useReduce && (opts.group || opts.groupLevel > 0):
+		return nil, badRequest("Invalid use of grouping on a map view.")
+	case useReduce && opts.includeDocs:
+		return nil, badRequest("include_docs is invalid for reduce")
+	case useReduce && len(opts.keys) > 1 && opts.reduceGroupLevel() == 0:
+		return nil, badRequest("Multi-key fetches for reduce views must use `group=true`")
+	}
+
+	var updateSeq string
+	if opts.updateSeq {
+		updateSeq = strconv.FormatInt(d.db.updateSeq(), 10)
+	}
+	all, err := d.db.viewIndex(ddocID+"/"+view).update(d.db, fmt.Sprintf("%d-%s", rev.ID, rev.Rev), jsMapFunc(funcs.Map))
+	if err != nil {
+		return nil, err
+	}
+	rows, offset := opts.filter(all)
+
+	if useReduce {
+		input := make(reduce.Rows, len(rows))
+		for i, row := range rows {
+			input[i] = reduce.Row{ID: row.ID, FirstKey: row.Key, FirstPK: i + 1, Value: row.Value}
+		}
+		results, err := reduce.Reduce(&input, funcs.Reduce, log.New(io.Discard, "", 0), opts.reduceGroupLevel())
+		if err != nil {
+			return nil, err
+		}
+		out := *results
+		start, end := opts.page(len(out))
+		out = out[start:end]
+		return &out, nil
+	}
+
+	start, end := opts.page(len(rows))
+	return &viewResults{
+		db:        d.db,
+		rows:      rows[start:end],
+		docs:      opts.includeDocs,
+		offset:    offset + opts.skip,
+		totalRows: int64(len(all)),
+		updateSeq: updateSeq,
+	}, nil
+}
+
+// page returns the bounds of the rows selected by skip and limit, out of
+// count rows. A negative limit means no limit.
+func (v *viewOptions) page(count int) (start, end int) {
+	total := int64(count)
+	if v.skip >= total {
+		return count, count
+	}
+	last := total
+	if v.limit >= 0 && v.skip+v.limit < total {
+		last = v.skip + v.limit
+	}
+	return int(v.skip), int(last)
+}
+
+type viewResults struct {
+	db                *database
+	rows              []viewRow
+	docs              bool
+	offset, totalRows int64
+	updateSeq         string
+}
+
+var _ driver.Rows = &viewResults{}
+
+func (r *viewResults) Next(row *driver.Row) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	var next viewRow
+	next, r.rows = r.rows[0], r.rows[1:]
+	row.ID = next.ID
+	row.Key, _ = json.Marshal(next.Key)
+	value, _ := json.Marshal(next.Value)
+	row.Value = bytes.NewReader(value)
+	if r.docs {
+		row.Doc = bytes.NewReader(r.includeDoc(next))
+	}
+	return nil
+}
+
+// includeDoc returns the document for row. As with CouchDB, if the emitted
+// value is an object with an _id field, the referenced document is returned
+// instead.
+func (r *viewResults) includeDoc(row viewRow) []byte {
+	docID := row.ID
+	var rev string
+	if value, ok := row.Value.(map[string]interface{}); ok {
+		if id, ok := value["_id"].(string); ok {
+			docID = id
+			rev, _ = value["_rev"].(string)
+		}
+	}
+	var (
+		doc   *revision
+		found bool
+	)
+	if rev != "" {
+		doc, found = r.db.getRevision(docID, rev)
+	} else {
+		doc, found = r.db.latestRevision(docID)
+	}
+	if !found || doc.Deleted {
+		return []byte("null")
+	}
+	return doc.data
+}
+
+func (r *viewResults) Close() error {
+	r.rows = nil
+	return nil
+}
+
+func (r *viewResults) UpdateSeq() string { return r.updateSeq }
+func (r *viewResults) TotalRows() int64  { return r.totalRows }
+func (r *viewResults) Offset() int64     { return r.offset }
@@ -0,0 +1,382 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package memorydb
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"gitlab.com/flimzy/testy"
+
+	"github.com/go-kivik/kivik/v4"
+	"github.com/go-kivik/kivik/v4/driver"
+	internal "github.com/go-kivik/kivik/v4/int/errors"
+)
+
+type queryRow struct {
+	ID    string          `json:"id,omitempty"`
+	Key   json.RawMessage `json:"key"`
+	Value json.RawMessage `json:"value"`
+	Doc   json.RawMessage `json:"doc,omitempty"`
+}
+
+func readRows(t *testing.T, rows driver.Rows) []queryRow {
+	t.Helper()
+	var result []queryRow
+	for {
+		var row driver.Row
+		if err := rows.Next(&row); err != nil {
+			if err == io.EOF {
+				break
+			}
+			t.Fatal(err)
+		}
+		r := queryRow{ID: row.ID, Key: row.Key}
+		r.Value, _ = io.ReadAll(row.Value)
+		if row.Doc != nil {
+			doc, _ := io.ReadAll(row.Doc)
+			var d map[string]interface{}
+			if err := json.Unmarshal(doc, &d); err != nil {
+				t.Fatal(err)
+			}
+			delete(d, "_rev")
+			r.Doc, _ = json.Marshal(d)
+		}
+		result = append(result, r)
+	}
+	return result
+}
+
+func setupViewDB(t *testing.T) *db {
+	t.Helper()
+	d := setupDB(t)
+	ddoc := map[string]interface{}{
+		"views": map[string]interface{}{
+			"byAge": map[string]string{
+				"map": `function(doc) { if (doc.age) { emit(doc.age, doc.name); } }`,
+			},
+			"count": map[string]string{
+				"map":    `function(doc) { emit([doc.type, doc.age], 1); }`,
+				"reduce": "_count",
+			},
+			"stats": map[string]string{
+				"map":    `function(doc) { emit(doc.type, doc.age); }`,
+				"reduce": "_stats",
+			},
+			"sum": map[string]string{
+				"map":    `function(doc) { emit(doc.type, doc.age); }`,
+				"reduce": `function(keys, values, rereduce) { return values.reduce(function(a, b) { return a + b; }, 0); }`,
+			},
+			"notFunction": map[string]string{
+				"map": `"emit"`,
+			},
+		},
+	}
+	if _, err := d.Put(context.Background(), "_design/foo", ddoc, nil); err != nil {
+		t.Fatal(err)
+	}
+	docs := []map[string]interface{}{
+		{"_id": "alice", "name": "Alice", "age": 30, "type": "human"},
+		{"_id": "bob", "name": "Bob", "age": 25, "type": "human"},
+		{"_id": "rex", "name": "Rex", "age": 5, "type": "dog"},
+		{"_id": "carol", "name": "Carol", "age": 40, "type": "human"},
+	}
+	for _, doc := range docs {
+		if _, err := d.Put(context.Background(), doc["_id"].(string), doc, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return d
+}
+
+func TestQuery(t *testing.T) {
+	d := setupViewDB(t)
+
+	tests := []struct {
+		name      string
+		ddoc      string
+		view      string
+		options   driver.Options
+		want      string
+		offset    int64
+		totalRows int64
+		status    int
+		err       string
+	}{
+		{
+			name:      "map only",
+			ddoc:      "foo",
+			view:      "byAge",
+			options:   kivik.Params(nil),
+			want:      `[{"id":"rex","key":5,"value":"Rex"},{"id":"bob","key":25,"value":"Bob"},{"id":"alice","key":30,"value":"Alice"},{"id":"carol","key":40,"value":"Carol"}]`,
+			totalRows: 4,
+		},
+		{
+			name:      "ddoc prefix",
+			ddoc:      "_design/foo",
+			view:      "byAge",
+			options:   kivik.Param("key", 25),
+			want:      `[{"id":"bob","key":25,"value":"Bob"}]`,
+			totalRows: 4,
+		},
+		{
+			name: "range",
+			ddoc: "foo",
+			view: "byAge",
+			options: kivik.Params(map[string]interface{}{
+				"startkey":      25,
+				"endkey":        40,
+				"inclusive_end": false,
+			}),
+			want:      `[{"id":"bob","key":25,"value":"Bob"},{"id":"alice","key":30,"value":"Alice"}]`,
+			offset:    1,
+			totalRows: 4,
+		},
+		{
+			name: "descending with skip and limit",
+			ddoc: "foo",
+			view: "byAge",
+			options: kivik.Params(map[string]interface{}{
+				"descending": true,
+				"skip":       1,
+				"limit":      2,
+			}),
+			want:      `[{"id":"alice","key":30,"value":"Alice"},{"id":"bob","key":25,"value":"Bob"}]`,
+			offset:    1,
+			totalRows: 4,
+		},
+		{
+			name:      "keys",
+			ddoc:      "foo",
+			view:      "byAge",
+			options:   kivik.Param("keys", []int{40, 5, 99}),
+			want:      `[{"id":"carol","key":40,"value":"Carol"},{"id":"rex","key":5,"value":"Rex"}]`,
+			totalRows: 4,
+		},
+		{
+			name:      "include docs",
+			ddoc:      "foo",
+			view:      "byAge",
+			options:   kivik.Params(map[string]interface{}{"key": 5, "include_docs": true}),
+			want:      `[{"id":"rex","key":5,"value":"Rex","doc":{"_id":"rex","age":5,"name":"Rex","type":"dog"}}]`,
+			totalRows: 4,
+		},
+		{
+			name:    "reduce",
+			ddoc:    "foo",
+			view:    "count",
+			options: kivik.Params(nil),
+			want:    `[{"key":null,"value":4}]`,
+		},
+		{
+			name:    "group level",
+			ddoc:    "foo",
+			view:    "count",
+			options: kivik.Param("group_level", 1),
+			want:    `[{"key":["dog"],"value":1},{"key":["human"],"value":3}]`,
+		},
+		{
+			name:    "stats",
+			ddoc:    "foo",
+			view:    "stats",
+			options: kivik.Param("key", "human"),
+			want:    `[{"key":null,"value":{"sum":95,"min":25,"max":40,"count":3,"sumsqr":3125}}]`,
+		},
+		{
+			name:    "javascript reduce",
+			ddoc:    "foo",
+			view:    "sum",
+			options: kivik.Param("group", true),
+			want:    `[{"key":"dog","value":5},{"key":"human","value":95}]`,
+		},
+		{
+			name:      "reduce=false",
+			ddoc:      "foo",
+			view:      "count",
+			options:   kivik.Params(map[string]interface{}{"reduce": false, "limit": 1}),
+			want:      `[{"id":"rex","key":["dog",5],"value":1}]`,
+			totalRows: 4,
+		},
+		{
+			name:    "missing ddoc",
+			ddoc:    "bar",
+			view:    "byAge",
+			options: kivik.Params(nil),
+			status:  http.StatusNotFound,
+			err:     "missing",
+		},
+		{
+			name:    "missing view",
+			ddoc:    "foo",
+			view:    "bar",
+			options: kivik.Params(nil),
+			status:  http.StatusNotFound,
+			err:     "missing_named_view",
+		},
+		{
+			name:    "reduce on map-only view",
+			ddoc:    "foo",
+			view:    "byAge",
+			options: kivik.Param("reduce", true),
+			status:  http.StatusBadRequest,
+			err:     "reduce is invalid for map-only views",
+		},
+		{
+			name:    "include docs with reduce",
+			ddoc:    "foo",
+			view:    "count",
+			options: kivik.Param("include_docs", true),
+			status:  http.StatusBadRequest,
+			err:     "include_docs is invalid for reduce",
+		},
+		{
+			name:    "map is not a function",
+			ddoc:    "foo",
+			view:    "notFunction",
+			options: kivik.Params(nil),
+			status:  http.StatusInternalServerError,
+			err:     "compilation_error: map is not a function",
+		},
+		{
+			name:    "reversed range",
+			ddoc:    "foo",
+			view:    "byAge",
+			options: kivik.Params(map[string]interface{}{"startkey": 40, "endkey": 5}),
+			status:  http.StatusBadRequest,
+			err:     "No rows can match your key range, reverse your start_key and end_key or set descending=true",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := d.Query(context.Background(), tt.ddoc, tt.view, tt.options)
+			if d := internal.StatusErrorDiff(tt.err, tt.status, err); d != "" {
+				t.Error(d)
+			}
+			if err != nil {
+				return
+			}
+			if d := testy.DiffAsJSON([]byte(tt.want), readRows(t, rows)); d != nil {
+				t.Error(d)
+			}
+			if rows.Offset() != tt.offset {
+				t.Errorf("Unexpected offset: %d", rows.Offset())
+			}
+			if rows.TotalRows() != tt.totalRows {
+				t.Errorf("Unexpected total rows: %d", rows.TotalRows())
+			}
+		})
+	}
+}
+
+func TestQueryIncremental(t *testing.T) {
+	d := setupViewDB(t)
+	query := func(t *testing.T, want string) {
+		t.Helper()
+		rows, err := d.Query(context.Background(), "foo", "byAge", kivik.Params(nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d := testy.DiffAsJSON([]byte(want), readRows(t, rows)); d != nil {
+			t.Error(d)
+		}
+	}
+	query(t, `[{"id":"rex","key":5,"value":"Rex"},{"id":"bob","key":25,"value":"Bob"},{"id":"alice","key":30,"value":"Alice"},{"id":"carol","key":40,"value":"Carol"}]`)
+
+	rev, _ := d.db.latestRevision("bob")
+	if _, err := d.Put(context.Background(), "bob", map[string]interface{}{
+		"_rev": "1-" + rev.Rev,
+		"name": "Robert",
+		"age":  50,
+	}, nil); err != nil {
+		t.Fatal(err)
+	}
+	rev, _ = d.db.latestRevision("rex")
+	if _, err := d.Delete(context.Background(), "rex", kivik.Rev("1-"+rev.Rev)); err != nil {
+		t.Fatal(err)
+	}
+	query(t, `[{"id":"alice","key":30,"value":"Alice"},{"id":"carol","key":40,"value":"Carol"},{"id":"bob","key":50,"value":"Robert"}]`)
+
+	rev, _ = d.db.latestRevision("_design/foo")
+	if _, err := d.Put(context.Background(), "_design/foo", map[string]interface{}{
+		"_rev": "1-" + rev.Rev,
+		"views": map[string]interface{}{
+			"byAge": map[string]string{"map": `function(doc) { emit(doc.name, null); }`},
+		},
+	}, nil); err != nil {
+		t.Fatal(err)
+	}
+	query(t, `[{"id":"alice","key":"Alice","value":null},{"id":"carol","key":"Carol","value":null},{"id":"bob","key":"Robert","value":null}]`)
+}
+
+func TestViewIndexUpdate(t *testing.T) {
+	d := setupViewDB(t)
+	var mapped []string
+	compile := func() (mapFunc, error) {
+		return func(doc map[string]interface{}) ([]viewRow, error) {
+			mapped = append(mapped, doc["_id"].(string))
+			return []viewRow{{Key: doc["name"]}}, nil
+		}, nil
+	}
+	idx := &viewIndex{}
+	keys := func(rows []viewRow) []interface{} {
+		keys := make([]interface{}, len(rows))
+		for i, row := range rows {
+			keys[i] = row.Key
+		}
+		return keys
+	}
+
+	rows, err := idx.update(d.db, "1", compile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := testy.DiffInterface([]interface{}{"Alice", "Bob", "Carol", "Rex"}, keys(rows)); d != nil {
+		t.Error(d)
+	}
+	if len(mapped) != 4 { // nolint:gomnd
+		t.Errorf("Expected 4 documents to be mapped, got %v", mapped)
+	}
+
+	mapped = nil
+	rev, _ := d.db.latestRevision("bob")
+	if _, err := d.Put(context.Background(), "bob", map[string]interface{}{
+		"_rev": "1-" + rev.Rev,
+		"name": "Zed",
+	}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.Put(context.Background(), "_local/foo", map[string]interface{}{}, nil); err != nil {
+		t.Fatal(err)
+	}
+	rows, err = idx.update(d.db, "1", compile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := testy.DiffInterface([]interface{}{"Alice", "Carol", "Rex", "Zed"}, keys(rows)); d != nil {
+		t.Error(d)
+	}
+	if d := testy.DiffInterface([]string{"bob"}, mapped); d != nil {
+		t.Errorf("Unexpected documents mapped:\n%s", d)
+	}
+
+	mapped = nil
+	if _, err := idx.update(d.db, "1", compile); err != nil {
+		t.Fatal(err)
+	}
+	if len(mapped) != 0 {
+		t.Errorf("Expected no documents to be mapped, got %v", mapped)
+	}
+}
@@ -17,13 +17,13 @@ import (
 	"fmt"
 	"log"
 	"net/http"
-	"slices"
+	"sort"
 	"strings"
 
 	"github.com/mitchellh/mapstructure"
 
 	internal "github.com/go-kivik/kivik/v4/int/errors"
-	"github.com/go-kivik/kivik/x/sqlite/v4/js"
+	"github.com/go-kivik/kivik/v4/x/js"
 )
 
 // Count is the built-in reduce function, [_count].
@@ -145,8 +145,8 @@ func Stats(_ [][2]interface{}, values []interface{}, rereduce bool) ([]interface
 			result.Count += value.Count
 			result.SumSqr += value.SumSqr
 		}
-		result.Min = slices.Min(mins)
-		result.Max = slices.Max(maxs)
+		result.Min = minOf(mins)
+		result.Max = maxOf(maxs)
 		return []interface{}{result}, nil
 	}
 	result.Count = float64(len(values))
@@ -171,7 +171,7 @@ func Stats(_ [][2]interface{}, values []interface{}, rereduce bool) ([]interface
 			if err := mapstructure.DecodeMetadata(v, &mapStats, &metadata); err == nil {
 				if len(metadata.Unset) > 0 {
 					raw, _ := json.Marshal(v)
-					slices.Sort(metadata.Unset)
+					sort.Strings(metadata.Unset)
 					return nil, &internal.Error{
 						Status:  http.StatusInternalServerError,
 						Message: fmt.Sprintf("user _stats input missing required field %s (%s)", strings.ToLower(metadata.Unset[0]), string(raw)),
@@ -194,8 +194,8 @@ func Stats(_ [][2]interface{}, values []interface{}, rereduce bool) ([]interface
 			Message: fmt.Sprintf("the _stats function requires that map values be numbers or arrays of numbers, not '%s'", string(valBytes)),
 		}
 	}
-	result.Min = slices.Min(slices.Concat(nvals, mins))
-	result.Max = slices.Max(slices.Concat(nvals, maxs))
+	result.Min = minOf(append(nvals, mins...))
+	result.Max = maxOf(append(nvals, maxs...))
 	return []interface{}{result}, nil
 }
 
@@ -212,8 +212,8 @@ func reduceStatsFloatArray(values [][]float64) []interface{} {
 	}
 	for i, mm := range minmax {
 		results[i].Count += float64(len(values))
-		results[i].Min = slices.Min(mm)
-		results[i].Max = slices.Max(mm)
+		results[i].Min = minOf(mm)
+		results[i].Max = maxOf(mm)
 	}
 
 	return []interface{}{results}
@@ -233,8 +233,8 @@ func rereduceStatsFloatArray(values [][]stats) []interface{} {
 		}
 	}
 	for i, mm := range mins {
-		result[i].Min = slices.Min(mm)
-		result[i].Max = slices.Max(maxs[i])
+		result[i].Min = minOf(mm)
+		result[i].Max = maxOf(maxs[i])
 	}
 
 	return []interface{}{result}
@@ -273,3 +273,25 @@ func ParseFunc(javascript string, logger *log.Logger) (Func, error) {
 		}, nil
 	}
 }
+
+// minOf returns the smallest value in values, which must not be empty.
+func minOf(values []float64) float64 {
+	result := values[0]
+	for _, v := range values[1:] {
+		if v < result {
+			result = v
+		}
+	}
+	return result
+}
+
+// maxOf returns the largest value in values, which must not be empty.
+func maxOf(values []float64) float64 {
+	result := values[0]
+	for _, v := range values[1:] {
+		if v > result {
+			result = v
+		}
+	}
+	return result
+}
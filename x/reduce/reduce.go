@@ -40,22 +40,22 @@ type Row struct {
 
 	// TargetKey is the key of a map row, or the key of a reduced row. It is
 	// used in output for grouping.
-	TargetKey any
+	TargetKey interface{}
 
 	// FirstKey represents the key of a map row, or the first key of a reduced
 	// row. It is used for grouping and caching.
-	FirstKey any
+	FirstKey interface{}
 	// FirstPK disambiguates multiple identical keys.
 	FirstPK int
 
 	// LastKey is the last key of a reduced row. It is only populated for
 	// reduced rows. LastKey and LastPK may be omitted for map rows. If omitted,
 	// Key and PK are used.
-	LastKey any
+	LastKey interface{}
 	// LastPK disambiguates multiple identical keys.
 	LastPK int
 
-	Value any
+	Value interface{}
 }
 
 // Rows is a slice of Row, and implements RowIterator.
@@ -138,11 +138,11 @@ func reduceWithBatchSize(rows Reducer, javascript string, logger *log.Logger, gr
 func reduce(rows Reducer, fn Func, groupLevel int, batchSize int) (*Rows, error) {
 	out := make(Rows, 0, 1)
 	var (
-		firstKey, lastKey any
+		firstKey, lastKey interface{}
 		firstPK, lastPK   int
 	)
 
-	callReduce := func(keys [][2]interface{}, values []interface{}, rereduce bool, key any) error {
+	callReduce := func(keys [][2]interface{}, values []interface{}, rereduce bool, key interface{}) error {
 		if len(keys) == 0 {
 			return nil
 		}
@@ -188,7 +188,7 @@ func reduce(rows Reducer, fn Func, groupLevel int, batchSize int) (*Rows, error)
 	const defaultCap = 10
 	keys := make([][2]interface{}, 0, defaultCap)
 	values := make([]interface{}, 0, defaultCap)
-	var targetKey any
+	var targetKey interface{}
 	var rereduce bool
 	for {
 		var row Row
@@ -252,22 +252,22 @@ func reduce(rows Reducer, fn Func, groupLevel int, batchSize int) (*Rows, error)
 	return &out, nil
 }
 
-func keyLen(key any) int {
+func keyLen(key interface{}) int {
 	if key == nil {
 		return 0
 	}
-	if k, ok := key.([]any); ok {
+	if k, ok := key.([]interface{}); ok {
 		return len(k)
 	}
 	return 1
 }
 
 // truncateKey truncates the key to the given level.
-func truncateKey(key any, level int) any {
+func truncateKey(key interface{}, level int) interface{} {
 	if level == 0 {
 		return nil
 	}
-	target, ok := key.([]any)
+	target, ok := key.([]interface{})
 	if !ok {
 		return key
 	}
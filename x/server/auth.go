@@ -24,6 +24,7 @@ import (
 	"github.com/go-kivik/kivik/v4"
 	internal "github.com/go-kivik/kivik/v4/int/errors"
 	"github.com/go-kivik/kivik/v4/x/server/auth"
+	"github.com/go-kivik/kivik/v4/x/server/config"
 )
 
 type contextKey struct{ name string }
@@ -46,6 +47,11 @@ func (s *authService) UserStore() auth.UserStore {
 	return s.s.userStores
 }
 
+// Config returns the server configuration.
+func (s *authService) Config() config.Config {
+	return s.s.config
+}
+
 func (s *authService) Bind(r *http.Request, v interface{}) error {
 	return s.s.bind(r, v)
 }
@@ -65,6 +71,20 @@ func (w *doneWriter) Write(b []byte) (int, error) {
 	return w.ResponseWriter.Write(b)
 }
 
+// session returns the user context of the current session.
+func (s *Server) session() httpe.HandlerWithError {
+	return httpe.HandlerWithErrorFunc(func(w http.ResponseWriter, r *http.Request) error {
+		userCtx := userFromContext(r.Context())
+		if userCtx == nil {
+			userCtx = &auth.UserContext{Roles: []string{}}
+		}
+		return serveJSON(w, http.StatusOK, map[string]interface{}{
+			"ok":      true,
+			"userCtx": userCtx,
+		})
+	})
+}
+
 // authMiddleware sets the user context based on the authenticated user, if any.
 func (s *Server) authMiddleware(next httpe.HandlerWithError) httpe.HandlerWithError {
 	return httpe.HandlerWithErrorFunc(func(w http.ResponseWriter, r *http.Request) error {
@@ -15,6 +15,8 @@ package auth
 
 import (
 	"net/http"
+
+	"github.com/go-kivik/kivik/v4/x/server/config"
 )
 
 // CouchDB system roles.
@@ -55,6 +57,8 @@ func (c *UserContext) HasRole(role string) bool {
 type Server interface {
 	UserStore() UserStore
 	Bind(*http.Request, interface{}) error
+	// Config returns the server configuration.
+	Config() config.Config
 }
 
 // AuthenticateFunc authenticates the HTTP request. On success, a user context
@@ -0,0 +1,341 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-kivik/kivik/v4"
+	internal "github.com/go-kivik/kivik/v4/int/errors"
+)
+
+type jwtAuth struct {
+	s   Server
+	now func() time.Time
+}
+
+// JWTAuth returns an auth handler which authenticates requests bearing a
+// [JSON Web Token] in the Authorization header, as CouchDB does. Tokens are
+// verified against the keys in the [jwt_keys] configuration section, which
+// are named after the key type and the token's "kid" header, or "_default":
+//
+//	[jwt_keys]
+//	hmac:_default = base64-encoded secret
+//	rsa:foo = -----BEGIN PUBLIC KEY-----\n...\n-----END PUBLIC KEY-----\n
+//	ec:bar = -----BEGIN PUBLIC KEY-----\n...\n-----END PUBLIC KEY-----\n
+//
+// The user name is read from the "sub" claim, and roles from the top-level
+// "_couchdb.roles" claim, or, if jwt_auth/roles_claim_path is set, from the
+// claim at that dot-separated path, such as "realm.roles". Claims listed in
+// jwt_auth/required_claims must be present, and if given in the form
+// {claim, "value"}, must have the specified value.
+//
+// [JSON Web Token]: https://docs.couchdb.org/en/stable/api/server/authn.html#jwt-authentication
+// [jwt_keys]: https://docs.couchdb.org/en/stable/config/auth.html#jwt_keys
+func JWTAuth() Handler {
+	return &jwtAuth{now: time.Now}
+}
+
+func (a *jwtAuth) Init(s Server) (string, AuthenticateFunc) {
+	a.s = s
+	return "jwt", // For compatibility with the name used by CouchDB
+		a.Authenticate
+}
+
+func errJWT(format string, args ...interface{}) error {
+	return &internal.Error{Status: http.StatusUnauthorized, Message: fmt.Sprintf(format, args...)}
+}
+
+func (a *jwtAuth) Authenticate(_ http.ResponseWriter, r *http.Request) (*UserContext, error) {
+	const prefix = "Bearer "
+	header := r.Header.Get("Authorization")
+	if !strings.HasPrefix(header, prefix) {
+		return nil, nil
+	}
+	claims, err := a.verify(r.Context(), strings.TrimPrefix(header, prefix))
+	if err != nil {
+		return nil, err
+	}
+	if err := a.checkClaims(r.Context(), claims); err != nil {
+		return nil, err
+	}
+	name, _ := claims["sub"].(string)
+	if name == "" {
+		return nil, errJWT("Token missing sub claim")
+	}
+	roles, err := a.roles(r.Context(), claims)
+	if err != nil {
+		return nil, err
+	}
+	return &UserContext{
+		Name:  name,
+		Roles: roles,
+	}, nil
+}
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// verify checks the token's signature, and returns its claims.
+func (a *jwtAuth) verify(ctx context.Context, token string) (map[string]interface{}, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errJWT("Malformed token")
+	}
+	var header jwtHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, errJWT("Malformed token header")
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, errJWT("Malformed token signature")
+	}
+	kty, hash, err := jwtAlg(header.Alg)
+	if err != nil {
+		return nil, err
+	}
+	kid := header.Kid
+	if kid == "" {
+		kid = "_default"
+	}
+	key, err := a.s.Config().Key(ctx, "jwt_keys", kty+":"+kid)
+	if err != nil {
+		return nil, errJWT("Unknown kid: %s", kid)
+	}
+	if err := verifySignature(kty, hash, key, []byte(parts[0]+"."+parts[1]), sig); err != nil {
+		return nil, err
+	}
+	var claims map[string]interface{}
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, errJWT("Malformed token payload")
+	}
+	return claims, nil
+}
+
+func decodeSegment(seg string, target interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, target)
+}
+
+// jwtAlg returns the key type and hash function for the named algorithm.
+func jwtAlg(alg string) (string, crypto.Hash, error) {
+	if len(alg) == 5 {
+		var hash crypto.Hash
+		switch alg[2:] {
+		case "256":
+			hash = crypto.SHA256
+		case "384":
+			hash = crypto.SHA384
+		case "512":
+			hash = crypto.SHA512
+		}
+		if hash != 0 {
+			switch alg[:2] {
+			case "HS":
+				return "hmac", hash, nil
+			case "RS":
+				return "rsa", hash, nil
+			case "ES":
+				return "ec", hash, nil
+			}
+		}
+	}
+	return "", 0, errJWT("Invalid alg header parameter: %s", alg)
+}
+
+// verifySignature verifies sig, the signature of msg, with the key from the
+// configuration.
+func verifySignature(kty string, hash crypto.Hash, key string, msg, sig []byte) error {
+	errSig := errJWT("Signature verification failed")
+	if kty == "hmac" {
+		secret, err := base64.StdEncoding.DecodeString(key)
+		if err != nil {
+			return &internal.Error{Status: http.StatusInternalServerError, Err: fmt.Errorf("invalid hmac key: %w", err)}
+		}
+		mac := hmac.New(hash.New, secret)
+		_, _ = mac.Write(msg)
+		if !hmac.Equal(mac.Sum(nil), sig) {
+			return errSig
+		}
+		return nil
+	}
+	pub, err := parsePublicKey(key)
+	if err != nil {
+		return &internal.Error{Status: http.StatusInternalServerError, Err: err}
+	}
+	h := hash.New()
+	_, _ = h.Write(msg)
+	digest := h.Sum(nil)
+	switch k := pub.(type) {
+	case *rsa.PublicKey:
+		if kty != "rsa" || rsa.VerifyPKCS1v15(k, hash, digest, sig) != nil {
+			return errSig
+		}
+	case *ecdsa.PublicKey:
+		size := (k.Curve.Params().BitSize + 7) / 8
+		if kty != "ec" || len(sig) != 2*size {
+			return errSig
+		}
+		r := new(big.Int).SetBytes(sig[:size])
+		s := new(big.Int).SetBytes(sig[size:])
+		if !ecdsa.Verify(k, digest, r, s) {
+			return errSig
+		}
+	default:
+		return errSig
+	}
+	return nil
+}
+
+func parsePublicKey(key string) (interface{}, error) {
+	// Keys in ini-style configuration files have their newlines escaped.
+	block, _ := pem.Decode([]byte(strings.ReplaceAll(key, `\n`, "\n")))
+	if block == nil {
+		return nil, errors.New("invalid public key")
+	}
+	return x509.ParsePKIXPublicKey(block.Bytes)
+}
+
+// requiredClaim is an entry of the jwt_auth/required_claims configuration
+// value. If value is non-nil, the claim must have that value.
+type requiredClaim struct {
+	name  string
+	value *string
+}
+
+// parseRequiredClaims parses a list such as `exp, iat, {iss, "issuer"}`.
+func parseRequiredClaims(conf string) ([]requiredClaim, error) {
+	var claims []requiredClaim
+	for conf = strings.TrimSpace(conf); conf != ""; conf = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(conf), ",")) {
+		if conf[0] != '{' {
+			i := strings.Index(conf, ",")
+			if i < 0 {
+				i = len(conf)
+			}
+			claims = append(claims, requiredClaim{name: strings.TrimSpace(conf[:i])})
+			conf = conf[i:]
+			continue
+		}
+		end := strings.Index(conf, "}")
+		if end < 0 {
+			return nil, fmt.Errorf("invalid required_claims: %s", conf)
+		}
+		parts := strings.SplitN(conf[1:end], ",", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid required_claims: %s", conf[:end+1])
+		}
+		var value string
+		if err := json.Unmarshal([]byte(strings.TrimSpace(parts[1])), &value); err != nil {
+			return nil, fmt.Errorf("invalid required_claims: %s", conf[:end+1])
+		}
+		claims = append(claims, requiredClaim{name: strings.TrimSpace(parts[0]), value: &value})
+		conf = conf[end+1:]
+	}
+	return claims, nil
+}
+
+// checkClaims validates the registered time claims, when present, and the
+// configured required claims.
+func (a *jwtAuth) checkClaims(ctx context.Context, claims map[string]interface{}) error {
+	now := a.now().Unix()
+	if exp, ok := claims["exp"].(float64); ok && int64(exp) <= now {
+		return errJWT("exp not in future")
+	}
+	if nbf, ok := claims["nbf"].(float64); ok && int64(nbf) > now {
+		return errJWT("nbf not in past")
+	}
+	conf, err := a.s.Config().Key(ctx, "jwt_auth", "required_claims")
+	if kivik.HTTPStatus(err) == http.StatusNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	required, err := parseRequiredClaims(conf)
+	if err != nil {
+		return &internal.Error{Status: http.StatusInternalServerError, Err: err}
+	}
+	for _, claim := range required {
+		value, ok := claims[claim.name]
+		if !ok {
+			return errJWT("Missing required claim: %s", claim.name)
+		}
+		if claim.value != nil && value != *claim.value {
+			return errJWT("Required claim %s does not match", claim.name)
+		}
+	}
+	return nil
+}
+
+// defaultRolesClaim is the top-level claim from which roles are read, when
+// jwt_auth/roles_claim_path is not set. Despite the dot, it is a single key.
+const defaultRolesClaim = "_couchdb.roles"
+
+// roles returns the roles from the literal "_couchdb.roles" claim, or, if
+// jwt_auth/roles_claim_path is set, from the claim at that dot-separated path.
+func (a *jwtAuth) roles(ctx context.Context, claims map[string]interface{}) ([]string, error) {
+	value, ok := claims[defaultRolesClaim]
+	if path, err := a.s.Config().Key(ctx, "jwt_auth", "roles_claim_path"); err == nil && path != "" {
+		value, ok = claimAtPath(claims, path)
+	}
+	if !ok {
+		return []string{}, nil
+	}
+	list, ok := value.([]interface{})
+	if !ok {
+		return nil, errJWT("Roles claim must be a list of strings")
+	}
+	roles := make([]string, 0, len(list))
+	for _, role := range list {
+		r, ok := role.(string)
+		if !ok {
+			return nil, errJWT("Roles claim must be a list of strings")
+		}
+		roles = append(roles, r)
+	}
+	return roles, nil
+}
+
+// claimAtPath returns the claim at the dot-separated path.
+func claimAtPath(claims map[string]interface{}, path string) (interface{}, bool) {
+	var value interface{} = claims
+	for _, part := range strings.Split(path, ".") {
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if value, ok = obj[part]; !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
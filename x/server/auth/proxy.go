@@ -0,0 +1,100 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	internal "github.com/go-kivik/kivik/v4/int/errors"
+)
+
+// Proxy authentication headers, as used by CouchDB.
+const (
+	HeaderProxyUserName = "X-Auth-CouchDB-UserName"
+	HeaderProxyRoles    = "X-Auth-CouchDB-Roles"
+	HeaderProxyToken    = "X-Auth-CouchDB-Token"
+)
+
+type proxyAuth struct {
+	s Server
+}
+
+// ProxyAuth returns a [proxy auth] handler, which trusts the user name and
+// comma-separated roles passed by an authenticating proxy in the
+// X-Auth-CouchDB-UserName and X-Auth-CouchDB-Roles headers. The
+// X-Auth-CouchDB-Token header must also contain the hex-encoded HMAC-SHA1 of
+// the user name, keyed by the chttpd_auth/secret configuration value, and
+// requests are rejected if no secret is configured. To trust the headers
+// without a token, chttpd_auth/proxy_use_secret must be explicitly set to
+// false.
+//
+// [proxy auth]: https://docs.couchdb.org/en/stable/api/server/authn.html#proxy-authentication
+func ProxyAuth() Handler {
+	return &proxyAuth{}
+}
+
+func (a *proxyAuth) Init(s Server) (string, AuthenticateFunc) {
+	a.s = s
+	return "proxy", // For compatibility with the name used by CouchDB
+		a.Authenticate
+}
+
+func (a *proxyAuth) Authenticate(_ http.ResponseWriter, r *http.Request) (*UserContext, error) {
+	name := r.Header.Get(HeaderProxyUserName)
+	if name == "" {
+		return nil, nil
+	}
+	if err := a.verify(r.Context(), name, r.Header.Get(HeaderProxyToken)); err != nil {
+		return nil, err
+	}
+	roles := []string{}
+	for _, role := range strings.Split(r.Header.Get(HeaderProxyRoles), ",") {
+		if role = strings.TrimSpace(role); role != "" {
+			roles = append(roles, role)
+		}
+	}
+	return &UserContext{
+		Name:  name,
+		Roles: roles,
+	}, nil
+}
+
+// verify checks the proxy auth token for the named user against the
+// configured secret, unless proxy_use_secret is disabled.
+func (a *proxyAuth) verify(ctx context.Context, name, header string) error {
+	conf := a.s.Config()
+	if useSecret, _ := conf.Key(ctx, "chttpd_auth", "proxy_use_secret"); useSecret == "false" {
+		return nil
+	}
+	secret, _ := conf.Key(ctx, "chttpd_auth", "secret")
+	if secret == "" {
+		return &internal.Error{Status: http.StatusUnauthorized, Message: "proxy auth secret not configured"}
+	}
+	token, err := hex.DecodeString(header)
+	if err != nil || !hmac.Equal(token, ProxyToken(name, secret)) {
+		return &internal.Error{Status: http.StatusUnauthorized, Message: "invalid proxy auth token"}
+	}
+	return nil
+}
+
+// ProxyToken returns the proxy auth token for the named user.
+func ProxyToken(name, secret string) []byte {
+	h := hmac.New(sha1.New, []byte(secret))
+	_, _ = h.Write([]byte(name))
+	return h.Sum(nil)
+}
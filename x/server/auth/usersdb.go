@@ -0,0 +1,223 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package auth
+
+import (
+	"context"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"hash"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/pbkdf2"
+
+	"github.com/go-kivik/kivik/v4"
+)
+
+// UsersDB is the name of the database which stores user documents.
+const UsersDB = "_users"
+
+// Password schemes and pseudo-random functions supported in user documents.
+const (
+	SchemePBKDF2 = "pbkdf2"
+	SchemeSimple = "simple"
+	PRFSHA1      = "sha"
+	PRFSHA256    = "sha256"
+)
+
+// DefaultIterations is the number of pbkdf2 iterations used by [HashPassword]
+// when none is specified, matching the CouchDB default.
+const DefaultIterations = 600000
+
+// validateCacheTTL is how long a successful password check is remembered, so
+// that the costly pbkdf2 derivation isn't repeated for every request, as with
+// CouchDB's auth cache.
+const validateCacheTTL = 5 * time.Minute
+
+// UsersDBStore is a UserStore backed by a CouchDB-style [_users database].
+//
+// [_users database]: https://docs.couchdb.org/en/stable/intro/security.html#authentication-database
+type UsersDBStore struct {
+	client *kivik.Client
+
+	mu sync.Mutex
+	// validated holds the last successful password check for each user.
+	validated map[string]validated
+	now       func() time.Time
+}
+
+// validated records a successful password check against a revision of a user
+// document. Any change to the document invalidates it.
+type validated struct {
+	rev     string
+	key     [sha256.Size]byte
+	expires time.Time
+}
+
+var _ UserStore = (*UsersDBStore)(nil)
+
+// NewUsersDBStore returns a new UsersDBStore, which reads user documents from
+// the _users database of client.
+func NewUsersDBStore(client *kivik.Client) *UsersDBStore {
+	return &UsersDBStore{
+		client:    client,
+		validated: map[string]validated{},
+		now:       time.Now,
+	}
+}
+
+type userDoc struct {
+	Rev            string   `json:"_rev"`
+	Name           string   `json:"name"`
+	Roles          []string `json:"roles"`
+	PasswordScheme string   `json:"password_scheme"`
+	PRF            string   `json:"pbkdf2_prf"`
+	Iterations     int      `json:"iterations"`
+	Salt           string   `json:"salt"`
+	DerivedKey     string   `json:"derived_key"`
+	PasswordSHA    string   `json:"password_sha"`
+}
+
+func (s *UsersDBStore) user(ctx context.Context, username string) (*userDoc, error) {
+	var user userDoc
+	err := s.client.DB(UsersDB).Get(ctx, kivik.UserPrefix+username).ScanDoc(&user)
+	if kivik.HTTPStatus(err) == http.StatusNotFound {
+		return nil, errNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (u *userDoc) userCtx() *UserContext {
+	return &UserContext{
+		Name:  u.Name,
+		Roles: u.Roles,
+		Salt:  u.Salt,
+	}
+}
+
+// checkPassword returns true if password matches the credentials stored in
+// the user document.
+func (u *userDoc) checkPassword(password string) bool {
+	var key string
+	var want string
+	switch u.PasswordScheme {
+	case SchemePBKDF2:
+		h, size := prf(u.PRF)
+		if h == nil || u.Iterations <= 0 {
+			return false
+		}
+		key = hex.EncodeToString(pbkdf2.Key([]byte(password), []byte(u.Salt), u.Iterations, size, h))
+		want = u.DerivedKey
+	case SchemeSimple, "":
+		sum := sha1.Sum([]byte(password + u.Salt))
+		key = hex.EncodeToString(sum[:])
+		want = u.PasswordSHA
+	default:
+		return false
+	}
+	return want != "" && subtle.ConstantTimeCompare([]byte(key), []byte(want)) == 1
+}
+
+// prf returns the hash function and key length for the named pbkdf2
+// pseudo-random function, or nil if it is unsupported.
+func prf(name string) (func() hash.Hash, int) {
+	switch name {
+	case PRFSHA1, "":
+		return sha1.New, sha1.Size
+	case PRFSHA256:
+		return sha256.New, sha256.Size
+	}
+	return nil, 0
+}
+
+// Validate returns a user context object if the credentials are valid.
+// Successful checks are cached for a few minutes, for as long as the user
+// document is unchanged.
+func (s *UsersDBStore) Validate(ctx context.Context, username, password string) (*UserContext, error) {
+	user, err := s.user(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	key := sha256.Sum256([]byte(user.Salt + password))
+	if s.isValidated(username, user.Rev, key) {
+		return user.userCtx(), nil
+	}
+	if !user.checkPassword(password) {
+		return nil, errUnauthorized
+	}
+	s.mu.Lock()
+	s.validated[username] = validated{
+		rev:     user.Rev,
+		key:     key,
+		expires: s.now().Add(validateCacheTTL),
+	}
+	s.mu.Unlock()
+	return user.userCtx(), nil
+}
+
+// isValidated returns true if the password, identified by key, was recently
+// validated against rev of the user document. A stale entry is discarded.
+func (s *UsersDBStore) isValidated(username, rev string, key [sha256.Size]byte) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.validated[username]
+	if !ok {
+		return false
+	}
+	if v.rev != rev || s.now().After(v.expires) {
+		delete(s.validated, username)
+		return false
+	}
+	return subtle.ConstantTimeCompare(v.key[:], key[:]) == 1
+}
+
+// UserCtx returns a user context object if the user exists.
+func (s *UsersDBStore) UserCtx(ctx context.Context, username string) (*UserContext, error) {
+	user, err := s.user(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	return user.userCtx(), nil
+}
+
+// HashPassword replaces the plaintext "password" field of a user document, if
+// present, with a pbkdf2-sha256 derived key, as CouchDB does when a user
+// document is written. If iterations is 0, [DefaultIterations] is used.
+func HashPassword(doc map[string]interface{}, iterations int) error {
+	password, ok := doc["password"].(string)
+	if !ok {
+		return nil
+	}
+	if iterations <= 0 {
+		iterations = DefaultIterations
+	}
+	salt, err := generateSalt()
+	if err != nil {
+		return err
+	}
+	delete(doc, "password")
+	delete(doc, "password_sha")
+	doc["password_scheme"] = SchemePBKDF2
+	doc["pbkdf2_prf"] = PRFSHA256
+	doc["iterations"] = iterations
+	doc["salt"] = salt
+	doc["derived_key"] = hex.EncodeToString(pbkdf2.Key([]byte(password), []byte(salt), iterations, sha256.Size, sha256.New))
+	return nil
+}
@@ -0,0 +1,90 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+//go:build !js
+
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-kivik/kivik/v4"
+	_ "github.com/go-kivik/kivik/v4/x/memorydb" // Memory driver
+)
+
+func TestUsersDBStoreValidateCache(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	client, err := kivik.New("memory", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.CreateDB(ctx, UsersDB, nil); err != nil {
+		t.Fatal(err)
+	}
+	putUser := func(password, rev string) {
+		t.Helper()
+		doc := map[string]interface{}{
+			"name":     "sam",
+			"type":     "user",
+			"roles":    []string{},
+			"password": password,
+		}
+		if rev != "" {
+			doc["_rev"] = rev
+		}
+		if err := HashPassword(doc, 10); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := client.DB(UsersDB).Put(ctx, kivik.UserPrefix+"sam", doc); err != nil {
+			t.Fatal(err)
+		}
+	}
+	putUser("abc123", "")
+
+	now := time.Now()
+	s := NewUsersDBStore(client)
+	s.now = func() time.Time { return now }
+
+	if _, err := s.Validate(ctx, "sam", "abc123"); err != nil {
+		t.Fatal(err)
+	}
+	first := s.validated["sam"]
+	if first.rev == "" {
+		t.Fatal("Expected successful check to be cached")
+	}
+	if _, err := s.Validate(ctx, "sam", "wrong"); err == nil {
+		t.Error("Expected wrong password to fail despite cache")
+	}
+
+	now = now.Add(validateCacheTTL + time.Second)
+	if _, err := s.Validate(ctx, "sam", "abc123"); err != nil {
+		t.Fatal(err)
+	}
+	if !s.validated["sam"].expires.After(first.expires) {
+		t.Error("Expected expired entry to be replaced")
+	}
+
+	// Changing the user document invalidates the cache.
+	putUser("xyz789", first.rev)
+	if _, err := s.Validate(ctx, "sam", "abc123"); err == nil {
+		t.Error("Expected old password to fail after change")
+	}
+	if _, ok := s.validated["sam"]; ok {
+		t.Error("Expected stale entry to be discarded")
+	}
+	if _, err := s.Validate(ctx, "sam", "xyz789"); err != nil {
+		t.Errorf("Expected new password to succeed: %s", err)
+	}
+}
@@ -0,0 +1,268 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+//go:build !js
+
+package server
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kivik/kivik/v4/x/server/auth"
+	"github.com/go-kivik/kivik/v4/x/server/config"
+)
+
+const jwtSecret = "s3cr3t"
+
+type jwtKeys struct {
+	rsa *rsa.PrivateKey
+	ec  *ecdsa.PrivateKey
+}
+
+func newJWTKeys(t *testing.T) *jwtKeys {
+	t.Helper()
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &jwtKeys{rsa: rsaKey, ec: ecKey}
+}
+
+func pemPublicKey(t *testing.T, key interface{}) string {
+	t.Helper()
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Escape newlines, as in an ini file.
+	return strings.ReplaceAll(string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})), "\n", `\n`)
+}
+
+func (k *jwtKeys) config(t *testing.T, jwtAuth map[string]string) Option {
+	t.Helper()
+	return WithConfig(config.Map(map[string]map[string]string{
+		"jwt_keys": {
+			"hmac:_default": base64.StdEncoding.EncodeToString([]byte(jwtSecret)),
+			"rsa:rsakey":    pemPublicKey(t, &k.rsa.PublicKey),
+			"ec:eckey":      pemPublicKey(t, &k.ec.PublicKey),
+		},
+		"jwt_auth": jwtAuth,
+	}))
+}
+
+// token returns a signed JWT. alg must be HS256, RS256 or ES256.
+func (k *jwtKeys) token(t *testing.T, alg, kid string, claims map[string]interface{}) string {
+	t.Helper()
+	header := map[string]string{"alg": alg, "typ": "JWT"}
+	if kid != "" {
+		header["kid"] = kid
+	}
+	encode := func(v interface{}) string {
+		data, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+	msg := encode(header) + "." + encode(claims)
+	digest := sha256.Sum256([]byte(msg))
+	var sig []byte
+	switch alg {
+	case "HS256":
+		mac := hmac.New(sha256.New, []byte(jwtSecret))
+		_, _ = mac.Write([]byte(msg))
+		sig = mac.Sum(nil)
+	case "RS256":
+		var err error
+		sig, err = rsa.SignPKCS1v15(rand.Reader, k.rsa, crypto.SHA256, digest[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+	case "ES256":
+		r, s, err := ecdsa.Sign(rand.Reader, k.ec, digest[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		sig = make([]byte, 64)
+		r.FillBytes(sig[:32])
+		s.FillBytes(sig[32:])
+	}
+	return "Bearer " + msg + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func TestJWTAuth(t *testing.T) {
+	t.Parallel()
+
+	keys := newJWTKeys(t)
+	future := time.Now().Add(time.Hour).Unix()
+	past := time.Now().Add(-time.Hour).Unix()
+	test := func(name, token string, jwtAuth map[string]string, status int, want interface{}) serverTest {
+		return serverTest{
+			name:         name,
+			method:       http.MethodGet,
+			path:         "/_session",
+			headers:      map[string]string{"Authorization": token},
+			extraOptions: []Option{keys.config(t, jwtAuth), WithAuthHandlers(auth.JWTAuth())},
+			wantStatus:   status,
+			wantJSON:     want,
+		}
+	}
+	session := func(name string, roles ...string) interface{} {
+		if roles == nil {
+			roles = []string{}
+		}
+		return map[string]interface{}{
+			"ok": true,
+			"userCtx": map[string]interface{}{
+				"name":  name,
+				"roles": roles,
+			},
+		}
+	}
+	unauthorized := func(reason string) interface{} {
+		return map[string]interface{}{"error": "unauthorized", "reason": reason}
+	}
+
+	tests := serverTests{
+		test("HS256", keys.token(t, "HS256", "", map[string]interface{}{
+			"sub":            "jan",
+			"_couchdb.roles": []string{roleFoo},
+		}), nil, http.StatusOK, session("jan", roleFoo)),
+		test("nested roles ignored by default", keys.token(t, "HS256", "", map[string]interface{}{
+			"sub":      "jan",
+			"_couchdb": map[string]interface{}{"roles": []string{roleFoo}},
+		}), nil, http.StatusOK, session("jan")),
+		test("RS256", keys.token(t, "RS256", "rsakey", map[string]interface{}{
+			"sub": "jan",
+			"exp": future,
+		}), nil, http.StatusOK, session("jan")),
+		test("ES256", keys.token(t, "ES256", "eckey", map[string]interface{}{
+			"sub": "jan",
+		}), nil, http.StatusOK, session("jan")),
+		test("custom roles claim path", keys.token(t, "HS256", "", map[string]interface{}{
+			"sub":   "jan",
+			"realm": map[string]interface{}{"roles": []string{roleBar}},
+		}), map[string]string{"roles_claim_path": "realm.roles"}, http.StatusOK, session("jan", roleBar)),
+		test("required claims present", keys.token(t, "HS256", "", map[string]interface{}{
+			"sub": "jan",
+			"exp": future,
+			"iss": "gateway",
+		}), map[string]string{"required_claims": `exp, {iss, "gateway"}`}, http.StatusOK, session("jan")),
+		test("required claim missing", keys.token(t, "HS256", "", map[string]interface{}{
+			"sub": "jan",
+		}), map[string]string{"required_claims": "exp"}, http.StatusUnauthorized, unauthorized("Missing required claim: exp")),
+		test("required claim mismatch", keys.token(t, "HS256", "", map[string]interface{}{
+			"sub": "jan",
+			"iss": "elsewhere",
+		}), map[string]string{"required_claims": `{iss, "gateway"}`}, http.StatusUnauthorized, unauthorized("Required claim iss does not match")),
+		test("expired", keys.token(t, "HS256", "", map[string]interface{}{
+			"sub": "jan",
+			"exp": past,
+		}), nil, http.StatusUnauthorized, unauthorized("exp not in future")),
+		test("wrong key", keys.token(t, "RS256", "eckey", map[string]interface{}{
+			"sub": "jan",
+		}), nil, http.StatusUnauthorized, unauthorized("Unknown kid: eckey")),
+		test("bad signature", keys.token(t, "HS256", "", map[string]interface{}{
+			"sub": "jan",
+		})+"x", nil, http.StatusUnauthorized, unauthorized("Signature verification failed")),
+		test("unsupported alg", keys.token(t, "none", "", map[string]interface{}{
+			"sub": "jan",
+		}), nil, http.StatusUnauthorized, unauthorized("Invalid alg header parameter: none")),
+		test("missing sub", keys.token(t, "HS256", "", map[string]interface{}{}),
+			nil, http.StatusUnauthorized, unauthorized("Token missing sub claim")),
+	}
+
+	tests.Run(t)
+}
+
+func TestProxyAuth(t *testing.T) {
+	t.Parallel()
+
+	const secret = "proxysecret"
+	test := func(name string, chttpdAuth map[string]string, headers map[string]string, status int, want interface{}) serverTest {
+		return serverTest{
+			name:    name,
+			method:  http.MethodGet,
+			path:    "/_session",
+			headers: headers,
+			extraOptions: []Option{
+				WithConfig(config.Map(map[string]map[string]string{"chttpd_auth": chttpdAuth})),
+				WithAuthHandlers(auth.ProxyAuth()),
+			},
+			wantStatus: status,
+			wantJSON:   want,
+		}
+	}
+
+	tests := serverTests{
+		test("secret disabled", map[string]string{"proxy_use_secret": "false"}, map[string]string{
+			auth.HeaderProxyUserName: "kim",
+			auth.HeaderProxyRoles:    "foo, bar",
+		}, http.StatusOK, map[string]interface{}{
+			"ok": true,
+			"userCtx": map[string]interface{}{
+				"name":  "kim",
+				"roles": []string{roleFoo, roleBar},
+			},
+		}),
+		test("no secret configured", nil, map[string]string{
+			auth.HeaderProxyUserName: "kim",
+		}, http.StatusUnauthorized, map[string]interface{}{
+			"error":  "unauthorized",
+			"reason": "proxy auth secret not configured",
+		}),
+		test("valid token", map[string]string{"secret": secret}, map[string]string{
+			auth.HeaderProxyUserName: "kim",
+			auth.HeaderProxyToken:    hex.EncodeToString(auth.ProxyToken("kim", secret)),
+		}, http.StatusOK, map[string]interface{}{
+			"ok": true,
+			"userCtx": map[string]interface{}{
+				"name":  "kim",
+				"roles": []string{},
+			},
+		}),
+		test("secret required", map[string]string{"secret": secret, "proxy_use_secret": "true"}, map[string]string{
+			auth.HeaderProxyUserName: "kim",
+		}, http.StatusUnauthorized, map[string]interface{}{
+			"error":  "unauthorized",
+			"reason": "invalid proxy auth token",
+		}),
+		test("invalid token", map[string]string{"secret": secret}, map[string]string{
+			auth.HeaderProxyUserName: "kim",
+			auth.HeaderProxyToken:    hex.EncodeToString(auth.ProxyToken("kim", "wrong")),
+		}, http.StatusUnauthorized, map[string]interface{}{
+			"error":  "unauthorized",
+			"reason": "invalid proxy auth token",
+		}),
+	}
+
+	tests.Run(t)
+}
@@ -29,6 +29,7 @@ import (
 	"github.com/go-kivik/kivik/v4"
 	"github.com/go-kivik/kivik/v4/driver"
 	internal "github.com/go-kivik/kivik/v4/int/errors"
+	"github.com/go-kivik/kivik/v4/x/server/auth"
 )
 
 func (s *Server) db() httpe.HandlerWithError {
@@ -244,6 +245,11 @@ func whichView(r *http.Request) (ddoc, view string, isQueries bool) {
 
 func (s *Server) query() httpe.HandlerWithError {
 	return httpe.HandlerWithErrorFunc(func(w http.ResponseWriter, r *http.Request) error {
+		if chi.URLParam(r, "db") == auth.UsersDB {
+			if err := authorizeUsersDBQuery(r); err != nil {
+				return err
+			}
+		}
 		ddoc, view, isQueries := whichView(r)
 		req := map[string]interface{}{}
 		if isQueries {
@@ -20,6 +20,9 @@ import (
 
 	"github.com/go-chi/chi/v5"
 	"gitlab.com/flimzy/httpe"
+
+	internal "github.com/go-kivik/kivik/v4/int/errors"
+	"github.com/go-kivik/kivik/v4/x/server/auth"
 )
 
 func (s *Server) postDoc() httpe.HandlerWithError {
@@ -29,6 +32,13 @@ func (s *Server) postDoc() httpe.HandlerWithError {
 		if err := json.NewDecoder(r.Body).Decode(&doc); err != nil {
 			return err
 		}
+		if db == auth.UsersDB {
+			obj, _ := doc.(map[string]interface{})
+			id, _ := obj["_id"].(string)
+			if err := s.prepareUserDoc(r, id, obj); err != nil {
+				return err
+			}
+		}
 		id, rev, err := s.client.DB(db).CreateDoc(r.Context(), doc, options(r))
 		if err != nil {
 			return err
@@ -41,10 +51,44 @@ func (s *Server) postDoc() httpe.HandlerWithError {
 	})
 }
 
+func (s *Server) putDoc() httpe.HandlerWithError {
+	return httpe.HandlerWithErrorFunc(func(w http.ResponseWriter, r *http.Request) error {
+		db := chi.URLParam(r, "db")
+		id := chi.URLParam(r, "docid")
+		var doc interface{}
+		if err := json.NewDecoder(r.Body).Decode(&doc); err != nil {
+			return &internal.Error{Status: http.StatusBadRequest, Err: err}
+		}
+		obj, _ := doc.(map[string]interface{})
+		if rev := r.URL.Query().Get("rev"); rev != "" && obj != nil && obj["_rev"] == nil {
+			obj["_rev"] = rev
+		}
+		if db == auth.UsersDB {
+			if err := s.prepareUserDoc(r, id, obj); err != nil {
+				return err
+			}
+		}
+		rev, err := s.client.DB(db).Put(r.Context(), id, doc, options(r))
+		if err != nil {
+			return err
+		}
+		return serveJSON(w, http.StatusCreated, map[string]interface{}{
+			"id":  id,
+			"rev": rev,
+			"ok":  true,
+		})
+	})
+}
+
 func (s *Server) doc() httpe.HandlerWithError {
 	return httpe.HandlerWithErrorFunc(func(w http.ResponseWriter, r *http.Request) error {
 		db := chi.URLParam(r, "db")
 		id := chi.URLParam(r, "docid")
+		if db == auth.UsersDB {
+			if err := authorizeUserDocRead(r, id); err != nil {
+				return err
+			}
+		}
 		var doc interface{}
 		err := s.client.DB(db).Get(r.Context(), id, options(r)).ScanDoc(&doc)
 		if err != nil {
@@ -0,0 +1,72 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+//go:build !js
+
+package server
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"gitlab.com/flimzy/httpe"
+)
+
+func (s *Server) getRevsLimit() httpe.HandlerWithError {
+	return s.getLimit(func(ctx context.Context, db string) (int, error) {
+		return s.client.DB(db).RevsLimit(ctx)
+	})
+}
+
+func (s *Server) putRevsLimit() httpe.HandlerWithError {
+	return s.putLimit(func(ctx context.Context, db string, limit int) error {
+		return s.client.DB(db).SetRevsLimit(ctx, limit)
+	})
+}
+
+func (s *Server) getPurgedInfosLimit() httpe.HandlerWithError {
+	return s.getLimit(func(ctx context.Context, db string) (int, error) {
+		return s.client.DB(db).PurgedInfosLimit(ctx)
+	})
+}
+
+func (s *Server) putPurgedInfosLimit() httpe.HandlerWithError {
+	return s.putLimit(func(ctx context.Context, db string, limit int) error {
+		return s.client.DB(db).SetPurgedInfosLimit(ctx, limit)
+	})
+}
+
+func (s *Server) getLimit(get func(context.Context, string) (int, error)) httpe.HandlerWithError {
+	return httpe.HandlerWithErrorFunc(func(w http.ResponseWriter, r *http.Request) error {
+		limit, err := get(r.Context(), chi.URLParam(r, "db"))
+		if err != nil {
+			return err
+		}
+		return serveJSON(w, http.StatusOK, limit)
+	})
+}
+
+func (s *Server) putLimit(set func(context.Context, string, int) error) httpe.HandlerWithError {
+	return httpe.HandlerWithErrorFunc(func(w http.ResponseWriter, r *http.Request) error {
+		var limit int
+		if err := s.bind(r, &limit); err != nil {
+			return err
+		}
+		if err := set(r.Context(), chi.URLParam(r, "db"), limit); err != nil {
+			return err
+		}
+		return serveJSON(w, http.StatusOK, map[string]bool{
+			"ok": true,
+		})
+	})
+}
@@ -90,6 +90,7 @@ func (s *Server) routes(mux *chi.Mux) {
 		httpe.ToMiddleware(adminRequired),
 	)
 	auth.Get("/", e(s.root()))
+	auth.Get("/_session", e(s.session()))
 	admin.Get("/_active_tasks", e(s.activeTasks()))
 	admin.Get("/_all_dbs", e(s.allDBs()))
 	auth.Get("/_dbs_info", e(s.allDBsStats()))
@@ -177,17 +178,17 @@ func (s *Server) routes(mux *chi.Mux) {
 		member.Get("/_security", e(s.getSecurity()))
 		dbAdmin.Put("/_security", e(s.putSecurity()))
 		member.Post("/_purge", e(s.notImplemented()))
-		member.Get("/_purged_infos_limit", e(s.notImplemented()))
-		member.Put("/_purged_infos_limit", e(s.notImplemented()))
+		member.Get("/_purged_infos_limit", e(s.getPurgedInfosLimit()))
+		dbAdmin.Put("/_purged_infos_limit", e(s.putPurgedInfosLimit()))
 		member.Post("/_missing_revs", e(s.notImplemented()))
 		member.Post("/_revs_diff", e(s.notImplemented()))
-		member.Get("/_revs_limit", e(s.notImplemented()))
-		dbAdmin.Put("/_revs_limit", e(s.notImplemented()))
+		member.Get("/_revs_limit", e(s.getRevsLimit()))
+		dbAdmin.Put("/_revs_limit", e(s.putRevsLimit()))
 
 		// Documents
 		member.Post("/", e(s.postDoc()))
 		member.Get("/{docid}", e(s.doc()))
-		member.Put("/{docid}", e(s.notImplemented()))
+		member.Put("/{docid}", e(s.putDoc()))
 		member.Delete("/{docid}", e(s.notImplemented()))
 		member.Method("COPY", "/{db}/{docid}", httpe.ToHandler(s.notImplemented()))
 		member.Delete("/{docid}", e(s.notImplemented()))
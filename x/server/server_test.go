@@ -31,6 +31,8 @@ import (
 	"gitlab.com/flimzy/testy"
 
 	"github.com/go-kivik/kivik/v4"
+	"github.com/go-kivik/kivik/v4/driver"
+	"github.com/go-kivik/kivik/v4/mockdb"
 	_ "github.com/go-kivik/kivik/v4/x/fsdb"     // Filesystem driver
 	_ "github.com/go-kivik/kivik/v4/x/memorydb" // Memory driver
 	"github.com/go-kivik/kivik/v4/x/server/auth"
@@ -242,6 +244,34 @@ func TestServer(t *testing.T) {
 			wantStatus: http.StatusOK,
 			wantJSON:   []interface{}{},
 		},
+		{
+			name: "active tasks, from driver",
+			client: func() *kivik.Client {
+				client, mock, err := mockdb.New()
+				if err != nil {
+					t.Fatal(err)
+				}
+				mock.ExpectActiveTasks().WillReturn([]*driver.ActiveTask{
+					{Type: "indexer", Node: "node1", Database: "foo", DesignDocument: "_design/bar", Progress: 50, ChangesDone: 5, TotalChanges: 10},
+				})
+				return client
+			}(),
+			method:     http.MethodGet,
+			path:       "/_active_tasks",
+			authUser:   userAdmin,
+			wantStatus: http.StatusOK,
+			wantJSON: []interface{}{
+				map[string]interface{}{
+					"type":            "indexer",
+					"node":            "node1",
+					"database":        "foo",
+					"design_document": "_design/bar",
+					"progress":        50,
+					"changes_done":    5,
+					"total_changes":   10,
+				},
+			},
+		},
 		{
 			name:       "all dbs",
 			method:     http.MethodGet,
@@ -829,6 +859,98 @@ func TestServer(t *testing.T) {
 				},
 			}
 		}(),
+		{
+			name: "get revs limit",
+			client: func() *kivik.Client {
+				client, mock, err := mockdb.New()
+				if err != nil {
+					t.Fatal(err)
+				}
+				db := mock.NewDB()
+				mock.ExpectDB().WithName("db1").WillReturn(db)
+				db.ExpectSecurity().WillReturn(&driver.Security{})
+				mock.ExpectDB().WithName("db1").WillReturn(db)
+				db.ExpectRevsLimit().WillReturn(1000)
+				return client
+			}(),
+			method:     http.MethodGet,
+			path:       "/db1/_revs_limit",
+			authUser:   userAdmin,
+			wantStatus: http.StatusOK,
+			wantJSON:   1000,
+		},
+		{
+			name: "put revs limit",
+			client: func() *kivik.Client {
+				client, mock, err := mockdb.New()
+				if err != nil {
+					t.Fatal(err)
+				}
+				db := mock.NewDB()
+				mock.ExpectDB().WithName("db1").WillReturn(db)
+				db.ExpectSecurity().WillReturn(&driver.Security{})
+				mock.ExpectDB().WithName("db1").WillReturn(db)
+				db.ExpectSecurity().WillReturn(&driver.Security{})
+				mock.ExpectDB().WithName("db1").WillReturn(db)
+				db.ExpectSetRevsLimit().WithLimit(50)
+				return client
+			}(),
+			method:     http.MethodPut,
+			path:       "/db1/_revs_limit",
+			authUser:   userAdmin,
+			headers:    map[string]string{"Content-Type": "application/json"},
+			body:       strings.NewReader(`50`),
+			wantStatus: http.StatusOK,
+			wantJSON: map[string]interface{}{
+				"ok": true,
+			},
+		},
+		{
+			name:       "put revs limit, invalid",
+			method:     http.MethodPut,
+			path:       "/db1/_revs_limit",
+			authUser:   userAdmin,
+			headers:    map[string]string{"Content-Type": "application/json"},
+			body:       strings.NewReader(`"fifty"`),
+			wantStatus: http.StatusBadRequest,
+			wantJSON: map[string]interface{}{
+				"error":  "bad_request",
+				"reason": "json: cannot unmarshal string into Go value of type int",
+			},
+		},
+		{
+			name: "get purged infos limit",
+			client: func() *kivik.Client {
+				client, mock, err := mockdb.New()
+				if err != nil {
+					t.Fatal(err)
+				}
+				db := mock.NewDB()
+				mock.ExpectDB().WithName("db1").WillReturn(db)
+				db.ExpectSecurity().WillReturn(&driver.Security{})
+				mock.ExpectDB().WithName("db1").WillReturn(db)
+				db.ExpectPurgedInfosLimit().WillReturn(1000)
+				return client
+			}(),
+			method:     http.MethodGet,
+			path:       "/db1/_purged_infos_limit",
+			authUser:   userAdmin,
+			wantStatus: http.StatusOK,
+			wantJSON:   1000,
+		},
+		{
+			name:       "put purged infos limit, no admin access",
+			method:     http.MethodPut,
+			authUser:   userBob,
+			path:       "/db2/_purged_infos_limit",
+			headers:    map[string]string{"Content-Type": "application/json"},
+			body:       strings.NewReader(`50`),
+			wantStatus: http.StatusForbidden,
+			wantJSON: map[string]interface{}{
+				"error":  "forbidden",
+				"reason": "User lacks sufficient privileges",
+			},
+		},
 		{
 			name:       "put security, unauthorized",
 			method:     http.MethodPut,
@@ -0,0 +1,149 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+//go:build !js
+
+package server
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/go-kivik/kivik/v4"
+	internal "github.com/go-kivik/kivik/v4/int/errors"
+	"github.com/go-kivik/kivik/v4/x/server/auth"
+)
+
+func forbidden(msg string) error {
+	return &internal.Error{Status: http.StatusForbidden, Message: msg}
+}
+
+// prepareUserDoc validates a document about to be written to the _users
+// database, following the rules of CouchDB's default validation function, and
+// hashes any plaintext password it contains. Admins may write any user
+// document, while other users may only create new users without roles, or
+// update their own user document without changing its roles.
+func (s *Server) prepareUserDoc(r *http.Request, id string, doc map[string]interface{}) error {
+	if doc == nil {
+		return &internal.Error{Status: http.StatusBadRequest, Message: "Document must be a JSON object"}
+	}
+	if deleted, _ := doc["_deleted"].(bool); deleted {
+		return s.authorizeUserDoc(r, id, nil)
+	}
+	if t, _ := doc["type"].(string); t != "user" {
+		return forbidden("doc.type must be user")
+	}
+	name, ok := doc["name"].(string)
+	if !ok || name == "" {
+		return forbidden("doc.name is required")
+	}
+	if id != kivik.UserPrefix+name {
+		return forbidden("Doc ID must be of the form org.couchdb.user:name")
+	}
+	roles, err := docRoles(doc)
+	if err != nil {
+		return err
+	}
+	if err := s.authorizeUserDoc(r, id, roles); err != nil {
+		return err
+	}
+	var iterations int
+	_ = s.conf(r.Context(), "chttpd_auth", "iterations", &iterations)
+	return auth.HashPassword(doc, iterations)
+}
+
+func docRoles(doc map[string]interface{}) ([]string, error) {
+	raw, ok := doc["roles"].([]interface{})
+	if !ok {
+		return nil, forbidden("doc.roles must be an array")
+	}
+	roles := make([]string, 0, len(raw))
+	for _, role := range raw {
+		r, ok := role.(string)
+		if !ok {
+			return nil, forbidden("doc.roles can only contain strings")
+		}
+		roles = append(roles, r)
+	}
+	return roles, nil
+}
+
+// authorizeUserDoc returns an error if the current user may not write the
+// user document with the given ID and roles. A nil roles slice indicates
+// deletion.
+func (s *Server) authorizeUserDoc(r *http.Request, id string, roles []string) error {
+	user := userFromContext(r.Context())
+	if user != nil && user.HasRole(auth.RoleAdmin) {
+		return nil
+	}
+	var existing struct {
+		Name  string   `json:"name"`
+		Roles []string `json:"roles"`
+	}
+	err := s.client.DB(auth.UsersDB).Get(r.Context(), id).ScanDoc(&existing)
+	switch {
+	case kivik.HTTPStatus(err) == http.StatusNotFound:
+		if roles == nil {
+			return err
+		}
+		if len(roles) > 0 {
+			return forbidden("Only _admin may set roles")
+		}
+		return nil
+	case err != nil:
+		return err
+	}
+	if user == nil || user.Name != existing.Name {
+		return forbidden("You may only update your own user document.")
+	}
+	if roles != nil && !sameRoles(roles, existing.Roles) {
+		return forbidden("Only _admin may edit roles")
+	}
+	return nil
+}
+
+// authorizeUserDocRead returns an error if the current user may not read the
+// document with the given ID from the _users database. As in CouchDB, only
+// admins may read other users' documents, which otherwise appear not to exist.
+func authorizeUserDocRead(r *http.Request, id string) error {
+	user := userFromContext(r.Context())
+	if user != nil && (user.HasRole(auth.RoleAdmin) || id == kivik.UserPrefix+user.Name) {
+		return nil
+	}
+	return &internal.Error{Status: http.StatusNotFound, Message: "missing"}
+}
+
+// authorizeUsersDBQuery returns an error if the current user may not list or
+// query the _users database, which, as in CouchDB, is reserved to admins.
+func authorizeUsersDBQuery(r *http.Request) error {
+	user := userFromContext(r.Context())
+	if user == nil || !user.HasRole(auth.RoleAdmin) {
+		return forbidden("Only admins may query the _users database.")
+	}
+	return nil
+}
+
+func sameRoles(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a = append([]string(nil), a...)
+	b = append([]string(nil), b...)
+	sort.Strings(a)
+	sort.Strings(b)
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
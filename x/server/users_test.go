@@ -0,0 +1,384 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+//go:build !js
+
+package server
+
+import (
+	"context"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/pbkdf2"
+
+	"github.com/go-kivik/kivik/v4"
+	"github.com/go-kivik/kivik/v4/x/server/auth"
+	"github.com/go-kivik/kivik/v4/x/server/config"
+)
+
+const (
+	userSam  = "sam"
+	userTess = "tess"
+	userUma  = "uma"
+)
+
+// usersDBTest configures tt to run against a memory client with a populated
+// _users database, authenticated by a UsersDBStore.
+func usersDBTest(t *testing.T, tt serverTest) serverTest {
+	t.Helper()
+	client, err := kivik.New("memory", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if err := client.CreateDB(ctx, auth.UsersDB, nil); err != nil {
+		t.Fatal(err)
+	}
+	const salt = "7897f3451f59da741c87ec5f10fe7abe"
+	simple := sha1.Sum([]byte(testPassword + salt))
+	users := []map[string]interface{}{
+		{
+			"name":            userSam,
+			"roles":           []string{roleFoo},
+			"password_scheme": "pbkdf2",
+			"iterations":      10,
+			"salt":            salt,
+			"derived_key":     hex.EncodeToString(pbkdf2.Key([]byte(testPassword), []byte(salt), 10, sha1.Size, sha1.New)),
+		},
+		{
+			"name":            userTess,
+			"roles":           []string{},
+			"password_scheme": "pbkdf2",
+			"pbkdf2_prf":      "sha256",
+			"iterations":      10,
+			"salt":            salt,
+			"derived_key":     hex.EncodeToString(pbkdf2.Key([]byte(testPassword), []byte(salt), 10, sha256.Size, sha256.New)),
+		},
+		{
+			"name":            userUma,
+			"roles":           []string{},
+			"password_scheme": "simple",
+			"salt":            salt,
+			"password_sha":    hex.EncodeToString(simple[:]),
+		},
+	}
+	for _, user := range users {
+		user["type"] = "user"
+		if _, err := client.DB(auth.UsersDB).Put(ctx, kivik.UserPrefix+user["name"].(string), user); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tt.client = client
+	tt.extraOptions = append(tt.extraOptions,
+		WithUserStores(auth.NewUsersDBStore(client)),
+		WithConfig(config.Map(map[string]map[string]string{
+			"chttpd_auth": {"iterations": "10"},
+		})),
+	)
+	return tt
+}
+
+func userRev(t *testing.T, client *kivik.Client, name string) string {
+	t.Helper()
+	rev, err := client.DB(auth.UsersDB).GetRev(context.Background(), kivik.UserPrefix+name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rev
+}
+
+func Test_usersDB(t *testing.T) {
+	t.Parallel()
+
+	checkLogin := func(name, password string, wantOK bool) func(*testing.T, *kivik.Client) {
+		return func(t *testing.T, client *kivik.Client) {
+			t.Helper()
+			_, err := auth.NewUsersDBStore(client).Validate(context.Background(), name, password)
+			if wantOK && err != nil {
+				t.Errorf("Expected login to succeed: %s", err)
+			}
+			if !wantOK && err == nil {
+				t.Error("Expected login to fail")
+			}
+		}
+	}
+
+	tests := serverTests{
+		usersDBTest(t, serverTest{
+			name:       "pbkdf2 sha1 login",
+			method:     http.MethodPost,
+			path:       "/_session",
+			headers:    map[string]string{"Content-Type": "application/json"},
+			body:       strings.NewReader(`{"name":"sam","password":"abc123"}`),
+			wantStatus: http.StatusOK,
+			wantJSON: map[string]interface{}{
+				"ok":    true,
+				"name":  userSam,
+				"roles": []string{roleFoo},
+			},
+		}),
+		usersDBTest(t, serverTest{
+			name:       "pbkdf2 sha256 login",
+			method:     http.MethodPost,
+			path:       "/_session",
+			headers:    map[string]string{"Content-Type": "application/json"},
+			body:       strings.NewReader(`{"name":"tess","password":"abc123"}`),
+			wantStatus: http.StatusOK,
+			wantJSON: map[string]interface{}{
+				"ok":    true,
+				"name":  userTess,
+				"roles": []string{},
+			},
+		}),
+		usersDBTest(t, serverTest{
+			name:       "simple login",
+			method:     http.MethodPost,
+			path:       "/_session",
+			headers:    map[string]string{"Content-Type": "application/json"},
+			body:       strings.NewReader(`{"name":"uma","password":"abc123"}`),
+			wantStatus: http.StatusOK,
+			wantJSON: map[string]interface{}{
+				"ok":    true,
+				"name":  userUma,
+				"roles": []string{},
+			},
+		}),
+		usersDBTest(t, serverTest{
+			name:       "wrong password",
+			method:     http.MethodPost,
+			path:       "/_session",
+			headers:    map[string]string{"Content-Type": "application/json"},
+			body:       strings.NewReader(`{"name":"sam","password":"wrong"}`),
+			wantStatus: http.StatusUnauthorized,
+			wantJSON: map[string]interface{}{
+				"error":  "unauthorized",
+				"reason": "Invalid username or password",
+			},
+		}),
+		usersDBTest(t, serverTest{
+			name:       "memory store users still work",
+			method:     http.MethodPost,
+			path:       "/_session",
+			headers:    map[string]string{"Content-Type": "application/json"},
+			body:       strings.NewReader(`{"name":"admin","password":"abc123"}`),
+			wantStatus: http.StatusOK,
+			wantJSON: map[string]interface{}{
+				"ok":    true,
+				"name":  userAdmin,
+				"roles": []string{auth.RoleAdmin},
+			},
+		}),
+		usersDBTest(t, serverTest{
+			name:       "anonymous signup",
+			method:     http.MethodPut,
+			path:       "/_users/org.couchdb.user:vic",
+			body:       strings.NewReader(`{"name":"vic","type":"user","roles":[],"password":"secret"}`),
+			wantStatus: http.StatusCreated,
+			target: &struct {
+				ID  string `json:"id" validate:"required,eq=org.couchdb.user:vic"`
+				Rev string `json:"rev" validate:"required,startswith=1-"`
+				OK  bool   `json:"ok" validate:"required,eq=true"`
+			}{},
+			check: func(t *testing.T, client *kivik.Client) { //nolint:thelper // not a helper
+				var doc map[string]interface{}
+				if err := client.DB(auth.UsersDB).Get(context.Background(), "org.couchdb.user:vic").ScanDoc(&doc); err != nil {
+					t.Fatal(err)
+				}
+				if _, ok := doc["password"]; ok {
+					t.Error("plaintext password was stored")
+				}
+				if doc["password_scheme"] != "pbkdf2" || doc["pbkdf2_prf"] != "sha256" || doc["iterations"] != float64(10) {
+					t.Errorf("Unexpected password fields: %v", doc)
+				}
+				checkLogin("vic", "secret", true)(t, client)
+			},
+		}),
+		usersDBTest(t, serverTest{
+			name:       "anonymous signup with roles",
+			method:     http.MethodPut,
+			path:       "/_users/org.couchdb.user:vic",
+			body:       strings.NewReader(`{"name":"vic","type":"user","roles":["_admin"],"password":"secret"}`),
+			wantStatus: http.StatusForbidden,
+			wantJSON: map[string]interface{}{
+				"error":  "forbidden",
+				"reason": "Only _admin may set roles",
+			},
+		}),
+		usersDBTest(t, serverTest{
+			name:       "id does not match name",
+			method:     http.MethodPut,
+			path:       "/_users/org.couchdb.user:vic",
+			body:       strings.NewReader(`{"name":"victor","type":"user","roles":[]}`),
+			wantStatus: http.StatusForbidden,
+			wantJSON: map[string]interface{}{
+				"error":  "forbidden",
+				"reason": "Doc ID must be of the form org.couchdb.user:name",
+			},
+		}),
+		usersDBTest(t, serverTest{
+			name:       "wrong type",
+			method:     http.MethodPost,
+			path:       "/_users",
+			body:       strings.NewReader(`{"_id":"org.couchdb.user:vic","name":"vic","roles":[]}`),
+			wantStatus: http.StatusForbidden,
+			wantJSON: map[string]interface{}{
+				"error":  "forbidden",
+				"reason": "doc.type must be user",
+			},
+		}),
+		func() serverTest {
+			tt := usersDBTest(t, serverTest{
+				name:       "change own password",
+				method:     http.MethodPut,
+				headers:    map[string]string{"Authorization": basicAuth(userTess)},
+				wantStatus: http.StatusCreated,
+				target: &struct {
+					Rev string `json:"rev" validate:"required,startswith=2-"`
+				}{},
+				check: func(t *testing.T, client *kivik.Client) { //nolint:thelper // not a helper
+					checkLogin(userTess, "newpass", true)(t, client)
+					checkLogin(userTess, testPassword, false)(t, client)
+				},
+			})
+			tt.path = "/_users/org.couchdb.user:tess?rev=" + userRev(t, tt.client, userTess)
+			tt.body = strings.NewReader(`{"name":"tess","type":"user","roles":[],"password":"newpass"}`)
+			return tt
+		}(),
+		func() serverTest {
+			tt := usersDBTest(t, serverTest{
+				name:       "change own roles",
+				method:     http.MethodPut,
+				headers:    map[string]string{"Authorization": basicAuth(userTess)},
+				wantStatus: http.StatusForbidden,
+				wantJSON: map[string]interface{}{
+					"error":  "forbidden",
+					"reason": "Only _admin may edit roles",
+				},
+			})
+			tt.path = "/_users/org.couchdb.user:tess?rev=" + userRev(t, tt.client, userTess)
+			tt.body = strings.NewReader(`{"name":"tess","type":"user","roles":["foo"]}`)
+			return tt
+		}(),
+		func() serverTest {
+			tt := usersDBTest(t, serverTest{
+				name:       "update another user",
+				method:     http.MethodPut,
+				headers:    map[string]string{"Authorization": basicAuth(userTess)},
+				wantStatus: http.StatusForbidden,
+				wantJSON: map[string]interface{}{
+					"error":  "forbidden",
+					"reason": "You may only update your own user document.",
+				},
+			})
+			tt.path = "/_users/org.couchdb.user:sam?rev=" + userRev(t, tt.client, userSam)
+			tt.body = strings.NewReader(`{"name":"sam","type":"user","roles":[],"password":"hijacked"}`)
+			return tt
+		}(),
+		func() serverTest {
+			tt := usersDBTest(t, serverTest{
+				name:       "admin sets roles",
+				method:     http.MethodPut,
+				authUser:   userAdmin,
+				wantStatus: http.StatusCreated,
+				target: &struct {
+					Rev string `json:"rev" validate:"required,startswith=2-"`
+				}{},
+				check: func(t *testing.T, client *kivik.Client) { //nolint:thelper // not a helper
+					user, err := auth.NewUsersDBStore(client).UserCtx(context.Background(), userSam)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if !user.HasRole(roleBar) {
+						t.Errorf("Unexpected roles: %v", user.Roles)
+					}
+					checkLogin(userSam, testPassword, false)(t, client)
+				},
+			})
+			tt.path = "/_users/org.couchdb.user:sam?rev=" + userRev(t, tt.client, userSam)
+			tt.body = strings.NewReader(`{"name":"sam","type":"user","roles":["bar"]}`)
+			return tt
+		}(),
+		usersDBTest(t, serverTest{
+			name:       "read own user doc",
+			method:     http.MethodGet,
+			path:       "/_users/org.couchdb.user:tess",
+			headers:    map[string]string{"Authorization": basicAuth(userTess)},
+			wantStatus: http.StatusOK,
+			target: &struct {
+				ID         string `json:"_id" validate:"required,eq=org.couchdb.user:tess"`
+				DerivedKey string `json:"derived_key" validate:"required"`
+			}{},
+		}),
+		usersDBTest(t, serverTest{
+			name:       "read another user's doc",
+			method:     http.MethodGet,
+			path:       "/_users/org.couchdb.user:sam",
+			headers:    map[string]string{"Authorization": basicAuth(userTess)},
+			wantStatus: http.StatusNotFound,
+			wantJSON: map[string]interface{}{
+				"error":  "not_found",
+				"reason": "missing",
+			},
+		}),
+		usersDBTest(t, serverTest{
+			name:       "anonymous read of user doc",
+			method:     http.MethodGet,
+			path:       "/_users/org.couchdb.user:sam",
+			wantStatus: http.StatusNotFound,
+			wantJSON: map[string]interface{}{
+				"error":  "not_found",
+				"reason": "missing",
+			},
+		}),
+		usersDBTest(t, serverTest{
+			name:       "admin reads another user's doc",
+			method:     http.MethodGet,
+			path:       "/_users/org.couchdb.user:sam",
+			authUser:   userAdmin,
+			wantStatus: http.StatusOK,
+			target: &struct {
+				ID         string `json:"_id" validate:"required,eq=org.couchdb.user:sam"`
+				DerivedKey string `json:"derived_key" validate:"required"`
+			}{},
+		}),
+		usersDBTest(t, serverTest{
+			name:       "all docs with include_docs",
+			method:     http.MethodGet,
+			path:       "/_users/_all_docs?include_docs=true",
+			headers:    map[string]string{"Authorization": basicAuth(userTess)},
+			wantStatus: http.StatusForbidden,
+			wantJSON: map[string]interface{}{
+				"error":  "forbidden",
+				"reason": "Only admins may query the _users database.",
+			},
+		}),
+		usersDBTest(t, serverTest{
+			name:       "anonymous all docs",
+			method:     http.MethodPost,
+			path:       "/_users/_all_docs",
+			headers:    map[string]string{"Content-Type": "application/json"},
+			body:       strings.NewReader(`{"include_docs":true}`),
+			wantStatus: http.StatusForbidden,
+			wantJSON: map[string]interface{}{
+				"error":  "forbidden",
+				"reason": "Only admins may query the _users database.",
+			},
+		}),
+	}
+
+	tests.Run(t)
+}
@@ -43,11 +43,17 @@ func (s *Server) up() httpe.HandlerWithError {
 	})
 }
 
-// activeTasks returns a list of running tasks. For now it always returns an
-// empty list, as this server doesn't support running asynchronous tasks. But it
-// may be expanded in the future.
+// activeTasks returns a list of running tasks. If the backing driver doesn't
+// report its tasks, the list is always empty.
 func (s *Server) activeTasks() httpe.HandlerWithError {
-	return httpe.HandlerWithErrorFunc(func(w http.ResponseWriter, _ *http.Request) error {
-		return serveJSON(w, http.StatusOK, []interface{}{})
+	return httpe.HandlerWithErrorFunc(func(w http.ResponseWriter, r *http.Request) error {
+		tasks, err := s.client.ActiveTasks(r.Context(), options(r))
+		if kivik.HTTPStatus(err) == http.StatusNotImplemented {
+			return serveJSON(w, http.StatusOK, []interface{}{})
+		}
+		if err != nil {
+			return err
+		}
+		return serveJSON(w, http.StatusOK, tasks)
 	})
 }
@@ -29,12 +29,13 @@ import (
 
 	"github.com/go-kivik/kivik/v4/driver"
 	internal "github.com/go-kivik/kivik/v4/int/errors"
-	"github.com/go-kivik/kivik/x/sqlite/v4/js"
+	"github.com/go-kivik/kivik/v4/x/js"
 )
 
 const (
-	feedNormal   = "normal"
-	feedLongpoll = "longpoll"
+	feedNormal     = "normal"
+	feedLongpoll   = "longpoll"
+	feedContinuous = "continuous"
 )
 
 type normalChanges struct {
@@ -389,20 +390,30 @@ func (d *db) Changes(ctx context.Context, options driver.Options) (driver.Change
 	if err != nil {
 		return nil, err
 	}
-	if sinceNow && feed == feedLongpoll {
+	if feed == feedContinuous || (sinceNow && feed == feedLongpoll) {
 		attachments, err := opts.attachments()
 		if err != nil {
 			return nil, err
 		}
-		return d.newLongpollChanges(ctx, includeDocs, attachments)
+		if sinceNow {
+			seq, err := d.lastSeq(ctx)
+			if err != nil {
+				return nil, err
+			}
+			since = &seq
+		}
+		return d.newLongpollChanges(ctx, since, feed == feedContinuous, includeDocs, attachments)
 	}
 
 	return d.newNormalChanges(ctx, opts, since, lastSeq, sinceNow, feed)
 }
 
+// longpollChanges serves both the longpoll and continuous feeds. A longpoll
+// feed ends after the first change, a continuous feed only when closed.
 type longpollChanges struct {
 	stmt        *sql.Stmt
 	since       uint64
+	continuous  bool
 	includeDocs bool
 	attachments bool
 	lastSeq     string
@@ -418,12 +429,7 @@ type longpollChange struct {
 
 var _ driver.Changes = (*longpollChanges)(nil)
 
-func (d *db) newLongpollChanges(ctx context.Context, includeDocs, attachments bool) (*longpollChanges, error) {
-	since, err := d.lastSeq(ctx)
-	if err != nil {
-		return nil, err
-	}
-
+func (d *db) newLongpollChanges(ctx context.Context, since *uint64, continuous, includeDocs, attachments bool) (*longpollChanges, error) {
 	stmt, err := d.db.PrepareContext(ctx, d.query(`
 		SELECT
 			CASE WHEN row_number = 1 THEN id END AS id,
@@ -476,13 +482,16 @@ func (d *db) newLongpollChanges(ctx context.Context, includeDocs, attachments bo
 	changes := make(chan longpollChange)
 	c := &longpollChanges{
 		stmt:        stmt,
-		since:       since,
+		continuous:  continuous,
 		attachments: attachments,
 		includeDocs: includeDocs,
 		ctx:         ctx,
 		cancel:      cancel,
 		changes:     changes,
 	}
+	if since != nil {
+		c.since = *since
+	}
 
 	go c.watch(changes)
 
@@ -490,16 +499,36 @@ func (d *db) newLongpollChanges(ctx context.Context, includeDocs, attachments bo
 }
 
 // watch runs in a loop until either the context is cancelled, or a change is
-// detected.
+// detected. For a continuous feed, it keeps watching after each change.
 func (c *longpollChanges) watch(changes chan<- longpollChange) {
 	defer close(changes)
+	defer c.stmt.Close()
 
 	bo := backoff.NewExponentialBackOff()
 	bo.InitialInterval = 50 * time.Millisecond
 	bo.MaxInterval = 3 * time.Minute
 	bo.MaxElapsedTime = 0
 
-	err := backoff.Retry(func() error {
+	for {
+		err := backoff.Retry(c.next(changes), bo)
+		if err != nil {
+			select {
+			case changes <- longpollChange{err: err}:
+			case <-c.ctx.Done():
+			}
+			return
+		}
+		if !c.continuous {
+			return
+		}
+		bo.Reset()
+	}
+}
+
+// next returns a function which polls for the next change after c.since, and
+// sends it to changes.
+func (c *longpollChanges) next(changes chan<- longpollChange) func() error {
+	return func() error {
 		rows, err := c.stmt.QueryContext(c.ctx, c.since, c.attachments, c.includeDocs)
 		if err != nil {
 			return backoff.Permanent(err)
@@ -561,6 +590,11 @@ func (c *longpollChanges) watch(changes chan<- longpollChange) {
 
 		change.Changes = driver.ChangedRevs{rev}
 		c.lastSeq = change.Seq
+		seq, err := strconv.ParseUint(change.Seq, 10, 64)
+		if err != nil {
+			return backoff.Permanent(err)
+		}
+		c.since = seq
 
 		if doc != nil {
 			toMerge := fullDoc{
@@ -573,11 +607,12 @@ func (c *longpollChanges) watch(changes chan<- longpollChange) {
 			change.Doc = toMerge.toRaw()
 		}
 
-		changes <- longpollChange{change: &change}
-		return nil
-	}, bo)
-	if err != nil {
-		changes <- longpollChange{err: err}
+		select {
+		case changes <- longpollChange{change: &change}:
+			return nil
+		case <-c.ctx.Done():
+			return backoff.Permanent(c.ctx.Err())
+		}
 	}
 }
 
@@ -598,6 +633,7 @@ func (c *longpollChanges) Next(change *driver.Change) error {
 }
 
 func (c *longpollChanges) Close() error {
+	c.cancel()
 	return nil
 }
 
@@ -106,7 +106,7 @@ func TestDBChanges(t *testing.T) {
 	})
 	tests.Add("invalid feed type", test{
 		options:    kivik.Param("feed", "invalid"),
-		wantErr:    "supported `feed` types: normal, longpoll",
+		wantErr:    "supported `feed` types: normal, longpoll, continuous",
 		wantStatus: http.StatusBadRequest,
 	})
 	tests.Add("since=1", func(t *testing.T) interface{} {
@@ -530,7 +530,7 @@ func TestDBChanges(t *testing.T) {
 				"filter": "foo/bar",
 			}),
 			wantStatus: http.StatusInternalServerError,
-			wantErr:    `failed to compile filter function: SyntaxError: SyntaxError: \(anonymous\): Line 1:43 Unexpected end of input \(and 1 more errors\)`,
+			wantErr:    `compilation_error: SyntaxError: SyntaxError: \(anonymous\): Line 1:43 Unexpected end of input \(and 1 more errors\)`,
 		}
 	})
 	tests.Add("filter function throws an exception", func(t *testing.T) any {
@@ -857,6 +857,50 @@ loop:
 	}
 }
 
+func TestDBChanges_continuous(t *testing.T) {
+	t.Parallel()
+	db := newDB(t)
+
+	rev1 := db.tPut("doc1", map[string]string{"foo": "bar"})
+
+	feed, err := db.Changes(context.Background(), kivik.Param("feed", "continuous"))
+	if err != nil {
+		t.Fatalf("Failed to start changes feed: %s", err)
+	}
+	t.Cleanup(func() {
+		_ = feed.Close()
+	})
+
+	// Changes made after the feed was started must be delivered too.
+	rev2 := db.tPut("doc2", map[string]string{"foo": "bar"})
+	rev3 := db.tDelete("doc1", kivik.Rev(rev1))
+
+	var got []driver.Change
+	for len(got) < 3 {
+		change := driver.Change{}
+		if err := feed.Next(&change); err != nil {
+			t.Fatalf("iteration failed: %s", err)
+		}
+		got = append(got, change)
+	}
+
+	wantChanges := []driver.Change{
+		{ID: "doc1", Seq: "1", Changes: driver.ChangedRevs{rev1}},
+		{ID: "doc2", Seq: "2", Changes: driver.ChangedRevs{rev2}},
+		{ID: "doc1", Seq: "3", Deleted: true, Changes: driver.ChangedRevs{rev3}},
+	}
+	if d := cmp.Diff(wantChanges, got); d != "" {
+		t.Errorf("Unexpected changes:\n%s", d)
+	}
+
+	if err := feed.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := feed.Next(&driver.Change{}); !testy.ErrorMatches("context canceled", err) {
+		t.Errorf("Unexpected error after Close: %s", err)
+	}
+}
+
 func TestDBChanges_longpoll_include_docs(t *testing.T) {
 	t.Parallel()
 	db := newDB(t)
@@ -1198,7 +1242,7 @@ func Test_longpoll_changes_query(t *testing.T) {
 
 	d := newDB(t)
 
-	changes, err := d.DB.(*db).newLongpollChanges(context.Background(), true, false)
+	changes, err := d.DB.(*db).newLongpollChanges(context.Background(), nil, false, true, false)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -1259,7 +1303,7 @@ func Test_longpoll_changes_query_without_docs(t *testing.T) {
 
 	d := newDB(t)
 
-	changes, err := d.DB.(*db).newLongpollChanges(context.Background(), false, false)
+	changes, err := d.DB.(*db).newLongpollChanges(context.Background(), nil, false, false, false)
 	if err != nil {
 		t.Fatal(err)
 	}
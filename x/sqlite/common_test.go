@@ -40,8 +40,11 @@ type DB interface {
 	driver.RevGetter
 	driver.LocalDocer
 	driver.DesignDocer
+	driver.DesignDocInfoer
 	driver.DocCreator
 	driver.Finder
+	driver.RevsLimiter
+	driver.PurgedInfosLimiter
 }
 
 type testDB struct {
@@ -67,6 +70,17 @@ func (tdb *testDB) tPut(docID string, doc interface{}, options ...driver.Options
 	return rev
 }
 
+func (tdb *testDB) tCreateIndex(ddoc, name, index string, options ...driver.Options) {
+	tdb.t.Helper()
+	opt := driver.Options(mock.NilOption)
+	if len(options) > 0 {
+		opt = options[0]
+	}
+	if err := tdb.CreateIndex(context.Background(), ddoc, name, index, opt); err != nil {
+		tdb.t.Fatalf("Failed to create index: %s", err)
+	}
+}
+
 func (tdb *testDB) tDelete(docID string, options ...driver.Options) string { //nolint:unparam
 	tdb.t.Helper()
 	opt := driver.Options(mock.NilOption)
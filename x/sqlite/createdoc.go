@@ -39,6 +39,10 @@ func (d *db) CreateDoc(ctx context.Context, doc interface{}, _ driver.Options) (
 	}
 	defer tx.Rollback()
 
+	if err := d.checkDocID(ctx, tx, data.ID); err != nil {
+		return "", "", err
+	}
+
 	var exists bool
 	err = tx.QueryRowContext(ctx, d.query(`
 		SELECT EXISTS (
@@ -15,7 +15,6 @@ package sqlite
 import (
 	"context"
 	"database/sql"
-	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -54,44 +53,71 @@ func (d *db) Ping(ctx context.Context) error {
 
 /* -- stub methods -- */
 
-func (db) Stats(context.Context) (*driver.DBStats, error) {
-	return nil, errors.New("not implemented")
-}
-
-func (db) Compact(context.Context) error {
-	return errors.New("not implemented")
-}
-
-func (db) CompactView(context.Context, string) error {
-	return errors.New("not implemented")
-}
+var errNotImplemented = &internal.Error{Status: http.StatusNotImplemented, Message: "not implemented"}
 
-func (db) ViewCleanup(context.Context) error {
-	return errors.New("not implemented")
-}
-
-func (db) BulkDocs(context.Context, []interface{}, driver.Options) ([]driver.BulkResult, error) {
-	return nil, errors.New("not implemented")
-}
+// Compact enforces the revs limit on every document, and the purged infos
+// limit, then removes attachments no longer referenced by any revision.
+func (d *db) Compact(ctx context.Context) error {
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
 
-func (db) Copy(context.Context, string, string, driver.Options) (string, error) {
-	return "", errors.New("not implemented")
-}
+	limit, err := d.setting(ctx, tx, settingRevsLimit, defaultRevsLimit)
+	if err != nil {
+		return err
+	}
+	rows, err := tx.QueryContext(ctx, d.query(`
+		SELECT id
+		FROM {{ .Revs }}
+		GROUP BY id
+		HAVING MAX(rev) > $1
+	`), limit)
+	if err != nil {
+		return d.errDatabaseNotFound(err)
+	}
+	defer rows.Close()
+	var docIDs []string
+	for rows.Next() {
+		var docID string
+		if err := rows.Scan(&docID); err != nil {
+			return err
+		}
+		docIDs = append(docIDs, docID)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	_ = rows.Close()
 
-func (db) CreateIndex(context.Context, string, string, interface{}, driver.Options) error {
-	return errors.New("not implemented")
+	for _, docID := range docIDs {
+		if err := d.pruneRevs(ctx, tx, docID); err != nil {
+			return err
+		}
+	}
+	if err := d.trimPurgedInfos(ctx, tx); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, d.query(`
+		DELETE FROM {{ .Attachments }}
+		WHERE pk NOT IN (SELECT pk FROM {{ .AttachmentsBridge }})
+	`)); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
-func (db) GetIndexes(context.Context, driver.Options) ([]driver.Index, error) {
-	return nil, errors.New("not implemented")
+func (db) CompactView(context.Context, string) error {
+	return errNotImplemented
 }
 
-func (db) DeleteIndex(context.Context, string, string, driver.Options) error {
-	return errors.New("not implemented")
+func (db) ViewCleanup(context.Context) error {
+	return errNotImplemented
 }
 
 func (db) Explain(context.Context, interface{}, driver.Options) (*driver.QueryPlan, error) {
-	return nil, errors.New("not implemented")
+	return nil, errNotImplemented
 }
 
 // errDatabaseNotFound converts a sqlite "no such table"  error into a kivik
@@ -211,6 +211,22 @@ func Test_db_not_found(t *testing.T) {
 			return err
 		},
 	})
+	tests.Add("Compact", test{
+		call: func(d *db) error {
+			return d.Compact(context.Background())
+		},
+	})
+	tests.Add("RevsLimit", test{
+		call: func(d *db) error {
+			_, err := d.RevsLimit(context.Background())
+			return err
+		},
+	})
+	tests.Add("SetRevsLimit", test{
+		call: func(d *db) error {
+			return d.SetRevsLimit(context.Background(), 10)
+		},
+	})
 	tests.Add("RevsDiff", test{
 		call: func(d *db) error {
 			_, err := d.RevsDiff(context.Background(), map[string][]string{"doc": {"1-x"}})
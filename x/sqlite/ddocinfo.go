@@ -0,0 +1,131 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package sqlite
+
+import (
+	"context"
+	"crypto/md5"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-kivik/kivik/v4/driver"
+	internal "github.com/go-kivik/kivik/v4/int/errors"
+)
+
+var _ driver.DesignDocInfoer = (*db)(nil)
+
+// DesignDocInfo reports on the map tables of the design document's winning
+// revision. UpdateSeq is the lowest last_seq of its map functions, as a view
+// is only brought up to date when it is queried. Sizes are taken from SQLite's
+// dbstat table: DiskSize counts the pages used by the map tables and their
+// indexes, ActiveSize the payload stored in them, and ExternalSize the length
+// of the stored ids, keys and values.
+func (d *db) DesignDocInfo(ctx context.Context, ddoc string, _ driver.Options) (*driver.DesignDocInfo, error) {
+	docID := "_design/" + ddoc
+	var (
+		rev     revision
+		deleted bool
+	)
+	// The winning revision is the highest non-deleted leaf, as with Get. Only
+	// if every leaf is deleted is the design document reported as deleted.
+	err := d.db.QueryRowContext(ctx, d.query(`
+		SELECT leaf.rev, leaf.rev_id, doc.deleted
+		FROM {{ .Revs }} AS leaf
+		LEFT JOIN {{ .Revs }} AS child ON child.id = leaf.id AND child.parent_rev = leaf.rev AND child.parent_rev_id = leaf.rev_id
+		JOIN {{ .Docs }} AS doc ON doc.id = leaf.id AND doc.rev = leaf.rev AND doc.rev_id = leaf.rev_id
+		WHERE leaf.id = $1
+			AND child.id IS NULL
+		ORDER BY doc.deleted, leaf.rev DESC, leaf.rev_id DESC
+		LIMIT 1
+	`), docID).Scan(&rev.rev, &rev.id, &deleted)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, &internal.Error{Status: http.StatusNotFound, Message: "missing"}
+	case err != nil:
+		return nil, d.errDatabaseNotFound(err)
+	case deleted:
+		return nil, &internal.Error{Status: http.StatusNotFound, Message: "deleted"}
+	}
+
+	rows, err := d.db.QueryContext(ctx, d.query(`
+		SELECT func_type, func_name, func_body, language, COALESCE(last_seq, 0)
+		FROM {{ .Design }}
+		WHERE id = $1 AND rev = $2 AND rev_id = $3
+			AND func_type IN ('map', 'reduce')
+		ORDER BY func_name, func_type
+	`), docID, rev.rev, rev.id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	info := &driver.DesignDocInfo{
+		Name:     ddoc,
+		Language: "javascript",
+	}
+	sig := md5.New()
+	var views []string
+	updateSeq := -1
+	for rows.Next() {
+		var (
+			funcType, name, body, language string
+			lastSeq                        int
+		)
+		if err := rows.Scan(&funcType, &name, &body, &language, &lastSeq); err != nil {
+			return nil, err
+		}
+		info.Language = language
+		_, _ = fmt.Fprintf(sig, "%s\x00%s\x00%s\x00", funcType, name, body)
+		if funcType != "map" {
+			continue
+		}
+		views = append(views, name)
+		if updateSeq < 0 || lastSeq < updateSeq {
+			updateSeq = lastSeq
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if updateSeq < 0 {
+		updateSeq = 0
+	}
+	info.Signature = hex.EncodeToString(sig.Sum(nil))
+	info.UpdateSeq = strconv.Itoa(updateSeq)
+
+	for _, view := range views {
+		var disk, active, external int64
+		table, _ := strconv.Unquote(d.ddocQuery(docID, view, rev.String(), "{{ .Map }}"))
+		index, _ := strconv.Unquote(d.ddocQuery(docID, view, rev.String(), "{{ .IndexMap }}"))
+		err := d.db.QueryRowContext(ctx, d.ddocQuery(docID, view, rev.String(), `
+			SELECT
+				(SELECT COALESCE(SUM(pgsize), 0) FROM dbstat WHERE name IN ($1, $2)),
+				(SELECT COALESCE(SUM(payload), 0) FROM dbstat WHERE name IN ($1, $2)),
+				(
+					SELECT COALESCE(SUM(LENGTH(id) + COALESCE(LENGTH(key), 0) + COALESCE(LENGTH(value), 0)), 0)
+					FROM {{ .Map }}
+				)
+		`), table, index).Scan(&disk, &active, &external)
+		if err != nil {
+			return nil, err
+		}
+		info.DiskSize += disk
+		info.ActiveSize += active
+		info.ExternalSize += external
+	}
+	return info, nil
+}
@@ -0,0 +1,199 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+//go:build !js
+
+package sqlite
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"gitlab.com/flimzy/testy"
+
+	"github.com/go-kivik/kivik/v4"
+	"github.com/go-kivik/kivik/v4/driver"
+	"github.com/go-kivik/kivik/v4/int/mock"
+)
+
+func TestDBDesignDocInfo(t *testing.T) {
+	t.Parallel()
+	type test struct {
+		db   *testDB
+		ddoc string
+		// want is compared without its Signature and sizes.
+		want *driver.DesignDocInfo
+		// wantIndexed indicates that the map tables contain rows.
+		wantIndexed bool
+		wantErr     string
+		wantStatus  int
+	}
+	view := map[string]interface{}{
+		"views": map[string]interface{}{
+			"bar": map[string]string{
+				"map": `function(doc) { emit(doc._id, null); }`,
+			},
+		},
+	}
+	tests := testy.NewTable()
+	tests.Add("missing ddoc", test{
+		ddoc:       "foo",
+		wantErr:    "missing",
+		wantStatus: http.StatusNotFound,
+	})
+	tests.Add("deleted ddoc", func(t *testing.T) interface{} {
+		d := newDB(t)
+		rev := d.tPut("_design/foo", view)
+		_ = d.tDelete("_design/foo", kivik.Rev(rev))
+
+		return test{
+			db:         d,
+			ddoc:       "foo",
+			wantErr:    "deleted",
+			wantStatus: http.StatusNotFound,
+		}
+	})
+	tests.Add("conflict with deleted highest leaf", func(t *testing.T) interface{} {
+		d := newDB(t)
+		_ = d.tPut("_design/foo", view)
+		_ = d.tPut("_design/foo", map[string]interface{}{
+			"_rev":     "3-deleted",
+			"_deleted": true,
+		}, kivik.Param("new_edits", false))
+
+		return test{
+			db:   d,
+			ddoc: "foo",
+			want: &driver.DesignDocInfo{
+				Name:      "foo",
+				Language:  "javascript",
+				UpdateSeq: "0",
+			},
+		}
+	})
+	tests.Add("no views", func(t *testing.T) interface{} {
+		d := newDB(t)
+		_ = d.tPut("_design/foo", map[string]interface{}{"updates": map[string]string{"update1": "function() {}"}})
+
+		return test{
+			db:   d,
+			ddoc: "foo",
+			want: &driver.DesignDocInfo{
+				Name:      "foo",
+				Language:  "javascript",
+				UpdateSeq: "0",
+			},
+		}
+	})
+	tests.Add("view not yet built", func(t *testing.T) interface{} {
+		d := newDB(t)
+		_ = d.tPut("_design/foo", view)
+		_ = d.tPut("a", map[string]string{"cat": "meow"})
+
+		return test{
+			db:   d,
+			ddoc: "foo",
+			want: &driver.DesignDocInfo{
+				Name:      "foo",
+				Language:  "javascript",
+				UpdateSeq: "0",
+			},
+		}
+	})
+	tests.Add("view built", func(t *testing.T) interface{} {
+		d := newDB(t)
+		_ = d.tPut("_design/foo", view)
+		_ = d.tPut("a", map[string]string{"cat": "meow"})
+		rows, err := d.Query(context.Background(), "foo", "bar", mock.NilOption)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = rows.Close()
+
+		return test{
+			db:   d,
+			ddoc: "foo",
+			want: &driver.DesignDocInfo{
+				Name:      "foo",
+				Language:  "javascript",
+				UpdateSeq: "2",
+			},
+			wantIndexed: true,
+		}
+	})
+
+	tests.Run(t, func(t *testing.T, tt test) {
+		t.Parallel()
+		db := tt.db
+		if db == nil {
+			db = newDB(t)
+		}
+		got, err := db.DesignDocInfo(context.Background(), tt.ddoc, mock.NilOption)
+		if !testy.ErrorMatches(tt.wantErr, err) {
+			t.Errorf("Unexpected error: %s", err)
+		}
+		if status := kivik.HTTPStatus(err); status != tt.wantStatus {
+			t.Errorf("Unexpected status: %d", status)
+		}
+		if err != nil {
+			return
+		}
+		if len(got.Signature) != 32 {
+			t.Errorf("Unexpected signature: %q", got.Signature)
+		}
+		if tt.wantIndexed != (got.ExternalSize > 0) {
+			t.Errorf("Unexpected external size: %d", got.ExternalSize)
+		}
+		if tt.wantIndexed && (got.ActiveSize <= 0 || got.DiskSize < got.ActiveSize) {
+			t.Errorf("Unexpected sizes: disk=%d, active=%d", got.DiskSize, got.ActiveSize)
+		}
+		got.Signature = ""
+		got.DiskSize, got.ActiveSize, got.ExternalSize = 0, 0, 0
+		if d := cmp.Diff(tt.want, got); d != "" {
+			t.Error(d)
+		}
+	})
+}
+
+func TestDBDesignDocInfoSignature(t *testing.T) {
+	t.Parallel()
+	d := newDB(t)
+	signature := func() string {
+		t.Helper()
+		info, err := d.DesignDocInfo(context.Background(), "foo", mock.NilOption)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return info.Signature
+	}
+	ddoc := func(mapFunc, other string) map[string]interface{} {
+		return map[string]interface{}{
+			"views": map[string]interface{}{
+				"bar": map[string]string{"map": mapFunc},
+			},
+			"other": other,
+		}
+	}
+
+	rev := d.tPut("_design/foo", ddoc(`function(doc) { emit(doc._id, null); }`, "a"))
+	first := signature()
+	rev = d.tPut("_design/foo", ddoc(`function(doc) { emit(doc._id, null); }`, "b"), kivik.Rev(rev))
+	if sig := signature(); sig != first {
+		t.Errorf("Signature changed without a view change: %s != %s", sig, first)
+	}
+	_ = d.tPut("_design/foo", ddoc(`function(doc) { emit(doc._id, 1); }`, "b"), kivik.Rev(rev))
+	if sig := signature(); sig == first {
+		t.Errorf("Signature unchanged after view change: %s", sig)
+	}
+}
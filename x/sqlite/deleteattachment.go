@@ -16,6 +16,7 @@ import (
 	"context"
 	"net/http"
 
+	"github.com/go-kivik/kivik/v4"
 	"github.com/go-kivik/kivik/v4/driver"
 	internal "github.com/go-kivik/kivik/v4/int/errors"
 )
@@ -44,6 +45,11 @@ func (d *db) DeleteAttachment(ctx context.Context, docID, filename string, optio
 	}
 
 	data.MD5sum, err = d.isLeafRev(ctx, tx, docID, curRev.rev, curRev.id)
+	if kivik.HTTPStatus(err) == http.StatusNotFound {
+		// CouchDB reports a missing document as a conflict, since the
+		// provided rev can't be a leaf.
+		return "", &internal.Error{Status: http.StatusConflict, Message: "document update conflict"}
+	}
 	if err != nil {
 		return "", d.errDatabaseNotFound(err)
 	}
@@ -47,8 +47,8 @@ func TestDBDeleteAttachment(t *testing.T) {
 		docID:      "foo",
 		filename:   "foo.txt",
 		options:    kivik.Rev("1-9bb58f26192e4ba00f01e2e7b136bbd8"),
-		wantErr:    "document not found",
-		wantStatus: http.StatusNotFound,
+		wantErr:    "document update conflict",
+		wantStatus: http.StatusConflict,
 	})
 	tests.Add("doc exists, but no rev provided", func(t *testing.T) interface{} {
 		db := newDB(t)
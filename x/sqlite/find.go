@@ -14,15 +14,50 @@ package sqlite
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 
 	"github.com/go-kivik/kivik/v4/driver"
 )
 
-func (d *db) Find(ctx context.Context, query interface{}, _ driver.Options) (driver.Rows, error) {
+// indexSpec is the use_index field of a _find query, naming a design document
+// and, optionally, an index within it.
+type indexSpec struct {
+	ddoc  string
+	index string
+}
+
+func (i *indexSpec) UnmarshalJSON(data []byte) error {
+	if data[0] == '"' {
+		return json.Unmarshal(data, &i.ddoc)
+	}
+	var values []string
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+	const maxValues = 2
+	if len(values) == 0 || len(values) > maxValues {
+		return errors.New("invalid index specification")
+	}
+	i.ddoc = values[0]
+	if len(values) == maxValues {
+		i.index = values[1]
+	}
+	return nil
+}
+
+func (d *db) Find(ctx context.Context, query interface{}, options driver.Options) (driver.Rows, error) {
 	vopts, err := findOptions(query)
 	if err != nil {
 		return nil, err
 	}
+	vopts.partition, err = d.partition(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.selectIndex(ctx, vopts); err != nil {
+		return nil, err
+	}
 
 	return d.queryBuiltinView(ctx, vopts)
 }
@@ -34,6 +34,10 @@ func TestFind(t *testing.T) {
 		want       []rowResult
 		wantStatus int
 		wantErr    string
+		// indexed is true if the query is answered by an index, and
+		// wantWarning overrides the warning expected otherwise.
+		indexed     bool
+		wantWarning string
 	}
 
 	tests := testy.NewTable()
@@ -75,6 +79,112 @@ func TestFind(t *testing.T) {
 			},
 		}
 	})
+	tests.Add("$text", func(t *testing.T) interface{} {
+		d := newDB(t)
+		d.tCreateIndex("search", "title", `{"fields":[{"title":"string"}]}`, kivik.Param("type", "text"))
+		rev := d.tPut("foo", map[string]string{"title": "The Quick Fox"})
+		_ = d.tPut("bar", map[string]string{"title": "A lazy dog"})
+
+		return test{
+			db:    d,
+			query: `{"selector":{"$text":"quick"}}`,
+			want: []rowResult{
+				{Doc: `{"_id":"foo","_rev":"` + rev + `","title":"The Quick Fox"}`},
+			},
+			indexed: true,
+		}
+	})
+	tests.Add("$text matches only indexed fields", func(t *testing.T) interface{} {
+		d := newDB(t)
+		d.tCreateIndex("search", "title", `{"fields":[{"title":"string"}]}`, kivik.Param("type", "text"))
+		rev := d.tPut("foo", map[string]string{"title": "The Quick Fox"})
+		_ = d.tPut("bar", map[string]string{"title": "A lazy dog", "body": "Not so quick"})
+
+		return test{
+			db:    d,
+			query: `{"selector":{"$text":"quick"}}`,
+			want: []rowResult{
+				{Doc: `{"_id":"foo","_rev":"` + rev + `","title":"The Quick Fox"}`},
+			},
+			indexed: true,
+		}
+	})
+	tests.Add("$text without text index", func(t *testing.T) interface{} {
+		d := newDB(t)
+		_ = d.tPut("foo", map[string]string{"title": "The Quick Fox"})
+
+		return test{
+			db:         d,
+			query:      `{"selector":{"$text":"quick"}}`,
+			wantStatus: http.StatusBadRequest,
+			wantErr:    "No text index exists for this query, try creating a text index.",
+		}
+	})
+	tests.Add("partial index named by use_index", func(t *testing.T) interface{} {
+		d := newDB(t)
+		d.tCreateIndex("live", "name", `{"fields":["name"],"partial_filter_selector":{"deleted":{"$ne":true}}}`)
+		rev := d.tPut("foo", map[string]interface{}{"name": "foo", "deleted": false})
+		_ = d.tPut("bar", map[string]interface{}{"name": "bar", "deleted": true})
+
+		return test{
+			db:    d,
+			query: `{"selector":{"name":{"$gt":null}},"use_index":["live","name"]}`,
+			want: []rowResult{
+				{Doc: `{"_id":"foo","_rev":"` + rev + `","deleted":false,"name":"foo"}`},
+			},
+			indexed: true,
+		}
+	})
+	tests.Add("partial index not used without use_index", func(t *testing.T) interface{} {
+		d := newDB(t)
+		d.tCreateIndex("live", "name", `{"fields":["name"],"partial_filter_selector":{"deleted":{"$ne":true}}}`)
+		rev := d.tPut("foo", map[string]interface{}{"name": "foo"})
+		rev2 := d.tPut("bar", map[string]interface{}{"name": "bar", "deleted": true})
+
+		return test{
+			db:    d,
+			query: `{"selector":{"name":{"$gt":null}}}`,
+			want: []rowResult{
+				{Doc: `{"_id":"bar","_rev":"` + rev2 + `","deleted":true,"name":"bar"}`},
+				{Doc: `{"_id":"foo","_rev":"` + rev + `","name":"foo"}`},
+			},
+		}
+	})
+	tests.Add("use_index not valid for query", func(t *testing.T) interface{} {
+		d := newDB(t)
+		d.tCreateIndex("live", "name", `{"fields":["name"],"partial_filter_selector":{"deleted":{"$ne":true}}}`)
+		rev := d.tPut("foo", map[string]interface{}{"age": 3.0, "deleted": true})
+
+		return test{
+			db:    d,
+			query: `{"selector":{"age":3},"use_index":"live"}`,
+			want: []rowResult{
+				{Doc: `{"_id":"foo","_rev":"` + rev + `","age":3,"deleted":true}`},
+			},
+			wantWarning: "live was not used because it is not a valid index for this query.\n" +
+				"No matching index found, create an index to optimize query time.",
+		}
+	})
+	tests.Add("json index excludes documents lacking its fields", func(t *testing.T) interface{} {
+		d := newDB(t)
+		d.tCreateIndex("byname", "name", `{"fields":["name","age"]}`)
+		rev := d.tPut("foo", map[string]interface{}{"name": "foo", "age": 3.0})
+		_ = d.tPut("bar", map[string]interface{}{"name": "bar"})
+
+		return test{
+			db:    d,
+			query: `{"selector":{"name":{"$gt":null},"age":{"$exists":true}},"use_index":"byname"}`,
+			want: []rowResult{
+				{Doc: `{"_id":"foo","_rev":"` + rev + `","age":3,"name":"foo"}`},
+			},
+			indexed: true,
+		}
+	})
+	tests.Add("invalid selector", test{
+		query:      `{"selector":{"foo":{"$mod":[0,1]}}}`,
+		wantStatus: http.StatusBadRequest,
+		wantErr:    `Bad argument for operator \$mod: \[0,1\]`,
+	})
 	tests.Add("skip", func(t *testing.T) interface{} {
 		d := newDB(t)
 		_ = d.tPut("foo", map[string]string{"foo": "bar"})
@@ -222,7 +332,6 @@ func TestFind(t *testing.T) {
 		- stable
 		- update
 		- stale
-		- use_index
 		- execution_stats -- Not currently supported by Kivik
 	*/
 
@@ -242,6 +351,13 @@ func TestFind(t *testing.T) {
 		if err != nil {
 			return
 		}
+		wantWarning := tt.wantWarning
+		if wantWarning == "" && !tt.indexed {
+			wantWarning = "No matching index found, create an index to optimize query time."
+		}
+		if warning := rows.(driver.RowsWarner).Warning(); warning != wantWarning {
+			t.Errorf("Unexpected warning: %s", warning)
+		}
 		checkRows(t, rows, tt.want)
 	})
 }
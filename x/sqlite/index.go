@@ -0,0 +1,583 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package sqlite
+
+import (
+	"context"
+	"crypto/sha1"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/go-kivik/kivik/v4"
+	"github.com/go-kivik/kivik/v4/driver"
+	internal "github.com/go-kivik/kivik/v4/int/errors"
+	"github.com/go-kivik/kivik/v4/x/mango"
+)
+
+// sortField is a single field of a json index definition.
+type sortField struct {
+	field string
+	desc  bool
+}
+
+func (f *sortField) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		return json.Unmarshal(data, &f.field)
+	}
+	var obj map[string]string
+	if err := json.Unmarshal(data, &obj); err != nil || len(obj) != 1 {
+		return errors.New("invalid sort field")
+	}
+	for field, dir := range obj {
+		switch dir {
+		case "asc":
+		case "desc":
+			f.desc = true
+		default:
+			return fmt.Errorf("invalid sort direction: %s", dir)
+		}
+		f.field = field
+	}
+	return nil
+}
+
+func (f sortField) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]string{f.field: f.dir()})
+}
+
+func (f sortField) dir() string {
+	if f.desc {
+		return "desc"
+	}
+	return "asc"
+}
+
+// textField is a single field of a text index definition, of the form
+// {"name": "type"}.
+type textField struct {
+	name string
+	typ  string
+}
+
+func (f *textField) UnmarshalJSON(data []byte) error {
+	var obj map[string]string
+	if err := json.Unmarshal(data, &obj); err != nil || len(obj) != 1 {
+		return errors.New("invalid text index field")
+	}
+	for name, typ := range obj {
+		switch typ {
+		case "string", "number", "boolean":
+		default:
+			return fmt.Errorf("invalid text index field type: %s", typ)
+		}
+		f.name, f.typ = name, typ
+	}
+	return nil
+}
+
+func (f textField) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]string{f.name: f.typ})
+}
+
+// jsonIndexDef is the definition of a json index.
+type jsonIndexDef struct {
+	Fields  []sortField     `json:"fields"`
+	Partial json.RawMessage `json:"partial_filter_selector,omitempty"`
+}
+
+// textIndexDef is the definition of a text index. With no fields, all string
+// values in the document are indexed.
+type textIndexDef struct {
+	Fields  []textField     `json:"fields,omitempty"`
+	Partial json.RawMessage `json:"partial_filter_selector,omitempty"`
+}
+
+// queryView is the representation of a json index in a design document.
+type queryView struct {
+	Map struct {
+		Fields  map[string]string `json:"fields"`
+		Partial json.RawMessage   `json:"partial_filter_selector,omitempty"`
+	} `json:"map"`
+	Reduce  string `json:"reduce"`
+	Options struct {
+		Def jsonIndexDef `json:"def"`
+	} `json:"options"`
+}
+
+// textIndex is the representation of a text index in a design document.
+type textIndex struct {
+	Index textIndexDef `json:"index"`
+}
+
+// queryDesignDoc is a design document with the "query" language, in which
+// Mango indexes are stored.
+type queryDesignDoc struct {
+	Language string                     `json:"language"`
+	Views    map[string]json.RawMessage `json:"views"`
+	Indexes  map[string]json.RawMessage `json:"indexes"`
+}
+
+// mangoIndex is a json or text index. As with CouchDB, json indexes are
+// stored as views in a design document with the "query" language, and text
+// indexes under the "indexes" key of the same design document.
+//
+// Queries are always answered by scanning all documents, so an index serves
+// only to limit the results of a _find query to the documents it contains.
+type mangoIndex struct {
+	ddoc       string // empty for the special _all_docs index
+	name       string
+	fields     []sortField
+	textFields []textField
+	// partialDef is the raw partial_filter_selector, and partial its parsed
+	// form. Documents which don't match it are excluded from the index.
+	partialDef json.RawMessage
+	partial    *mango.Selector
+	text       bool
+	special    bool
+}
+
+var allDocsIndex = &mangoIndex{
+	name:    "_all_docs",
+	fields:  []sortField{{field: "_id"}},
+	special: true,
+}
+
+func (i *mangoIndex) driverIndex() driver.Index {
+	def := map[string]interface{}{"fields": i.fields}
+	idx := driver.Index{
+		DesignDoc:  i.ddoc,
+		Name:       i.name,
+		Type:       "json",
+		Definition: def,
+	}
+	switch {
+	case i.special:
+		idx.Type = "special"
+	case i.text:
+		idx.Type = "text"
+		def["fields"] = i.textFields
+		if i.textFields == nil {
+			def["fields"] = []textField{}
+		}
+	}
+	if i.partialDef != nil {
+		def["partial_filter_selector"] = i.partialDef
+	}
+	return idx
+}
+
+// usable returns true if the json index can be used to answer a query with
+// the given field ranges. Documents lacking any indexed field are not in the
+// index, so the query must require all of them.
+func (i *mangoIndex) usable(ranges map[string]*mango.Range) bool {
+	if i.text || i.special {
+		return false
+	}
+	for _, f := range i.fields {
+		if _, ok := ranges[f.field]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// matches returns true if the index is the one named by use_index.
+func (i *mangoIndex) matches(spec indexSpec) bool {
+	return !i.special &&
+		strings.TrimPrefix(i.ddoc, "_design/") == strings.TrimPrefix(spec.ddoc, "_design/") &&
+		(spec.index == "" || spec.index == i.name)
+}
+
+// includes returns true if the document is in the index. For a text index,
+// the document must also contain one of terms in its indexed fields. A nil
+// index includes every document.
+func (i *mangoIndex) includes(doc map[string]interface{}, terms []string) bool {
+	if i == nil || i.special {
+		return true
+	}
+	if i.partial != nil && !i.partial.Match(doc) {
+		return false
+	}
+	if i.text {
+		return i.hasTerm(doc, terms)
+	}
+	for _, f := range i.fields {
+		if _, ok := fieldValue(doc, f.field); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// hasTerm returns true if any of the tokens found in the indexed fields of doc
+// is one of terms.
+func (i *mangoIndex) hasTerm(doc map[string]interface{}, terms []string) bool {
+	var values []string
+	if len(i.textFields) == 0 {
+		for field, value := range doc {
+			if !strings.HasPrefix(field, "_") {
+				values = append(values, mango.TextValues(value)...)
+			}
+		}
+	}
+	for _, f := range i.textFields {
+		if value, ok := fieldValue(doc, f.name); ok {
+			values = append(values, mango.TextValues(value)...)
+		}
+	}
+	for _, value := range values {
+		for _, token := range mango.Tokenize(value) {
+			for _, term := range terms {
+				if token == term {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// fieldValue returns the value of the named field, which may refer to a
+// nested field using dot notation.
+func fieldValue(doc map[string]interface{}, field string) (interface{}, bool) {
+	var value interface{} = doc
+	for _, part := range strings.Split(field, ".") {
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if value, ok = obj[part]; !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// parsePartial parses a partial_filter_selector, which may be empty.
+func parsePartial(raw json.RawMessage) (*mango.Selector, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	var sel mango.Selector
+	if err := json.Unmarshal(raw, &sel); err != nil {
+		return nil, err
+	}
+	return &sel, nil
+}
+
+// indexes returns the special _all_docs index, followed by all json and text
+// indexes, sorted by design document and name.
+func (d *db) indexes(ctx context.Context) ([]*mangoIndex, error) {
+	rows, err := d.db.QueryContext(ctx, d.query(leavesCTE+`
+		SELECT id, doc
+		FROM (
+			SELECT id, doc, ROW_NUMBER() OVER (PARTITION BY id ORDER BY rev DESC, rev_id DESC) AS rank
+			FROM leaves
+			WHERE id LIKE '_design/%'
+		)
+		WHERE rank = 1
+	`))
+	if err != nil {
+		return nil, d.errDatabaseNotFound(err)
+	}
+	defer rows.Close()
+
+	indexes := []*mangoIndex{allDocsIndex}
+	for rows.Next() {
+		var (
+			docID string
+			doc   []byte
+		)
+		if err := rows.Scan(&docID, &doc); err != nil {
+			return nil, err
+		}
+		var ddoc queryDesignDoc
+		if err := json.Unmarshal(doc, &ddoc); err != nil || ddoc.Language != "query" {
+			continue
+		}
+		for name, raw := range ddoc.Views {
+			var view queryView
+			if err := json.Unmarshal(raw, &view); err != nil || len(view.Options.Def.Fields) == 0 {
+				continue
+			}
+			partial, err := parsePartial(view.Options.Def.Partial)
+			if err != nil {
+				continue
+			}
+			indexes = append(indexes, &mangoIndex{
+				ddoc:       docID,
+				name:       name,
+				fields:     view.Options.Def.Fields,
+				partialDef: view.Options.Def.Partial,
+				partial:    partial,
+			})
+		}
+		for name, raw := range ddoc.Indexes {
+			var index textIndex
+			if err := json.Unmarshal(raw, &index); err != nil {
+				continue
+			}
+			partial, err := parsePartial(index.Index.Partial)
+			if err != nil {
+				continue
+			}
+			indexes = append(indexes, &mangoIndex{
+				ddoc:       docID,
+				name:       name,
+				textFields: index.Index.Fields,
+				partialDef: index.Index.Partial,
+				partial:    partial,
+				text:       true,
+			})
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	sort.Slice(indexes[1:], func(i, j int) bool {
+		a, b := indexes[i+1], indexes[j+1]
+		if a.ddoc != b.ddoc {
+			return a.ddoc < b.ddoc
+		}
+		return a.name < b.name
+	})
+	return indexes, nil
+}
+
+// selectIndex chooses the index used to answer a _find query. As with
+// CouchDB, partial indexes are only used when named by use_index, and $text
+// queries require a text index.
+func (d *db) selectIndex(ctx context.Context, vopts *viewOptions) error {
+	indexes, err := d.indexes(ctx)
+	if err != nil {
+		return err
+	}
+	useIndex := vopts.useIndex
+	if query, ok := vopts.selector.TextQuery(); ok {
+		for _, idx := range indexes {
+			if idx.text && (useIndex.ddoc == "" || idx.matches(useIndex)) {
+				vopts.index = idx
+				vopts.terms = mango.Tokenize(query)
+				return nil
+			}
+		}
+		return &internal.Error{Status: http.StatusBadRequest, Message: "No text index exists for this query, try creating a text index."}
+	}
+	ranges := vopts.selector.Ranges()
+	if useIndex.ddoc != "" {
+		for _, idx := range indexes {
+			if idx.matches(useIndex) && idx.usable(ranges) {
+				vopts.index = idx
+				return nil
+			}
+		}
+		name := useIndex.ddoc
+		if useIndex.index != "" {
+			name += ", " + useIndex.index
+		}
+		vopts.warnings = append(vopts.warnings, name+" was not used because it is not a valid index for this query.")
+	}
+	// Prefer the json index which covers the most fields.
+	for _, idx := range indexes[1:] {
+		if idx.partial == nil && idx.usable(ranges) && (vopts.index == nil || len(idx.fields) > len(vopts.index.fields)) {
+			vopts.index = idx
+		}
+	}
+	return nil
+}
+
+// parseIndexDef parses the index definition, into a jsonIndexDef or a
+// textIndexDef, depending on typ.
+func parseIndexDef(index interface{}, typ string) (interface{}, error) {
+	if index == nil {
+		if typ == "text" {
+			return textIndexDef{}, nil
+		}
+		return nil, &internal.Error{Status: http.StatusBadRequest, Message: "Missing required key: fields"}
+	}
+	var indexJSON []byte
+	switch t := index.(type) {
+	case string:
+		indexJSON = []byte(t)
+	case []byte:
+		indexJSON = t
+	case json.RawMessage:
+		indexJSON = t
+	default:
+		var err error
+		if indexJSON, err = json.Marshal(index); err != nil {
+			return nil, &internal.Error{Status: http.StatusBadRequest, Err: err}
+		}
+	}
+	invalid := func(err error) error {
+		return &internal.Error{Status: http.StatusBadRequest, Err: fmt.Errorf("invalid index definition: %w", err)}
+	}
+	switch typ {
+	case "text":
+		var def textIndexDef
+		if err := json.Unmarshal(indexJSON, &def); err != nil {
+			return nil, invalid(err)
+		}
+		if _, err := parsePartial(def.Partial); err != nil {
+			return nil, invalid(err)
+		}
+		return def, nil
+	case "", "json":
+		var def jsonIndexDef
+		if err := json.Unmarshal(indexJSON, &def); err != nil {
+			return nil, invalid(err)
+		}
+		if len(def.Fields) == 0 {
+			return nil, &internal.Error{Status: http.StatusBadRequest, Message: "Missing required key: fields"}
+		}
+		if _, err := parsePartial(def.Partial); err != nil {
+			return nil, invalid(err)
+		}
+		return def, nil
+	}
+	return nil, &internal.Error{Status: http.StatusBadRequest, Message: "Invalid index type: " + typ}
+}
+
+// designDoc returns the body and winning revision of the design document, or
+// a nil body if it does not exist.
+func (d *db) designDoc(ctx context.Context, docID string) (map[string]interface{}, string, error) {
+	var (
+		rev revision
+		doc []byte
+	)
+	err := d.db.QueryRowContext(ctx, d.query(leavesCTE+`
+		SELECT rev, rev_id, doc
+		FROM leaves
+		WHERE id = $1
+		ORDER BY rev DESC, rev_id DESC
+		LIMIT 1
+	`), docID).Scan(&rev.rev, &rev.id, &doc)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, "", nil
+	case err != nil:
+		return nil, "", d.errDatabaseNotFound(err)
+	}
+	body := map[string]interface{}{}
+	if err := json.Unmarshal(doc, &body); err != nil {
+		return nil, "", err
+	}
+	return body, rev.String(), nil
+}
+
+func (d *db) CreateIndex(ctx context.Context, ddoc, name string, index interface{}, options driver.Options) error {
+	typ, _ := newOpts(options)["type"].(string)
+	def, err := parseIndexDef(index, typ)
+	if err != nil {
+		return err
+	}
+	if ddoc == "" || name == "" {
+		var hashed interface{} = def
+		if jsonDef, ok := def.(jsonIndexDef); ok && jsonDef.Partial == nil {
+			// Plain json indexes are named for their fields alone.
+			hashed = jsonDef.Fields
+		}
+		defJSON, _ := json.Marshal(hashed)
+		if typ == "text" {
+			defJSON = append([]byte("text:"), defJSON...)
+		}
+		hash := fmt.Sprintf("%x", sha1.Sum(defJSON))
+		if ddoc == "" {
+			ddoc = hash
+		}
+		if name == "" {
+			name = hash
+		}
+	}
+	ddocID := "_design/" + strings.TrimPrefix(ddoc, "_design/")
+	doc, rev, err := d.designDoc(ctx, ddocID)
+	if err != nil {
+		return err
+	}
+	if doc == nil {
+		doc = map[string]interface{}{}
+	} else if lang, _ := doc["language"].(string); lang != "query" {
+		return &internal.Error{Status: http.StatusBadRequest, Message: ddocID + " is not a query design document"}
+	}
+	doc["language"] = "query"
+	if rev != "" {
+		doc["_rev"] = rev
+	}
+	switch t := def.(type) {
+	case jsonIndexDef:
+		view := queryView{Reduce: "_count"}
+		view.Map.Fields = make(map[string]string, len(t.Fields))
+		for _, f := range t.Fields {
+			view.Map.Fields[f.field] = f.dir()
+		}
+		view.Map.Partial = t.Partial
+		view.Options.Def = t
+		views, _ := doc["views"].(map[string]interface{})
+		if views == nil {
+			views = map[string]interface{}{}
+		}
+		views[name] = view
+		doc["views"] = views
+	case textIndexDef:
+		indexes, _ := doc["indexes"].(map[string]interface{})
+		if indexes == nil {
+			indexes = map[string]interface{}{}
+		}
+		indexes[name] = textIndex{Index: t}
+		doc["indexes"] = indexes
+	}
+	_, err = d.Put(ctx, ddocID, doc, kivik.Params(nil))
+	return err
+}
+
+func (d *db) GetIndexes(ctx context.Context, _ driver.Options) ([]driver.Index, error) {
+	indexes, err := d.indexes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]driver.Index, len(indexes))
+	for i, idx := range indexes {
+		result[i] = idx.driverIndex()
+	}
+	return result, nil
+}
+
+func (d *db) DeleteIndex(ctx context.Context, ddoc, name string, _ driver.Options) error {
+	ddocID := "_design/" + strings.TrimPrefix(ddoc, "_design/")
+	doc, rev, err := d.designDoc(ctx, ddocID)
+	if err != nil {
+		return err
+	}
+	notFound := &internal.Error{Status: http.StatusNotFound, Message: "Index not found"}
+	if doc == nil {
+		return notFound
+	}
+	views, _ := doc["views"].(map[string]interface{})
+	indexes, _ := doc["indexes"].(map[string]interface{})
+	if lang, _ := doc["language"].(string); lang != "query" || (views[name] == nil && indexes[name] == nil) {
+		return notFound
+	}
+	delete(views, name)
+	delete(indexes, name)
+	doc["_rev"] = rev
+	if len(views) == 0 && len(indexes) == 0 {
+		doc["_deleted"] = true
+	}
+	_, err = d.Put(ctx, ddocID, doc, kivik.Params(nil))
+	return err
+}
@@ -0,0 +1,201 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package sqlite
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"gitlab.com/flimzy/testy"
+
+	"github.com/go-kivik/kivik/v4"
+	internal "github.com/go-kivik/kivik/v4/int/errors"
+	"github.com/go-kivik/kivik/v4/int/mock"
+)
+
+func TestCreateIndex(t *testing.T) {
+	t.Parallel()
+	type test struct {
+		db         *testDB
+		index      interface{}
+		typ        string
+		wantStatus int
+		wantErr    string
+	}
+
+	tests := testy.NewTable()
+	tests.Add("valid", test{
+		index: `{"fields":["foo"]}`,
+	})
+	tests.Add("explicit direction", test{
+		index: map[string]interface{}{"fields": []interface{}{map[string]string{"foo": "asc"}}},
+	})
+	tests.Add("nil index", test{
+		wantStatus: http.StatusBadRequest,
+		wantErr:    "Missing required key: fields",
+	})
+	tests.Add("invalid JSON", test{
+		index:      `chicken`,
+		wantStatus: http.StatusBadRequest,
+		wantErr:    "invalid index definition: invalid character 'c' looking for beginning of value",
+	})
+	tests.Add("partial index", test{
+		index: `{"fields":["foo"],"partial_filter_selector":{"deleted":{"$ne":true}}}`,
+	})
+	tests.Add("invalid partial filter selector", test{
+		index:      `{"fields":["foo"],"partial_filter_selector":{"foo":{"$bogus":1}}}`,
+		wantStatus: http.StatusBadRequest,
+		wantErr:    "invalid index definition: Invalid operator: $bogus",
+	})
+	tests.Add("text index", test{
+		index: `{"fields":[{"title":"string"}]}`,
+		typ:   "text",
+	})
+	tests.Add("text index of all fields", test{
+		typ: "text",
+	})
+	tests.Add("invalid text field", test{
+		index:      `{"fields":["title"]}`,
+		typ:        "text",
+		wantStatus: http.StatusBadRequest,
+		wantErr:    "invalid index definition: invalid text index field",
+	})
+	tests.Add("invalid type", test{
+		index:      `{"fields":["foo"]}`,
+		typ:        "geo",
+		wantStatus: http.StatusBadRequest,
+		wantErr:    "Invalid index type: geo",
+	})
+	tests.Add("not a query design doc", func(t *testing.T) interface{} {
+		d := newDB(t)
+		_ = d.tPut("_design/foo", map[string]interface{}{"views": map[string]interface{}{}})
+
+		return test{
+			db:         d,
+			index:      `{"fields":["foo"]}`,
+			wantStatus: http.StatusBadRequest,
+			wantErr:    "_design/foo is not a query design document",
+		}
+	})
+
+	tests.Run(t, func(t *testing.T, tt test) {
+		t.Parallel()
+		db := tt.db
+		if db == nil {
+			db = newDB(t)
+		}
+		opts := map[string]interface{}{}
+		if tt.typ != "" {
+			opts["type"] = tt.typ
+		}
+		err := db.CreateIndex(context.Background(), "foo", "bar", tt.index, kivik.Params(opts))
+		if d := internal.StatusErrorDiff(tt.wantErr, tt.wantStatus, err); d != "" {
+			t.Error(d)
+		}
+	})
+}
+
+func TestGetIndexes(t *testing.T) {
+	t.Parallel()
+	d := newDB(t)
+	ctx := context.Background()
+	if err := d.CreateIndex(ctx, "foo", "bar", `{"fields":["foo",{"bar":"asc"}]}`, mock.NilOption); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.CreateIndex(ctx, "", "", `{"fields":["baz"]}`, mock.NilOption); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.CreateIndex(ctx, "foo", "live", `{"fields":["qux"],"partial_filter_selector":{"deleted":{"$ne":true}}}`, mock.NilOption); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.CreateIndex(ctx, "foo", "search", `{"fields":[{"title":"string"}]}`, kivik.Param("type", "text")); err != nil {
+		t.Fatal(err)
+	}
+	indexes, err := d.GetIndexes(ctx, mock.NilOption)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []interface{}{
+		map[string]interface{}{
+			"name": "_all_docs",
+			"type": "special",
+			"def":  map[string]interface{}{"fields": []interface{}{map[string]string{"_id": "asc"}}},
+		},
+		map[string]interface{}{
+			"ddoc": "_design/4d058bbf2ac1cba47fb777cdddcae3d5b2a20c43",
+			"name": "4d058bbf2ac1cba47fb777cdddcae3d5b2a20c43",
+			"type": "json",
+			"def":  map[string]interface{}{"fields": []interface{}{map[string]string{"baz": "asc"}}},
+		},
+		map[string]interface{}{
+			"ddoc": "_design/foo",
+			"name": "bar",
+			"type": "json",
+			"def": map[string]interface{}{"fields": []interface{}{
+				map[string]string{"foo": "asc"},
+				map[string]string{"bar": "asc"},
+			}},
+		},
+		map[string]interface{}{
+			"ddoc": "_design/foo",
+			"name": "live",
+			"type": "json",
+			"def": map[string]interface{}{
+				"fields":                  []interface{}{map[string]string{"qux": "asc"}},
+				"partial_filter_selector": map[string]interface{}{"deleted": map[string]interface{}{"$ne": true}},
+			},
+		},
+		map[string]interface{}{
+			"ddoc": "_design/foo",
+			"name": "search",
+			"type": "text",
+			"def":  map[string]interface{}{"fields": []interface{}{map[string]string{"title": "string"}}},
+		},
+	}
+	if d := testy.DiffAsJSON(want, indexes); d != nil {
+		t.Error(d)
+	}
+}
+
+func TestDeleteIndex(t *testing.T) {
+	t.Parallel()
+	d := newDB(t)
+	ctx := context.Background()
+	if err := d.CreateIndex(ctx, "foo", "bar", `{"fields":["foo"]}`, mock.NilOption); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.CreateIndex(ctx, "foo", "baz", `{"fields":["baz"]}`, mock.NilOption); err != nil {
+		t.Fatal(err)
+	}
+
+	err := d.DeleteIndex(ctx, "notfound", "bar", mock.NilOption)
+	if d := internal.StatusErrorDiff("Index not found", http.StatusNotFound, err); d != "" {
+		t.Error(d)
+	}
+	err = d.DeleteIndex(ctx, "foo", "notfound", mock.NilOption)
+	if d := internal.StatusErrorDiff("Index not found", http.StatusNotFound, err); d != "" {
+		t.Error(d)
+	}
+	if err := d.DeleteIndex(ctx, "foo", "bar", mock.NilOption); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.DeleteIndex(ctx, "foo", "baz", mock.NilOption); err != nil {
+		t.Fatal(err)
+	}
+	// The design document is deleted along with its last index.
+	_, err = d.Get(ctx, "_design/foo", mock.NilOption)
+	if d := internal.StatusErrorDiff("not found", http.StatusNotFound, err); d != "" {
+		t.Error(d)
+	}
+}
@@ -225,6 +225,15 @@ func (m md5sum) MarshalText() ([]byte, error) {
 	return b, nil
 }
 
+func (m *md5sum) UnmarshalText(text []byte) error {
+	x, err := parseDigest(string(text))
+	if err != nil {
+		return err
+	}
+	*m = x
+	return nil
+}
+
 func (m md5sum) Bytes() []byte {
 	return m[:]
 }
@@ -311,7 +320,15 @@ func prepareDoc(docID string, doc interface{}) (*docData, error) {
 	}
 	var ddocData designDocData
 	if strings.HasPrefix(docID, "_design/") {
-		if err := json.Unmarshal(tmpJSON, &ddocData); err != nil {
+		var lang struct {
+			Language string `json:"language"`
+		}
+		_ = json.Unmarshal(tmpJSON, &lang)
+		if lang.Language == "query" {
+			// Query design documents hold Mango index definitions, which
+			// are read from the document itself.
+			ddocData.Language = lang.Language
+		} else if err := json.Unmarshal(tmpJSON, &ddocData); err != nil {
 			return nil, &internal.Error{Status: http.StatusBadRequest, Err: err}
 		}
 		if ddocData.Language == "" {
@@ -347,6 +364,9 @@ func prepareDoc(docID string, doc interface{}) (*docData, error) {
 	if data.ID == "" {
 		data.ID = docID
 	}
+	if strings.HasPrefix(data.ID, "_") && !strings.HasPrefix(data.ID, "_design/") && !strings.HasPrefix(data.ID, "_local/") {
+		return nil, &internal.Error{Status: http.StatusBadRequest, Message: "Only reserved document ids may start with underscore."}
+	}
 
 	h := md5.New()
 	b, _ := json.Marshal(tmp)
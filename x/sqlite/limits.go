@@ -0,0 +1,280 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+//go:build !js
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	"github.com/go-kivik/kivik/v4/driver"
+)
+
+// Settings keys, and their defaults, which match CouchDB's.
+const (
+	settingRevsLimit        = "revs_limit"
+	settingPurgedInfosLimit = "purged_infos_limit"
+	defaultRevsLimit        = 1000
+	defaultPurgedInfosLimit = 1000
+)
+
+var (
+	_ driver.RevsLimiter        = (*db)(nil)
+	_ driver.PurgedInfosLimiter = (*db)(nil)
+)
+
+func (d *db) RevsLimit(ctx context.Context) (int, error) {
+	return d.setting(ctx, d.db, settingRevsLimit, defaultRevsLimit)
+}
+
+func (d *db) SetRevsLimit(ctx context.Context, limit int) error {
+	return d.setSetting(ctx, settingRevsLimit, limit)
+}
+
+func (d *db) PurgedInfosLimit(ctx context.Context) (int, error) {
+	return d.setting(ctx, d.db, settingPurgedInfosLimit, defaultPurgedInfosLimit)
+}
+
+func (d *db) SetPurgedInfosLimit(ctx context.Context, limit int) error {
+	return d.setSetting(ctx, settingPurgedInfosLimit, limit)
+}
+
+// setting returns the value of the named setting, or def if it has never been
+// set.
+func (d *db) setting(ctx context.Context, tx queryer, key string, def int) (int, error) {
+	var value int
+	err := tx.QueryRowContext(ctx, d.query(`
+		SELECT value
+		FROM {{ .Settings }}
+		WHERE key = $1
+	`), key).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return def, nil
+	}
+	if err != nil {
+		return 0, d.errDatabaseNotFound(err)
+	}
+	return value, nil
+}
+
+func (d *db) setSetting(ctx context.Context, key string, value int) error {
+	_, err := d.db.ExecContext(ctx, d.query(`
+		INSERT INTO {{ .Settings }} (key, value)
+		VALUES ($1, $2)
+		ON CONFLICT (key) DO UPDATE SET value = excluded.value
+	`), key, value)
+	return d.errDatabaseNotFound(err)
+}
+
+// pruneRevs enforces the revs limit on docID, by removing every revision which
+// is more than limit revisions away from all of the document's leaves.
+func (d *db) pruneRevs(ctx context.Context, tx *sql.Tx, docID string) error {
+	limit, err := d.setting(ctx, tx, settingRevsLimit, defaultRevsLimit)
+	if err != nil {
+		return err
+	}
+	// A child's rev is always one more than its parent's, so no revision can
+	// be more than this many revisions from a leaf. In the common case, that
+	// lets us skip the tree walk below.
+	var depth int
+	if err := tx.QueryRowContext(ctx, d.query(`
+		SELECT COALESCE(MAX(rev) - MIN(rev) + 1, 0)
+		FROM {{ .Revs }}
+		WHERE id = $1
+	`), docID).Scan(&depth); err != nil {
+		return err
+	}
+	if depth <= limit {
+		return nil
+	}
+	rows, err := tx.QueryContext(ctx, d.query(`
+		WITH RECURSIVE keep (rev, rev_id, parent_rev, parent_rev_id, depth) AS (
+			SELECT leaf.rev, leaf.rev_id, leaf.parent_rev, leaf.parent_rev_id, 1
+			FROM {{ .Revs }} AS leaf
+			LEFT JOIN {{ .Revs }} AS child
+				ON child.id = leaf.id
+				AND child.parent_rev = leaf.rev
+				AND child.parent_rev_id = leaf.rev_id
+			WHERE leaf.id = $1
+				AND child.id IS NULL
+
+			UNION
+
+			SELECT parent.rev, parent.rev_id, parent.parent_rev, parent.parent_rev_id, keep.depth + 1
+			FROM keep
+			JOIN {{ .Revs }} AS parent
+				ON parent.id = $1
+				AND parent.rev = keep.parent_rev
+				AND parent.rev_id = keep.parent_rev_id
+			WHERE keep.depth < $2
+		)
+		SELECT rev, rev_id
+		FROM {{ .Revs }}
+		WHERE id = $1
+			AND (rev, rev_id) NOT IN (SELECT rev, rev_id FROM keep)
+		ORDER BY rev
+	`), docID, limit)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	var revs []revision
+	for rows.Next() {
+		var r revision
+		if err := rows.Scan(&r.rev, &r.id); err != nil {
+			return err
+		}
+		revs = append(revs, r)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	_ = rows.Close()
+	return d.dropRevs(ctx, tx, docID, revs)
+}
+
+// dropRevs deletes the specified revisions of docID, along with their document
+// bodies and view index entries. Children of a dropped revision are kept, and
+// become roots of the revision tree.
+func (d *db) dropRevs(ctx context.Context, tx *sql.Tx, docID string, revs []revision) error {
+	if len(revs) == 0 {
+		return nil
+	}
+	views, err := d.mapViews(ctx, tx)
+	if err != nil {
+		return err
+	}
+	dropped := make(map[revision]bool, len(revs))
+	for _, r := range revs {
+		dropped[r] = true
+	}
+
+	stmts := newStmtCache()
+	for _, v := range views {
+		if v.id == docID && dropped[v.rev] {
+			// The map tables of a dropped design doc revision go with it.
+			_, err := tx.ExecContext(ctx, d.ddocQuery(v.id, v.view, v.rev.String(), `DROP TABLE IF EXISTS {{ .Map }}`))
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		stmt, err := stmts.prepare(ctx, tx, d.ddocQuery(v.id, v.view, v.rev.String(), `
+			DELETE FROM {{ .Map }}
+			WHERE id = $1 AND rev = $2 AND rev_id = $3
+		`))
+		if err != nil {
+			return err
+		}
+		for _, r := range revs {
+			if _, err := stmt.ExecContext(ctx, docID, r.rev, r.id); err != nil {
+				return err
+			}
+		}
+	}
+
+	detach, err := tx.PrepareContext(ctx, d.query(`
+		UPDATE {{ .Revs }}
+		SET parent_rev = NULL, parent_rev_id = NULL
+		WHERE id = $1 AND parent_rev = $2 AND parent_rev_id = $3
+	`))
+	if err != nil {
+		return err
+	}
+	defer detach.Close()
+	del, err := tx.PrepareContext(ctx, d.query(`
+		DELETE FROM {{ .Revs }}
+		WHERE id = $1 AND rev = $2 AND rev_id = $3
+	`))
+	if err != nil {
+		return err
+	}
+	defer del.Close()
+	for _, r := range revs {
+		// Detach children first, so the delete doesn't cascade to them.
+		if _, err := detach.ExecContext(ctx, docID, r.rev, r.id); err != nil {
+			return err
+		}
+		if _, err := del.ExecContext(ctx, docID, r.rev, r.id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type mapView struct {
+	id, view string
+	rev      revision
+}
+
+// mapViews returns every view map function in the database.
+func (d *db) mapViews(ctx context.Context, tx *sql.Tx) ([]mapView, error) {
+	rows, err := tx.QueryContext(ctx, d.query(`
+		SELECT
+			id,
+			rev,
+			rev_id,
+			func_name
+		FROM {{ .Design }}
+		WHERE func_type = 'map'
+	`))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var views []mapView
+	for rows.Next() {
+		var v mapView
+		if err := rows.Scan(&v.id, &v.rev.rev, &v.rev.id, &v.view); err != nil {
+			return nil, err
+		}
+		views = append(views, v)
+	}
+	return views, rows.Err()
+}
+
+// recordPurge adds an entry to the purged infos, and trims the oldest entries
+// beyond the purged infos limit. It returns the new purge sequence.
+func (d *db) recordPurge(ctx context.Context, tx *sql.Tx, docID string, revs []string) (int64, error) {
+	revsJSON, err := json.Marshal(revs)
+	if err != nil {
+		return 0, err
+	}
+	var seq int64
+	err = tx.QueryRowContext(ctx, d.query(`
+		INSERT INTO {{ .PurgedInfos }} (id, revs)
+		VALUES ($1, $2)
+		RETURNING seq
+	`), docID, string(revsJSON)).Scan(&seq)
+	if err != nil {
+		return 0, d.errDatabaseNotFound(err)
+	}
+	return seq, d.trimPurgedInfos(ctx, tx)
+}
+
+// trimPurgedInfos deletes all but the most recent purged infos, as set by the
+// purged infos limit.
+func (d *db) trimPurgedInfos(ctx context.Context, tx *sql.Tx) error {
+	limit, err := d.setting(ctx, tx, settingPurgedInfosLimit, defaultPurgedInfosLimit)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, d.query(`
+		DELETE FROM {{ .PurgedInfos }}
+		WHERE seq <= (SELECT MAX(seq) FROM {{ .PurgedInfos }}) - $1
+	`), limit)
+	return d.errDatabaseNotFound(err)
+}
@@ -0,0 +1,216 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+//go:build !js
+
+package sqlite
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/go-kivik/kivik/v4"
+	"github.com/go-kivik/kivik/v4/driver"
+	"github.com/go-kivik/kivik/v4/int/mock"
+)
+
+func TestDBRevsLimit(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	d := newDB(t)
+
+	limit, err := d.RevsLimit(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if limit != defaultRevsLimit {
+		t.Errorf("Unexpected default revs limit: %d", limit)
+	}
+	if err := d.SetRevsLimit(ctx, 3); err != nil {
+		t.Fatal(err)
+	}
+	if limit, _ = d.RevsLimit(ctx); limit != 3 {
+		t.Errorf("Unexpected revs limit: %d", limit)
+	}
+
+	limit, err = d.PurgedInfosLimit(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if limit != defaultPurgedInfosLimit {
+		t.Errorf("Unexpected default purged infos limit: %d", limit)
+	}
+	if err := d.SetPurgedInfosLimit(ctx, 5); err != nil {
+		t.Fatal(err)
+	}
+	if limit, _ = d.PurgedInfosLimit(ctx); limit != 5 {
+		t.Errorf("Unexpected purged infos limit: %d", limit)
+	}
+}
+
+// putRevs creates count revisions of docID, and returns the last.
+func (tdb *testDB) putRevs(docID string, count int) string {
+	tdb.t.Helper()
+	var rev string
+	for i := 0; i < count; i++ {
+		doc := map[string]interface{}{"i": i}
+		if rev != "" {
+			doc["_rev"] = rev
+		}
+		rev = tdb.tPut(docID, doc)
+	}
+	return rev
+}
+
+func TestDBRevsLimit_prune(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	d := newDB(t)
+	if err := d.SetRevsLimit(ctx, 2); err != nil {
+		t.Fatal(err)
+	}
+	rev := d.putRevs("foo", 4)
+	_ = d.putRevs("bar", 1)
+
+	got := readRevisions(t, d.underlying())
+	if len(got) != 3 { // nolint:gomnd
+		t.Fatalf("Expected 3 revisions, got %d", len(got))
+	}
+	if got[0].ID != "bar" {
+		t.Errorf("Unexpected first revision: %+v", got[0])
+	}
+	if got[1].Rev != 3 || got[1].ParentRev != nil {
+		t.Errorf("Expected rev 3 to become the root, got: %+v", got[1])
+	}
+	if r, _ := parseRev(rev); got[2].Rev != r.rev || got[2].RevID != r.id {
+		t.Errorf("Unexpected leaf: %+v", got[2])
+	}
+
+	var body map[string]interface{}
+	doc, err := d.Get(ctx, "foo", kivik.Param("revs", true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.NewDecoder(doc.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	revisions := body["_revisions"].(map[string]interface{})
+	if ids := revisions["ids"].([]interface{}); len(ids) != 2 {
+		t.Errorf("Expected 2 revisions in history, got %d", len(ids))
+	}
+}
+
+func TestDBRevsLimit_pruneIndexed(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	d := newDB(t)
+	if err := d.SetRevsLimit(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	_ = d.tPut("_design/foo", map[string]interface{}{
+		"views": map[string]interface{}{
+			"bar": map[string]string{
+				"map": `function(doc) { emit(doc._id, doc.i); }`,
+			},
+		},
+	})
+	rev := d.putRevs("a", 1)
+	rows, err := d.Query(ctx, "_design/foo", "_view/bar", mock.NilOption)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = rows.Close()
+
+	// The indexed revision is pruned by this update.
+	_ = d.tPut("a", map[string]interface{}{"i": 2, "_rev": rev})
+
+	rows, err = d.Query(ctx, "_design/foo", "_view/bar", mock.NilOption)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := readRows(t, rows)
+	if len(got) != 1 || string(got[0].Value) != "2" {
+		t.Errorf("Unexpected rows: %v", got)
+	}
+}
+
+func TestDBCompact(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	d := newDB(t)
+	_ = d.putRevs("foo", 5)
+	if err := d.SetRevsLimit(ctx, 2); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(readRevisions(t, d.underlying())); got != 5 {
+		t.Fatalf("Expected 5 revisions before compaction, got %d", got)
+	}
+	if err := d.Compact(ctx); err != nil {
+		t.Fatal(err)
+	}
+	got := readRevisions(t, d.underlying())
+	if len(got) != 2 {
+		t.Fatalf("Expected 2 revisions after compaction, got %d", len(got))
+	}
+	if got[0].Rev != 4 || got[0].ParentRev != nil {
+		t.Errorf("Unexpected oldest revision: %+v", got[0])
+	}
+}
+
+func TestDBPurge_purgedInfos(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	d := newDB(t)
+	if err := d.SetPurgedInfosLimit(ctx, 2); err != nil {
+		t.Fatal(err)
+	}
+	var result *driver.PurgeResult
+	for _, docID := range []string{"a", "b", "c"} {
+		rev := d.tPut(docID, map[string]string{"foo": "bar"})
+		var err error
+		result, err = d.Purge(ctx, map[string][]string{docID: {rev}})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if result.Seq != 3 {
+		t.Errorf("Unexpected purge seq: %d", result.Seq)
+	}
+
+	rows, err := d.underlying().Query(`SELECT id FROM "test_purged_infos" ORDER BY seq`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, id)
+	}
+	if d := cmp.Diff([]string{"b", "c"}, ids); d != "" {
+		t.Errorf("Unexpected purged infos:\n%s", d)
+	}
+
+	result, err = d.Purge(ctx, map[string][]string{"a": {"1-x"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Seq != 3 {
+		t.Errorf("Unexpected purge seq after no-op purge: %d", result.Seq)
+	}
+}
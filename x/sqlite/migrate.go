@@ -0,0 +1,103 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+//go:build !js
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+)
+
+// migrate runs migrations against the named database, the first time it is
+// opened by this client. A database which doesn't exist is left alone.
+func (c *client) migrate(ctx context.Context, d *db) error {
+	if _, ok := c.migrated.Load(d.name); ok {
+		return nil
+	}
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var exists bool
+	err = tx.QueryRowContext(ctx, `
+		SELECT TRUE
+		FROM sqlite_schema
+		WHERE type = 'table' AND name = $1
+	`, d.name+"_revs").Scan(&exists)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, query := range migrations {
+		if _, err := tx.ExecContext(ctx, d.query(query)); err != nil {
+			return err
+		}
+	}
+	if err := d.renameMapTables(ctx, tx); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	c.migrated.Store(d.name, true)
+	return nil
+}
+
+// renameMapTables renames view map tables, and their indexes, created before
+// the database name was included in the table name hash.
+func (d *db) renameMapTables(ctx context.Context, tx *sql.Tx) error {
+	views, err := d.mapViews(ctx, tx)
+	if err != nil {
+		return err
+	}
+	for _, v := range views {
+		legacy := &tmplFuncs{
+			db:       d,
+			ddoc:     strings.TrimPrefix(v.id, "_design/"),
+			viewName: v.view,
+			rev:      v.rev.String(),
+		}
+		legacy.hash = md5sumString(strings.Join([]string{legacy.ddoc, legacy.rev, legacy.viewName}, "_"))[:8]
+
+		var exists bool
+		err := tx.QueryRowContext(ctx, `
+			SELECT TRUE
+			FROM sqlite_schema
+			WHERE type = 'table' AND name = $1
+		`, legacy.hashedName("map")).Scan(&exists)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		table := d.ddocQuery(v.id, v.view, v.rev.String(), `{{ .Map }}`)
+		for _, query := range []string{
+			`DROP INDEX IF EXISTS ` + legacy.IndexMap(),
+			`ALTER TABLE ` + legacy.Map() + ` RENAME TO ` + table,
+			d.ddocQuery(v.id, v.view, v.rev.String(), `CREATE INDEX IF NOT EXISTS {{ .IndexMap }} ON {{ .Map }} (key)`),
+		} {
+			if _, err := tx.ExecContext(ctx, query); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
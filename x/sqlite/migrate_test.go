@@ -0,0 +1,161 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+//go:build !js
+
+package sqlite
+
+import (
+	"context"
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/go-kivik/kivik/v4/int/mock"
+)
+
+func TestClientMigrate(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	d := newDB(t)
+	_ = d.tPut("foo", map[string]string{"foo": "bar"})
+
+	// Simulate a database created before the settings and purged infos tables
+	// were added.
+	for _, table := range []string{"test_settings", "test_purged_infos"} {
+		if _, err := d.underlying().Exec(`DROP TABLE "` + table + `"`); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c := &client{db: d.underlying(), logger: log.Default()}
+	drvDB, err := c.DB("test", mock.NilOption)
+	if err != nil {
+		t.Fatal(err)
+	}
+	migrated := &testDB{t: t, DB: drvDB.(DB)}
+
+	if err := migrated.SetRevsLimit(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	rev := migrated.tPut("bar", map[string]string{"bar": "baz"})
+	rev = migrated.tPut("bar", map[string]string{"bar": "qux", "_rev": rev})
+	if _, err := migrated.Purge(ctx, map[string][]string{"bar": {rev}}); err != nil {
+		t.Fatal(err)
+	}
+	if limit, err := migrated.RevsLimit(ctx); err != nil || limit != 1 {
+		t.Errorf("Unexpected revs limit: %d, %v", limit, err)
+	}
+}
+
+func TestClientMigrate_legacyNames(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	d := newDB(t)
+	rev := d.tPut("_design/foo", map[string]interface{}{
+		"views": map[string]interface{}{
+			"bar": map[string]string{
+				"map": `function(doc) { emit(doc._id, null); }`,
+			},
+		},
+	})
+	_ = d.tPut("a", map[string]string{})
+	rows, err := d.Query(ctx, "_design/foo", "_view/bar", mock.NilOption)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = rows.Close()
+
+	// Restore the names used before the database name was part of them.
+	legacy := &tmplFuncs{db: d.DB.(*db), ddoc: "foo", viewName: "bar", rev: rev}
+	legacy.hash = md5sumString("foo_" + rev + "_bar")[:8]
+	table := d.DB.(*db).ddocQuery("_design/foo", "bar", rev, `{{ .Map }}`)
+	for _, query := range []string{
+		`DROP INDEX "idx_test_revs_key"`,
+		`DROP INDEX "idx_test_revs_parent"`,
+		`CREATE INDEX default_key ON "test_revs" (key)`,
+		`CREATE INDEX idx_parent ON "test_revs" (id, parent_rev, parent_rev_id)`,
+		`ALTER TABLE ` + table + ` RENAME TO ` + legacy.Map(),
+	} {
+		if _, err := d.underlying().Exec(query); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c := &client{db: d.underlying(), logger: log.Default()}
+	drvDB, err := c.DB("test", mock.NilOption)
+	if err != nil {
+		t.Fatal(err)
+	}
+	migrated := &testDB{t: t, DB: drvDB.(DB)}
+
+	rows, err = migrated.Query(ctx, "_design/foo", "_view/bar", mock.NilOption)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := readRows(t, rows); len(got) != 1 || got[0].ID != "a" {
+		t.Errorf("Unexpected rows: %v", got)
+	}
+
+	var names []string
+	indexes, err := d.underlying().Query(`
+		SELECT name
+		FROM sqlite_schema
+		WHERE type = 'index' AND name NOT LIKE 'sqlite_%'
+		ORDER BY name
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer indexes.Close()
+	for indexes.Next() {
+		var name string
+		if err := indexes.Scan(&name); err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, name)
+	}
+	if err := indexes.Err(); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		strings.Trim(d.DB.(*db).ddocQuery("_design/foo", "bar", rev, `{{ .IndexMap }}`), `"`),
+		"idx_test_revs_key",
+		"idx_test_revs_parent",
+	}
+	if d := cmp.Diff(want, names); d != "" {
+		t.Errorf("Unexpected indexes (-want +got):\n%s", d)
+	}
+}
+
+func TestClientMigrate_missingDB(t *testing.T) {
+	t.Parallel()
+	d := newDB(t)
+
+	c := &client{db: d.underlying(), logger: log.Default()}
+	if _, err := c.DB("missing", mock.NilOption); err != nil {
+		t.Fatal(err)
+	}
+	var count int
+	if err := d.underlying().QueryRow(`
+		SELECT COUNT(*)
+		FROM sqlite_schema
+		WHERE name LIKE 'missing%'
+	`).Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	if count != 0 {
+		t.Errorf("Migration created %d tables for a missing database", count)
+	}
+}
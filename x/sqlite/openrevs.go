@@ -154,7 +154,11 @@ func (d *db) OpenRevs(ctx context.Context, docID string, revs []string, options
 				att.digest,
 				att.rev_pos,
 				att.data,
-				SUM(CASE WHEN bridge.pk IS NOT NULL THEN 1 ELSE 0 END) OVER (PARTITION BY open_revs.rev, open_revs.rev_id) AS attachment_count,
+				(
+					SELECT COUNT(*)
+					FROM {{ .AttachmentsBridge }} AS b
+					WHERE b.id = open_revs.id AND b.rev = open_revs.rev AND b.rev_id = open_revs.rev_id
+				) AS attachment_count,
 				ROW_NUMBER() OVER (PARTITION BY open_revs.rev, open_revs.rev_id) AS row_number
 			FROM open_revs
 			LEFT JOIN {{ .Docs }} AS docs ON open_revs.id = docs.id AND open_revs.rev = docs.rev AND open_revs.rev_id = docs.rev_id
@@ -169,10 +169,10 @@ func (o optsMap) feed() (string, error) {
 		return "normal", nil
 	}
 	switch feed {
-	case feedNormal, feedLongpoll:
+	case feedNormal, feedLongpoll, feedContinuous:
 		return feed, nil
 	}
-	return "", &internal.Error{Status: http.StatusBadRequest, Message: "supported `feed` types: normal, longpoll"}
+	return "", &internal.Error{Status: http.StatusBadRequest, Message: "supported `feed` types: normal, longpoll, continuous"}
 }
 
 // since returns true if the value is "now", otherwise it returns the sequence
@@ -422,6 +422,18 @@ func toBool(in interface{}) (value bool, ok bool) {
 	}
 }
 
+func (o optsMap) partitioned() (bool, error) {
+	param, ok := o["partitioned"]
+	if !ok {
+		return false, nil
+	}
+	v, ok := toBool(param)
+	if !ok {
+		return false, &internal.Error{Status: http.StatusBadRequest, Message: fmt.Sprintf("invalid value for 'partitioned': %v", param)}
+	}
+	return v, nil
+}
+
 func (o optsMap) sorted() (bool, error) {
 	param, ok := o["sorted"]
 	if !ok {
@@ -690,9 +702,20 @@ func (v viewOptions) buildReduceCacheWhere(args *[]any) []string {
 	return where
 }
 
+// partitionWhere returns the WHERE condition which limits the query to the
+// requested partition, if any.
+func (v viewOptions) partitionWhere(args *[]any) []string {
+	if v.partition == "" {
+		return nil
+	}
+	idx := strconv.Itoa(len(*args) + 1)
+	*args = append(*args, v.partition+":")
+	return []string{"SUBSTR(view.id, 1, LENGTH($" + idx + ")) = $" + idx}
+}
+
 // buildGroupWhere returns WHERE conditions for use with grouping.
 func (v viewOptions) buildGroupWhere(args *[]any) []string {
-	where := make([]string, 0, defaultWhereCap)
+	where := v.partitionWhere(args)
 	if v.endkey != "" {
 		op := endKeyOp(v.descending, v.inclusiveEnd)
 		where = append(where, fmt.Sprintf("view.key %s $%d", op, len(*args)+1))
@@ -726,14 +749,9 @@ func (v viewOptions) bookmarkWhere() string {
 // buildWhere returns WHERE conditions based on the provided configuration
 // arguments, and may append to args as needed.
 func (v viewOptions) buildWhere(args *[]any) []string {
-	where := make([]string, 0, defaultWhereCap)
-	switch v.view {
-	case viewAllDocs:
-		where = append(where, `view.key NOT LIKE '"_local/%'`)
-	case viewLocalDocs:
-		where = append(where, `view.key LIKE '"_local/%'`)
-	case viewDesignDocs:
-		where = append(where, `view.key LIKE '"_design/%'`)
+	where := append(make([]string, 0, defaultWhereCap), v.partitionWhere(args)...)
+	if filter := v.builtinViewFilter(); filter != "" {
+		where = append(where, filter)
 	}
 	if v.endkey != "" {
 		op := endKeyOp(v.descending, v.inclusiveEnd)
@@ -766,6 +784,20 @@ func (v viewOptions) buildWhere(args *[]any) []string {
 	return where
 }
 
+// builtinViewFilter returns the condition which restricts a built-in view to
+// the relevant documents, or an empty string for user-defined views.
+func (v viewOptions) builtinViewFilter() string {
+	switch v.view {
+	case viewAllDocs:
+		return `view.key NOT LIKE '"_local/%'`
+	case viewLocalDocs:
+		return `view.key LIKE '"_local/%'`
+	case viewDesignDocs:
+		return `view.key LIKE '"_design/%'`
+	}
+	return ""
+}
+
 func (v viewOptions) buildOrderBy(moreColumns ...string) string {
 	if v.sorted {
 		direction := descendingToDirection(v.descending)
@@ -779,7 +811,7 @@ func (v viewOptions) buildOrderBy(moreColumns ...string) string {
 }
 
 // reduceGroupLevel returns the calculated groupLevel value to pass to
-// [github.com/go-kivik/kivik/v4/x/sqlite/reduce.Reduce].
+// [github.com/go-kivik/kivik/v4/x/reduce.Reduce].
 //
 //	-1: Maximum grouping, same as group=true
 //	 0: No grouping, same as group=false
@@ -817,6 +849,9 @@ type viewOptions struct {
 	keys            []string
 	sorted          bool
 	attEncodingInfo bool
+	// partition, if set, limits the query to the documents in the named
+	// partition.
+	partition string
 
 	// Find-specific options
 	selector  *mango.Selector
@@ -825,6 +860,12 @@ type viewOptions struct {
 	fields    []string
 	bookmark  string
 	sort      []string
+	useIndex  indexSpec
+	// index is the index chosen to answer the query, and terms the tokenized
+	// query of a $text selector.
+	index    *mangoIndex
+	terms    []string
+	warnings []string
 }
 
 // findOptions converts a _find query body into a viewOptions struct.
@@ -832,6 +873,7 @@ func findOptions(query interface{}) (*viewOptions, error) {
 	input := query.(json.RawMessage)
 	var s struct {
 		Selector *mango.Selector `json:"selector"`
+		UseIndex indexSpec       `json:"use_index"`
 	}
 	if err := json.Unmarshal(input, &s); err != nil {
 		return nil, &internal.Error{Status: http.StatusBadRequest, Err: err}
@@ -886,6 +928,7 @@ func findOptions(query interface{}) (*viewOptions, error) {
 		fields:      fields,
 		bookmark:    bookmark,
 		sort:        sort,
+		useIndex:    s.UseIndex,
 	}
 
 	return v, v.validate()
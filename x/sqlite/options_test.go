@@ -966,7 +966,7 @@ func Test_viewOptions(t *testing.T) {
 		if err != nil {
 			return
 		}
-		if d := cmp.Diff(tt.want, got, cmp.AllowUnexported(viewOptions{})); d != "" {
+		if d := cmp.Diff(tt.want, got, cmp.AllowUnexported(viewOptions{}, indexSpec{})); d != "" {
 			t.Errorf("Unexpected result:\n%s", d)
 		}
 	})
@@ -0,0 +1,143 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package sqlite
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/go-kivik/kivik/v4/driver"
+	internal "github.com/go-kivik/kivik/v4/int/errors"
+)
+
+// settingPartitioned is set to 1 for databases created with partitioned=true.
+const settingPartitioned = "partitioned"
+
+var _ driver.PartitionedDB = (*db)(nil)
+
+func (d *db) isPartitioned(ctx context.Context, tx queryer) (bool, error) {
+	partitioned, err := d.setting(ctx, tx, settingPartitioned, 0)
+	return partitioned == 1, err
+}
+
+// validatePartitionedID returns an error if docID is not valid for a
+// partitioned database, using the same messages as CouchDB.
+func validatePartitionedID(docID string) error {
+	if strings.HasPrefix(docID, "_design/") || strings.HasPrefix(docID, "_local/") {
+		return nil
+	}
+	partition, id, ok := strings.Cut(docID, ":")
+	if !ok {
+		return &internal.Error{Status: http.StatusBadRequest, Message: "Doc id must be of form partition:id"}
+	}
+	if err := validatePartitionName(partition); err != nil {
+		return err
+	}
+	switch {
+	case id == "":
+		return &internal.Error{Status: http.StatusBadRequest, Message: "Document id must not be empty"}
+	case strings.HasPrefix(id, "_"):
+		return &internal.Error{Status: http.StatusBadRequest, Message: "Only reserved document ids may start with underscore."}
+	}
+	return nil
+}
+
+func validatePartitionName(partition string) error {
+	switch {
+	case partition == "":
+		return &internal.Error{Status: http.StatusBadRequest, Message: "Partition name cannot be empty"}
+	case strings.HasPrefix(partition, "_"):
+		return &internal.Error{Status: http.StatusBadRequest, Message: "Partition name must not start with an underscore"}
+	}
+	return nil
+}
+
+// checkDocID returns an error if the database is partitioned, and docID is
+// not a valid partitioned document ID.
+func (d *db) checkDocID(ctx context.Context, tx queryer, docID string) error {
+	partitioned, err := d.isPartitioned(ctx, tx)
+	if err != nil || !partitioned {
+		return err
+	}
+	return validatePartitionedID(docID)
+}
+
+// partition returns the partition to which options limit a query, if any.
+// It is an error to request a partition of a database which is not
+// partitioned.
+func (d *db) partition(ctx context.Context, options driver.Options) (string, error) {
+	if options == nil {
+		return "", nil
+	}
+	part := &driver.Partition{}
+	options.Apply(part)
+	if part.Name == "" {
+		return "", nil
+	}
+	if err := validatePartitionName(part.Name); err != nil {
+		return "", err
+	}
+	partitioned, err := d.isPartitioned(ctx, d.db)
+	if err != nil {
+		return "", err
+	}
+	if !partitioned {
+		return "", &internal.Error{Status: http.StatusBadRequest, Message: "database is not partitioned"}
+	}
+	return part.Name, nil
+}
+
+func (d *db) PartitionStats(ctx context.Context, name string) (*driver.PartitionStats, error) {
+	if err := validatePartitionName(name); err != nil {
+		return nil, err
+	}
+	partitioned, err := d.isPartitioned(ctx, d.db)
+	if err != nil {
+		return nil, err
+	}
+	if !partitioned {
+		return nil, &internal.Error{Status: http.StatusBadRequest, Message: "database is not partitioned"}
+	}
+	var docCount, deletedCount int64
+	err = d.db.QueryRowContext(ctx, d.query(`
+		WITH leaves AS (
+			SELECT
+				rev.id,
+				doc.deleted
+			FROM {{ .Revs }} AS rev
+			LEFT JOIN {{ .Revs }} AS child ON child.id = rev.id AND rev.rev = child.parent_rev AND rev.rev_id = child.parent_rev_id
+			JOIN {{ .Docs }} AS doc ON rev.id = doc.id AND rev.rev = doc.rev AND rev.rev_id = doc.rev_id
+			WHERE child.id IS NULL
+				AND SUBSTR(rev.id, 1, LENGTH($1)) = $1
+		),
+		live AS (
+			SELECT COUNT(DISTINCT id) AS count
+			FROM leaves
+			WHERE NOT deleted
+		)
+		SELECT
+			live.count,
+			(SELECT COUNT(DISTINCT id) FROM leaves) - live.count
+		FROM live
+	`), name+":").Scan(&docCount, &deletedCount)
+	if err != nil {
+		return nil, d.errDatabaseNotFound(err)
+	}
+	return &driver.PartitionStats{
+		DBName:          d.name,
+		DocCount:        docCount,
+		DeletedDocCount: deletedCount,
+		Partition:       name,
+	}, nil
+}
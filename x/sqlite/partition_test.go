@@ -0,0 +1,251 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+//go:build !js
+
+package sqlite
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/go-kivik/kivik/v4"
+	"github.com/go-kivik/kivik/v4/driver"
+	"github.com/go-kivik/kivik/v4/int/mock"
+)
+
+// newPartitionedDB creates a partitioned database alongside the one created
+// by newDB.
+func newPartitionedDB(t *testing.T) *testDB {
+	t.Helper()
+	tdb := newDB(t)
+	d := tdb.DB.(*db)
+	c := &client{db: d.db, logger: d.logger}
+	if err := c.CreateDB(context.Background(), "parts", kivik.Partitioned()); err != nil {
+		t.Fatal(err)
+	}
+	return &testDB{
+		t:    t,
+		DB:   c.newDB("parts"),
+		logs: tdb.logs,
+	}
+}
+
+func TestPartitionedDB_writes(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	d := newPartitionedDB(t)
+
+	stats, err := d.Stats(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !stats.Partitioned {
+		t.Errorf("Expected database to be partitioned")
+	}
+
+	_ = d.tPut("sensor-1:reading-1", map[string]interface{}{"value": 1})
+	_ = d.tPut("_design/foo", map[string]interface{}{})
+	_ = d.tPut("_local/foo", map[string]interface{}{})
+
+	tests := []struct {
+		name  string
+		write func() error
+		want  string
+	}{
+		{
+			name: "put without partition",
+			write: func() error {
+				_, err := d.Put(ctx, "reading-1", map[string]interface{}{}, mock.NilOption)
+				return err
+			},
+			want: "Doc id must be of form partition:id",
+		},
+		{
+			name: "put with underscore partition",
+			write: func() error {
+				_, err := d.Put(ctx, "_sensor:reading-1", map[string]interface{}{}, mock.NilOption)
+				return err
+			},
+			want: "Only reserved document ids may start with underscore.",
+		},
+		{
+			name: "put with empty doc id",
+			write: func() error {
+				_, err := d.Put(ctx, "sensor-1:", map[string]interface{}{}, mock.NilOption)
+				return err
+			},
+			want: "Document id must not be empty",
+		},
+		{
+			name: "create with generated ID",
+			write: func() error {
+				_, _, err := d.CreateDoc(ctx, map[string]interface{}{}, mock.NilOption)
+				return err
+			},
+			want: "Doc id must be of form partition:id",
+		},
+		{
+			name: "put attachment",
+			write: func() error {
+				_, err := d.PutAttachment(ctx, "reading-2", &driver.Attachment{
+					Filename:    "foo.txt",
+					ContentType: "text/plain",
+					Content:     io.NopCloser(strings.NewReader("foo")),
+				}, mock.NilOption)
+				return err
+			},
+			want: "Doc id must be of form partition:id",
+		},
+	}
+	for _, tt := range tests {
+		err := tt.write()
+		if err == nil || err.Error() != tt.want {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+		}
+		if status := kivik.HTTPStatus(err); status != http.StatusBadRequest {
+			t.Errorf("%s: unexpected status: %d", tt.name, status)
+		}
+	}
+
+	if _, _, err := d.CreateDoc(ctx, map[string]interface{}{"_id": "sensor-2:reading-1"}, mock.NilOption); err != nil {
+		t.Errorf("Unexpected error creating partitioned doc: %s", err)
+	}
+}
+
+func TestPartitionedDB_queries(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	d := newPartitionedDB(t)
+
+	rev1 := d.tPut("sensor-1:a", map[string]interface{}{"value": 1})
+	rev2 := d.tPut("sensor-1:b", map[string]interface{}{"value": 2})
+	_ = d.tPut("sensor-2:a", map[string]interface{}{"value": 4})
+	_ = d.tPut("sensor-10:a", map[string]interface{}{"value": 8})
+	_ = d.tPut("_design/foo", map[string]interface{}{
+		"views": map[string]interface{}{
+			"values": map[string]interface{}{
+				"map":    `function(doc) { if (doc.value) { emit(doc._id, doc.value); } }`,
+				"reduce": "_sum",
+			},
+		},
+	})
+
+	t.Run("AllDocs", func(t *testing.T) {
+		rows, err := d.AllDocs(ctx, kivik.Partition("sensor-1"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { _ = rows.Close() })
+		checkRows(t, rows, []rowResult{
+			{ID: "sensor-1:a", Key: `"sensor-1:a"`, Value: `{"rev":"` + rev1 + `"}`},
+			{ID: "sensor-1:b", Key: `"sensor-1:b"`, Value: `{"rev":"` + rev2 + `"}`},
+		})
+	})
+	t.Run("Query group", func(t *testing.T) {
+		rows, err := d.Query(ctx, "_design/foo", "_view/values", multiOptions{kivik.Partition("sensor-1"), kivik.Param("group", true)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { _ = rows.Close() })
+		checkRows(t, rows, []rowResult{
+			{Key: `"sensor-1:a"`, Value: "1"},
+			{Key: `"sensor-1:b"`, Value: "2"},
+		})
+	})
+	t.Run("Query", func(t *testing.T) {
+		rows, err := d.Query(ctx, "_design/foo", "_view/values", multiOptions{kivik.Partition("sensor-1"), kivik.Param("reduce", false)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { _ = rows.Close() })
+		checkRows(t, rows, []rowResult{
+			{ID: "sensor-1:a", Key: `"sensor-1:a"`, Value: "1"},
+			{ID: "sensor-1:b", Key: `"sensor-1:b"`, Value: "2"},
+		})
+	})
+	t.Run("Query reduce", func(t *testing.T) {
+		rows, err := d.Query(ctx, "_design/foo", "_view/values", kivik.Partition("sensor-2"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { _ = rows.Close() })
+		checkRows(t, rows, []rowResult{
+			{Key: "null", Value: "4"},
+		})
+	})
+	t.Run("Find", func(t *testing.T) {
+		rows, err := d.Find(ctx, json.RawMessage(`{"selector":{"value":{"$gt":1}},"fields":["_id"]}`), kivik.Partition("sensor-1"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { _ = rows.Close() })
+		checkRows(t, rows, []rowResult{
+			{Doc: `{"_id":"sensor-1:b"}`},
+		})
+	})
+	t.Run("invalid partition", func(t *testing.T) {
+		_, err := d.AllDocs(ctx, kivik.Partition("_sensor"))
+		if status := kivik.HTTPStatus(err); status != http.StatusBadRequest {
+			t.Errorf("Unexpected status: %d", status)
+		}
+	})
+	t.Run("PartitionStats", func(t *testing.T) {
+		d.tDelete("sensor-1:b", kivik.Rev(rev2))
+		stats, err := d.DB.(driver.PartitionedDB).PartitionStats(ctx, "sensor-1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := &driver.PartitionStats{
+			DBName:          "parts",
+			DocCount:        1,
+			DeletedDocCount: 1,
+			Partition:       "sensor-1",
+		}
+		if d := cmp.Diff(want, stats); d != "" {
+			t.Errorf("Unexpected stats:\n%s", d)
+		}
+	})
+}
+
+func TestPartition_not_partitioned(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	d := newDB(t)
+
+	_ = d.tPut("reading-1", map[string]interface{}{})
+
+	_, err := d.AllDocs(ctx, kivik.Partition("sensor-1"))
+	if err == nil || err.Error() != "database is not partitioned" {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if status := kivik.HTTPStatus(err); status != http.StatusBadRequest {
+		t.Errorf("Unexpected status: %d", status)
+	}
+	_, err = d.DB.(driver.PartitionedDB).PartitionStats(ctx, "sensor-1")
+	if status := kivik.HTTPStatus(err); status != http.StatusBadRequest {
+		t.Errorf("Unexpected status: %d", status)
+	}
+	stats, err := d.Stats(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Partitioned {
+		t.Errorf("Expected database not to be partitioned")
+	}
+}
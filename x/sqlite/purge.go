@@ -28,7 +28,6 @@ func (d *db) Purge(ctx context.Context, request map[string][]string) (*driver.Pu
 	}
 	defer tx.Rollback()
 
-	stmts := newStmtCache()
 	result := &driver.PurgeResult{}
 
 	for docID, revs := range request {
@@ -48,15 +47,7 @@ func (d *db) Purge(ctx context.Context, request map[string][]string) (*driver.Pu
 				}
 			}
 
-			stmt, err := stmts.prepare(ctx, tx, d.query(`
-				DELETE FROM {{ .Revs }}
-				WHERE id = $1 AND rev = $2 AND rev_id = $3
-			`))
-			if err != nil {
-				return nil, err
-			}
-			_, err = stmt.ExecContext(ctx, docID, r.rev, r.id)
-			if err != nil {
+			if err := d.dropRevs(ctx, tx, docID, []revision{r}); err != nil {
 				return nil, fmt.Errorf("exec failed: %w", err)
 			}
 			if result.Purged == nil {
@@ -64,6 +55,19 @@ func (d *db) Purge(ctx context.Context, request map[string][]string) (*driver.Pu
 			}
 			result.Purged[docID] = append(result.Purged[docID], rev)
 		}
+		if revs := result.Purged[docID]; len(revs) > 0 {
+			if result.Seq, err = d.recordPurge(ctx, tx, docID, revs); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if result.Purged == nil {
+		if err := tx.QueryRowContext(ctx, d.query(`
+			SELECT COALESCE(MAX(seq), 0)
+			FROM {{ .PurgedInfos }}
+		`)).Scan(&result.Seq); err != nil {
+			return nil, d.errDatabaseNotFound(err)
+		}
 	}
 
 	return result, tx.Commit()
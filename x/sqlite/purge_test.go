@@ -53,6 +53,7 @@ func TestDBPurge(t *testing.T) {
 				"foo": {rev},
 			},
 			want: &driver.PurgeResult{
+				Seq: 1,
 				Purged: map[string][]string{
 					"foo": {rev},
 				},
@@ -107,6 +108,7 @@ func TestDBPurge(t *testing.T) {
 				"foo": {"3-ccc"},
 			},
 			want: &driver.PurgeResult{
+				Seq: 1,
 				Purged: map[string][]string{
 					"foo": {"3-ccc"},
 				},
@@ -123,7 +125,6 @@ func TestDBPurge(t *testing.T) {
 	/*
 		TODO:
 		- What happens when purging a leaf, and its parent at the same time?
-		- what is purge seq?
 		- refactor: bulk delete, bulk lookup
 	*/
 
@@ -42,6 +42,10 @@ func (d *db) Put(ctx context.Context, docID string, doc interface{}, options dri
 	}
 	defer tx.Rollback()
 
+	if err := d.checkDocID(ctx, tx, data.ID); err != nil {
+		return "", err
+	}
+
 	if data.Revisions.Start != 0 {
 		if newEdits {
 			stmt, err := tx.PrepareContext(ctx, d.query(`
@@ -144,6 +148,9 @@ func (d *db) Put(ctx context.Context, docID string, doc interface{}, options dri
 		if err := d.createDocAttachments(ctx, data, tx, rev, ancestorRev); err != nil {
 			return "", err
 		}
+		if err := d.pruneRevs(ctx, tx, docID); err != nil {
+			return "", err
+		}
 
 		return newRev, tx.Commit()
 	}
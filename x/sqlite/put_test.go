@@ -251,6 +251,14 @@ func TestDBPut(t *testing.T) {
 		wantStatus: http.StatusBadRequest,
 		wantErr:    "Document ID must match _id in document",
 	})
+	tests.Add("leading underscore in doc id", test{
+		docID: "_foo",
+		doc: map[string]interface{}{
+			"foo": "bar",
+		},
+		wantStatus: http.StatusBadRequest,
+		wantErr:    "Only reserved document ids may start with underscore.",
+	})
 	tests.Add("set _deleted=true", func(t *testing.T) interface{} {
 		d := newDB(t)
 
@@ -31,6 +31,10 @@ func (d *db) PutAttachment(ctx context.Context, docID string, att *driver.Attach
 	}
 	defer tx.Rollback()
 
+	if err := d.checkDocID(ctx, tx, docID); err != nil {
+		return "", err
+	}
+
 	data := &docData{
 		ID: docID,
 	}
@@ -26,8 +26,8 @@ import (
 	"github.com/go-kivik/kivik/v4"
 	"github.com/go-kivik/kivik/v4/driver"
 	internal "github.com/go-kivik/kivik/v4/int/errors"
-	"github.com/go-kivik/kivik/x/sqlite/v4/js"
-	"github.com/go-kivik/kivik/x/sqlite/v4/reduce"
+	"github.com/go-kivik/kivik/v4/x/js"
+	"github.com/go-kivik/kivik/v4/x/reduce"
 )
 
 func fromJSValue(v any) (*string, error) {
@@ -48,6 +48,10 @@ func (d *db) Query(ctx context.Context, ddoc, view string, options driver.Option
 	if err != nil {
 		return nil, err
 	}
+	vopts.partition, err = d.partition(ctx, options)
+	if err != nil {
+		return nil, err
+	}
 
 	if isBuiltinView(ddoc) {
 		return d.queryBuiltinView(ctx, vopts)
@@ -138,7 +142,7 @@ func (d *db) performQuery(
 				reduce.reduce_func,
 				IIF($4, last_seq, "") AS update_seq,
 				MAX(last_seq)         AS last_seq,
-				NULL,
+				(SELECT COUNT(*) FROM {{ .Map }}) AS total_rows,
 				0    AS attachment_count,
 				NULL AS filename,
 				NULL AS content_type,
@@ -228,7 +232,7 @@ func (d *db) performQuery(
 					JOIN reduce
 					JOIN {{ .Docs }} AS docs ON view.id = docs.id AND view.rev = docs.rev AND view.rev_id = docs.rev_id
 					LEFT JOIN leaves AS conflicts ON conflicts.id = view.id AND NOT (view.rev = conflicts.rev AND view.rev_id = conflicts.rev_id)
-					WHERE $3 == FALSE OR NOT reduce.reducible
+					WHERE ($3 == FALSE OR NOT reduce.reducible)
 						%[2]s -- WHERE
 					GROUP BY view.id, view.key, view.value, view.rev, view.rev_id
 					%[1]s -- ORDER BY
@@ -280,6 +284,7 @@ func (d *db) performQuery(
 			db:        d,
 			rows:      results,
 			updateSeq: meta.updateSeq,
+			totalRows: meta.totalRows,
 		}, nil
 	}
 }
@@ -330,7 +335,7 @@ func (d *db) performGroupQuery(ctx context.Context, ddoc, view string, vopts *vi
 				reduce.reduce_func,
 				IIF($7, last_seq, "") AS update_seq,
 				MAX(last_seq)         AS last_seq,
-				NULL,
+				(SELECT COUNT(*) FROM {{ .Map }}) AS total_rows,
 				0    AS attachment_count,
 				NULL AS filename,
 				NULL AS content_type,
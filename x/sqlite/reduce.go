@@ -17,7 +17,7 @@ import (
 	"encoding/json"
 	"io"
 
-	"github.com/go-kivik/kivik/x/sqlite/v4/reduce"
+	"github.com/go-kivik/kivik/v4/x/reduce"
 )
 
 type reduceRowIter struct {
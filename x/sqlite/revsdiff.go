@@ -85,64 +85,76 @@ type revsDiffResponse struct {
 var _ driver.Rows = (*revsDiffResponse)(nil)
 
 func (r *revsDiffResponse) Next(row *driver.Row) error {
-	var (
-		id       string
-		revs     = map[string]struct{}{}
-		revCount int
-	)
-
 	for {
-		if !r.rows.Next() {
-			if err := r.rows.Err(); err != nil {
-				return err
-			}
-			if len(r.req) > 0 {
-				if len(r.sortedDocIDs) == 0 {
-					// First time, we need to sort the remaining doc IDs.
-					r.sortedDocIDs = make([]string, 0, len(r.req))
-					for id := range r.req {
-						r.sortedDocIDs = append(r.sortedDocIDs, id)
-					}
-					sort.Strings(r.sortedDocIDs)
-				}
-
-				row.ID = r.sortedDocIDs[0]
-				revs := r.req[row.ID]
-				sort.Strings(revs)
-				row.Value = jsonToReader(driver.RevDiff{
-					Missing: revs,
-				})
-				delete(r.req, row.ID)
-				r.sortedDocIDs = r.sortedDocIDs[1:]
-				return nil
+		id, revs, err := r.nextDoc()
+		if err != nil {
+			return err
+		}
+		if id == "" {
+			return r.nextMissingDoc(row)
+		}
+		missing := make([]string, 0, len(r.req[id]))
+		for _, rev := range r.req[id] {
+			if _, ok := revs[rev]; !ok {
+				missing = append(missing, rev)
 			}
-			return io.EOF
 		}
+		delete(r.req, id)
+		if len(missing) == 0 {
+			// Documents with no missing revisions are omitted, as by CouchDB.
+			continue
+		}
+		row.ID = id
+		row.Value = jsonToReader(driver.RevDiff{
+			Missing: missing,
+		})
+		return nil
+	}
+}
+
+// nextDoc reads all known revisions of the next document which exists in the
+// database. An empty id is returned when there are no more such documents.
+func (r *revsDiffResponse) nextDoc() (id string, revs map[string]struct{}, err error) {
+	revs = map[string]struct{}{}
+	for r.rows.Next() {
 		var (
-			rowID *string
-			rev   string
+			rev      string
+			revCount int
 		)
-		if err := r.rows.Scan(&id, &rev, &rowID, &revCount); err != nil {
-			return err
+		if err := r.rows.Scan(&id, &rev, &revCount); err != nil {
+			return "", nil, err
 		}
-		if rowID != nil {
-			id = *rowID
-		}
-		revs[id] = struct{}{}
+		revs[rev] = struct{}{}
 		if len(revs) == revCount {
-			break
+			return id, revs, nil
 		}
 	}
-	row.ID = id
-	missing := make([]string, 0, len(r.req[id]))
-	for _, rev := range r.req[id] {
-		if _, ok := revs[rev]; !ok {
-			missing = append(missing, rev)
+	return "", nil, r.rows.Err()
+}
+
+// nextMissingDoc returns the next requested document which does not exist in
+// the database at all, so all of its revisions are missing.
+func (r *revsDiffResponse) nextMissingDoc(row *driver.Row) error {
+	if len(r.req) == 0 {
+		return io.EOF
+	}
+	if len(r.sortedDocIDs) == 0 {
+		// First time, we need to sort the remaining doc IDs.
+		r.sortedDocIDs = make([]string, 0, len(r.req))
+		for id := range r.req {
+			r.sortedDocIDs = append(r.sortedDocIDs, id)
 		}
+		sort.Strings(r.sortedDocIDs)
 	}
+
+	row.ID = r.sortedDocIDs[0]
+	revs := r.req[row.ID]
+	sort.Strings(revs)
 	row.Value = jsonToReader(driver.RevDiff{
-		Missing: missing,
+		Missing: revs,
 	})
+	delete(r.req, row.ID)
+	r.sortedDocIDs = r.sortedDocIDs[1:]
 	return nil
 }
 
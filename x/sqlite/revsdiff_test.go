@@ -53,6 +53,24 @@ func TestDBRevsDiff(t *testing.T) {
 			{ID: "foo", Value: `{"missing":["1-abc","2-def"]}`},
 		},
 	})
+	tests.Add("some present", func(t *testing.T) interface{} {
+		db := newDB(t)
+		rev := db.tPut("foo", map[string]string{"foo": "bar"})
+		rev2 := db.tPut("baz", map[string]string{"foo": "bar"})
+
+		return test{
+			db: db,
+			revMap: map[string][]string{
+				"foo": {rev, "2-def"},
+				"bar": {"3-ghi"},
+				"baz": {rev2},
+			},
+			want: []rowResult{
+				{ID: "foo", Value: `{"missing":["2-def"]}`},
+				{ID: "bar", Value: `{"missing":["3-ghi"]}`},
+			},
+		}
+	})
 
 	/*
 		TODO:
@@ -24,8 +24,8 @@ var schema = []string{
 		FOREIGN KEY (id, parent_rev, parent_rev_id) REFERENCES {{ .Revs }} (id, rev, rev_id) ON DELETE CASCADE,
 		UNIQUE(id, rev, rev_id)
 	)`,
-	`CREATE INDEX default_key ON {{ .Revs }} (key)`,
-	`CREATE INDEX idx_parent ON {{ .Revs }} (id, parent_rev, parent_rev_id)`,
+	createIndexRevsKey,
+	createIndexRevsParent,
 	// the main db table
 	`CREATE TABLE {{ .Docs }} (
 		seq INTEGER PRIMARY KEY,
@@ -85,6 +85,37 @@ var schema = []string{
 		FOREIGN KEY (id, rev, rev_id) REFERENCES {{ .Docs }} (id, rev, rev_id) ON DELETE CASCADE,
 		UNIQUE (id, rev, rev_id, func_type, func_name)
 	)`,
+	createSettings,
+	createPurgedInfos,
+}
+
+const (
+	createIndexRevsKey    = `CREATE INDEX IF NOT EXISTS {{ .IndexRevsKey }} ON {{ .Revs }} (key)`
+	createIndexRevsParent = `CREATE INDEX IF NOT EXISTS {{ .IndexRevsParent }} ON {{ .Revs }} (id, parent_rev, parent_rev_id)`
+	// per-database settings, such as revs_limit
+	createSettings = `CREATE TABLE IF NOT EXISTS {{ .Settings }} (
+		key TEXT PRIMARY KEY,
+		value INTEGER NOT NULL
+	)`
+	// purged_infos records purge requests, as a JSON array of revs per row
+	createPurgedInfos = `CREATE TABLE IF NOT EXISTS {{ .PurgedInfos }} (
+		seq INTEGER PRIMARY KEY,
+		id TEXT NOT NULL,
+		revs TEXT NOT NULL
+	)`
+)
+
+// migrations bring databases created by earlier versions of this driver up to
+// date. Every statement must be safe to run against an up-to-date database.
+var migrations = []string{
+	// The revs indexes used to have fixed names, which prevented more than
+	// one database per file.
+	`DROP INDEX IF EXISTS default_key`,
+	`DROP INDEX IF EXISTS idx_parent`,
+	createIndexRevsKey,
+	createIndexRevsParent,
+	createSettings,
+	createPurgedInfos,
 }
 
 var viewSchema = []string{
@@ -101,6 +132,8 @@ var viewSchema = []string{
 }
 
 var destroySchema = []string{
+	`DROP TABLE {{ .PurgedInfos }}`,
+	`DROP TABLE {{ .Settings }}`,
 	`DROP TABLE {{ .Design }}`,
 	`DROP TABLE {{ .AttachmentsBridge }}`,
 	`DROP TABLE {{ .Attachments }}`,
@@ -0,0 +1,411 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package sqlite
+
+import (
+	sqldriver "database/sql/driver"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"modernc.org/sqlite"
+
+	"github.com/go-kivik/kivik/v4/x/collate"
+	"github.com/go-kivik/kivik/v4/x/mango"
+)
+
+/*
+Mango selectors are translated into SQL conditions on the JSON1 representation
+of each document, so that SQLite can discard non-matching documents without
+returning them to Go. Each condition matches a superset of the documents the
+selector matches: operators which can't be expressed in SQL translate to TRUE,
+and the selector is always evaluated in Go against the remaining documents.
+
+A translation is "exact" when it matches precisely the documents the selector
+matches. Only exact conditions may be negated, as negating a superset would
+yield a subset.
+
+Missing fields yield SQL NULL, which is treated as false; negations are
+wrapped in COALESCE(..., FALSE) to preserve this under NOT.
+*/
+
+func init() {
+	if err := sqlite.RegisterCollationUtf8("COUCHDB_STRING", collate.CompareString); err != nil {
+		panic(err)
+	}
+	if err := sqlite.RegisterDeterministicScalarFunction("regexp", 2, sqlRegexp); err != nil {
+		panic(err)
+	}
+}
+
+var regexpCache sync.Map
+
+// sqlRegexp implements the SQL `X REGEXP Y` operator, which SQLite calls as
+// regexp(Y, X), using Go regular expressions, to match the semantics of the
+// $regex operator.
+func sqlRegexp(_ *sqlite.FunctionContext, args []sqldriver.Value) (sqldriver.Value, error) {
+	pattern, ok := args[0].(string)
+	if !ok {
+		return nil, fmt.Errorf("regexp: invalid pattern: %v", args[0])
+	}
+	var value string
+	switch t := args[1].(type) {
+	case string:
+		value = t
+	case []byte:
+		value = string(t)
+	default:
+		return false, nil
+	}
+	re, ok := regexpCache.Load(pattern)
+	if !ok {
+		compiled, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, err
+		}
+		re, _ = regexpCache.LoadOrStore(pattern, compiled)
+	}
+	return re.(*regexp.Regexp).MatchString(value), nil
+}
+
+// sqlTarget describes a JSON value, such as a document field, as SQL
+// expressions. Each expression evaluates to NULL when the value doesn't
+// exist.
+type sqlTarget struct {
+	json string // JSON representation of the value
+	typ  string // JSON type of the value, as returned by json_type()
+	val  string // SQL value, as returned by json_extract()
+}
+
+// rank returns an expression ordering the value's type by CouchDB collation.
+func (t sqlTarget) rank() string {
+	return fmt.Sprintf(`(CASE %s WHEN 'null' THEN 0 WHEN 'false' THEN 1 WHEN 'true' THEN 2 WHEN 'integer' THEN 3 WHEN 'real' THEN 3 WHEN 'text' THEN 4 WHEN 'array' THEN 5 WHEN 'object' THEN 6 END)`, t.typ)
+}
+
+// valueRank returns the collation rank of v, as used by [sqlTarget.rank].
+func valueRank(v interface{}) int {
+	switch t := v.(type) {
+	case nil:
+		return 0
+	case bool:
+		if t {
+			return 2
+		}
+		return 1
+	case float64:
+		return 3
+	case string:
+		return 4
+	case []interface{}:
+		return 5
+	}
+	return 6
+}
+
+type selectorSQL struct {
+	doc     string // the document column
+	args    *[]any
+	aliases int
+}
+
+// selectorWhere returns an SQL condition which matches a superset of the
+// documents matched by sel. Arguments are appended to args.
+func selectorWhere(sel *mango.Selector, doc string, args *[]any) string {
+	root := sel.Root()
+	if root == nil {
+		return "TRUE"
+	}
+	s := &selectorSQL{doc: doc, args: args}
+	cond, _ := s.node(root)
+	return cond
+}
+
+func (s *selectorSQL) arg(v any) string {
+	*s.args = append(*s.args, v)
+	return "$" + strconv.Itoa(len(*s.args))
+}
+
+func (s *selectorSQL) alias() string {
+	s.aliases++
+	return "sel" + strconv.Itoa(s.aliases)
+}
+
+func not(cond string) string {
+	return "NOT COALESCE(" + cond + ", FALSE)"
+}
+
+// node returns a condition for the selector node, and whether it is exact.
+func (s *selectorSQL) node(n mango.Node) (string, bool) {
+	if f, ok := n.(mango.FieldNode); ok {
+		t, ok := s.field(f.Field())
+		if !ok {
+			return "TRUE", false
+		}
+		return s.condition(t, f.Condition())
+	}
+	switch n.Op() {
+	case mango.OpAnd, mango.OpOr:
+		sels, _ := n.Value().([]mango.Node)
+		conds := make([]string, 0, len(sels))
+		exact := true
+		for _, sel := range sels {
+			cond, ok := s.node(sel)
+			exact = exact && ok
+			if cond == "TRUE" {
+				if n.Op() == mango.OpOr {
+					return "TRUE", false
+				}
+				continue
+			}
+			conds = append(conds, cond)
+		}
+		if len(conds) == 0 {
+			return "TRUE", exact
+		}
+		sep := " AND "
+		if n.Op() == mango.OpOr {
+			sep = " OR "
+		}
+		return "(" + strings.Join(conds, sep) + ")", exact
+	case mango.OpNor:
+		sels, _ := n.Value().([]mango.Node)
+		conds := make([]string, 0, len(sels))
+		for _, sel := range sels {
+			cond, exact := s.node(sel)
+			if !exact {
+				return "TRUE", false
+			}
+			conds = append(conds, cond)
+		}
+		if len(conds) == 0 {
+			return "TRUE", true
+		}
+		return not("(" + strings.Join(conds, " OR ") + ")"), true
+	case mango.OpNot:
+		sel, _ := n.Value().(mango.Node)
+		if sel == nil {
+			return "TRUE", false
+		}
+		cond, exact := s.node(sel)
+		if !exact {
+			return "TRUE", false
+		}
+		return not(cond), true
+	}
+	// A bare condition applies to the document itself.
+	return "TRUE", false
+}
+
+// field returns the target for the named top-level document field.
+func (s *selectorSQL) field(name string) (sqlTarget, bool) {
+	switch name {
+	case "_id":
+		return sqlTarget{json: "json_quote(view.id)", typ: "'text'", val: "view.id"}, true
+	case "_rev":
+		const rev = "(view.rev || '-' || view.rev_id)"
+		return sqlTarget{json: "json_quote" + rev, typ: "'text'", val: rev}, true
+	}
+	if strings.HasPrefix(name, "_") || strings.Contains(name, `"`) {
+		// Other special fields aren't stored in the document body, and
+		// quotes can't be expressed in a JSON path.
+		return sqlTarget{}, false
+	}
+	path := s.arg(`$."` + name + `"`)
+	return sqlTarget{
+		json: fmt.Sprintf("(%s -> %s)", s.doc, path),
+		typ:  fmt.Sprintf("json_type(%s, %s)", s.doc, path),
+		val:  fmt.Sprintf("json_extract(%s, %s)", s.doc, path),
+	}, true
+}
+
+// condition returns a condition applying n to the target value.
+func (s *selectorSQL) condition(t sqlTarget, n mango.Node) (string, bool) {
+	switch op, v := n.Op(), n.Value(); op {
+	case mango.OpEqual:
+		return s.equal(t, v)
+	case mango.OpNotEqual:
+		cond, exact := s.equal(t, v)
+		if !exact {
+			return t.typ + " IS NOT NULL", false
+		}
+		return "(" + t.typ + " IS NOT NULL AND " + not(cond) + ")", true
+	case mango.OpLessThan, mango.OpLessThanOrEqual, mango.OpGreaterThan, mango.OpGreaterThanOrEqual:
+		return s.compare(t, op, v)
+	case mango.OpIn, mango.OpNotIn:
+		values, _ := v.([]interface{})
+		conds := make([]string, 0, len(values))
+		exact := true
+		for _, value := range values {
+			cond, ok := s.equal(t, value)
+			exact = exact && ok
+			conds = append(conds, cond)
+		}
+		in := "FALSE"
+		if len(conds) > 0 {
+			in = "(" + strings.Join(conds, " OR ") + ")"
+		}
+		if op == mango.OpIn {
+			return in, exact
+		}
+		if !exact {
+			return t.typ + " IS NOT NULL", false
+		}
+		return "(" + t.typ + " IS NOT NULL AND " + not(in) + ")", true
+	case mango.OpExists:
+		if exists, _ := v.(bool); exists {
+			return t.typ + " IS NOT NULL", false
+		}
+		return "TRUE", false
+	case mango.OpType:
+		var types []string
+		switch v {
+		case "null":
+			types = []string{"null"}
+		case "boolean":
+			types = []string{"true", "false"}
+		case "number":
+			types = []string{"integer", "real"}
+		case "string":
+			types = []string{"text"}
+		case "array":
+			types = []string{"array"}
+		case "object":
+			types = []string{"object"}
+		default:
+			return "TRUE", false
+		}
+		return t.typ + " IN ('" + strings.Join(types, "', '") + "')", true
+	case mango.OpSize:
+		return fmt.Sprintf("(%s = 'array' AND json_array_length(%s) = %s)", t.typ, t.json, s.arg(v)), true
+	case mango.OpMod:
+		mod, ok := v.([2]int64)
+		if !ok {
+			return "TRUE", false
+		}
+		return fmt.Sprintf("(%[1]s IN ('integer', 'real') AND %[2]s = CAST(%[2]s AS INTEGER) AND CAST(%[2]s AS INTEGER) %% %[3]s = %[4]s)",
+			t.typ, t.val, s.arg(mod[0]), s.arg(mod[1])), true
+	case mango.OpRegex:
+		re, ok := v.(*regexp.Regexp)
+		if !ok {
+			return "TRUE", false
+		}
+		return fmt.Sprintf("(%s = 'text' AND %s REGEXP %s)", t.typ, t.val, s.arg(re.String())), true
+	case mango.OpBeginsWith:
+		prefix, ok := v.(string)
+		if !ok {
+			return "TRUE", false
+		}
+		p := s.arg(prefix)
+		return fmt.Sprintf("(%s = 'text' AND substr(%s, 1, length(%s)) = %s)", t.typ, t.val, p, p), true
+	case mango.OpAll:
+		values, _ := v.([]interface{})
+		conds := []string{t.typ + " = 'array'"}
+		exact := true
+		for _, value := range values {
+			alias := s.alias()
+			cond, ok := s.equal(s.element(t, alias), value)
+			if !ok {
+				exact = false
+				continue
+			}
+			conds = append(conds, fmt.Sprintf("EXISTS (SELECT 1 FROM json_each(%s) AS %s WHERE %s)", t.json, alias, cond))
+		}
+		return "(" + strings.Join(conds, " AND ") + ")", exact
+	case mango.OpElemMatch, mango.OpAllMatch:
+		sel, _ := v.(mango.Node)
+		if _, isField := sel.(mango.FieldNode); sel == nil || isField {
+			// Field selectors on elements are left to the Go-side matcher.
+			return t.typ + " = 'array'", false
+		}
+		alias := s.alias()
+		cond, exact := s.condition(s.element(t, alias), sel)
+		if op == mango.OpElemMatch {
+			return fmt.Sprintf("(%s = 'array' AND EXISTS (SELECT 1 FROM json_each(%s) AS %s WHERE %s))", t.typ, t.json, alias, cond), exact
+		}
+		if !exact {
+			return t.typ + " = 'array'", false
+		}
+		return fmt.Sprintf("(%s = 'array' AND NOT EXISTS (SELECT 1 FROM json_each(%s) AS %s WHERE %s))", t.typ, t.json, alias, not(cond)), true
+	case mango.OpKeyMapMatch:
+		return t.typ + " = 'object'", false
+	}
+	return "TRUE", false
+}
+
+// element returns the target for the elements of the array t, as iterated by
+// json_each with the given alias.
+func (s *selectorSQL) element(t sqlTarget, alias string) sqlTarget {
+	return sqlTarget{
+		json: fmt.Sprintf("(%s -> %s.fullkey)", t.json, alias),
+		typ:  alias + ".type",
+		val:  alias + ".value",
+	}
+}
+
+// equal returns a condition matching values equal to v.
+func (s *selectorSQL) equal(t sqlTarget, v interface{}) (string, bool) {
+	switch value := v.(type) {
+	case nil:
+		return t.typ + " = 'null'", true
+	case bool:
+		if value {
+			return t.typ + " = 'true'", true
+		}
+		return t.typ + " = 'false'", true
+	case float64:
+		return fmt.Sprintf("(%s IN ('integer', 'real') AND %s = %s)", t.typ, t.val, s.arg(value)), true
+	case string:
+		return fmt.Sprintf("(%s = 'text' AND %s = %s COLLATE COUCHDB_STRING)", t.typ, t.val, s.arg(value)), true
+	case []interface{}:
+		return t.typ + " = 'array'", false
+	}
+	return t.typ + " = 'object'", false
+}
+
+// compare returns a condition matching values which collate relative to v
+// as specified by op.
+func (s *selectorSQL) compare(t sqlTarget, op mango.Operator, v interface{}) (string, bool) {
+	var sqlOp string
+	switch op {
+	case mango.OpLessThan:
+		sqlOp = "<"
+	case mango.OpLessThanOrEqual:
+		sqlOp = "<="
+	case mango.OpGreaterThan:
+		sqlOp = ">"
+	default:
+		sqlOp = ">="
+	}
+	strictOp := sqlOp[:1]
+	rank := valueRank(v)
+	var sameType string
+	switch value := v.(type) {
+	case nil, bool:
+		// Values of these types are equal when their ranks are.
+		if len(sqlOp) == 1 {
+			sameType = "FALSE"
+		} else {
+			sameType = "TRUE"
+		}
+	case float64:
+		sameType = fmt.Sprintf("%s %s %s", t.val, sqlOp, s.arg(value))
+	case string:
+		sameType = fmt.Sprintf("%s %s %s COLLATE COUCHDB_STRING", t.val, sqlOp, s.arg(value))
+	default:
+		// Arrays and objects are only narrowed by type.
+		return fmt.Sprintf("%s %s %d", t.rank(), sqlOp[:1]+"=", rank), false
+	}
+	return fmt.Sprintf("(%[1]s %[2]s %[3]d OR (%[1]s = %[3]d AND %[4]s))", t.rank(), strictOp, rank, sameType), true
+}
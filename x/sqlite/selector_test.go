@@ -0,0 +1,153 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package sqlite
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/go-kivik/kivik/v4/x/mango"
+)
+
+func TestSelectorWhere(t *testing.T) {
+	t.Parallel()
+	docs := map[string]string{
+		"a": `{"n": 1, "s": "a", "b": true, "tags": ["x", "y"], "obj": {"k": 1}}`,
+		"b": `{"n": 1.5, "s": "A", "b": false, "tags": ["y"]}`,
+		"c": `{"n": 2, "s": "b", "b": null, "tags": [], "z": null}`,
+		"d": `{"n": -3, "s": "ab", "tags": [1, 2, 3]}`,
+		"e": `{"s": 4, "n": "x", "tags": "x"}`,
+		"f": `{}`,
+	}
+	d := newDB(t)
+	docMaps := make(map[string]map[string]interface{}, len(docs))
+	for id, doc := range docs {
+		var m map[string]interface{}
+		if err := json.Unmarshal([]byte(doc), &m); err != nil {
+			t.Fatal(err)
+		}
+		_ = d.tPut(id, m)
+		m["_id"] = id
+		docMaps[id] = m
+	}
+
+	tests := []struct {
+		selector string
+		exact    bool
+	}{
+		{`{}`, true},
+		{`{"s": "a"}`, true},
+		{`{"n": 1.5}`, true},
+		{`{"b": false}`, true},
+		{`{"b": null}`, true},
+		{`{"_id": "c"}`, true},
+		{`{"_id": {"$gt": "b"}}`, true},
+		{`{"n": {"$gt": 1}}`, true},
+		{`{"$and": [{"n": {"$gte": 1}}, {"n": {"$lt": 2}}]}`, true},
+		{`{"n": {"$lt": "a"}}`, true},
+		{`{"s": {"$gt": "a"}}`, true},
+		{`{"s": {"$lte": "A"}}`, true},
+		{`{"b": {"$gt": false}}`, true},
+		{`{"b": {"$lte": null}}`, true},
+		{`{"n": {"$ne": 1}}`, true},
+		{`{"n": {"$in": [1, 2, "x"]}}`, true},
+		{`{"n": {"$nin": [1, 2]}}`, true},
+		{`{"z": {"$exists": true}}`, false},
+		{`{"n": {"$type": "number"}}`, true},
+		{`{"b": {"$type": "boolean"}}`, true},
+		{`{"tags": {"$size": 1}}`, true},
+		{`{"n": {"$mod": [2, 0]}}`, true},
+		{`{"s": {"$regex": "^a"}}`, true},
+		{`{"s": {"$beginsWith": "a"}}`, true},
+		{`{"s": {"$beginsWith": ""}}`, true},
+		{`{"tags": {"$all": ["x", "y"]}}`, true},
+		{`{"tags": {"$elemMatch": {"$gt": 1}}}`, true},
+		{`{"tags": {"$allMatch": {"$gt": 1}}}`, true},
+		{`{"tags": {"$elemMatch": {"k": 1}}}`, false},
+		{`{"tags": {"$eq": ["y"]}}`, false},
+		{`{"obj": {"$gt": {}}}`, false},
+		{`{"$or": [{"s": "a"}, {"n": 2}]}`, true},
+		{`{"$or": [{"s": "a"}, {"tags": {"$eq": ["y"]}}]}`, false},
+		{`{"$nor": [{"s": "a"}, {"n": 2}]}`, true},
+		{`{"$not": {"n": {"$gt": 1}}}`, true},
+		{`{"$not": {"tags": {"$eq": ["y"]}}}`, false},
+		{`{"$and": [{"n": {"$type": "number"}}, {"$not": {"s": {"$regex": "b"}}}]}`, true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.selector, func(t *testing.T) {
+			t.Parallel()
+			var sel mango.Selector
+			if err := json.Unmarshal([]byte(tt.selector), &sel); err != nil {
+				t.Fatal(err)
+			}
+			var want []string
+			for id, doc := range docMaps {
+				if sel.Match(doc) {
+					want = append(want, id)
+				}
+			}
+			sort.Strings(want)
+
+			var args []any
+			s := &selectorSQL{doc: "view.doc", args: &args}
+			cond, exact := s.node(sel.Root())
+			if exact != tt.exact {
+				t.Errorf("Unexpected exactness: %t", exact)
+			}
+			underlying := d.DB.(*db)
+			rows, err := underlying.db.QueryContext(context.Background(), underlying.query(`
+				SELECT view.id
+				FROM {{ .Docs }} AS view
+				WHERE `+cond+`
+				ORDER BY view.id
+			`), args...)
+			if err != nil {
+				t.Fatalf("%s: %s", err, cond)
+			}
+			defer rows.Close()
+			var got []string
+			for rows.Next() {
+				var id string
+				if err := rows.Scan(&id); err != nil {
+					t.Fatal(err)
+				}
+				got = append(got, id)
+			}
+			if err := rows.Err(); err != nil {
+				t.Fatal(err)
+			}
+
+			if exact {
+				if d := cmp.Diff(want, got); d != "" {
+					t.Errorf("Unexpected result for %s (-want +got):\n%s", cond, d)
+				}
+				return
+			}
+			// An inexact condition must match at least the selected documents.
+			gotSet := make(map[string]bool, len(got))
+			for _, id := range got {
+				gotSet[id] = true
+			}
+			for _, id := range want {
+				if !gotSet[id] {
+					t.Errorf("Expected %s to match %s", id, cond)
+				}
+			}
+		})
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"log"
 	"net/http"
 	"regexp"
+	"sync"
 
 	"modernc.org/sqlite"
 
@@ -64,6 +65,9 @@ type client struct {
 	dsn    string
 	db     *sql.DB
 	logger *log.Logger
+	// migrated records the names of databases already brought up to date by
+	// migrate.
+	migrated sync.Map
 }
 
 var _ driver.Client = (*client)(nil)
@@ -73,7 +77,7 @@ const (
 	vendor  = "Kivik"
 )
 
-func (client) Version(context.Context) (*driver.Version, error) {
+func (*client) Version(context.Context) (*driver.Version, error) {
 	return &driver.Version{
 		Version: version,
 		Vendor:  vendor,
@@ -81,14 +85,23 @@ func (client) Version(context.Context) (*driver.Version, error) {
 }
 
 func (c *client) AllDBs(ctx context.Context, _ driver.Options) ([]string, error) {
+	// Each database is stored in several tables; only the main table, which
+	// has a companion revs table, is reported.
 	rows, err := c.db.QueryContext(ctx, `
 		SELECT
 			name
 		FROM
-			sqlite_schema
+			sqlite_schema AS db
 		WHERE
 			type ='table' AND
-			name NOT LIKE 'sqlite_%'
+			name NOT LIKE 'sqlite_%' AND
+			EXISTS (
+				SELECT 1
+				FROM sqlite_schema AS revs
+				WHERE revs.type = 'table'
+					AND revs.name = db.name || '_revs'
+			)
+		ORDER BY name
 		`)
 	if err != nil {
 		return nil, err
@@ -136,10 +149,18 @@ func validateDBName(name string) error {
 	return nil
 }
 
-func (c *client) CreateDB(ctx context.Context, name string, _ driver.Options) error {
+func (c *client) CreateDB(ctx context.Context, name string, options driver.Options) error {
 	if err := validateDBName(name); err != nil {
 		return err
 	}
+	var partitioned bool
+	if options != nil {
+		var err error
+		partitioned, err = newOpts(options).partitioned()
+		if err != nil {
+			return err
+		}
+	}
 	tx, err := c.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
@@ -156,30 +177,40 @@ func (c *client) CreateDB(ctx context.Context, name string, _ driver.Options) er
 			return err
 		}
 	}
+	if partitioned {
+		_, err := tx.ExecContext(ctx, d.query(`
+			INSERT INTO {{ .Settings }} (key, value)
+			VALUES ($1, 1)
+		`), settingPartitioned)
+		if err != nil {
+			return err
+		}
+	}
 
-	return tx.Commit()
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	c.migrated.Store(name, true)
+	return nil
 }
 
 func (c *client) DestroyDB(ctx context.Context, name string, _ driver.Options) error {
 	if err := validateDBName(name); err != nil {
 		return err
 	}
+	d := c.newDB(name)
+	if err := c.migrate(ctx, d); err != nil {
+		return err
+	}
 	tx, err := c.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
 	defer tx.Rollback()
 
-	d := c.newDB(name)
-	rows, err := tx.QueryContext(ctx, d.query(`
-		SELECT
-			id,
-			rev,
-			rev_id,
-			func_name
-		FROM {{ .Design }}
-		WHERE func_type = 'map'
-	`))
+	// Collect the views first, as SQLite can't drop a table while a
+	// statement is still reading from the same database.
+	views, err := d.mapViews(ctx, tx)
 	if err != nil {
 		if errIsNoSuchTable(err) {
 			return &internal.Error{Status: http.StatusNotFound, Message: "database not found"}
@@ -187,23 +218,12 @@ func (c *client) DestroyDB(ctx context.Context, name string, _ driver.Options) e
 		return err
 	}
 
-	defer rows.Close()
-	for rows.Next() {
-		var (
-			id, view string
-			rev      revision
-		)
-		if err := rows.Scan(&id, &rev.rev, &rev.id, &view); err != nil {
-			return err
-		}
-		_, err := tx.ExecContext(ctx, d.ddocQuery(id, view, rev.String(), `DROP TABLE {{ .Map }}`))
+	for _, v := range views {
+		_, err := tx.ExecContext(ctx, d.ddocQuery(v.id, v.view, v.rev.String(), `DROP TABLE IF EXISTS {{ .Map }}`))
 		if err != nil {
 			return err
 		}
 	}
-	if err := rows.Err(); err != nil {
-		return err
-	}
 
 	for _, query := range destroySchema {
 		_, err := tx.ExecContext(ctx, d.query(query))
@@ -214,12 +234,20 @@ func (c *client) DestroyDB(ctx context.Context, name string, _ driver.Options) e
 			return err
 		}
 	}
-	return tx.Commit()
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	c.migrated.Delete(name)
+	return nil
 }
 
 func (c *client) DB(name string, _ driver.Options) (driver.DB, error) {
 	if err := validateDBName(name); err != nil {
 		return nil, err
 	}
-	return c.newDB(name), nil
+	d := c.newDB(name)
+	if err := c.migrate(context.Background(), d); err != nil {
+		return nil, err
+	}
+	return d, nil
 }
@@ -59,20 +59,17 @@ func TestClientAllDBs(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	c := dClient.(*client)
-
-	if _, err := c.db.Exec("CREATE TABLE foo (id INTEGER)"); err != nil {
-		t.Fatal(err)
-	}
-	if _, err := c.db.Exec("CREATE TABLE bar (id INTEGER)"); err != nil {
-		t.Fatal(err)
+	for _, name := range []string{"foo", "bar"} {
+		if err := dClient.CreateDB(context.Background(), name, mock.NilOption); err != nil {
+			t.Fatal(err)
+		}
 	}
 
 	dbs, err := dClient.AllDBs(context.Background(), mock.NilOption)
 	if err != nil {
 		t.Fatal("err should be nil")
 	}
-	wantDBs := []string{"foo", "bar"}
+	wantDBs := []string{"bar", "foo"}
 	if d := cmp.Diff(wantDBs, dbs); d != "" {
 		t.Fatal(d)
 	}
@@ -156,6 +153,20 @@ func TestClientCreateDB(t *testing.T) {
 			t.Fatal("foo should exist")
 		}
 	})
+	t.Run("multiple databases", func(t *testing.T) {
+		d := drv{}
+		dClient, err := d.NewClient(":memory:", mock.NilOption)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := dClient.CreateDB(context.Background(), "foo", mock.NilOption); err != nil {
+			t.Fatal(err)
+		}
+		if err := dClient.CreateDB(context.Background(), "bar", mock.NilOption); err != nil {
+			t.Fatal(err)
+		}
+	})
 	t.Run("db already exists", func(t *testing.T) {
 		d := drv{}
 		dClient, err := d.NewClient(":memory:", mock.NilOption)
@@ -206,6 +217,38 @@ func TestClientDestroyDB(t *testing.T) {
 			t.Fatal("foo should not exist")
 		}
 	})
+	t.Run("with a built view index", func(t *testing.T) {
+		d := drv{}
+		dClient, err := d.NewClient(":memory:", mock.NilOption)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := dClient.CreateDB(context.Background(), "foo", mock.NilOption); err != nil {
+			t.Fatal(err)
+		}
+		db, err := dClient.DB("foo", mock.NilOption)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ddoc := map[string]interface{}{
+			"views": map[string]interface{}{
+				"bar": map[string]string{"map": "function(doc) { emit(doc._id); }"},
+			},
+		}
+		if _, err := db.Put(context.Background(), "_design/foo", ddoc, mock.NilOption); err != nil {
+			t.Fatal(err)
+		}
+		rows, err := db.Query(context.Background(), "_design/foo", "_view/bar", mock.NilOption)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = rows.Close()
+
+		if err := dClient.DestroyDB(context.Background(), "foo", mock.NilOption); err != nil {
+			t.Fatal(err)
+		}
+	})
 	t.Run("doesn't exist", func(t *testing.T) {
 		d := drv{}
 		dClient, err := d.NewClient(":memory:", mock.NilOption)
@@ -0,0 +1,63 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+package sqlite
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/go-kivik/kivik/v4/driver"
+)
+
+func (d *db) Stats(ctx context.Context) (*driver.DBStats, error) {
+	var (
+		docCount, deletedCount int64
+		updateSeq              int
+	)
+	err := d.db.QueryRowContext(ctx, d.query(`
+		WITH leaves AS (
+			SELECT
+				rev.id,
+				doc.deleted
+			FROM {{ .Revs }} AS rev
+			LEFT JOIN {{ .Revs }} AS child ON child.id = rev.id AND rev.rev = child.parent_rev AND rev.rev_id = child.parent_rev_id
+			JOIN {{ .Docs }} AS doc ON rev.id = doc.id AND rev.rev = doc.rev AND rev.rev_id = doc.rev_id
+			WHERE child.id IS NULL
+				AND SUBSTR(rev.id, 1, 7) != '_local/'
+		),
+		live AS (
+			SELECT COUNT(DISTINCT id) AS count
+			FROM leaves
+			WHERE NOT deleted
+		)
+		SELECT
+			live.count,
+			(SELECT COUNT(DISTINCT id) FROM leaves) - live.count,
+			(SELECT COALESCE(MAX(seq), 0) FROM {{ .Docs }})
+		FROM live
+	`)).Scan(&docCount, &deletedCount, &updateSeq)
+	if err != nil {
+		return nil, d.errDatabaseNotFound(err)
+	}
+	partitioned, err := d.isPartitioned(ctx, d.db)
+	if err != nil {
+		return nil, err
+	}
+	return &driver.DBStats{
+		Name:         d.name,
+		DocCount:     docCount,
+		DeletedCount: deletedCount,
+		UpdateSeq:    strconv.Itoa(updateSeq),
+		Partitioned:  partitioned,
+	}, nil
+}
@@ -0,0 +1,96 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+//go:build !js
+
+package sqlite
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"gitlab.com/flimzy/testy"
+
+	"github.com/go-kivik/kivik/v4"
+	"github.com/go-kivik/kivik/v4/driver"
+)
+
+func TestDBStats(t *testing.T) {
+	t.Parallel()
+	type test struct {
+		db         *testDB
+		want       *driver.DBStats
+		wantErr    string
+		wantStatus int
+	}
+	tests := testy.NewTable()
+	tests.Add("empty database", test{
+		want: &driver.DBStats{
+			Name:      "test",
+			UpdateSeq: "0",
+		},
+	})
+	tests.Add("live, deleted and local docs", func(t *testing.T) interface{} {
+		db := newDB(t)
+		_ = db.tPut("foo", map[string]string{"foo": "bar"})
+		_ = db.tPut("bar", map[string]string{"foo": "bar"})
+		rev := db.tPut("baz", map[string]string{"foo": "bar"})
+		_ = db.tDelete("baz", kivik.Rev(rev))
+		_ = db.tPut("_local/qux", map[string]string{"foo": "bar"})
+
+		return test{
+			db: db,
+			want: &driver.DBStats{
+				Name:         "test",
+				DocCount:     2,
+				DeletedCount: 1,
+				UpdateSeq:    "5",
+			},
+		}
+	})
+	tests.Add("database missing", func(t *testing.T) interface{} {
+		db := newDB(t)
+		_, err := db.underlying().Exec(`DROP TABLE "test"`)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return test{
+			db:         db,
+			wantErr:    "database not found: test",
+			wantStatus: http.StatusNotFound,
+		}
+	})
+
+	tests.Run(t, func(t *testing.T, tt test) {
+		t.Parallel()
+		db := tt.db
+		if db == nil {
+			db = newDB(t)
+		}
+		got, err := db.Stats(context.Background())
+		if !testy.ErrorMatches(tt.wantErr, err) {
+			t.Errorf("Unexpected error: %s", err)
+		}
+		if status := kivik.HTTPStatus(err); status != tt.wantStatus {
+			t.Errorf("Unexpected status: %d", status)
+		}
+		if err != nil {
+			return
+		}
+		if d := cmp.Diff(tt.want, got); d != "" {
+			t.Error(d)
+		}
+	})
+}
@@ -57,6 +57,25 @@ func (t *tmplFuncs) Design() string {
 	return strconv.Quote(t.db.name + "_design")
 }
 
+func (t *tmplFuncs) Settings() string {
+	return strconv.Quote(t.db.name + "_settings")
+}
+
+func (t *tmplFuncs) PurgedInfos() string {
+	return strconv.Quote(t.db.name + "_purged_infos")
+}
+
+// IndexRevsKey and IndexRevsParent return the names of the indexes on the revs
+// table. SQLite index names share a single namespace, so they must include the
+// database name.
+func (t *tmplFuncs) IndexRevsKey() string {
+	return strconv.Quote("idx_" + t.db.name + "_revs_key")
+}
+
+func (t *tmplFuncs) IndexRevsParent() string {
+	return strconv.Quote("idx_" + t.db.name + "_revs_parent")
+}
+
 const maxTableLen = 59 // 64 minus the `idx_` prefix, and one more `_` separator
 
 // hashedName returns a table name in the format "{{db name}}_{{ddoc}}_{{typ}}_{{hash}}"
@@ -69,7 +88,9 @@ func (t *tmplFuncs) hashedName(typ string) string {
 	}
 	name := strings.Join([]string{t.ddoc, t.rev, t.viewName}, "_")
 	if t.hash == "" {
-		t.hash = md5sumString(name)[:8]
+		// The database name is included in the hash, so that identical design
+		// documents in different databases don't share a table.
+		t.hash = md5sumString(t.db.name + "_" + name)[:8]
 	}
 	name += "_" + typ
 	if len(name) > maxTableLen-len(t.hash) {
@@ -106,6 +127,10 @@ func (t *tmplFuncs) Collation() string {
 //	{{ .Attachments }} -> db.name + "_attachments"
 //	{{ .AttachmentsBridge }} -> db.name + "_attachments_bridge"
 //	{{ .Design }} -> db.name + "_design"
+//	{{ .Settings }} -> db.name + "_settings"
+//	{{ .PurgedInfos }} -> db.name + "_purged_infos"
+//	{{ .IndexRevsKey }} -> "idx_" + db.name + "_revs_key"
+//	{{ .IndexRevsParent }} -> "idx_" + db.name + "_revs_parent"
 func (d *db) query(format string) string {
 	var buf bytes.Buffer
 	tmpl := getTmpl(format)
@@ -0,0 +1,148 @@
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy of
+// the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations under
+// the License.
+
+// Package test provides SQLite integration tests.
+package test
+
+import (
+	"context"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-kivik/kivik/v4"
+	"github.com/go-kivik/kivik/v4/kiviktest"
+	"github.com/go-kivik/kivik/v4/kiviktest/kt"
+)
+
+// RegisterSQLiteSuite registers the SQLite integration test suite.
+func RegisterSQLiteSuite() {
+	kiviktest.RegisterSuite(kiviktest.SuiteKivikSQLite, kt.SuiteConfig{
+		"AllDBs.expected": []string{"_users"},
+
+		"CreateDB/RW/Admin/Recreate.status": http.StatusPreconditionFailed,
+
+		"DestroyDB/RW/Admin/NonExistantDB.status": http.StatusNotFound,
+
+		"DBExists.databases":             []string{"_users", "chicken"},
+		"DBExists/Admin/_users.exists":   true,
+		"DBExists/Admin/chicken.exists":  false,
+		"DBExists/RW/group/Admin.exists": true,
+
+		"AllDBsStats.expected": []*kivik.DBStats{
+			{
+				Name:      "_users",
+				UpdateSeq: "0",
+			},
+		},
+
+		"Stats.databases":            []string{"_users", "chicken"},
+		"Stats/Admin/chicken.status": http.StatusNotFound,
+
+		"AllDocs.databases":            []string{"_users", "chicken"},
+		"AllDocs/Admin/chicken.status": http.StatusNotFound,
+
+		"Version.version":        `^0\.0\.1$`,
+		"Version.vendor":         `^Kivik$`,
+		"Version.vendor_version": ``,
+
+		"Get/RW/group/Admin/bogus.status": http.StatusNotFound,
+
+		"GetRev/RW/group/Admin/bogus.status": http.StatusNotFound,
+
+		"Put/RW/Admin/group/LeadingUnderscoreInID.status": http.StatusBadRequest,
+		"Put/RW/Admin/group/Conflict.status":              http.StatusConflict,
+
+		"Delete/RW/Admin/group/MissingDoc.status":       http.StatusNotFound,
+		"Delete/RW/Admin/group/InvalidRevFormat.status": http.StatusBadRequest,
+		"Delete/RW/Admin/group/WrongRev.status":         http.StatusConflict,
+
+		"BulkDocs/RW/Admin/group/Mix/Conflict.status": http.StatusConflict,
+
+		"GetAttachment/RW/group/Admin/foo/NotFound.status": http.StatusNotFound,
+
+		"GetAttachmentMeta/RW/group/Admin/foo/NotFound.status": http.StatusNotFound,
+
+		"PutAttachment/RW/group/Admin/Conflict.status": http.StatusConflict,
+
+		"DeleteAttachment/RW/group/Admin/NotFound.status": http.StatusNotFound,
+		"DeleteAttachment/RW/group/Admin/NoDoc.status":    http.StatusConflict,
+
+		"Query/RW/group/Admin/WithoutDocs/ScanDoc.status": http.StatusBadRequest,
+
+		"Find.databases":                      []string{"chicken"},
+		"Find/Admin/chicken.status":           http.StatusNotFound,
+		"Find/RW/group/Admin/Warning.warning": "No matching index found, create an index to optimize query time.",
+
+		"Changes/Continuous.options": kivik.Params(map[string]interface{}{
+			"feed":  "continuous",
+			"since": "now",
+		}),
+
+		// Unsupported features
+		"Log.status":          http.StatusNotImplemented,
+		"Log/Admin/HTTP.skip": true,
+
+		"Flush.databases":                   []string{"_users"},
+		"Flush/Admin/_users/DoFlush.status": http.StatusNotImplemented,
+
+		"Security.databases": []string{"_users"},
+		"Security.status":    http.StatusNotImplemented,
+		"Security/RW.skip":   true, // setup requires SetSecurity
+		"SetSecurity.status": http.StatusNotImplemented,
+
+		"DBUpdates.status": http.StatusNotImplemented,
+
+		"ViewCleanup/RW/Admin.status": http.StatusNotImplemented,
+
+		"Explain.databases": []string{"_users"},
+		"Explain.status":    http.StatusNotImplemented,
+
+		"CreateIndex/RW/Admin/group/EmptyIndex.status":   http.StatusBadRequest,
+		"CreateIndex/RW/Admin/group/BlankIndex.status":   http.StatusBadRequest,
+		"CreateIndex/RW/Admin/group/InvalidIndex.status": http.StatusBadRequest,
+		"CreateIndex/RW/Admin/group/NilIndex.status":     http.StatusBadRequest,
+		"CreateIndex/RW/Admin/group/InvalidJSON.status":  http.StatusBadRequest,
+
+		"GetIndexes.databases":            []string{"_users", "chicken"},
+		"GetIndexes/Admin/_users.indexes": []kivik.Index{kt.AllDocsIndex},
+		"GetIndexes/Admin/chicken.status": http.StatusNotFound,
+
+		"DeleteIndex/RW/Admin/group/NotFoundDdoc.status": http.StatusNotFound,
+		"DeleteIndex/RW/Admin/group/NotFoundName.status": http.StatusNotFound,
+
+		// Replications not to be implemented
+		"GetReplications.skip": true,
+		"Replicate.skip":       true,
+	})
+}
+
+// SQLiteTest runs the integration tests for the SQLite driver.
+func SQLiteTest(t *testing.T) {
+	t.Helper()
+	// A file-backed database in WAL mode is used, so that open iterators,
+	// such as a changes feed, don't block concurrent writes.
+	dsn := "file:" + filepath.Join(t.TempDir(), "kiviktest.db") + "?_pragma=journal_mode(WAL)&_pragma=busy_timeout(5000)"
+	client, err := kivik.New("sqlite", dsn)
+	if err != nil {
+		t.Fatalf("Failed to connect to SQLite driver: %s\n", err)
+	}
+	clients := &kt.Context{
+		RW:    true,
+		Admin: client,
+		T:     t,
+	}
+	if err := client.CreateDB(context.Background(), "_users"); err != nil {
+		t.Fatal(err)
+	}
+	kiviktest.RunTestsInternal(clients, kiviktest.SuiteKivikSQLite)
+}
@@ -162,8 +162,10 @@ func (d *db) createRev(ctx context.Context, tx *sql.Tx, data *docData, curRev re
 	if err := d.createDocAttachments(ctx, data, tx, r, &curRev); err != nil {
 		return r, err
 	}
-	err = d.updateDesignDoc(ctx, tx, r, data)
-	return r, err
+	if err := d.updateDesignDoc(ctx, tx, r, data); err != nil {
+		return r, err
+	}
+	return r, d.pruneRevs(ctx, tx, data.ID)
 }
 
 func (d *db) createDocAttachments(ctx context.Context, data *docData, tx *sql.Tx, r revision, curRev *revision) error {
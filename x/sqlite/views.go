@@ -83,6 +83,16 @@ func (d *db) queryBuiltinView(
 	args := []interface{}{vopts.includeDocs, vopts.conflicts, vopts.updateSeq, vopts.attachments, vopts.bookmark}
 
 	where := append([]string{""}, vopts.buildWhere(&args)...)
+	if vopts.selector != nil {
+		where = append(where, selectorWhere(vopts.selector, "view.doc", &args))
+	}
+	if vopts.index != nil && vopts.index.partial != nil {
+		where = append(where, selectorWhere(vopts.index.partial, "view.doc", &args))
+	}
+	filter := vopts.builtinViewFilter()
+	if filter == "" {
+		filter = "TRUE"
+	}
 
 	query := fmt.Sprintf(d.query(leavesCTE+`,
 		main AS (
@@ -105,7 +115,7 @@ func (d *db) queryBuiltinView(
 				SELECT
 					view.id,
 					view.key,
-					'{"value":{"rev":"' || view.rev || '-' || view.rev_id || '"}}' AS value,
+					'{"rev":"' || view.rev || '-' || view.rev_id || '"}' AS value,
 					view.rev || '-' || view.rev_id AS rev,
 					view.doc,
 					view.conflicts,
@@ -158,9 +168,9 @@ func (d *db) queryBuiltinView(
 			TRUE                  AS up_to_date,
 			FALSE                 AS reducible,
 			""                    AS reduce_func,
-			IIF($3, MAX(seq), "") AS update_seq,
-			NULL,
+			IIF($3, COALESCE(MAX(seq), 0), "") AS update_seq,
 			NULL,
+			(SELECT COUNT(DISTINCT id) FROM leaves AS view WHERE %[6]s) AS total_rows,
 			NULL AS attachment_count,
 			NULL AS filename,
 			NULL AS content_type,
@@ -188,7 +198,7 @@ func (d *db) queryBuiltinView(
 			data
 		FROM main
 		%[5]s -- bookmark filtering
-	`), vopts.buildOrderBy(), strings.Join(where, " AND "), vopts.limit, vopts.skip, vopts.bookmarkWhere())
+	`), vopts.buildOrderBy(), strings.Join(where, " AND "), vopts.limit, vopts.skip, vopts.bookmarkWhere(), filter)
 	results, err := d.db.QueryContext(ctx, query, args...) //nolint:rowserrcheck // Err checked in Next
 	if err != nil {
 		return nil, d.errDatabaseNotFound(err)
@@ -204,10 +214,14 @@ func (d *db) queryBuiltinView(
 		db:        d,
 		rows:      results,
 		updateSeq: meta.updateSeq,
+		totalRows: meta.totalRows,
 		selector:  vopts.selector,
 		findLimit: vopts.findLimit,
 		findSkip:  vopts.findSkip,
 		fields:    vopts.fields,
+		findIndex: vopts.index,
+		terms:     vopts.terms,
+		warnings:  vopts.warnings,
 	}, nil
 }
 
@@ -217,6 +231,7 @@ type viewMetadata struct {
 	reduceFuncJS string
 	updateSeq    string
 	lastSeq      int
+	totalRows    int64
 }
 
 // readFirstRow reads the first row from the resultset, which contains. In the
@@ -228,9 +243,12 @@ func readFirstRow(results *sql.Rows, vopts *viewOptions) (*viewMetadata, error)
 		return nil, errors.New("no rows returned")
 	}
 	var meta viewMetadata
-	var lastSeq *int
+	var (
+		lastSeq   *int
+		totalRows *int64
+	)
 	if err := results.Scan(
-		&meta.upToDate, &meta.reducible, &meta.reduceFuncJS, &meta.updateSeq, &lastSeq, discard{},
+		&meta.upToDate, &meta.reducible, &meta.reduceFuncJS, &meta.updateSeq, &lastSeq, &totalRows,
 		discard{}, discard{}, discard{}, discard{}, discard{}, discard{}, discard{},
 	); err != nil {
 		_ = results.Close() //nolint:sqlclosecheck // Aborting
@@ -250,6 +268,9 @@ func readFirstRow(results *sql.Rows, vopts *viewOptions) (*viewMetadata, error)
 	if lastSeq != nil {
 		meta.lastSeq = *lastSeq
 	}
+	if totalRows != nil {
+		meta.totalRows = *totalRows
+	}
 	return &meta, nil
 }
 
@@ -265,10 +286,14 @@ type rows struct {
 	db                  *db
 	rows                *sql.Rows
 	updateSeq           string
+	totalRows           int64
 	selector            *mango.Selector
 	findLimit, findSkip int64
 	index               int64
 	fields              []string
+	findIndex           *mangoIndex
+	terms               []string
+	warnings            []string
 
 	done     bool
 	bookmark string
@@ -358,7 +383,8 @@ func (r *rows) Next(row *driver.Row) error {
 		if r.selector != nil {
 			// This means we're responding to a _find query, which requires
 			// filtering the results, and a different format.
-			if !r.selector.Match(full.toMap()) {
+			doc := full.toMap()
+			if !r.selector.Match(doc) || !r.findIndex.includes(doc, r.terms) {
 				return r.Next(row)
 			}
 			r.index++
@@ -392,8 +418,25 @@ func (*rows) Offset() int64 {
 	return 0
 }
 
-func (*rows) TotalRows() int64 {
-	return 0
+func (r *rows) TotalRows() int64 {
+	if r.selector != nil {
+		// _find responses don't include a row count.
+		return 0
+	}
+	return r.totalRows
+}
+
+// Warning returns the warnings CouchDB sends with a _find response, such as
+// when it is served without an index.
+func (r *rows) Warning() string {
+	if r.selector == nil {
+		return ""
+	}
+	warnings := r.warnings
+	if r.findIndex == nil {
+		warnings = append(warnings, "No matching index found, create an index to optimize query time.")
+	}
+	return strings.Join(warnings, "\n")
 }
 
 func (r *rows) Bookmark() string {